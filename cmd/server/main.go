@@ -11,19 +11,27 @@ package main
 import (
 	"context"
 	"github.com/ZnNr/subscription-service/docs"
+	docsv2 "github.com/ZnNr/subscription-service/docs/v2"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/ZnNr/subscription-service/internal/config"
+	"github.com/ZnNr/subscription-service/internal/events"
+	"github.com/ZnNr/subscription-service/internal/graphqlapi"
+	"github.com/ZnNr/subscription-service/internal/grpcapi"
 	"github.com/ZnNr/subscription-service/internal/handler"
+	handlerv2 "github.com/ZnNr/subscription-service/internal/handler/v2"
 	"github.com/ZnNr/subscription-service/internal/repository"
 	"github.com/ZnNr/subscription-service/internal/service"
+	"github.com/ZnNr/subscription-service/internal/webhook"
 	"github.com/ZnNr/subscription-service/pkg/database"
 	"github.com/ZnNr/subscription-service/pkg/logger"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -54,12 +62,34 @@ func main() {
 
 	// Initialize repository, service, and handler
 	repo := repository.NewPostgresRepository(db)
-	svc := service.NewSubscriptionService(repo)
+	// Лента изменений подписок: все API пишут через один сервис, поэтому события видны в SSE
+	broker := events.NewBroker(cfg.Events.LogSize)
+	webhookTargets := webhook.TargetPolicy{AllowInsecure: cfg.Webhooks.AllowInsecureTargets}
+	svc := service.NewSubscriptionService(repo,
+		service.WithIdempotencyTTL(cfg.Idempotency.TTL), service.WithEvents(broker),
+		service.WithWebhooks(), service.WithWebhookTargets(webhookTargets))
 	h := handler.NewHandler(svc)
 
+	// Очередь вебхуков хранится в БД: доставки, не завершенные до остановки, продолжатся после запуска
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	dispatched := make(chan struct{})
+	go func() {
+		webhook.NewDispatcher(repo, cfg.Webhooks.MaxAttempts, webhookTargets).Run(dispatchCtx)
+		close(dispatched)
+	}()
+
+	// Горизонт журнала списаний сдвигается вслед за текущим месяцем, пока сервер работает
+	ledgerCtx, stopLedger := context.WithCancel(context.Background())
+	extended := make(chan struct{})
+	go func() {
+		svc.RunLedgerExtension(ledgerCtx)
+		close(extended)
+	}()
+
 	// Setup Gin router
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(handler.RequestID())
 	router.Use(logger.GinLogger())
 
 	// Настраиваем Swagger
@@ -69,9 +99,35 @@ func main() {
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	docs.SwaggerInfo.Schemes = []string{"http"}
 
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Документация v1 — /swagger/index.html, v2 — /swagger/v2/index.html
+	v1Docs := ginSwagger.WrapHandler(swaggerFiles.Handler)
+	v2Docs := ginSwagger.WrapHandler(swaggerFiles.NewHandler(), ginSwagger.InstanceName(docsv2.SwaggerInfov2.InstanceName()))
+	router.GET("/swagger/*any", func(c *gin.Context) {
+		if strings.HasPrefix(c.Param("any"), "/v2/") {
+			v2Docs(c)
+			return
+		}
+		v1Docs(c)
+	})
+
+	// GraphQL API поверх того же сервиса
+	gql := graphqlapi.NewHandler(svc)
+	router.GET("/graphql", gql.Handle)
+	router.POST("/graphql", gql.Handle)
+
+	// Запросы проверяются по той же спецификации, что отдает /swagger
+	v1Validator, err := handler.OpenAPIValidator(docs.SwaggerInfo.ReadDoc(), cfg.API.ValidateResponses)
+	if err != nil {
+		logger.Fatal("Failed to load API v1 specification", "error", err)
+	}
+	v2Validator, err := handler.OpenAPIValidator(docsv2.SwaggerInfov2.ReadDoc(), cfg.API.ValidateResponses)
+	if err != nil {
+		logger.Fatal("Failed to load API v2 specification", "error", err)
+	}
 
-	h.SetupRoutes(router)
+	// v1 заморожен: новые возможности появляются только в v2
+	h.SetupRoutes(router, handler.Deprecation(cfg.API.V1DeprecatedAt, cfg.API.V1Sunset, "/api/v2"), v1Validator)
+	handlerv2.NewHandler(svc, broker).SetupRoutes(router, v2Validator)
 
 	// Start server
 	server := &http.Server{
@@ -81,6 +137,8 @@ func main() {
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Потоки SSE не завершаются сами: при остановке слушатели отключаются, и Shutdown их не ждет
+	server.RegisterOnShutdown(broker.Close)
 
 	go func() {
 		logger.Info("Starting server", "address", server.Addr)
@@ -89,6 +147,22 @@ func main() {
 		}
 	}()
 
+	// gRPC API работает с тем же экземпляром сервиса, что и REST API
+	grpcServer := grpcapi.NewGRPCServer(svc)
+	grpcAddr := cfg.Server.Host + ":" + cfg.GRPC.Port
+
+	go func() {
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Fatal("Failed to listen gRPC address", "error", err)
+		}
+
+		logger.Info("Starting gRPC server", "address", grpcAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			logger.Fatal("Failed to start gRPC server", "error", err)
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -103,5 +177,23 @@ func main() {
 		logger.Fatal("Server forced to shutdown", "error", err)
 	}
 
+	// GracefulStop ждет завершения текущих вызовов; по истечении ctx они прерываются
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	stopDispatch()
+	<-dispatched
+	stopLedger()
+	<-extended
+
 	logger.Info("Server exited properly")
 }
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ZnNr/subscription-service/pkg/client"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Коды завершения
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// app — окружение команд: потоки ввода-вывода и переменные окружения
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	// defaultConfigPath — файл конфигурации, если он не задан флагом или SUBCTL_CONFIG
+	defaultConfigPath string
+}
+
+// command — подкоманда subctl
+type command struct {
+	name    string
+	summary string
+	run     func(a *app, ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"create", "создать подписку", (*app).create},
+	{"get", "показать подписку", (*app).get},
+	{"list", "список подписок с фильтрами", (*app).list},
+	{"update", "изменить подписку", (*app).update},
+	{"delete", "удалить подписку", (*app).delete},
+	{"summary", "сумма подписок за период", (*app).summary},
+	{"import", "импорт подписок из CSV или JSON", (*app).importFile},
+}
+
+// usageError — неверные аргументы команды
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// errFlagsPrinted — ошибка разбора флагов, о которой flag.FlagSet уже сообщил
+var errFlagsPrinted = errors.New("invalid flags")
+
+func (a *app) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		a.usage()
+		return exitUsage
+	}
+	if name := args[0]; name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		a.usage()
+		return exitOK
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(a.stderr, "subctl: unknown command %q\n\n", args[0])
+		a.usage()
+		return exitUsage
+	}
+
+	err := cmd.run(a, ctx, args[1:])
+
+	var usageErr *usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errFlagsPrinted):
+		return exitUsage
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(a.stderr, "subctl %s: %s\nrun 'subctl %s -h' for usage\n", cmd.name, usageErr.message, cmd.name)
+		return exitUsage
+	default:
+		a.printError(err)
+		return exitError
+	}
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "Использование: subctl <команда> [флаги]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Команды:")
+
+	w := tabwriter.NewWriter(a.stderr, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+	w.Flush()
+
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Флаги команды: subctl <команда> -h")
+}
+
+// printError выводит ошибку; для ответа сервиса — с нарушениями по полям, конфликтами и идентификатором запроса
+func (a *app) printError(err error) {
+	fmt.Fprintf(a.stderr, "error: %s\n", err)
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		return
+	}
+
+	for _, violation := range apiErr.Errors {
+		fmt.Fprintf(a.stderr, "  %s: %s\n", violation.Field, violation.Message)
+	}
+	if len(apiErr.Conflicts) > 0 {
+		ids := make([]string, 0, len(apiErr.Conflicts))
+		for _, id := range apiErr.Conflicts {
+			ids = append(ids, id.String())
+		}
+		fmt.Fprintf(a.stderr, "  conflicts with: %s (use --force to save anyway)\n", strings.Join(ids, ", "))
+	}
+	if apiErr.RequestID != "" {
+		fmt.Fprintf(a.stderr, "  request id: %s\n", apiErr.RequestID)
+	}
+}
+
+// newFlagSet создает флаги команды вместе с общими флагами подключения и вывода
+func (a *app) newFlagSet(name, arguments string) (*flag.FlagSet, *globalFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Использование: subctl %s %s\n\nФлаги:\n", name, arguments)
+		fs.PrintDefaults()
+	}
+
+	g := &globalFlags{}
+	g.register(fs)
+
+	return fs, g
+}
+
+// parseArgs разбирает флаги и возвращает позиционные аргументы; флаги можно указывать
+// и после позиционных аргументов (subctl get <id> -o json)
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errFlagsPrinted
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// connect собирает настройки и создает клиент сервиса
+func (a *app) connect(fs *flag.FlagSet, g *globalFlags) (*client.Client, config, error) {
+	cfg, err := a.loadConfig(fs, g)
+	if err != nil {
+		return nil, cfg, err
+	}
+
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, cfg, err
+	}
+
+	return c, cfg, nil
+}
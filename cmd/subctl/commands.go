@@ -0,0 +1,489 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ZnNr/subscription-service/pkg/client"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// monthLayout — формат месяцев в аргументах и выводе, как в REST API v1 и файлах импорта
+const monthLayout = "01-2006"
+
+// stringsFlag — флаг, который можно указать несколько раз
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func (a *app) create(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("create", "--service <название> --price <цена> --user <uuid> --start <MM-YYYY> [флаги]")
+	serviceName := fs.String("service", "", "название сервиса")
+	price := fs.Int("price", 0, "стоимость в месяц, рублей")
+	userID := fs.String("user", "", "идентификатор пользователя")
+	start := fs.String("start", "", "месяц начала, MM-YYYY")
+	end := fs.String("end", "", "месяц окончания, MM-YYYY")
+	category := fs.String("category", "", "категория")
+	force := fs.Bool("force", false, "сохранить, даже если подписка пересекается с существующими")
+	idempotencyKey := fs.String("idempotency-key", "", "ключ идемпотентности; повтор с тем же ключом не создает дубликат")
+
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	set := visited(fs)
+
+	for _, name := range []string{"service", "price", "user", "start"} {
+		if !set[name] {
+			return usageErrorf("--%s is required", name)
+		}
+	}
+
+	input := client.SubscriptionInput{ServiceName: *serviceName, Price: *price}
+	var err error
+	if input.UserID, err = parseUUID("user", *userID); err != nil {
+		return err
+	}
+	if input.StartDate, err = parseMonth("start", *start); err != nil {
+		return err
+	}
+	if set["end"] {
+		if input.EndDate, err = parseOptionalMonth("end", *end); err != nil {
+			return err
+		}
+	}
+	if *category != "" {
+		input.Category = category
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	created, err := c.CreateSubscription(ctx, input, client.WriteOptions{Force: *force, IdempotencyKey: *idempotencyKey})
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range created.Warnings {
+		fmt.Fprintf(a.stderr, "warning: %s\n", warning)
+	}
+
+	return writeSubscription(a.stdout, cfg.Output, &created.Subscription)
+}
+
+func (a *app) get(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("get", "<id> [флаги]")
+
+	id, err := parseIDArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	sub, err := c.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return writeSubscription(a.stdout, cfg.Output, sub)
+}
+
+func (a *app) list(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("list", "[флаги]")
+	userID := fs.String("user", "", "идентификатор пользователя")
+	var serviceNames stringsFlag
+	fs.Var(&serviceNames, "service", "точное название сервиса; можно указать несколько раз")
+	search := fs.String("search", "", "подстрока названия сервиса или категории")
+	activeAt := fs.String("active-at", "", "месяц, в котором подписка действует, MM-YYYY")
+	priceMin := fs.Int("price-min", 0, "наименьшая стоимость")
+	priceMax := fs.Int("price-max", 0, "наибольшая стоимость")
+	startFrom := fs.String("start-from", "", "месяц начала не раньше, MM-YYYY")
+	startTo := fs.String("start-to", "", "месяц начала не позже, MM-YYYY")
+	endFrom := fs.String("end-from", "", "месяц окончания не раньше, MM-YYYY")
+	endTo := fs.String("end-to", "", "месяц окончания не позже, MM-YYYY")
+	status := fs.String("status", "", "active, ended или future")
+	sort := fs.String("sort", "", "поля сортировки через запятую, '-' — по убыванию, например -price,service_name")
+	limit := fs.Int("limit", 50, "размер страницы (до 500)")
+	cursor := fs.String("cursor", "", "курсор следующей страницы")
+	all := fs.Bool("all", false, "вывести все страницы")
+
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	set := visited(fs)
+
+	opts := client.ListOptions{
+		ServiceNames: serviceNames,
+		Search:       *search,
+		Status:       *status,
+		Sort:         *sort,
+		Limit:        *limit,
+		Cursor:       *cursor,
+	}
+	if set["user"] {
+		id, err := parseUUID("user", *userID)
+		if err != nil {
+			return err
+		}
+		opts.UserID = &id
+	}
+	if set["price-min"] {
+		opts.PriceMin = priceMin
+	}
+	if set["price-max"] {
+		opts.PriceMax = priceMax
+	}
+
+	months := []struct {
+		name   string
+		value  string
+		target **time.Time
+	}{
+		{"active-at", *activeAt, &opts.ActiveAt},
+		{"start-from", *startFrom, &opts.StartFrom},
+		{"start-to", *startTo, &opts.StartTo},
+		{"end-from", *endFrom, &opts.EndFrom},
+		{"end-to", *endTo, &opts.EndTo},
+	}
+	for _, month := range months {
+		parsed, err := parseOptionalMonth(month.name, month.value)
+		if err != nil {
+			return err
+		}
+		*month.target = parsed
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	var subs []*client.Subscription
+	for {
+		page, err := c.ListSubscriptions(ctx, opts)
+		if err != nil {
+			return err
+		}
+		subs = append(subs, page.Items...)
+
+		if page.NextCursor == nil {
+			break
+		}
+		if !*all {
+			// Подсказка — в stderr, чтобы не смешивать её с выводом в JSON или CSV
+			fmt.Fprintf(a.stderr, "more results: --cursor %s (or --all)\n", *page.NextCursor)
+			break
+		}
+		opts.Cursor = *page.NextCursor
+	}
+
+	return writeSubscriptions(a.stdout, cfg.Output, subs)
+}
+
+func (a *app) update(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("update", "<id> [флаги]")
+	serviceName := fs.String("service", "", "название сервиса")
+	price := fs.Int("price", 0, "стоимость в месяц, рублей")
+	userID := fs.String("user", "", "идентификатор пользователя")
+	start := fs.String("start", "", "месяц начала, MM-YYYY")
+	end := fs.String("end", "", "месяц окончания, MM-YYYY; пустое значение убирает дату окончания")
+	category := fs.String("category", "", "категория; пустое значение убирает категорию")
+	force := fs.Bool("force", false, "сохранить, даже если подписка пересекается с существующими")
+	ifMatch := fs.Int("if-match", 0, "ожидаемая версия подписки (по умолчанию — версия, прочитанная перед изменением)")
+
+	id, err := parseIDArg(fs, args)
+	if err != nil {
+		return err
+	}
+	set := visited(fs)
+
+	if !set["service"] && !set["price"] && !set["user"] && !set["start"] && !set["end"] && !set["category"] {
+		return usageErrorf("nothing to update, set at least one of --service, --price, --user, --start, --end, --category")
+	}
+
+	// Аргументы проверяются до обращения к сервису
+	var (
+		newUserID  uuid.UUID
+		newStart   time.Time
+		newEnd     *time.Time
+		parseError error
+	)
+	if set["user"] {
+		newUserID, parseError = parseUUID("user", *userID)
+	}
+	if set["start"] && parseError == nil {
+		newStart, parseError = parseMonth("start", *start)
+	}
+	if set["end"] && parseError == nil {
+		newEnd, parseError = parseOptionalMonth("end", *end)
+	}
+	if parseError != nil {
+		return parseError
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	// Сервис заменяет подписку целиком: незаданные поля берутся из текущей версии,
+	// а If-Match с её номером не дает затереть изменения, сделанные между чтением и записью
+	current, err := c.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	input := client.SubscriptionInput{
+		ServiceName: current.ServiceName,
+		Price:       current.Price,
+		UserID:      current.UserID,
+		StartDate:   current.StartDate,
+		EndDate:     current.EndDate,
+		Category:    current.Category,
+	}
+	if set["service"] {
+		input.ServiceName = *serviceName
+	}
+	if set["price"] {
+		input.Price = *price
+	}
+	if set["user"] {
+		input.UserID = newUserID
+	}
+	if set["start"] {
+		input.StartDate = newStart
+	}
+	if set["end"] {
+		input.EndDate = newEnd
+	}
+	if set["category"] {
+		input.Category = nil
+		if *category != "" {
+			input.Category = category
+		}
+	}
+
+	opts := client.WriteOptions{Force: *force, IfMatch: &current.Version}
+	if set["if-match"] {
+		opts.IfMatch = ifMatch
+	}
+
+	if _, err := c.UpdateSubscription(ctx, id, input, opts); err != nil {
+		return err
+	}
+
+	updated, err := c.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return writeSubscription(a.stdout, cfg.Output, updated)
+}
+
+func (a *app) delete(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("delete", "<id> [флаги]")
+	ifMatch := fs.Int("if-match", 0, "удалить, только если версия подписки совпадает")
+
+	id, err := parseIDArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	var opts client.WriteOptions
+	if visited(fs)["if-match"] {
+		opts.IfMatch = ifMatch
+	}
+
+	if err := c.DeleteSubscription(ctx, id, opts); err != nil {
+		return err
+	}
+
+	if cfg.Output == outputTable {
+		fmt.Fprintf(a.stdout, "subscription %s deleted\n", id)
+	}
+
+	return nil
+}
+
+func (a *app) summary(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("summary", "--start <MM-YYYY> --end <MM-YYYY> [флаги]")
+	start := fs.String("start", "", "первый месяц периода, MM-YYYY")
+	end := fs.String("end", "", "последний месяц периода, MM-YYYY")
+	userID := fs.String("user", "", "идентификатор пользователя")
+	serviceName := fs.String("service", "", "название сервиса")
+	source := fs.String("source", "", "subscriptions (по умолчанию) или ledger — по журналу списаний")
+
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	set := visited(fs)
+
+	req := client.SummaryRequest{Source: *source}
+	var err error
+	if req.StartDate, err = parseMonth("start", *start); err != nil {
+		return err
+	}
+	if req.EndDate, err = parseMonth("end", *end); err != nil {
+		return err
+	}
+	if set["user"] {
+		id, err := parseUUID("user", *userID)
+		if err != nil {
+			return err
+		}
+		req.UserID = &id
+	}
+	if set["service"] {
+		req.ServiceName = serviceName
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	summary, err := c.CalculateSummary(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return writeSummary(a.stdout, cfg.Output, summary)
+}
+
+func (a *app) importFile(ctx context.Context, args []string) error {
+	fs, g := a.newFlagSet("import", "<файл | -> [флаги]")
+	format := fs.String("format", "", "формат файла: csv или json (по умолчанию — по расширению; для stdin обязателен)")
+	dryRun := fs.Bool("dry-run", false, "только проверить строки, ничего не сохраняя")
+	resumeFrom := fs.Int("resume-from", 0, "номер строки данных (с 1), с которой продолжить импорт")
+	force := fs.Bool("force", false, "сохранять подписки, несмотря на пересечение с существующими")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("expected one file argument, use - for stdin")
+	}
+	path := positional[0]
+
+	opts := client.ImportOptions{Format: *format, DryRun: *dryRun, ResumeFrom: *resumeFrom, Force: *force}
+	if opts.Format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			opts.Format = client.ImportFormatCSV
+		case ".json":
+			opts.Format = client.ImportFormatJSON
+		default:
+			return usageErrorf("cannot detect format of %q, set --format csv or --format json", path)
+		}
+	}
+
+	c, cfg, err := a.connect(fs, g)
+	if err != nil {
+		return err
+	}
+
+	var file io.Reader = a.stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	report, err := c.ImportSubscriptions(ctx, file, opts)
+	if report != nil {
+		if writeErr := writeImportReport(a.stdout, cfg.Output, report); writeErr != nil {
+			return writeErr
+		}
+	}
+
+	return err
+}
+
+// parseNoArgs разбирает флаги команды без позиционных аргументов
+func parseNoArgs(fs *flag.FlagSet, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("unexpected argument %q", positional[0])
+	}
+
+	return nil
+}
+
+// parseIDArg разбирает флаги команды и идентификатор подписки — единственный позиционный аргумент
+func parseIDArg(fs *flag.FlagSet, args []string) (uuid.UUID, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(positional) != 1 {
+		return uuid.Nil, usageErrorf("expected one subscription id argument")
+	}
+
+	return parseUUID("id", positional[0])
+}
+
+func parseUUID(name, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, usageErrorf("invalid %s %q, expected UUID", name, value)
+	}
+
+	return id, nil
+}
+
+func parseMonth(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, usageErrorf("--%s is required", name)
+	}
+
+	month, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, usageErrorf("invalid --%s %q, expected MM-YYYY", name, value)
+	}
+
+	return month, nil
+}
+
+// parseOptionalMonth разбирает месяц; пустое значение — месяц не задан
+func parseOptionalMonth(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	month, err := parseMonth(name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &month, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ZnNr/subscription-service/pkg/client"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Форматы вывода
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputCSV}
+
+// config — настройки подключения к сервису и вывода
+type config struct {
+	// Server — адрес сервиса, например http://localhost:8080
+	Server string `yaml:"server"`
+	// Output — формат вывода: table, json или csv
+	Output string `yaml:"output"`
+	// Timeout — ограничение времени одной попытки запроса
+	Timeout time.Duration `yaml:"timeout"`
+	// Retries — сколько раз повторяется запрос при временной ошибке
+	Retries int `yaml:"retries"`
+}
+
+func defaultConfig() config {
+	return config{
+		Server:  "http://localhost:8080",
+		Output:  outputTable,
+		Timeout: client.DefaultTimeout,
+		Retries: client.DefaultRetryPolicy.MaxRetries,
+	}
+}
+
+// globalFlags — флаги подключения и вывода, общие для всех команд
+type globalFlags struct {
+	config  string
+	server  string
+	output  string
+	timeout time.Duration
+	retries int
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	defaults := defaultConfig()
+
+	fs.StringVar(&g.config, "config", "", "файл конфигурации (SUBCTL_CONFIG)")
+	fs.StringVar(&g.server, "server", defaults.Server, "адрес сервиса (SUBCTL_SERVER)")
+	fs.StringVar(&g.output, "o", defaults.Output, "формат вывода: table, json или csv (SUBCTL_OUTPUT)")
+	fs.DurationVar(&g.timeout, "timeout", defaults.Timeout, "ограничение времени одной попытки запроса (SUBCTL_TIMEOUT)")
+	fs.IntVar(&g.retries, "retries", defaults.Retries, "число повторов при временной ошибке (SUBCTL_RETRIES)")
+}
+
+// loadConfig собирает настройки: значения по умолчанию, затем файл конфигурации,
+// переменные окружения и явно заданные флаги
+func (a *app) loadConfig(fs *flag.FlagSet, g *globalFlags) (config, error) {
+	cfg := defaultConfig()
+	set := visited(fs)
+
+	path, explicit := a.defaultConfigPath, false
+	if value := a.getenv("SUBCTL_CONFIG"); value != "" {
+		path, explicit = value, true
+	}
+	if set["config"] {
+		path, explicit = g.config, true
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("config %s: %w", path, err)
+			}
+		// Файл по умолчанию необязателен, указанный явно — должен существовать
+		case !errors.Is(err, os.ErrNotExist) || explicit:
+			return cfg, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	if value := a.getenv("SUBCTL_SERVER"); value != "" {
+		cfg.Server = value
+	}
+	if value := a.getenv("SUBCTL_OUTPUT"); value != "" {
+		cfg.Output = value
+	}
+	if value := a.getenv("SUBCTL_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid SUBCTL_TIMEOUT %q, expected duration like 30s", value)
+		}
+		cfg.Timeout = timeout
+	}
+	if value := a.getenv("SUBCTL_RETRIES"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid SUBCTL_RETRIES %q, expected integer", value)
+		}
+		cfg.Retries = retries
+	}
+
+	if set["server"] {
+		cfg.Server = g.server
+	}
+	if set["o"] {
+		cfg.Output = g.output
+	}
+	if set["timeout"] {
+		cfg.Timeout = g.timeout
+	}
+	if set["retries"] {
+		cfg.Retries = g.retries
+	}
+
+	if !slices.Contains(outputFormats, cfg.Output) {
+		return cfg, fmt.Errorf("unknown output format %q, expected table, json or csv", cfg.Output)
+	}
+	if cfg.Timeout <= 0 {
+		return cfg, errors.New("timeout must be positive")
+	}
+	if cfg.Retries < 0 {
+		return cfg, errors.New("retries must not be negative")
+	}
+
+	return cfg, nil
+}
+
+// newClient создает клиент сервиса по настройкам
+func newClient(cfg config) (*client.Client, error) {
+	retry := client.DefaultRetryPolicy
+	retry.MaxRetries = cfg.Retries
+
+	return client.New(cfg.Server,
+		client.WithHTTPClient(&http.Client{Timeout: cfg.Timeout}),
+		client.WithRetryPolicy(retry),
+		client.WithUserAgent("subctl/1.0"),
+	)
+}
+
+// visited возвращает флаги, заданные в командной строке явно
+func visited(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	return set
+}
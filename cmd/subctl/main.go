@@ -0,0 +1,42 @@
+// Command subctl — консольный клиент REST API сервиса подписок.
+//
+//	subctl list --user 60601fee-2bf1-4721-ae6f-7636e79a0cba --status active -o csv
+//	subctl create --service Netflix --price 599 --user 60601fee-2bf1-4721-ae6f-7636e79a0cba --start 01-2025
+//	subctl update 3f0c... --price 699
+//	subctl import --dry-run subscriptions.csv
+//
+// Адрес сервиса, формат вывода, таймаут и число повторов задаются флагами, переменными окружения
+// SUBCTL_SERVER, SUBCTL_OUTPUT, SUBCTL_TIMEOUT, SUBCTL_RETRIES или файлом конфигурации
+// (--config, SUBCTL_CONFIG, по умолчанию subctl/config.yaml в каталоге конфигурации пользователя).
+// Флаги важнее переменных окружения, переменные окружения — файла.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var configPath string
+	if dir, err := os.UserConfigDir(); err == nil {
+		configPath = filepath.Join(dir, "subctl", "config.yaml")
+	}
+
+	a := &app{
+		stdin:             os.Stdin,
+		stdout:            os.Stdout,
+		stderr:            os.Stderr,
+		getenv:            os.Getenv,
+		defaultConfigPath: configPath,
+	}
+
+	code := a.run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSubscriptionID = "3f0c6c1e-7b7a-4c55-9a8e-2d7c1b0f4a11"
+	testUserID         = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+)
+
+// testSubscriptionJSON — подписка в формате ответов /api/v2
+const testSubscriptionJSON = `{"id":"` + testSubscriptionID + `","service_name":"Yandex Plus","price":400,"user_id":"` + testUserID + `",
+	"start_date":"2025-07","end_date":null,"category":"video","created_at":"2025-07-01T10:00:00Z","updated_at":"2025-07-01T10:00:00Z","version":3}`
+
+// recordedRequest — запрос, полученный тестовым сервером
+type recordedRequest struct {
+	method string
+	uri    string
+	header http.Header
+	body   string
+}
+
+// testServer отвечает на запросы по методу и пути заготовленными ответами и запоминает запросы
+type testServer struct {
+	*httptest.Server
+	requests []recordedRequest
+}
+
+type cannedResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+func newTestServer(t *testing.T, responses map[string]cannedResponse) *testServer {
+	ts := &testServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts.requests = append(ts.requests, recordedRequest{method: r.Method, uri: r.URL.RequestURI(), header: r.Header, body: string(body)})
+
+		resp, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		for name, value := range resp.header {
+			w.Header().Set(name, value)
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(resp.status)
+		_, _ = io.WriteString(w, resp.body)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// runCommand выполняет subctl с окружением env и возвращает код завершения, stdout и stderr
+func runCommand(t *testing.T, env map[string]string, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	a := &app{
+		stdin:             strings.NewReader(stdin),
+		stdout:            &stdout,
+		stderr:            &stderr,
+		getenv:            func(key string) string { return env[key] },
+		defaultConfigPath: filepath.Join(t.TempDir(), "config.yaml"),
+	}
+
+	code := a.run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestList_FiltersAndCSV(t *testing.T) {
+	ts := newTestServer(t, map[string]cannedResponse{
+		"GET /api/v2/subscriptions": {status: http.StatusOK, body: `{"data":[` + testSubscriptionJSON + `],"pagination":{"limit":50,"next_cursor":"abc"}}`},
+	})
+
+	code, stdout, stderr := runCommand(t, map[string]string{"SUBCTL_SERVER": ts.URL}, "",
+		"list", "--user", testUserID, "--service", "Netflix", "--service", "Yandex Plus", "--active-at", "03-2025", "--price-min", "0", "-o", "csv")
+
+	require.Equal(t, exitOK, code, stderr)
+	require.Len(t, ts.requests, 1)
+	assert.Equal(t, "/api/v2/subscriptions?active_at=2025-03&limit=50&price_min=0&service_name=Netflix&service_name=Yandex+Plus&user_id="+testUserID, ts.requests[0].uri)
+	assert.Equal(t, "id,service_name,price,user_id,start_date,end_date,category,version,created_at,updated_at\n"+
+		testSubscriptionID+",Yandex Plus,400,"+testUserID+",07-2025,,video,3,2025-07-01T10:00:00Z,2025-07-01T10:00:00Z\n", stdout)
+	assert.Equal(t, "more results: --cursor abc (or --all)\n", stderr)
+}
+
+func TestList_AllPagesAsJSON(t *testing.T) {
+	ts := newTestServer(t, map[string]cannedResponse{
+		"GET /api/v2/subscriptions": {status: http.StatusOK, body: `{"data":[` + testSubscriptionJSON + `],"pagination":{"limit":50,"next_cursor":"abc"}}`},
+	})
+
+	// Тестовый сервер всегда возвращает курсор, поэтому вторую страницу отдает отдельный обработчик
+	ts.Config.Handler = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("cursor") == "abc" {
+				ts.requests = append(ts.requests, recordedRequest{method: r.Method, uri: r.URL.RequestURI()})
+				_, _ = io.WriteString(w, `{"data":[],"pagination":{"limit":50,"next_cursor":null}}`)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}(ts.Config.Handler)
+
+	code, stdout, stderr := runCommand(t, nil, "", "list", "--server", ts.URL, "--all", "-o", "json")
+
+	require.Equal(t, exitOK, code, stderr)
+	require.Len(t, ts.requests, 2)
+	assert.Contains(t, ts.requests[1].uri, "cursor=abc")
+
+	var subs []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout), &subs))
+	require.Len(t, subs, 1)
+	assert.Equal(t, testSubscriptionID, subs[0]["id"])
+	assert.Empty(t, stderr)
+}
+
+func TestUpdate_MergesCurrentSubscription(t *testing.T) {
+	ts := newTestServer(t, map[string]cannedResponse{
+		"GET /api/v2/subscriptions/" + testSubscriptionID: {status: http.StatusOK, body: `{"data":` + testSubscriptionJSON + `}`},
+		"PUT /api/v2/subscriptions/" + testSubscriptionID: {status: http.StatusOK, header: map[string]string{"ETag": `"4"`}, body: `{"data":` + testSubscriptionJSON + `}`},
+	})
+
+	code, stdout, stderr := runCommand(t, map[string]string{"SUBCTL_SERVER": ts.URL}, "",
+		"update", testSubscriptionID, "--price", "450", "--end", "12-2025", "--category", "")
+
+	require.Equal(t, exitOK, code, stderr)
+	require.Len(t, ts.requests, 3)
+
+	put := ts.requests[1]
+	assert.Equal(t, http.MethodPut, put.method)
+	assert.Equal(t, `"3"`, put.header.Get("If-Match"))
+	assert.JSONEq(t, `{"service_name":"Yandex Plus","price":450,"user_id":"`+testUserID+`","start_date":"2025-07","end_date":"2025-12"}`, put.body)
+	assert.Contains(t, stdout, "Yandex Plus")
+}
+
+func TestUpdate_PreconditionFailed(t *testing.T) {
+	ts := newTestServer(t, map[string]cannedResponse{
+		"GET /api/v2/subscriptions/" + testSubscriptionID: {status: http.StatusOK, body: `{"data":` + testSubscriptionJSON + `}`},
+		"PUT /api/v2/subscriptions/" + testSubscriptionID: {
+			status: http.StatusPreconditionFailed,
+			header: map[string]string{"Content-Type": "application/problem+json"},
+			body:   `{"title":"Precondition Failed","status":412,"detail":"subscription was modified","code":"precondition_failed","request_id":"req-1"}`,
+		},
+	})
+
+	code, _, stderr := runCommand(t, map[string]string{"SUBCTL_SERVER": ts.URL}, "", "update", testSubscriptionID, "--price", "450", "--if-match", "2")
+
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, `"2"`, ts.requests[1].header.Get("If-Match"))
+	assert.Equal(t, "error: subscription service: 412 precondition_failed: subscription was modified\n  request id: req-1\n", stderr)
+}
+
+func TestCreate_ValidationError(t *testing.T) {
+	ts := newTestServer(t, map[string]cannedResponse{
+		"POST /api/v2/subscriptions": {
+			status: http.StatusBadRequest,
+			header: map[string]string{"Content-Type": "application/problem+json"},
+			body:   `{"title":"Bad Request","status":400,"detail":"request body failed validation","code":"validation_failed","errors":[{"field":"price","message":"must be at least 1"}]}`,
+		},
+	})
+
+	code, _, stderr := runCommand(t, map[string]string{"SUBCTL_SERVER": ts.URL}, "",
+		"create", "--service", "Netflix", "--price", "0", "--user", testUserID, "--start", "01-2025")
+
+	assert.Equal(t, exitError, code)
+	require.Len(t, ts.requests, 1)
+	assert.NotEmpty(t, ts.requests[0].header.Get("Idempotency-Key"))
+	assert.Contains(t, stderr, "400 validation_failed: request body failed validation\n  price: must be at least 1\n")
+}
+
+func TestCreate_UsageErrors(t *testing.T) {
+	ts := newTestServer(t, nil)
+	env := map[string]string{"SUBCTL_SERVER": ts.URL}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing start", []string{"create", "--service", "Netflix", "--price", "599", "--user", testUserID}, "--start is required"},
+		{"invalid month", []string{"create", "--service", "Netflix", "--price", "599", "--user", testUserID, "--start", "2025-01"}, `invalid --start "2025-01", expected MM-YYYY`},
+		{"invalid user", []string{"create", "--service", "Netflix", "--price", "599", "--user", "42", "--start", "01-2025"}, `invalid user "42", expected UUID`},
+		{"unexpected argument", []string{"create", "Netflix"}, `unexpected argument "Netflix"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCommand(t, env, "", tt.args...)
+
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, stderr, tt.want)
+		})
+	}
+	assert.Empty(t, ts.requests)
+}
+
+func TestImport_DryRunFromStdin(t *testing.T) {
+	ts := newTestServer(t, map[string]cannedResponse{
+		"POST /api/v1/subscriptions/import": {
+			status: http.StatusUnprocessableEntity,
+			body: `{"dry_run":true,"total":2,"valid":1,"invalid":1,"created":0,"rows":[{"row":1,"status":"valid"},
+				{"row":2,"status":"invalid","code":"validation_failed","error":"row failed validation","errors":[{"field":"start_date","message":"must be in MM-YYYY format"}]}]}`,
+		},
+	})
+
+	file := `[{"service_name":"Netflix"},{"service_name":"Spotify"}]`
+	code, stdout, stderr := runCommand(t, map[string]string{"SUBCTL_SERVER": ts.URL}, file, "import", "-", "--format", "json", "--dry-run")
+
+	assert.Equal(t, exitError, code)
+	require.Len(t, ts.requests, 1)
+	assert.Equal(t, "/api/v1/subscriptions/import?dry_run=true", ts.requests[0].uri)
+	assert.Equal(t, "application/json", ts.requests[0].header.Get("Content-Type"))
+	assert.Equal(t, file, ts.requests[0].body)
+	assert.Contains(t, stdout, "total: 2, valid: 1, invalid: 1, created: 0 (dry run)\n")
+	assert.Contains(t, stdout, "row failed validation; start_date: must be in MM-YYYY format")
+	assert.Contains(t, stderr, "422 validation_failed: 1 of 2 rows failed validation, nothing was saved")
+}
+
+func TestImport_FormatRequiredForStdin(t *testing.T) {
+	code, _, stderr := runCommand(t, nil, "", "import", "-")
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "set --format csv or --format json")
+}
+
+func TestConfig_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subctl.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server: http://from-file:8080\noutput: json\ntimeout: 5s\nretries: 1\n"), 0o600))
+
+	a := &app{getenv: func(key string) string {
+		return map[string]string{"SUBCTL_CONFIG": path, "SUBCTL_OUTPUT": "csv", "SUBCTL_RETRIES": "0"}[key]
+	}}
+	fs, g := a.newFlagSet("list", "")
+	require.NoError(t, fs.Parse([]string{"--retries", "2"}))
+
+	cfg, err := a.loadConfig(fs, g)
+
+	require.NoError(t, err)
+	assert.Equal(t, config{Server: "http://from-file:8080", Output: outputCSV, Timeout: 5e9, Retries: 2}, cfg)
+}
+
+func TestConfig_Errors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	code, _, stderr := runCommand(t, map[string]string{"SUBCTL_CONFIG": missing}, "", "get", testSubscriptionID)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "no such file")
+
+	code, _, stderr = runCommand(t, nil, "", "get", testSubscriptionID, "-o", "xml")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, `unknown output format "xml"`)
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	code, _, stderr := runCommand(t, nil, "", "remove")
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "remove"`)
+	assert.Contains(t, stderr, "summary")
+}
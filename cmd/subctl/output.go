@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/ZnNr/subscription-service/pkg/client"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// subscriptionColumns — столбцы CSV со списком подписок. Названия полей совпадают с импортом,
+// поэтому выгрузку можно поправить и загрузить обратно командой import (id и служебные поля пропускаются).
+var subscriptionColumns = []string{"id", "service_name", "price", "user_id", "start_date", "end_date", "category", "version", "created_at", "updated_at"}
+
+// writeSubscription выводит одну подписку; в JSON — объектом, а не массивом
+func writeSubscription(w io.Writer, format string, sub *client.Subscription) error {
+	if format == outputJSON {
+		return writeJSON(w, sub)
+	}
+
+	return writeSubscriptions(w, format, []*client.Subscription{sub})
+}
+
+func writeSubscriptions(w io.Writer, format string, subs []*client.Subscription) error {
+	switch format {
+	case outputJSON:
+		if subs == nil {
+			subs = []*client.Subscription{}
+		}
+		return writeJSON(w, subs)
+
+	case outputCSV:
+		records := make([][]string, 0, len(subs))
+		for _, sub := range subs {
+			records = append(records, []string{
+				sub.ID.String(),
+				sub.ServiceName,
+				strconv.Itoa(sub.Price),
+				sub.UserID.String(),
+				sub.StartDate.Format(monthLayout),
+				formatOptionalMonth(sub.EndDate, ""),
+				optionalString(sub.Category, ""),
+				strconv.Itoa(sub.Version),
+				sub.CreatedAt.Format(time.RFC3339),
+				sub.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+		return writeCSV(w, subscriptionColumns, records)
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSERVICE\tPRICE\tUSER\tSTART\tEND\tCATEGORY\tVERSION")
+		for _, sub := range subs {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%d\n",
+				sub.ID, sub.ServiceName, sub.Price, sub.UserID,
+				sub.StartDate.Format(monthLayout), formatOptionalMonth(sub.EndDate, "-"),
+				optionalString(sub.Category, "-"), sub.Version)
+		}
+		return tw.Flush()
+	}
+}
+
+func writeSummary(w io.Writer, format string, summary *client.Summary) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, summary)
+
+	case outputCSV:
+		return writeCSV(w, []string{"total_amount", "count", "adjustments_amount"}, [][]string{{
+			strconv.Itoa(summary.TotalAmount),
+			strconv.Itoa(summary.Count),
+			strconv.Itoa(summary.AdjustmentsAmount),
+		}})
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TOTAL\tCOUNT\tADJUSTMENTS")
+		fmt.Fprintf(tw, "%d\t%d\t%d\n", summary.TotalAmount, summary.Count, summary.AdjustmentsAmount)
+		return tw.Flush()
+	}
+}
+
+func writeImportReport(w io.Writer, format string, report *client.ImportReport) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, report)
+
+	case outputCSV:
+		records := make([][]string, 0, len(report.Rows))
+		for _, row := range report.Rows {
+			var id string
+			if row.ID != nil {
+				id = row.ID.String()
+			}
+			records = append(records, []string{strconv.Itoa(row.Row), row.Status, id, row.Code, rowError(row)})
+		}
+		return writeCSV(w, []string{"row", "status", "id", "code", "error"}, records)
+
+	default:
+		fmt.Fprintf(w, "total: %d, valid: %d, invalid: %d, created: %d", report.Total, report.Valid, report.Invalid, report.Created)
+		if report.DryRun {
+			fmt.Fprint(w, " (dry run)")
+		}
+		fmt.Fprintln(w)
+		if report.ResumeFrom != nil {
+			fmt.Fprintf(w, "resume with: --resume-from %d\n", *report.ResumeFrom)
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ROW\tSTATUS\tID\tERROR")
+		for _, row := range report.Rows {
+			id := "-"
+			if row.ID != nil {
+				id = row.ID.String()
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", row.Row, row.Status, id, rowError(row))
+		}
+		return tw.Flush()
+	}
+}
+
+// rowError описывает ошибку строки импорта вместе с нарушениями по полям
+func rowError(row *client.ImportRowResult) string {
+	parts := make([]string, 0, len(row.Errors)+1)
+	if row.Error != "" {
+		parts = append(parts, row.Error)
+	}
+	for _, violation := range row.Errors {
+		parts = append(parts, violation.Field+": "+violation.Message)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func writeCSV(w io.Writer, header []string, records [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	return writer.WriteAll(records)
+}
+
+func formatOptionalMonth(month *time.Time, empty string) string {
+	if month == nil {
+		return empty
+	}
+
+	return month.Format(monthLayout)
+}
+
+func optionalString(value *string, empty string) string {
+	if value == nil {
+		return empty
+	}
+
+	return *value
+}
@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/subscriptions": {
-            "get": {
-                "description": "Возвращает список подписок с возможностью фильтрации",
+        "/adjustments/{id}": {
+            "delete": {
+                "description": "Удаляет корректировку по её ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,21 +25,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "adjustments"
                 ],
-                "summary": "Список подписок",
+                "summary": "Удалить корректировку",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID пользователя для фильтрации",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "ID корректировки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Корректировка удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
                     },
+                    "404": {
+                        "description": "Корректировка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets": {
+            "get": {
+                "description": "Возвращает все бюджеты пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Список бюджетов",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Название сервиса для фильтрации",
-                        "name": "service_name",
-                        "in": "query"
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -48,14 +84,20 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Subscription"
+                                "$ref": "#/definitions/model.Budget"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Неверный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Создает новую запись о подписке пользователя",
+                "description": "Задает месячный лимит расходов пользователя на подписки (общий или для категории). Повторный вызов для той же категории обновляет лимит",
                 "consumes": [
                     "application/json"
                 ],
@@ -63,47 +105,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Создать подписку",
+                "summary": "Задать бюджет",
                 "parameters": [
                     {
-                        "description": "Данные подписки",
+                        "description": "Параметры бюджета",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateSubscriptionRequest"
+                            "$ref": "#/definitions/model.SetBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Subscription"
+                            "$ref": "#/definitions/model.Budget"
                         }
                     },
                     "400": {
                         "description": "Неверный запрос",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
             }
         },
-        "/subscriptions/summary": {
+        "/budgets/report": {
             "post": {
-                "description": "Рассчитывает суммарную стоимость подписок за период с фильтрацией",
+                "description": "Для каждого месяца периода и каждого бюджета пользователя возвращает фактические расходы и признак превышения лимита",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,17 +151,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Сумма подписок",
+                "summary": "Отчет по бюджетам",
                 "parameters": [
                     {
-                        "description": "Параметры расчета",
+                        "description": "Параметры отчета",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SummaryRequest"
+                            "$ref": "#/definitions/model.BudgetReportRequest"
                         }
                     }
                 ],
@@ -129,22 +169,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.SummaryResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BudgetMonth"
+                            }
                         }
                     },
                     "400": {
                         "description": "Неверный запрос",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
             }
         },
-        "/subscriptions/{id}": {
-            "get": {
-                "description": "Возвращает информацию о подписке по её ID",
+        "/budgets/{id}": {
+            "delete": {
+                "description": "Удаляет бюджет по его ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -152,43 +194,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Получить подписку",
+                "summary": "Удалить бюджет",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID подписки",
+                        "description": "ID бюджета",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Subscription"
-                        }
+                    "204": {
+                        "description": "Бюджет удален"
                     },
                     "400": {
                         "description": "Неверный ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
                     "404": {
-                        "description": "Подписка не найдена",
+                        "description": "Бюджет не найден",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Обновляет информацию о существующей подписке",
+            }
+        },
+        "/charges": {
+            "get": {
+                "description": "Возвращает помесячные списания, сгенерированные из подписок, с возможностью фильтрации и постраничной выдачи.\nЕсли передан limit или cursor, ответ — объект {items, next_cursor}. Без них ответ — массив всех подходящих\nстрок (режим совместимости); для большого журнала используйте limit и cursor.",
                 "consumes": [
                     "application/json"
                 ],
@@ -196,125 +235,1630 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "charges"
                 ],
-                "summary": "Обновить подписку",
+                "summary": "Журнал списаний",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "ID подписки",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "subscription_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Обновленные данные",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UpdateSubscriptionRequest"
-                        }
+                        "type": "string",
+                        "description": "Название сервиса",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начальный месяц (MM-YYYY)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конечный месяц (MM-YYYY)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из next_cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Подписка обновлена",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ChargePage"
                         }
                     },
                     "400": {
                         "description": "Неверный запрос",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/charges/rebuild": {
+            "post": {
+                "description": "Пересоздает журнал списаний по всем подпискам и продлевает горизонт бессрочных подписок",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charges"
+                ],
+                "summary": "Пересоздать журнал списаний",
+                "responses": {
+                    "200": {
+                        "description": "Журнал пересоздан",
+                        "schema": {
+                            "$ref": "#/definitions/model.MessageResponse"
                         }
                     },
-                    "404": {
-                        "description": "Подписка не найдена",
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаляет подписку по её ID",
+            }
+        },
+        "/subscriptions": {
+            "get": {
+                "description": "Возвращает список подписок с возможностью фильтрации и постраничной выдачи.\nЕсли передан limit или cursor, ответ — объект {items, next_cursor}. Без них ответ — массив всех подходящих\nподписок (режим совместимости); для больших списков используйте limit и cursor.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
                     "subscriptions"
                 ],
-                "summary": "Удалить подписку",
+                "summary": "Список подписок",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID подписки",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название сервиса; можно передать несколько раз",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока названия сервиса или категории без учета регистра",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Месяц, в котором подписка действует (MM-YYYY)",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная цена",
+                        "name": "price_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная цена",
+                        "name": "price_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не раньше месяца (MM-YYYY)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не позже месяца (MM-YYYY)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание не раньше месяца (MM-YYYY)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание не позже месяца (MM-YYYY)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "ended",
+                            "future"
+                        ],
+                        "type": "string",
+                        "description": "Статус относительно текущего месяца",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поля сортировки через запятую, '-' — по убыванию: price, start_date, end_date, service_name, created_at (например -price,service_name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из next_cursor предыдущей страницы (действителен только при том же sort)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Выгрузка в файл (вместо Accept: text/csv или Accept: application/vnd.openxmlformats-officedocument.spreadsheetml.sheet); выгружаются все подходящие подписки, limit и cursor не учитываются. CSV отправляется потоком; XLSX собирается целиком и вмещает не больше 100000 подписок, иначе ответ 422 export_too_large",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Столбцы выгрузки через запятую: id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at, version",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "ru"
+                        ],
+                        "type": "string",
+                        "description": "Оформление выгрузки: en — разделитель ',', даты YYYY-MM-DD; ru — разделитель ';', даты DD.MM.YYYY (по умолчанию из Accept-Language)",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Подписка удалена"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SubscriptionPage"
+                        }
                     },
                     "400": {
-                        "description": "Неверный ID",
+                        "description": "Неверный запрос",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Подписка не найдена",
+                    "422": {
+                        "description": "Выгрузка XLSX не помещается в ограничение строк",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "model.CreateSubscriptionRequest": {
-            "type": "object",
-            "required": [
-                "price",
-                "service_name",
-                "start_date",
-                "user_id"
-            ],
-            "properties": {
-                "end_date": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "integer",
-                    "minimum": 1
-                },
-                "service_name": {
-                    "type": "string"
-                },
-                "start_date": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
+            },
+            "post": {
+                "description": "Создает новую запись о подписке пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создать подписку",
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить подписку, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом вернет сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateSubscriptionResponse"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true, если ответ взят из сохраненного по ключу идемпотентности"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка пересекается с существующей или запрос с этим ключом еще выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности уже использован с другим запросом или данные нарушают ограничения БД",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/batch": {
+            "post": {
+                "description": "Выполняет операции create/update/delete в одной транзакции. В режиме atomic (по умолчанию) ошибка любой операции\nотменяет весь пакет: ответ — application/problem+json со статусом и кодом ошибки этой операции, результаты\nвсех операций — в поле results. В режиме best_effort неудачные операции откатываются\nпо отдельности, остальные сохраняются; ответ 200 с результатом каждой операции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Пакетное изменение подписок",
+                "parameters": [
+                    {
+                        "description": "Операции",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранять подписки, несмотря на пересечения с существующими",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или неверные данные операции (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка операции не найдена (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка операции пересекается с существующей (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпала с version операции (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/export": {
+            "get": {
+                "description": "Отдает все подписки, подходящие под фильтры, по одному JSON-объекту на строку (application/x-ndjson).\nСтроки читаются из базы курсором и отправляются клиенту по мере чтения, не накапливаясь в памяти сервера.\nПодписки упорядочены по updated_at, затем по id. Для инкрементальной выгрузки передайте в updated_since\nнаибольший updated_at из предыдущей выгрузки: граница включается, поэтому записи на ней придут повторно.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Потоковая выгрузка подписок (NDJSON)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Только подписки, измененные в этот момент или позже (RFC 3339, например 2025-01-31T00:00:00Z)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название сервиса; можно передать несколько раз",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока названия сервиса или категории без учета регистра",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Месяц, в котором подписка действует (MM-YYYY)",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная цена",
+                        "name": "price_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная цена",
+                        "name": "price_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не раньше месяца (MM-YYYY)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не позже месяца (MM-YYYY)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание не раньше месяца (MM-YYYY)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание не позже месяца (MM-YYYY)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "ended",
+                            "future"
+                        ],
+                        "type": "string",
+                        "description": "Статус относительно текущего месяца",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписки, по одной на строку",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/import": {
+            "post": {
+                "description": "Принимает файл в поле file (multipart/form-data) или телом запроса (Content-Type: text/csv или application/json).\nCSV — с заголовком; столбцы сопоставляются с полями по имени: service_name, price, user_id, start_date, end_date, category,\nостальные столбцы пропускаются; разделитель (',', ';' или табуляция) определяется по заголовку. JSON — массив объектов,\nкак в POST /subscriptions. Каждая строка проверяется по правилам создания подписки; если хотя бы одна строка\nне прошла проверку, ничего не сохраняется (422). Строки сохраняются частями по 500 в отдельных транзакциях:\nпри ошибке сохраняются только предыдущие части, а resume_from в отчете указывает, с какой строки повторить импорт.",
+                "consumes": [
+                    "text/csv",
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Импорт подписок из CSV или JSON",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл CSV или JSON",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла, если его нельзя определить по Content-Type или расширению",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить строки, ничего не сохраняя",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер строки данных (с 1), с которой продолжить импорт; предыдущие строки пропускаются",
+                        "name": "resume_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранять подписки, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не удалось разобрать",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Строка пересекается с существующей подпиской; предыдущие части сохранены",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Неизвестный формат файла",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Строки не прошли проверку, ничего не сохранено",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/overlaps": {
+            "get": {
+                "description": "Возвращает все пары подписок одного пользователя на один сервис с пересекающимися периодами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Пересекающиеся подписки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SubscriptionOverlap"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/summary": {
+            "post": {
+                "description": "Рассчитывает суммарную стоимость подписок за период с фильтрацией. При source=ledger сумма считается по журналу списаний",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Сумма подписок",
+                "parameters": [
+                    {
+                        "description": "Параметры расчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SummaryRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Выгрузка в файл (вместо заголовка Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Столбцы выгрузки через запятую: start_date, end_date, user_id, service_name, source, total_amount, adjustments_amount, count",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "ru"
+                        ],
+                        "type": "string",
+                        "description": "Оформление выгрузки (по умолчанию из Accept-Language)",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SummaryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}": {
+            "get": {
+                "description": "Возвращает информацию о подписке по её ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия подписки для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью заменяет подписку: передаются все обязательные поля, не переданные end_date и category очищаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Заменить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить подписку, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки из GET; при несовпадении версии — 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/model.MessageResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия подписки"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка пересекается с существующей",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет подписку по её ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки из GET; при несовпадении версии — 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подписка удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Частично изменяет подписку по правилам JSON Merge Patch (RFC 7396): отсутствующие поля не меняются,\nnull очищает поле (например, \"end_date\": null делает подписку бессрочной). Результат проверяется так же, как при создании",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Изменить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SubscriptionPatchRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить подписку, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки из GET; при несовпадении версии — 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия подписки"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка пересекается с существующей",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/adjustments": {
+            "get": {
+                "description": "Возвращает возвраты, кредиты и разовые списания по подписке",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "adjustments"
+                ],
+                "summary": "Список корректировок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Adjustment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Записывает возврат (refund), кредит (credit) или разовое списание (charge) по подписке. Возвраты и кредиты уменьшают сумму в отчетах, разовые списания — увеличивают",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "adjustments"
+                ],
+                "summary": "Добавить корректировку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные корректировки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Adjustment"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.Adjustment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code — машиночитаемый код ошибки операции (как в поле code ответа application/problem+json)",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription": {
+                    "$ref": "#/definitions/model.Subscription"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.BatchOperationRequest": {
+            "type": "object",
+            "required": [
+                "op"
+            ],
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.UpdateSubscriptionRequest"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ]
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BatchRequest": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "mode": {
+                    "description": "Mode — режим выполнения, по умолчанию atomic",
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ]
+                },
+                "operations": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.BatchOperationRequest"
+                    }
+                }
+            }
+        },
+        "model.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "Committed — были ли сохранены изменения",
+                    "type": "boolean"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchItemResult"
+                    }
+                }
+            }
+        },
+        "model.Budget": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "monthly_limit": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BudgetMonth": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "integer"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "monthly_limit": {
+                    "type": "integer"
+                },
+                "over_budget": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.BudgetReportRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Charge": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChargePage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Charge"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "date",
+                "kind",
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "date": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "refund",
+                        "credit",
+                        "charge"
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "price",
+                "service_name",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version увеличивается при каждом изменении подписки и отдается в заголовке ETag",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.FieldViolation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "price"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be at least 1"
+                }
+            }
+        },
+        "model.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "resume_from": {
+                    "description": "ResumeFrom — номер строки, с которой нужно повторить импорт после ошибки (параметр resume_from)",
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportRowResult"
+                    }
+                },
+                "total": {
+                    "description": "Total — число строк данных в файле",
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code и Error — код и описание ошибки строки (как в ответе application/problem+json)",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors — ошибки разбора и проверки по полям строки",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldViolation"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "valid",
+                        "invalid",
+                        "created",
+                        "failed",
+                        "rolled_back",
+                        "skipped"
+                    ]
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "subscription updated"
+                }
+            }
+        },
+        "model.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "invalid_id",
+                        "invalid_parameter",
+                        "invalid_date_format",
+                        "malformed_body",
+                        "validation_failed",
+                        "unsupported_media_type",
+                        "service_name_required",
+                        "invalid_price",
+                        "user_id_required",
+                        "start_date_required",
+                        "invalid_end_date",
+                        "invalid_period",
+                        "invalid_limit",
+                        "invalid_cursor",
+                        "invalid_price_filter",
+                        "invalid_price_range",
+                        "invalid_start_range",
+                        "invalid_end_range",
+                        "invalid_status",
+                        "invalid_sort",
+                        "invalid_batch_operation",
+                        "search_query_required",
+                        "search_query_too_long",
+                        "payload_too_large",
+                        "export_too_large",
+                        "invalid_adjustment_kind",
+                        "invalid_adjustment_amount",
+                        "adjustment_date_required",
+                        "adjustment_reason_required",
+                        "invalid_budget_limit",
+                        "invalid_idempotency_key",
+                        "invalid_webhook_url",
+                        "webhook_url_not_allowed",
+                        "invalid_webhook_secret",
+                        "invalid_event_type",
+                        "subscription_not_found",
+                        "adjustment_not_found",
+                        "budget_not_found",
+                        "webhook_not_found",
+                        "webhook_delivery_not_found",
+                        "not_found",
+                        "subscription_overlap",
+                        "already_exists",
+                        "idempotency_request_in_progress",
+                        "precondition_failed",
+                        "idempotency_key_reused",
+                        "import_invalid",
+                        "constraint_violation",
+                        "internal_error"
+                    ]
+                },
+                "conflicts": {
+                    "description": "Conflicts — подписки, с которыми пересекается сохраняемая (для code=subscription_overlap)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "end date cannot be before start date"
+                },
+                "errors": {
+                    "description": "Errors — нарушения правил проверки по полям тела запроса (для code=validation_failed)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldViolation"
+                    }
+                },
+                "instance": {
+                    "description": "Instance — путь запроса, при обработке которого возникла ошибка",
+                    "type": "string",
+                    "example": "/api/v1/subscriptions"
+                },
+                "request_id": {
+                    "description": "RequestID — идентификатор запроса (заголовок X-Request-ID) для поиска в логах",
+                    "type": "string"
+                },
+                "results": {
+                    "description": "Results — результаты операций пакета, отмененного из-за ошибки одной из них (POST /subscriptions/batch)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchItemResult"
+                    }
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "description": "Type — URI типа ошибки, однозначно соответствует Code",
+                    "type": "string",
+                    "example": "urn:subscription-service:problem:invalid_end_date"
+                }
+            }
+        },
+        "model.SetBudgetRequest": {
+            "type": "object",
+            "required": [
+                "monthly_limit",
+                "user_id"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "monthly_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
         "model.Subscription": {
             "type": "object",
             "properties": {
+                "category": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -338,6 +1882,79 @@ const docTemplate = `{
                 },
                 "user_id": {
                     "type": "string"
+                },
+                "version": {
+                    "description": "Version увеличивается при каждом изменении подписки и отдается в заголовке ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SubscriptionOverlap": {
+            "type": "object",
+            "properties": {
+                "overlap_end": {
+                    "type": "string"
+                },
+                "overlap_start": {
+                    "type": "string"
+                },
+                "overlapping_id": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SubscriptionPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Subscription"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SubscriptionPatchRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "end_date": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "12-2025"
+                },
+                "price": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "service_name": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "start_date": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "07-2025"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "x-nullable": true
                 }
             }
         },
@@ -354,6 +1971,14 @@ const docTemplate = `{
                 "service_name": {
                     "type": "string"
                 },
+                "source": {
+                    "description": "Source — источник данных: \"subscriptions\" (по умолчанию) или \"ledger\" (журнал списаний)",
+                    "type": "string",
+                    "enum": [
+                        "subscriptions",
+                        "ledger"
+                    ]
+                },
                 "start_date": {
                     "type": "string"
                 },
@@ -365,6 +1990,10 @@ const docTemplate = `{
         "model.SummaryResponse": {
             "type": "object",
             "properties": {
+                "adjustments_amount": {
+                    "description": "AdjustmentsAmount — сумма возвратов, кредитов и разовых списаний за период (уже учтена в TotalAmount)",
+                    "type": "integer"
+                },
                 "count": {
                     "type": "integer"
                 },
@@ -375,18 +2004,31 @@ const docTemplate = `{
         },
         "model.UpdateSubscriptionRequest": {
             "type": "object",
+            "required": [
+                "price",
+                "service_name",
+                "start_date",
+                "user_id"
+            ],
             "properties": {
+                "category": {
+                    "type": "string"
+                },
                 "end_date": {
                     "type": "string"
                 },
                 "price": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 1
                 },
                 "service_name": {
                     "type": "string"
                 },
                 "start_date": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,1683 @@
+// Package v2 Code generated by swaggo/swag. DO NOT EDIT
+package v2
+
+import "github.com/swaggo/swag"
+
+const docTemplatev2 = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/subscriptions": {
+            "get": {
+                "description": "Возвращает страницу подписок с фильтрацией. Список всегда постраничный: курсор следующей страницы — pagination.next_cursor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Список подписок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название сервиса; можно передать несколько раз",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока названия сервиса или категории без учета регистра",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Месяц, в котором подписка действует (YYYY-MM)",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная цена",
+                        "name": "price_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная цена",
+                        "name": "price_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не раньше месяца (YYYY-MM)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не позже месяца (YYYY-MM)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание не раньше месяца (YYYY-MM)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание не позже месяца (YYYY-MM)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "ended",
+                            "future"
+                        ],
+                        "type": "string",
+                        "description": "Статус относительно текущего месяца",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подписки, измененные в этот момент или позже (RFC 3339)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поля сортировки через запятую, '-' — по убыванию: price, start_date, end_date, service_name, created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из pagination.next_cursor предыдущей страницы (действителен только при том же sort)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новую запись о подписке пользователя. Месяцы — в формате YYYY-MM",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создать подписку",
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить подписку, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом вернет сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия подписки для If-Match"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес созданной подписки"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка пересекается с существующей или запрос с этим ключом еще выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности уже использован с другим запросом или данные нарушают ограничения БД",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/events": {
+            "get": {
+                "description": "Поток text/event-stream: событие на каждое создание, изменение и удаление подписки. Поле event — тип события\n(subscription.created, subscription.updated, subscription.cancelled, subscription.deleted), id — номер события, data — SubscriptionEvent.\nПосле переподключения браузер передает Last-Event-ID, и сервер сначала отправляет пропущенные события из журнала\nпоследних изменений. Если их там уже нет, приходит событие reset: нужно заново загрузить список подписок.\nКаждые 15 секунд отправляется комментарий keep-alive.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Лента изменений подписок (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Только события подписок этого пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток событий",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/overlaps": {
+            "get": {
+                "description": "Возвращает все пары подписок одного пользователя на один сервис с пересекающимися периодами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Пересекающиеся подписки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.OverlapListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/search": {
+            "get": {
+                "description": "Нечеткий поиск по service_name без учета регистра: находит названия, похожие на запрос целиком\n(«Youtube premium» — «YouTube Premium», «Yotube» — «YouTube Premium») или содержащие похожее слово.\nСходство считается по триграммам (pg_trgm); результаты упорядочены по убыванию score.\nhighlights — фрагменты названия, похожие на слова запроса, для подсветки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Поиск подписок по названию сервиса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Искомое название сервиса (до 255 символов)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число результатов (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/service-names": {
+            "get": {
+                "description": "Возвращает различные названия сервисов, начинающиеся с prefix без учета регистра, — сначала те, у которых больше подписок.\nВарианты написания одного сервиса («YouTube Premium», «Youtube premium») возвращаются отдельно.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Автодополнение названий сервисов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало названия сервиса",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число названий (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.ServiceNameListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/summary": {
+            "post": {
+                "description": "Рассчитывает суммарную стоимость подписок за период с фильтрацией. При source=ledger сумма считается по журналу списаний",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Сумма подписок",
+                "parameters": [
+                    {
+                        "description": "Параметры расчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.SummaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SummaryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}": {
+            "get": {
+                "description": "Возвращает подписку по её ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия подписки для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью заменяет подписку: не переданные end_date и category очищаются. Ответ — подписка после изменения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Заменить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить подписку, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки из GET; при несовпадении версии — 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия подписки"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка пересекается с существующей",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет подписку по её ID",
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки из GET; при несовпадении версии — 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подписка удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Частично изменяет подписку по правилам JSON Merge Patch (RFC 7396): отсутствующие поля не меняются, null очищает поле",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Изменить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionPatch"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сохранить подписку, несмотря на пересечение с существующими",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки из GET; при несовпадении версии — 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SubscriptionResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия подписки"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка пересекается с существующей",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия подписки не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Возвращает все зарегистрированные вебхуки; ключи подписи не возвращаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Список вебхуков",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.WebhookListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Регистрирует адрес, на который POST-запросом отправляются события выбранных типов. Тело запроса — JSON\n{id, type, occurred_at, data}, где data — подписка (как в v1). Заголовок X-Webhook-Signature: t=\u003cunix-время\u003e,v1=\u003chex HMAC-SHA256 от \"\u003ct\u003e.\u003cтело\u003e\"\u003e\nс ключом secret. Успешная доставка — ответ 2xx; иначе попытка повторяется с экспоненциальной задержкой.\nURL — https-адрес в публичной сети: адреса loopback, частных и link-local сетей отклоняются (webhook_url_not_allowed).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Зарегистрировать вебхук",
+                "parameters": [
+                    {
+                        "description": "Адрес, ключ подписи и типы событий",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v2.WebhookResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес созданного вебхука"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "description": "Удаляет вебхук вместе с журналом доставок; недоставленные события не отправляются",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удалить вебхук",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Вебхук удален"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Возвращает последние 100 доставок вебхука, от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Доставки вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}": {
+            "get": {
+                "description": "Возвращает доставку вместе с журналом попыток: время, статус ответа, ошибка и длительность каждой попытки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Доставка вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID доставки",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.WebhookDeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Доставка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Возвращает доставку в очередь с новым набором попыток; первая попытка выполняется в течение нескольких секунд.\nПолучатель получит то же событие (тот же id) еще раз",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Повторить доставку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID доставки",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v2.WebhookDeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Доставка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code — машиночитаемый код ошибки операции (как в поле code ответа application/problem+json)",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription": {
+                    "$ref": "#/definitions/model.Subscription"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.FieldViolation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "price"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be at least 1"
+                }
+            }
+        },
+        "model.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "invalid_id",
+                        "invalid_parameter",
+                        "invalid_date_format",
+                        "malformed_body",
+                        "validation_failed",
+                        "unsupported_media_type",
+                        "service_name_required",
+                        "invalid_price",
+                        "user_id_required",
+                        "start_date_required",
+                        "invalid_end_date",
+                        "invalid_period",
+                        "invalid_limit",
+                        "invalid_cursor",
+                        "invalid_price_filter",
+                        "invalid_price_range",
+                        "invalid_start_range",
+                        "invalid_end_range",
+                        "invalid_status",
+                        "invalid_sort",
+                        "invalid_batch_operation",
+                        "search_query_required",
+                        "search_query_too_long",
+                        "payload_too_large",
+                        "export_too_large",
+                        "invalid_adjustment_kind",
+                        "invalid_adjustment_amount",
+                        "adjustment_date_required",
+                        "adjustment_reason_required",
+                        "invalid_budget_limit",
+                        "invalid_idempotency_key",
+                        "invalid_webhook_url",
+                        "webhook_url_not_allowed",
+                        "invalid_webhook_secret",
+                        "invalid_event_type",
+                        "subscription_not_found",
+                        "adjustment_not_found",
+                        "budget_not_found",
+                        "webhook_not_found",
+                        "webhook_delivery_not_found",
+                        "not_found",
+                        "subscription_overlap",
+                        "already_exists",
+                        "idempotency_request_in_progress",
+                        "precondition_failed",
+                        "idempotency_key_reused",
+                        "import_invalid",
+                        "constraint_violation",
+                        "internal_error"
+                    ]
+                },
+                "conflicts": {
+                    "description": "Conflicts — подписки, с которыми пересекается сохраняемая (для code=subscription_overlap)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "end date cannot be before start date"
+                },
+                "errors": {
+                    "description": "Errors — нарушения правил проверки по полям тела запроса (для code=validation_failed)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldViolation"
+                    }
+                },
+                "instance": {
+                    "description": "Instance — путь запроса, при обработке которого возникла ошибка",
+                    "type": "string",
+                    "example": "/api/v1/subscriptions"
+                },
+                "request_id": {
+                    "description": "RequestID — идентификатор запроса (заголовок X-Request-ID) для поиска в логах",
+                    "type": "string"
+                },
+                "results": {
+                    "description": "Results — результаты операций пакета, отмененного из-за ошибки одной из них (POST /subscriptions/batch)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchItemResult"
+                    }
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "description": "Type — URI типа ошибки, однозначно соответствует Code",
+                    "type": "string",
+                    "example": "urn:subscription-service:problem:invalid_end_date"
+                }
+            }
+        },
+        "model.Subscription": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version увеличивается при каждом изменении подписки и отдается в заголовке ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.Highlight": {
+            "type": "object",
+            "properties": {
+                "length": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "v2.Overlap": {
+            "type": "object",
+            "properties": {
+                "overlap_end": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-12"
+                },
+                "overlap_start": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "overlapping_id": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.OverlapListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Overlap"
+                    }
+                }
+            }
+        },
+        "v2.Pagination": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "x-nullable": true
+                }
+            }
+        },
+        "v2.ServiceName": {
+            "type": "object",
+            "properties": {
+                "service_name": {
+                    "type": "string",
+                    "example": "YouTube Premium"
+                },
+                "subscriptions": {
+                    "description": "Subscriptions — число подписок с этим названием",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "v2.ServiceNameListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ServiceName"
+                    }
+                }
+            }
+        },
+        "v2.Subscription": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-12"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v2.SubscriptionEvent": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1760000000000001
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "subscription": {
+                    "description": "Subscription — подписка после изменения; для удаления — её последнее состояние",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v2.Subscription"
+                        }
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "subscription.created",
+                        "subscription.updated",
+                        "subscription.cancelled",
+                        "subscription.deleted"
+                    ]
+                }
+            }
+        },
+        "v2.SubscriptionListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Subscription"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/v2.Pagination"
+                }
+            }
+        },
+        "v2.SubscriptionMatch": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "description": "Highlights — фрагменты service_name, похожие на слова запроса",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Highlight"
+                    }
+                },
+                "score": {
+                    "description": "Score — сходство названия сервиса с запросом от 0 до 1",
+                    "type": "number",
+                    "example": 0.83
+                },
+                "subscription": {
+                    "$ref": "#/definitions/v2.Subscription"
+                }
+            }
+        },
+        "v2.SubscriptionPatch": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "end_date": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-12"
+                },
+                "price": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "service_name": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "start_date": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-07"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "x-nullable": true
+                }
+            }
+        },
+        "v2.SubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "price",
+                "service_name",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-12"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 400
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.SubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v2.Subscription"
+                },
+                "warnings": {
+                    "description": "Warnings — предупреждения о сохранении подписки (например, при force=true)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v2.SubscriptionSearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.SubscriptionMatch"
+                    }
+                }
+            }
+        },
+        "v2.Summary": {
+            "type": "object",
+            "properties": {
+                "adjustments_amount": {
+                    "description": "AdjustmentsAmount — сумма возвратов, кредитов и разовых списаний за период (уже учтена в TotalAmount)",
+                    "type": "integer",
+                    "example": 0
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "end_date": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-12"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "total_amount": {
+                    "type": "integer",
+                    "example": 4800
+                }
+            }
+        },
+        "v2.SummaryRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-12"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source — источник данных: \"subscriptions\" (по умолчанию) или \"ledger\" (журнал списаний)",
+                    "type": "string",
+                    "enum": [
+                        "subscriptions",
+                        "ledger"
+                    ]
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.SummaryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v2.Summary"
+                }
+            }
+        },
+        "v2.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "subscription.created",
+                            "subscription.updated",
+                            "subscription.cancelled",
+                            "subscription.deleted"
+                        ]
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/subscriptions"
+                }
+            }
+        },
+        "v2.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "error": {
+                    "description": "Error — причина неудачи; null для успешной попытки",
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "unexpected response status 503"
+                },
+                "response_status": {
+                    "description": "ResponseStatus — HTTP-статус ответа получателя; null, если ответа не было",
+                    "type": "integer",
+                    "x-nullable": true,
+                    "example": 503
+                }
+            }
+        },
+        "v2.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempt_log": {
+                    "description": "AttemptLog — журнал попыток, от ранних к поздним; только в ответе с одной доставкой",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.WebhookAttempt"
+                    }
+                },
+                "attempts": {
+                    "description": "Attempts — число попыток с момента постановки в очередь или ручного повтора",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string",
+                    "enum": [
+                        "subscription.created",
+                        "subscription.updated",
+                        "subscription.cancelled",
+                        "subscription.deleted"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "payload": {
+                    "description": "Payload — тело запроса, которое получает вебхук",
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.WebhookDeliveryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.WebhookDelivery"
+                    }
+                }
+            }
+        },
+        "v2.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v2.WebhookDelivery"
+                }
+            }
+        },
+        "v2.WebhookListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Webhook"
+                    }
+                }
+            }
+        },
+        "v2.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "subscription.created",
+                            "subscription.updated",
+                            "subscription.cancelled",
+                            "subscription.deleted"
+                        ]
+                    }
+                },
+                "secret": {
+                    "description": "Secret — ключ подписи HMAC-SHA256, не короче 16 символов; в ответах не возвращается",
+                    "type": "string",
+                    "example": "5f2b8c0e9a4d4e71b3c6"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/subscriptions"
+                }
+            }
+        },
+        "v2.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v2.Webhook"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfov2 holds exported Swagger Info so clients can modify it
+var SwaggerInfov2 = &swag.Spec{
+	Version:          "2.0",
+	Host:             "localhost:8080",
+	BasePath:         "/api/v2",
+	Schemes:          []string{},
+	Title:            "Subscription Service API",
+	Description:      "REST API for managing user subscriptions, version 2",
+	InfoInstanceName: "v2",
+	SwaggerTemplate:  docTemplatev2,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfov2.InstanceName(), SwaggerInfov2)
+}
@@ -2,17 +2,25 @@
 package config
 
 import (
+	"github.com/ZnNr/subscription-service/internal/events"
+	"github.com/ZnNr/subscription-service/internal/webhook"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Logging  LoggingConfig  `yaml:"logging"`
+	Server      ServerConfig      `yaml:"server"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Logging     LoggingConfig     `yaml:"logging"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	API         APIConfig         `yaml:"api"`
+	Events      EventsConfig      `yaml:"events"`
+	Webhooks    WebhooksConfig    `yaml:"webhooks"`
 }
 
 type ServerConfig struct {
@@ -20,6 +28,11 @@ type ServerConfig struct {
 	Port string `yaml:"port"`
 }
 
+type GRPCConfig struct {
+	// Port — порт gRPC API; сервер слушает его на том же хосте, что и REST API
+	Port string `yaml:"port"`
+}
+
 type DatabaseConfig struct {
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
@@ -34,6 +47,40 @@ type LoggingConfig struct {
 	Format string `yaml:"format"`
 }
 
+type IdempotencyConfig struct {
+	// TTL — сколько хранится ответ на запрос с заголовком Idempotency-Key
+	TTL time.Duration `yaml:"ttl"`
+}
+
+type APIConfig struct {
+	// V1DeprecatedAt — дата, с которой /api/v1 считается устаревшим (заголовок Deprecation)
+	V1DeprecatedAt time.Time `yaml:"v1_deprecated_at"`
+	// V1Sunset — дата, после которой /api/v1 может быть отключен (заголовок Sunset)
+	V1Sunset time.Time `yaml:"v1_sunset"`
+	// ValidateResponses — проверять ответы по спецификации OpenAPI и записывать расхождения в лог.
+	// Предназначено для разработки и тестовых стендов: ответ буферизуется целиком
+	ValidateResponses bool `yaml:"validate_responses"`
+}
+
+type EventsConfig struct {
+	// LogSize — сколько последних изменений подписок хранится для продолжения ленты событий по Last-Event-ID
+	LogSize int `yaml:"log_size"`
+}
+
+type WebhooksConfig struct {
+	// MaxAttempts — сколько попыток доставки события делается, прежде чем доставка считается неудачной
+	MaxAttempts int `yaml:"max_attempts"`
+	// AllowInsecureTargets — разрешить вебхуки по http и на адреса локальной и частных сетей.
+	// Только для локальной разработки: в рабочем окружении должно быть выключено
+	AllowInsecureTargets bool `yaml:"allow_insecure_targets"`
+}
+
+// Даты вывода /api/v1 из эксплуатации по умолчанию
+var (
+	defaultV1DeprecatedAt = time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	defaultV1Sunset       = time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+)
+
 func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
 		return nil, err
@@ -63,6 +110,9 @@ func loadFromEnv() *Config {
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8080"),
 		},
+		GRPC: GRPCConfig{
+			Port: getEnv("GRPC_PORT", "9090"),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     port,
@@ -75,6 +125,21 @@ func loadFromEnv() *Config {
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Idempotency: IdempotencyConfig{
+			TTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		API: APIConfig{
+			V1DeprecatedAt:    getEnvDate("API_V1_DEPRECATED_AT", defaultV1DeprecatedAt),
+			V1Sunset:          getEnvDate("API_V1_SUNSET", defaultV1Sunset),
+			ValidateResponses: getEnvBool("API_VALIDATE_RESPONSES", false),
+		},
+		Events: EventsConfig{
+			LogSize: getEnvInt("EVENTS_LOG_SIZE", events.DefaultLogSize),
+		},
+		Webhooks: WebhooksConfig{
+			MaxAttempts:          getEnvInt("WEBHOOKS_MAX_ATTEMPTS", webhook.DefaultMaxAttempts),
+			AllowInsecureTargets: getEnvBool("WEBHOOKS_ALLOW_INSECURE_TARGETS", false),
+		},
 	}
 }
 
@@ -104,6 +169,40 @@ func overrideFromEnv(cfg *Config) {
 	if port := os.Getenv("SERVER_PORT"); port != "" {
 		cfg.Server.Port = port
 	}
+
+	cfg.GRPC.Port = getEnv("GRPC_PORT", cfg.GRPC.Port)
+	if cfg.GRPC.Port == "" {
+		cfg.GRPC.Port = "9090"
+	}
+
+	cfg.Idempotency.TTL = getEnvDuration("IDEMPOTENCY_TTL", cfg.Idempotency.TTL)
+	if cfg.Idempotency.TTL <= 0 {
+		cfg.Idempotency.TTL = 24 * time.Hour
+	}
+
+	cfg.API.V1DeprecatedAt = getEnvDate("API_V1_DEPRECATED_AT", cfg.API.V1DeprecatedAt)
+	if cfg.API.V1DeprecatedAt.IsZero() {
+		cfg.API.V1DeprecatedAt = defaultV1DeprecatedAt
+	}
+
+	cfg.API.V1Sunset = getEnvDate("API_V1_SUNSET", cfg.API.V1Sunset)
+	if cfg.API.V1Sunset.IsZero() {
+		cfg.API.V1Sunset = defaultV1Sunset
+	}
+
+	cfg.API.ValidateResponses = getEnvBool("API_VALIDATE_RESPONSES", cfg.API.ValidateResponses)
+
+	cfg.Events.LogSize = getEnvInt("EVENTS_LOG_SIZE", cfg.Events.LogSize)
+	if cfg.Events.LogSize <= 0 {
+		cfg.Events.LogSize = events.DefaultLogSize
+	}
+
+	cfg.Webhooks.MaxAttempts = getEnvInt("WEBHOOKS_MAX_ATTEMPTS", cfg.Webhooks.MaxAttempts)
+	if cfg.Webhooks.MaxAttempts <= 0 {
+		cfg.Webhooks.MaxAttempts = webhook.DefaultMaxAttempts
+	}
+
+	cfg.Webhooks.AllowInsecureTargets = getEnvBool("WEBHOOKS_ALLOW_INSECURE_TARGETS", cfg.Webhooks.AllowInsecureTargets)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -112,3 +211,40 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDate читает дату в формате YYYY-MM-DD
+func getEnvDate(key string, defaultValue time.Time) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if t, err := time.Parse(time.DateOnly, value); err == nil {
+			return t
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,164 @@
+// Package events — лента изменений подписок. Broker рассылает события подключенным
+// слушателям и хранит ограниченный журнал последних событий, по которому клиент после
+// переподключения получает пропущенные изменения (заголовок Last-Event-ID в SSE).
+//
+// Журнал хранится в памяти процесса: слушатели получают изменения, сделанные через этот
+// экземпляр сервиса, а после перезапуска журнал начинается заново.
+package events
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"sync"
+	"time"
+)
+
+// DefaultLogSize — сколько последних событий хранит журнал по умолчанию
+const DefaultLogSize = 1000
+
+// listenerBuffer — сколько событий может ждать отправки одному слушателю. Отстающий
+// слушатель отключается: после переподключения он дочитает пропущенное из журнала.
+const listenerBuffer = 64
+
+// Listener — подключение к ленте событий
+type Listener struct {
+	// Missed — события из журнала после запрошенного ID, которые нужно отправить первыми
+	Missed []model.SubscriptionEvent
+	// Reset — запрошенного события в журнале уже нет (или оно из предыдущего запуска):
+	// часть изменений потеряна, и клиенту нужно заново загрузить список
+	Reset bool
+	// LastID — ID последнего опубликованного события на момент подключения; с него
+	// продолжается лента после Reset
+	LastID int64
+	// Events — новые события; канал закрывается, когда слушатель отключен
+	Events <-chan model.SubscriptionEvent
+
+	events chan model.SubscriptionEvent
+}
+
+type Broker struct {
+	mu sync.Mutex
+	// log — кольцевой буфер журнала: count событий, начиная с индекса first
+	log       []model.SubscriptionEvent
+	first     int
+	count     int
+	lastID    int64
+	listeners map[*Listener]struct{}
+	closed    bool
+}
+
+// NewBroker создает ленту с журналом на logSize событий (DefaultLogSize, если logSize <= 0).
+// Нумерация событий начинается с текущего времени в микросекундах, поэтому ID после
+// перезапуска больше любого ID предыдущего запуска и не путаются с ним.
+func NewBroker(logSize int) *Broker {
+	if logSize <= 0 {
+		logSize = DefaultLogSize
+	}
+
+	return &Broker{
+		log:       make([]model.SubscriptionEvent, logSize),
+		lastID:    time.Now().UnixMicro(),
+		listeners: make(map[*Listener]struct{}),
+	}
+}
+
+// Publish присваивает событию ID и время, записывает его в журнал и рассылает слушателям
+func (b *Broker) Publish(event model.SubscriptionEvent) model.SubscriptionEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	if b.count < len(b.log) {
+		b.log[(b.first+b.count)%len(b.log)] = event
+		b.count++
+	} else {
+		b.log[b.first] = event
+		b.first = (b.first + 1) % len(b.log)
+	}
+
+	for l := range b.listeners {
+		select {
+		case l.events <- event:
+		default:
+			b.remove(l)
+		}
+	}
+
+	return event
+}
+
+// Subscribe подключает слушателя. Если lastEventID не nil, в Missed попадают события
+// журнала после него, а если продолжить с него нельзя — выставляется Reset.
+// Пропущенные и новые события идут без разрыва: подключение и выборка — под одной блокировкой.
+func (b *Broker) Subscribe(lastEventID *int64) *Listener {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan model.SubscriptionEvent, listenerBuffer)
+	l := &Listener{LastID: b.lastID, Events: events, events: events}
+
+	if lastEventID != nil {
+		l.Missed, l.Reset = b.since(*lastEventID)
+	}
+
+	if b.closed {
+		close(events)
+		return l
+	}
+
+	b.listeners[l] = struct{}{}
+	return l
+}
+
+// Unsubscribe отключает слушателя; повторный вызов ничего не делает
+func (b *Broker) Unsubscribe(l *Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(l)
+}
+
+// Close отключает всех слушателей, например при остановке сервера. Новые слушатели
+// подключаются уже отключенными, публикация продолжает пополнять журнал.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for l := range b.listeners {
+		b.remove(l)
+	}
+}
+
+// since возвращает события журнала с ID больше id; reset — событий между id и журналом уже нет
+func (b *Broker) since(id int64) ([]model.SubscriptionEvent, bool) {
+	if id == b.lastID {
+		return nil, false
+	}
+
+	if b.count == 0 || id > b.lastID || id < b.log[b.first].ID-1 {
+		return nil, true
+	}
+
+	// ID в журнале идут подряд, поэтому позиция события вычисляется без поиска
+	skip := int(id - b.log[b.first].ID + 1)
+	missed := make([]model.SubscriptionEvent, 0, b.count-skip)
+	for i := skip; i < b.count; i++ {
+		missed = append(missed, b.log[(b.first+i)%len(b.log)])
+	}
+
+	return missed, false
+}
+
+func (b *Broker) remove(l *Listener) {
+	if _, ok := b.listeners[l]; !ok {
+		return
+	}
+
+	delete(b.listeners, l)
+	close(l.events)
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishN(b *Broker, n int) []model.SubscriptionEvent {
+	published := make([]model.SubscriptionEvent, 0, n)
+	for range n {
+		published = append(published, b.Publish(model.SubscriptionEvent{
+			Type:         model.SubscriptionEventUpdated,
+			Subscription: &model.Subscription{ID: uuid.New()},
+		}))
+	}
+	return published
+}
+
+func TestBroker_DeliversNewEvents(t *testing.T) {
+	b := NewBroker(10)
+	publishN(b, 2)
+
+	l := b.Subscribe(nil)
+	assert.Empty(t, l.Missed)
+	assert.False(t, l.Reset)
+
+	published := publishN(b, 2)
+
+	require.Len(t, l.Events, 2)
+	assert.Equal(t, published[0], <-l.Events)
+	assert.Equal(t, published[1], <-l.Events)
+	assert.Equal(t, published[0].ID+1, published[1].ID)
+	assert.False(t, published[0].OccurredAt.IsZero())
+}
+
+func TestBroker_ResumesFromLastEventID(t *testing.T) {
+	b := NewBroker(10)
+	published := publishN(b, 5)
+
+	l := b.Subscribe(&published[1].ID)
+	assert.False(t, l.Reset)
+	assert.Equal(t, published[2:], l.Missed)
+
+	// Клиент получил все события — пропущенного нет
+	l = b.Subscribe(&published[4].ID)
+	assert.False(t, l.Reset)
+	assert.Empty(t, l.Missed)
+
+	// Событие непосредственно перед журналом: журнал отдается целиком
+	previous := published[0].ID - 1
+	l = b.Subscribe(&previous)
+	assert.False(t, l.Reset)
+	assert.Equal(t, published, l.Missed)
+}
+
+func TestBroker_ResetWhenEventLeftLog(t *testing.T) {
+	b := NewBroker(3)
+	published := publishN(b, 5)
+
+	// Журнал хранит три последних события, второе из него уже вытеснено
+	l := b.Subscribe(&published[0].ID)
+	assert.True(t, l.Reset)
+	assert.Empty(t, l.Missed)
+	assert.Equal(t, published[4].ID, l.LastID)
+
+	l = b.Subscribe(&published[1].ID)
+	assert.False(t, l.Reset)
+	assert.Equal(t, published[2:], l.Missed)
+
+	// ID из будущего или из предыдущего запуска продолжить нельзя
+	future := published[4].ID + 100
+	l = b.Subscribe(&future)
+	assert.True(t, l.Reset)
+
+	stale := int64(1)
+	l = b.Subscribe(&stale)
+	assert.True(t, l.Reset)
+
+	// После перезапуска журнал пуст
+	l = NewBroker(3).Subscribe(&published[4].ID)
+	assert.True(t, l.Reset)
+}
+
+func TestBroker_DropsSlowListener(t *testing.T) {
+	b := NewBroker(0)
+	l := b.Subscribe(nil)
+
+	publishN(b, listenerBuffer+1)
+
+	received := 0
+	for range l.Events {
+		received++
+	}
+	assert.Equal(t, listenerBuffer, received)
+}
+
+func TestBroker_UnsubscribeAndClose(t *testing.T) {
+	b := NewBroker(0)
+
+	l := b.Subscribe(nil)
+	b.Unsubscribe(l)
+	b.Unsubscribe(l)
+	_, ok := <-l.Events
+	assert.False(t, ok)
+
+	l = b.Subscribe(nil)
+	b.Close()
+	_, ok = <-l.Events
+	assert.False(t, ok)
+
+	// После Close слушатель подключается уже отключенным, но журнал доступен
+	published := publishN(b, 1)
+	before := published[0].ID - 1
+	l = b.Subscribe(&before)
+	assert.Equal(t, published, l.Missed)
+	_, ok = <-l.Events
+	assert.False(t, ok)
+}
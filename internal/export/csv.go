@@ -0,0 +1,121 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// utf8BOM — метка порядка байтов UTF-8
+const utf8BOM = "\xEF\xBB\xBF"
+
+// csvWriter пишет строки CSV; значения форматируются по локали
+type csvWriter struct {
+	out     io.Writer
+	writer  *csv.Writer
+	locale  Locale
+	header  []string
+	started bool
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, locale Locale, header []string) *csvWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = locale.CSVDelimiter
+
+	return &csvWriter{out: w, writer: writer, locale: locale, header: header, record: make([]string, len(header))}
+}
+
+func (w *csvWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if w.locale.CSVByteOrderMark {
+		if _, err := io.WriteString(w.out, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	return w.writer.Write(w.header)
+}
+
+func (w *csvWriter) WriteRow(values []any) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	for i, v := range values {
+		w.record[i] = w.format(v)
+	}
+
+	if err := w.writer.Write(w.record); err != nil {
+		return err
+	}
+
+	// Строки отправляются клиенту по мере записи, а не копятся в буфере
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return groupThousands(v, w.locale.ThousandsSeparator)
+	case time.Time:
+		return v.Format(w.locale.DateLayout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(w.locale.DateLayout)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// groupThousands записывает целое число с разделителем разрядов: 1234567 → "1,234,567"
+func groupThousands(n int, separator string) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	if separator == "" || len(digits) <= 3 {
+		return sign + digits
+	}
+
+	grouped := digits[:len(digits)%3]
+	for i := len(digits) % 3; i < len(digits); i += 3 {
+		if grouped != "" {
+			grouped += separator
+		}
+		grouped += digits[i : i+3]
+	}
+
+	return sign + grouped
+}
@@ -0,0 +1,214 @@
+// Package export выгружает табличные данные в CSV и XLSX построчно
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Format — формат выгрузки
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// MIME-типы форматов выгрузки
+const (
+	MIMETypeCSV  = "text/csv"
+	MIMETypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var (
+	ErrUnknownFormat = errors.New("format must be one of csv, xlsx")
+	ErrUnknownLocale = errors.New("locale must be one of en, ru")
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+// ParseFormat разбирает значение параметра format
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+
+	return "", ErrUnknownFormat
+}
+
+// FormatForMIMEType возвращает формат выгрузки для MIME-типа из заголовка Accept
+func FormatForMIMEType(mimeType string) (Format, bool) {
+	switch strings.ToLower(mimeType) {
+	case MIMETypeCSV:
+		return FormatCSV, true
+	case MIMETypeXLSX:
+		return FormatXLSX, true
+	}
+
+	return "", false
+}
+
+// ContentType — значение заголовка Content-Type для формата
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return MIMETypeXLSX
+	}
+
+	return MIMETypeCSV + "; charset=utf-8"
+}
+
+// Locale задает оформление чисел и дат в выгрузке
+type Locale struct {
+	// Lang — язык заголовков столбцов
+	Lang string
+	// CSVDelimiter — разделитель полей CSV; в русской локали Excel ожидает ";"
+	CSVDelimiter rune
+	// CSVByteOrderMark — писать BOM в начало CSV, чтобы Excel распознал UTF-8
+	CSVByteOrderMark bool
+	// DateLayout — формат дат в CSV (layout для time.Format)
+	DateLayout string
+	// ExcelDateFormat — числовой формат ячеек с датами в XLSX
+	ExcelDateFormat string
+	// ThousandsSeparator — разделитель разрядов целых чисел в CSV; в русской локали — неразрывный пробел
+	ThousandsSeparator string
+}
+
+var (
+	LocaleEN = Locale{
+		Lang:               "en",
+		CSVDelimiter:       ',',
+		DateLayout:         "2006-01-02",
+		ExcelDateFormat:    "yyyy-mm-dd",
+		ThousandsSeparator: ",",
+	}
+	LocaleRU = Locale{
+		Lang:               "ru",
+		CSVDelimiter:       ';',
+		CSVByteOrderMark:   true,
+		DateLayout:         "02.01.2006",
+		ExcelDateFormat:    "dd.mm.yyyy",
+		ThousandsSeparator: "\u00a0",
+	}
+)
+
+// ParseLocale разбирает название локали ("ru", "ru-RU", "en_US" и т. п.) по языку
+func ParseLocale(s string) (Locale, error) {
+	lang, _, _ := strings.Cut(strings.ToLower(s), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+
+	switch lang {
+	case LocaleEN.Lang:
+		return LocaleEN, nil
+	case LocaleRU.Lang:
+		return LocaleRU, nil
+	}
+
+	return Locale{}, ErrUnknownLocale
+}
+
+// Column — столбец выгрузки значений типа T
+type Column[T any] struct {
+	Key string
+	// Titles — заголовок столбца по языку локали; если заголовка нет, используется Key
+	Titles map[string]string
+	// Value возвращает значение ячейки: string, *string, int, uuid.UUID, time.Time или *time.Time
+	Value func(item T) any
+}
+
+// Title — заголовок столбца для локали
+func (c Column[T]) Title(locale Locale) string {
+	if title, ok := c.Titles[locale.Lang]; ok {
+		return title
+	}
+
+	return c.Key
+}
+
+// SelectColumns выбирает столбцы по ключам через запятую в заданном порядке.
+// Пустая строка выбирает столбцы defaults.
+func SelectColumns[T any](all []Column[T], keys string, defaults []string) ([]Column[T], error) {
+	selected := defaults
+	if keys != "" {
+		selected = strings.Split(keys, ",")
+	}
+
+	columns := make([]Column[T], 0, len(selected))
+	for _, key := range selected {
+		key = strings.TrimSpace(key)
+		i := slices.IndexFunc(all, func(c Column[T]) bool { return c.Key == key })
+		if i < 0 {
+			return nil, fmt.Errorf("%w %q", ErrUnknownColumn, key)
+		}
+		columns = append(columns, all[i])
+	}
+
+	return columns, nil
+}
+
+// ColumnKeys — ключи всех столбцов, для сообщений об ошибках и документации
+func ColumnKeys[T any](columns []Column[T]) []string {
+	keys := make([]string, 0, len(columns))
+	for _, c := range columns {
+		keys = append(keys, c.Key)
+	}
+
+	return keys
+}
+
+// rowWriter записывает строки таблицы в выбранном формате
+type rowWriter interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+// Table записывает значения типа T строками: по ячейке на каждый столбец.
+// Строка заголовка записывается вместе с первой строкой данных или при Close,
+// поэтому до первой строки в выходной поток ничего не пишется.
+type Table[T any] struct {
+	columns []Column[T]
+	writer  rowWriter
+	values  []any
+}
+
+// NewTable создает таблицу, которая пишет в w
+func NewTable[T any](w io.Writer, format Format, locale Locale, columns []Column[T]) (*Table[T], error) {
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.Title(locale))
+	}
+
+	var writer rowWriter
+	switch format {
+	case FormatCSV:
+		writer = newCSVWriter(w, locale, header)
+	case FormatXLSX:
+		var err error
+		if writer, err = newXLSXWriter(w, locale, header); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	return &Table[T]{columns: columns, writer: writer, values: make([]any, len(columns))}, nil
+}
+
+// Write записывает строку со значениями столбцов для item
+func (t *Table[T]) Write(item T) error {
+	for i, c := range t.columns {
+		t.values[i] = c.Value(item)
+	}
+
+	return t.writer.WriteRow(t.values)
+}
+
+// Close дописывает выгрузку; для XLSX файл целиком пишется в выходной поток здесь,
+// поэтому в XLSX выгружается не больше MaxXLSXRows строк
+func (t *Table[T]) Close() error {
+	return t.writer.Close()
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type testRow struct {
+	Name  string
+	Price int
+	Start time.Time
+	End   *time.Time
+	Note  *string
+	ID    uuid.UUID
+}
+
+var testColumns = []Column[*testRow]{
+	{Key: "name", Titles: map[string]string{"en": "Name", "ru": "Название"}, Value: func(r *testRow) any { return r.Name }},
+	{Key: "price", Value: func(r *testRow) any { return r.Price }},
+	{Key: "start", Value: func(r *testRow) any { return r.Start }},
+	{Key: "end", Value: func(r *testRow) any { return r.End }},
+	{Key: "note", Value: func(r *testRow) any { return r.Note }},
+	{Key: "id", Value: func(r *testRow) any { return r.ID }},
+}
+
+func testRows() []*testRow {
+	end := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	note := "annual"
+	return []*testRow{
+		{Name: "Netflix", Price: 1599, Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), End: &end, Note: &note, ID: uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")},
+		{Name: "Spotify, Premium", Price: 299, Start: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), ID: uuid.MustParse("70601fee-2bf1-4721-ae6f-7636e79a0cba")},
+	}
+}
+
+func writeTable(t *testing.T, format Format, locale Locale, columns []Column[*testRow], rows []*testRow) []byte {
+	var buf bytes.Buffer
+	table, err := NewTable(&buf, format, locale, columns)
+	require.NoError(t, err)
+
+	for _, row := range rows {
+		require.NoError(t, table.Write(row))
+	}
+	require.NoError(t, table.Close())
+
+	return buf.Bytes()
+}
+
+func TestCSV_LocaleEN(t *testing.T) {
+	out := writeTable(t, FormatCSV, LocaleEN, testColumns, testRows())
+
+	assert.Equal(t, "Name,price,start,end,note,id\n"+
+		"Netflix,\"1,599\",2025-01-01,2025-12-01,annual,60601fee-2bf1-4721-ae6f-7636e79a0cba\n"+
+		"\"Spotify, Premium\",299,2025-02-01,,,70601fee-2bf1-4721-ae6f-7636e79a0cba\n", string(out))
+}
+
+func TestCSV_LocaleRU(t *testing.T) {
+	columns, err := SelectColumns(testColumns, "name,price,start", nil)
+	require.NoError(t, err)
+
+	out := writeTable(t, FormatCSV, LocaleRU, columns, testRows()[:1])
+
+	assert.Equal(t, "\xEF\xBB\xBFНазвание;price;start\nNetflix;1\u00a0599;01.01.2025\n", string(out))
+}
+
+func TestCSV_NothingWrittenBeforeFirstRow(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewTable(&buf, FormatCSV, LocaleEN, testColumns)
+
+	require.NoError(t, err)
+	assert.Zero(t, buf.Len())
+}
+
+func TestXLSX_TypedCells(t *testing.T) {
+	out := writeTable(t, FormatXLSX, LocaleRU, testColumns, testRows())
+
+	file, err := excelize.OpenReader(bytes.NewReader(out))
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows(xlsxSheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"Название", "price", "start", "end", "note", "id"}, rows[0])
+	assert.Equal(t, "01.01.2025", rows[1][2])
+
+	priceType, err := file.GetCellType(xlsxSheet, "B2")
+	require.NoError(t, err)
+	// Числа записываются числовыми ячейками, а не строками
+	assert.NotContains(t, []excelize.CellType{excelize.CellTypeSharedString, excelize.CellTypeInlineString}, priceType)
+
+	price, err := file.GetCellValue(xlsxSheet, "B2", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1599", price)
+
+	// Дата хранится числом Excel с форматом даты
+	start, err := file.GetCellValue(xlsxSheet, "C2", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, "45658", start)
+}
+
+func TestXLSX_RowLimit(t *testing.T) {
+	writer, err := newXLSXWriter(&bytes.Buffer{}, LocaleEN, []string{"name"})
+	require.NoError(t, err)
+	defer writer.file.Close()
+	writer.maxRows = 2
+
+	require.NoError(t, writer.WriteRow([]any{"a"}))
+	require.NoError(t, writer.WriteRow([]any{"b"}))
+	assert.ErrorIs(t, writer.WriteRow([]any{"c"}), ErrTooManyRows)
+}
+
+func TestSelectColumns(t *testing.T) {
+	columns, err := SelectColumns(testColumns, "", []string{"price", "name"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"price", "name"}, ColumnKeys(columns))
+
+	_, err = SelectColumns(testColumns, "name,secret", nil)
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+}
+
+func TestParseLocale(t *testing.T) {
+	locale, err := ParseLocale("ru-RU")
+	require.NoError(t, err)
+	assert.Equal(t, LocaleRU, locale)
+
+	_, err = ParseLocale("de")
+	assert.ErrorIs(t, err, ErrUnknownLocale)
+}
+
+func TestGroupThousands(t *testing.T) {
+	assert.Equal(t, "999", groupThousands(999, ","))
+	assert.Equal(t, "1,234,567", groupThousands(1234567, ","))
+	assert.Equal(t, "-12,345", groupThousands(-12345, ","))
+	assert.Equal(t, "123456", groupThousands(123456, ""))
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxSheet — имя единственного листа выгрузки
+const xlsxSheet = "Sheet1"
+
+// MaxXLSXRows — наибольшее число строк данных в выгрузке XLSX. Файл собирается целиком
+// и отправляется только при Close, поэтому размер выгрузки ограничен.
+const MaxXLSXRows = 100_000
+
+// ErrTooManyRows — выгрузка не помещается в ограничение строк XLSX
+var ErrTooManyRows = errors.New("export exceeds the XLSX row limit")
+
+// xlsxWriter пишет строки на лист XLSX через потоковую запись excelize: строки не копятся
+// в памяти, но файл целиком отправляется в выходной поток только при Close
+type xlsxWriter struct {
+	out     io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	header  []string
+	row     int
+	maxRows int
+	cells   []any
+
+	headerStyle int
+	intStyle    int
+	dateStyle   int
+}
+
+func newXLSXWriter(w io.Writer, locale Locale, header []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Встроенный формат 3 — "#,##0": разделитель разрядов берется из настроек Excel пользователя
+	intStyle, err := file.NewStyle(&excelize.Style{NumFmt: 3})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	dateFormat := locale.ExcelDateFormat
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &xlsxWriter{
+		out:         w,
+		file:        file,
+		stream:      stream,
+		header:      header,
+		maxRows:     MaxXLSXRows,
+		cells:       make([]any, len(header)),
+		headerStyle: headerStyle,
+		intStyle:    intStyle,
+		dateStyle:   dateStyle,
+	}, nil
+}
+
+func (w *xlsxWriter) start() error {
+	if w.row > 0 {
+		return nil
+	}
+
+	for i, title := range w.header {
+		w.cells[i] = excelize.Cell{StyleID: w.headerStyle, Value: title}
+	}
+
+	return w.writeCells()
+}
+
+func (w *xlsxWriter) writeCells() error {
+	w.row++
+
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	return w.stream.SetRow(cell, w.cells)
+}
+
+func (w *xlsxWriter) WriteRow(values []any) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	// Первая строка листа — заголовок
+	if w.row > w.maxRows {
+		return fmt.Errorf("%w: at most %d rows are allowed, use CSV for larger exports", ErrTooManyRows, w.maxRows)
+	}
+
+	for i, v := range values {
+		w.cells[i] = w.cell(v)
+	}
+
+	return w.writeCells()
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.Write(w.out)
+}
+
+// cell — типизированная ячейка: числа и даты записываются как числа Excel со своим форматом
+func (w *xlsxWriter) cell(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case int:
+		return excelize.Cell{StyleID: w.intStyle, Value: v}
+	case time.Time:
+		return excelize.Cell{StyleID: w.dateStyle, Value: v}
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return excelize.Cell{StyleID: w.dateStyle, Value: *v}
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
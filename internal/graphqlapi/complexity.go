@@ -0,0 +1,103 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// listSizeEstimate — оценка числа элементов в списке без аргумента first
+const listSizeEstimate = 10
+
+// complexity — стоимость операции: каждое поле стоит 1, а стоимость вложенных полей
+// умножается на размер списка — значение first (по умолчанию размер страницы) или
+// listSizeEstimate. Списки items внутри *Connection уже учтены в first и не умножаются.
+// Служебные поля интроспекции не учитываются.
+type complexity struct {
+	schema *ast.Schema
+	limit  int
+}
+
+// operation разбирает и проверяет запрос по схеме и возвращает выбранную операцию и её стоимость.
+// Запрос, который не удалось разобрать или в котором нет выбранной операции, не выполняется:
+// иначе его стоимость осталась бы непроверенной.
+func (c *complexity) operation(query, operationName string, vars map[string]any) (*ast.OperationDefinition, int, error) {
+	doc, errs := gqlparser.LoadQuery(c.schema, query)
+	if len(errs) > 0 {
+		return nil, 0, &queryError{code: codeInvalidQuery, message: errs[0].Message}
+	}
+
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		if operationName == "" {
+			return nil, 0, &queryError{code: codeInvalidQuery, message: "operationName is required for a document with several operations"}
+		}
+		return nil, 0, &queryError{code: codeInvalidQuery, message: fmt.Sprintf("operation %q not found", operationName)}
+	}
+
+	return op, c.selectionCost(op.SelectionSet, vars), nil
+}
+
+func (c *complexity) selectionCost(set ast.SelectionSet, vars map[string]any) int {
+	total := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			total += c.fieldCost(sel, vars)
+		case *ast.InlineFragment:
+			total += c.selectionCost(sel.SelectionSet, vars)
+		case *ast.FragmentSpread:
+			total += c.selectionCost(sel.Definition.SelectionSet, vars)
+		}
+
+		// Дальше считать незачем; ограничение не дает переполнить int на глубоких запросах
+		if total > c.limit {
+			return c.limit + 1
+		}
+	}
+
+	return total
+}
+
+func (c *complexity) fieldCost(field *ast.Field, vars map[string]any) int {
+	if strings.HasPrefix(field.Name, "__") {
+		return 0
+	}
+
+	return 1 + multiplier(field, vars)*c.selectionCost(field.SelectionSet, vars)
+}
+
+// multiplier — ожидаемое число элементов, которое вернет поле
+func multiplier(field *ast.Field, vars map[string]any) int {
+	if field.Definition == nil {
+		return 1
+	}
+
+	if field.Definition.Arguments.ForName("first") != nil {
+		switch first := field.ArgumentMap(vars)["first"].(type) {
+		case int64:
+			return clampPageSize(int(first))
+		case float64:
+			return clampPageSize(int(first))
+		}
+		return service.DefaultPageSize
+	}
+
+	if field.Definition.Type.Elem != nil && !strings.HasSuffix(field.ObjectDefinition.Name, "Connection") {
+		return listSizeEstimate
+	}
+
+	return 1
+}
+
+// clampPageSize — размер страницы, который вернет сервис для first
+func clampPageSize(first int) int {
+	if first < 1 {
+		return 1
+	}
+
+	return min(first, service.MaxPageSize)
+}
@@ -0,0 +1,71 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/service"
+)
+
+// Коды ошибок, которые выявляются при разборе запроса, до вызова сервиса (те же, что в REST API)
+const (
+	codeInvalidID         = "invalid_id"
+	codeInvalidParameter  = "invalid_parameter"
+	codeInvalidDateFormat = "invalid_date_format"
+	codeMalformedBody     = "malformed_body"
+	codeQueryTooComplex   = "query_too_complex"
+	codeInvalidQuery      = "invalid_query"
+)
+
+// queryError — ошибка GraphQL с машиночитаемым кодом в extensions.code
+type queryError struct {
+	code       string
+	message    string
+	extensions map[string]any
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	for k, v := range e.extensions {
+		extensions[k] = v
+	}
+
+	return extensions
+}
+
+// invalidArgument — ошибка разбора аргумента запроса
+func invalidArgument(code, format string, args ...any) error {
+	return &queryError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// fail переводит ошибку сервиса в ошибку GraphQL с кодом по её описанию. Текст внутренних
+// ошибок и подробности ошибок репозитория клиенту не передаются: они попадают в лог запроса.
+// При пересечении подписок extensions.conflicts содержит идентификаторы конфликтующих подписок.
+func fail(ctx context.Context, err error) error {
+	var qe *queryError
+	if errors.As(err, &qe) {
+		return qe
+	}
+
+	described := service.Describe(err)
+	if service.Concealed(err) {
+		stateFrom(ctx).recordError(err)
+	}
+
+	result := &queryError{code: described.Code, message: described.Message}
+
+	var overlapErr *service.OverlapError
+	if errors.As(err, &overlapErr) {
+		conflicts := make([]string, 0, len(overlapErr.Overlaps))
+		for _, o := range overlapErr.Overlaps {
+			conflicts = append(conflicts, o.ID.String())
+		}
+		result.extensions = map[string]any{"conflicts": conflicts}
+	}
+
+	return result
+}
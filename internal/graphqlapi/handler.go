@@ -0,0 +1,110 @@
+package graphqlapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Ограничения запросов: глубина вложенности полей и стоимость (см. complexity)
+const (
+	maxDepth      = 15
+	maxComplexity = 5000
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Handler обслуживает запросы GraphQL поверх того же service.Service, что и REST API
+type Handler struct {
+	service    service.Service
+	schema     *graphql.Schema
+	complexity *complexity
+}
+
+// NewHandler разбирает схему и готовит резолверы; ошибка в схеме — ошибка сборки, поэтому паника
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{
+		service: svc,
+		schema: graphql.MustParseSchema(schemaSDL, &Resolver{service: svc},
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxDepth),
+		),
+		complexity: &complexity{
+			schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSDL}),
+			limit:  maxComplexity,
+		},
+	}
+}
+
+// request — тело запроса GraphQL
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handle выполняет запрос GraphQL: POST с JSON-телом или GET с параметрами query,
+// operationName и variables (только чтение). Ошибки полей возвращаются в errors
+// вместе с остальными данными ответа.
+func (h *Handler) Handle(c *gin.Context) {
+	var req request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				respondErrors(c, http.StatusBadRequest, codeMalformedBody, "variables must be a JSON object")
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrors(c, http.StatusBadRequest, codeMalformedBody, "request body must be a JSON object with query")
+		return
+	}
+
+	if req.Query == "" {
+		respondErrors(c, http.StatusBadRequest, codeMalformedBody, "query is required")
+		return
+	}
+
+	op, cost, err := h.complexity.operation(req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		respondErrors(c, http.StatusBadRequest, codeInvalidQuery, err.Error())
+		return
+	}
+	if op.Operation != ast.Query && c.Request.Method == http.MethodGet {
+		c.Header("Allow", http.MethodPost)
+		respondErrors(c, http.StatusMethodNotAllowed, codeInvalidParameter, "mutations are only allowed in POST requests")
+		return
+	}
+	if cost > h.complexity.limit {
+		respondErrors(c, http.StatusBadRequest, codeQueryTooComplex, "query complexity exceeds the limit")
+		return
+	}
+
+	state := newRequestState(h.service)
+	resp := h.schema.Exec(withState(c.Request.Context(), state), req.Query, req.OperationName, req.Variables)
+
+	// Внутренние ошибки и подробности ошибок репозитория не отдаются клиенту, а попадают в лог запроса
+	for _, err := range state.errors {
+		_ = c.Error(err)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// respondErrors отвечает ошибкой всего запроса в формате GraphQL
+func respondErrors(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, &graphql.Response{Errors: []*gqlerrors.QueryError{{
+		Message:    message,
+		Extensions: map[string]any{"code": code},
+	}}})
+}
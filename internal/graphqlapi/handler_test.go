@@ -0,0 +1,323 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubService реализует только те методы service.Service, которые вызывают тесты
+type stubService struct {
+	service.Service
+	mock.Mock
+}
+
+func (m *stubService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*model.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *stubService) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	args := m.Called(ctx, filter)
+	page, _ := args.Get(0).(*model.SubscriptionPage)
+	return page, args.Error(1)
+}
+
+func (m *stubService) ExportCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error {
+	args := m.Called(ctx, filter)
+	charges, _ := args.Get(0).([]*model.Charge)
+	for _, charge := range charges {
+		if err := fn(charge); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *stubService) ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error) {
+	args := m.Called(ctx)
+	overlaps, _ := args.Get(0).([]*model.SubscriptionOverlap)
+	return overlaps, args.Error(1)
+}
+
+func (m *stubService) CreateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) (*model.CreateSubscriptionResponse, error) {
+	args := m.Called(ctx, sub, opts)
+	resp, _ := args.Get(0).(*model.CreateSubscriptionResponse)
+	return resp, args.Error(1)
+}
+
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func setupRouter(svc service.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewHandler(svc)
+	router.GET("/graphql", h.Handle)
+	router.POST("/graphql", h.Handle)
+	return router
+}
+
+func postQuery(t *testing.T, router *gin.Engine, query string, variables map[string]any) (*httptest.ResponseRecorder, gqlResponse) {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var resp gqlResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w, resp
+}
+
+func newSubscription(userID uuid.UUID, serviceName string) *model.Subscription {
+	return &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: serviceName,
+		Price:       400,
+		UserID:      userID,
+		StartDate:   time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Version:     1,
+	}
+}
+
+func TestHandle_Subscription(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	sub := newSubscription(uuid.New(), "Netflix")
+	svc.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+
+	w, resp := postQuery(t, router, `query($id: ID!) { subscription(id: $id) { id serviceName price startDate endDate } }`,
+		map[string]any{"id": sub.ID.String()})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"subscription":{"id":"`+sub.ID.String()+`","serviceName":"Netflix","price":400,"startDate":"01-2025","endDate":null}}`, string(resp.Data))
+}
+
+func TestHandle_SubscriptionNotFound(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	id := uuid.New()
+	svc.On("GetSubscription", mock.Anything, id).Return(nil, repository.ErrNotFound)
+
+	_, resp := postQuery(t, router, `query($id: ID!) { subscription(id: $id) { id } }`, map[string]any{"id": id.String()})
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"subscription":null}`, string(resp.Data))
+}
+
+func TestHandle_ChargesBatched(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	userID := uuid.New()
+	first := newSubscription(userID, "Netflix")
+	second := newSubscription(userID, "Spotify")
+	svc.On("ListSubscriptions", mock.Anything, mock.Anything).
+		Return(&model.SubscriptionPage{Items: []*model.Subscription{first, second}}, nil)
+
+	// Журналы обеих подписок читаются одним запросом
+	svc.On("ExportCharges", mock.Anything, mock.MatchedBy(func(f model.ChargeFilter) bool {
+		return assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, f.SubscriptionIDs)
+	})).Return([]*model.Charge{
+		{ID: uuid.New(), SubscriptionID: first.ID, UserID: userID, Month: first.StartDate, Amount: 400, Kind: "charge"},
+		{ID: uuid.New(), SubscriptionID: second.ID, UserID: userID, Month: second.StartDate, Amount: 300, Kind: "charge"},
+	}, nil).Once()
+
+	_, resp := postQuery(t, router, `{ subscriptions { items { serviceName charges { amount kind } } } }`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"subscriptions":{"items":[
+		{"serviceName":"Netflix","charges":[{"amount":400,"kind":"CHARGE"}]},
+		{"serviceName":"Spotify","charges":[{"amount":300,"kind":"CHARGE"}]}
+	]}}`, string(resp.Data))
+	svc.AssertNumberOfCalls(t, "ExportCharges", 1)
+}
+
+func TestHandle_OverlapSubscriptionsBatched(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	userID := uuid.New()
+	first := newSubscription(userID, "Netflix")
+	second := newSubscription(userID, "Netflix")
+	svc.On("ListOverlaps", mock.Anything).Return([]*model.SubscriptionOverlap{{
+		UserID:         userID,
+		ServiceName:    "Netflix",
+		SubscriptionID: first.ID,
+		OverlappingID:  second.ID,
+		OverlapStart:   first.StartDate,
+	}}, nil)
+	svc.On("ListSubscriptions", mock.Anything, mock.MatchedBy(func(f model.SubscriptionFilter) bool {
+		return len(f.IDs) == 2 && f.Limit == 2
+	})).Return(&model.SubscriptionPage{Items: []*model.Subscription{second, first}}, nil).Once()
+
+	_, resp := postQuery(t, router, `{ overlaps { overlapStart subscription { id } overlapping { id } } }`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"overlaps":[{"overlapStart":"01-2025","subscription":{"id":"`+first.ID.String()+
+		`"},"overlapping":{"id":"`+second.ID.String()+`"}}]}`, string(resp.Data))
+	svc.AssertNumberOfCalls(t, "ListSubscriptions", 1)
+}
+
+func TestHandle_ErrorCodes(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	_, resp := postQuery(t, router, `{ subscription(id: "not-a-uuid") { id } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codeInvalidID, resp.Errors[0].Extensions["code"])
+
+	_, resp = postQuery(t, router, `{ summary(input: {startDate: "2025-01", endDate: "02-2025"}) { totalAmount } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codeInvalidDateFormat, resp.Errors[0].Extensions["code"])
+}
+
+func TestHandle_CreateSubscriptionConflict(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	existing := newSubscription(uuid.New(), "Netflix")
+	svc.On("CreateSubscription", mock.Anything, mock.Anything, model.WriteOptions{}).
+		Return(nil, &service.OverlapError{Overlaps: []*model.Subscription{existing}})
+
+	_, resp := postQuery(t, router, `mutation($input: SubscriptionInput!) { createSubscription(input: $input) { subscription { id } } }`,
+		map[string]any{"input": map[string]any{
+			"serviceName": "Netflix",
+			"price":       400,
+			"userId":      existing.UserID.String(),
+			"startDate":   "03-2025",
+		}})
+
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, service.ErrSubscriptionOverlap.Code, resp.Errors[0].Extensions["code"])
+	assert.Equal(t, []any{existing.ID.String()}, resp.Errors[0].Extensions["conflicts"])
+}
+
+func TestHandle_CreateSubscription(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	userID := uuid.New()
+	created := newSubscription(userID, "Netflix")
+	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	created.EndDate = &endDate
+	svc.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(sub *model.Subscription) bool {
+		return sub.ServiceName == "Netflix" && sub.UserID == userID && sub.EndDate.Equal(endDate)
+	}), model.WriteOptions{Force: true}).
+		Return(&model.CreateSubscriptionResponse{Subscription: created}, nil)
+
+	_, resp := postQuery(t, router, `mutation($input: SubscriptionInput!) {
+		createSubscription(input: $input, force: true) { subscription { serviceName endDate } warnings }
+	}`, map[string]any{"input": map[string]any{
+		"serviceName": "Netflix",
+		"price":       400,
+		"userId":      userID.String(),
+		"startDate":   "03-2025",
+		"endDate":     "12-2025",
+	}})
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"createSubscription":{"subscription":{"serviceName":"Netflix","endDate":"12-2025"},"warnings":[]}}`, string(resp.Data))
+}
+
+func TestHandle_MutationOverGET(t *testing.T) {
+	router := setupRouter(new(stubService))
+
+	query := url.Values{"query": {`mutation { deleteBudget(id: "` + uuid.NewString() + `") }`}}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandle_QueryTooComplex(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	// 500 подписок × 10 пользователей × (1 + 50 подписок × 2 поля)
+	w, resp := postQuery(t, router, `{
+		subscriptions(first: 500) { items { id user { subscriptions { items { id serviceName } } } } }
+	}`, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codeQueryTooComplex, resp.Errors[0].Extensions["code"])
+	svc.AssertNotCalled(t, "ListSubscriptions", mock.Anything, mock.Anything)
+}
+
+func TestHandle_InvalidQueryRejected(t *testing.T) {
+	tests := []struct {
+		name, query, operationName string
+	}{
+		{name: "syntax error", query: `{ subscriptions(first: 500) { items { id }`},
+		{name: "unknown field", query: `{ subscriptions(first: 500) { items { id unknownField } } }`},
+		{name: "unknown operation", query: `query A { subscriptions { items { id } } }`, operationName: "B"},
+		{name: "ambiguous operation", query: `query A { subscriptions { items { id } } } query B { subscriptions { items { id } } }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(stubService)
+			router := setupRouter(svc)
+
+			body, err := json.Marshal(map[string]any{"query": tt.query, "operationName": tt.operationName})
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), codeInvalidQuery)
+			svc.AssertNotCalled(t, "ListSubscriptions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandle_Introspection(t *testing.T) {
+	router := setupRouter(new(stubService))
+
+	w, resp := postQuery(t, router, `{ __schema { queryType { name } types { name fields { name type { name } } } } }`, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, resp.Errors)
+	assert.Contains(t, string(resp.Data), `"SubscriptionConnection"`)
+}
+
+func TestHandle_MalformedBody(t *testing.T) {
+	router := setupRouter(new(stubService))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"query":`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), codeMalformedBody)
+}
@@ -0,0 +1,238 @@
+package graphqlapi
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// monthYearLayout — формат месяцев в аргументах и полях, как в REST API
+const monthYearLayout = "01-2006"
+
+func parseID(name string, id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, invalidArgument(codeInvalidID, "invalid %s, expected UUID", name)
+	}
+
+	return parsed, nil
+}
+
+func parseOptionalID(name string, id *graphql.ID) (*uuid.UUID, error) {
+	if id == nil {
+		return nil, nil
+	}
+
+	parsed, err := uuid.Parse(string(*id))
+	if err != nil {
+		return nil, invalidArgument(codeInvalidParameter, "invalid %s, expected UUID", name)
+	}
+
+	return &parsed, nil
+}
+
+func parseMonth(name, value string) (time.Time, error) {
+	t, err := time.Parse(monthYearLayout, value)
+	if err != nil {
+		return time.Time{}, invalidArgument(codeInvalidDateFormat, "invalid %s format, expected MM-YYYY", name)
+	}
+
+	return t, nil
+}
+
+func parseOptionalMonth(name string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	t, err := parseMonth(name, *value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func formatMonth(t time.Time) string {
+	return t.Format(monthYearLayout)
+}
+
+func formatOptionalMonth(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	formatted := formatMonth(*t)
+	return &formatted
+}
+
+// enumValue переводит значение перечисления GraphQL в значение модели: START_DATE → start_date
+func enumValue(value string) string {
+	return strings.ToLower(value)
+}
+
+// enumName — значение перечисления GraphQL для значения модели: start_date → START_DATE
+func enumName(value string) string {
+	return strings.ToUpper(value)
+}
+
+type subscriptionFilterInput struct {
+	UserID       *graphql.ID
+	ServiceNames *[]string
+	Query        *string
+	ActiveAt     *string
+	PriceMin     *int32
+	PriceMax     *int32
+	StartFrom    *string
+	StartTo      *string
+	EndFrom      *string
+	EndTo        *string
+	Status       *string
+	UpdatedSince *graphql.Time
+}
+
+type sortInput struct {
+	Field string
+	Desc  bool
+}
+
+// subscriptionsArgs — аргументы списков подписок
+type subscriptionsArgs struct {
+	Filter *subscriptionFilterInput
+	Sort   *[]sortInput
+	First  *int32
+	After  *string
+}
+
+// filter собирает фильтр списка подписок; значения проверяет сервис
+func (a subscriptionsArgs) filter() (model.SubscriptionFilter, error) {
+	var filter model.SubscriptionFilter
+	var err error
+
+	if in := a.Filter; in != nil {
+		if filter.UserID, err = parseOptionalID("userId", in.UserID); err != nil {
+			return filter, err
+		}
+		if in.ServiceNames != nil {
+			filter.ServiceNames = *in.ServiceNames
+		}
+		filter.Search = in.Query
+
+		months := []struct {
+			name  string
+			value *string
+			dest  **time.Time
+		}{
+			{"activeAt", in.ActiveAt, &filter.ActiveAt},
+			{"startFrom", in.StartFrom, &filter.StartFrom},
+			{"startTo", in.StartTo, &filter.StartTo},
+			{"endFrom", in.EndFrom, &filter.EndFrom},
+			{"endTo", in.EndTo, &filter.EndTo},
+		}
+		for _, m := range months {
+			if *m.dest, err = parseOptionalMonth(m.name, m.value); err != nil {
+				return filter, err
+			}
+		}
+
+		if in.PriceMin != nil {
+			priceMin := int(*in.PriceMin)
+			filter.PriceMin = &priceMin
+		}
+		if in.PriceMax != nil {
+			priceMax := int(*in.PriceMax)
+			filter.PriceMax = &priceMax
+		}
+		if in.Status != nil {
+			filter.Status = enumValue(*in.Status)
+		}
+		if in.UpdatedSince != nil {
+			filter.UpdatedSince = &in.UpdatedSince.Time
+		}
+	}
+
+	if a.Sort != nil {
+		for _, s := range *a.Sort {
+			filter.Sort = append(filter.Sort, model.SortField{Field: enumValue(s.Field), Desc: s.Desc})
+		}
+	}
+
+	if a.First != nil {
+		if *a.First < 1 {
+			return filter, invalidArgument(codeInvalidParameter, "invalid first, expected positive integer")
+		}
+		filter.Limit = int(*a.First)
+	}
+	if a.After != nil {
+		filter.Cursor = *a.After
+	}
+
+	return filter, nil
+}
+
+type subscriptionInput struct {
+	ServiceName string
+	Price       int32
+	UserID      graphql.ID
+	StartDate   string
+	EndDate     *string
+	Category    *string
+}
+
+// subscription собирает подписку; обязательность полей и цену проверяет сервис
+func (in subscriptionInput) subscription(id uuid.UUID) (*model.Subscription, error) {
+	userID, err := parseID("userId", in.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, err := parseMonth("startDate", in.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseOptionalMonth("endDate", in.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Subscription{
+		ID:          id,
+		ServiceName: in.ServiceName,
+		Price:       int(in.Price),
+		UserID:      userID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    in.Category,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// periodArgs — аргументы from и to журнала списаний
+type periodArgs struct {
+	From *string
+	To   *string
+}
+
+func (a periodArgs) parse() (from, to *time.Time, err error) {
+	if from, err = parseOptionalMonth("from", a.From); err != nil {
+		return nil, nil, err
+	}
+	if to, err = parseOptionalMonth("to", a.To); err != nil {
+		return nil, nil, err
+	}
+
+	return from, to, nil
+}
+
+// summaryArgs — период и фильтры сводки
+type summaryArgs struct {
+	StartDate   string
+	EndDate     string
+	ServiceName *string
+	Source      string
+}
@@ -0,0 +1,129 @@
+package graphqlapi
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait — сколько загрузчик ждет следующих ключей, прежде чем выполнить пакетный запрос
+const loaderWait = 2 * time.Millisecond
+
+// chargesKey — ключ загрузки журнала подписки за период; границы — месяцы или nil
+type chargesKey struct {
+	subscriptionID uuid.UUID
+	from, to       time.Time
+}
+
+// requestState — данные одного запроса GraphQL: загрузчики, которые собирают обращения
+// резолверов к сервису в пакетные запросы, и внутренние ошибки для лога запроса
+type requestState struct {
+	subscriptions *dataloader.Loader[uuid.UUID, *model.Subscription]
+	charges       *dataloader.Loader[chargesKey, []*model.Charge]
+
+	mu     sync.Mutex
+	errors []error
+}
+
+type stateKey struct{}
+
+func newRequestState(svc service.Service) *requestState {
+	return &requestState{
+		subscriptions: dataloader.NewBatchedLoader(loadSubscriptions(svc),
+			dataloader.WithWait[uuid.UUID, *model.Subscription](loaderWait),
+			dataloader.WithBatchCapacity[uuid.UUID, *model.Subscription](service.MaxPageSize),
+		),
+		charges: dataloader.NewBatchedLoader(loadCharges(svc),
+			dataloader.WithWait[chargesKey, []*model.Charge](loaderWait),
+		),
+	}
+}
+
+func withState(ctx context.Context, state *requestState) context.Context {
+	return context.WithValue(ctx, stateKey{}, state)
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	return ctx.Value(stateKey{}).(*requestState)
+}
+
+func (s *requestState) recordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, err)
+}
+
+// loadSubscriptions загружает подписки по идентификаторам одним запросом списка.
+// Отсутствующей подписке соответствует nil.
+func loadSubscriptions(svc service.Service) dataloader.BatchFunc[uuid.UUID, *model.Subscription] {
+	return func(ctx context.Context, ids []uuid.UUID) []*dataloader.Result[*model.Subscription] {
+		results := make([]*dataloader.Result[*model.Subscription], len(ids))
+
+		page, err := svc.ListSubscriptions(ctx, model.SubscriptionFilter{IDs: ids, Limit: len(ids)})
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*model.Subscription]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[uuid.UUID]*model.Subscription, len(page.Items))
+		for _, sub := range page.Items {
+			byID[sub.ID] = sub
+		}
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*model.Subscription]{Data: byID[id]}
+		}
+
+		return results
+	}
+}
+
+// loadCharges загружает журнал подписок: один запрос на каждый период из ключей
+func loadCharges(svc service.Service) dataloader.BatchFunc[chargesKey, []*model.Charge] {
+	return func(ctx context.Context, keys []chargesKey) []*dataloader.Result[[]*model.Charge] {
+		type period struct{ from, to time.Time }
+
+		ids := make(map[period][]uuid.UUID)
+		for _, key := range keys {
+			p := period{key.from, key.to}
+			ids[p] = append(ids[p], key.subscriptionID)
+		}
+
+		charges := make(map[chargesKey][]*model.Charge, len(keys))
+		errs := make(map[period]error)
+		for p, subscriptionIDs := range ids {
+			filter := model.ChargeFilter{SubscriptionIDs: subscriptionIDs}
+			if !p.from.IsZero() {
+				filter.From = &p.from
+			}
+			if !p.to.IsZero() {
+				filter.To = &p.to
+			}
+
+			err := svc.ExportCharges(ctx, filter, func(charge *model.Charge) error {
+				key := chargesKey{subscriptionID: charge.SubscriptionID, from: p.from, to: p.to}
+				charges[key] = append(charges[key], charge)
+				return nil
+			})
+			if err != nil {
+				errs[p] = err
+			}
+		}
+
+		results := make([]*dataloader.Result[[]*model.Charge], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[[]*model.Charge]{
+				Data:  charges[key],
+				Error: errs[period{key.from, key.to}],
+			}
+		}
+
+		return results
+	}
+}
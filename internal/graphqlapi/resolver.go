@@ -0,0 +1,380 @@
+package graphqlapi
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Resolver — корневой резолвер поверх service.Service. Запросы и изменения
+// разнесены по отдельным резолверам: поле запроса subscription иначе совпало бы
+// с методом Subscription, который graphql-go ищет у корня для операций subscription.
+type Resolver struct {
+	service service.Service
+}
+
+func (r *Resolver) Query() *queryResolver       { return &queryResolver{r} }
+func (r *Resolver) Mutation() *mutationResolver { return &mutationResolver{r} }
+
+type queryResolver struct{ *Resolver }
+
+type mutationResolver struct{ *Resolver }
+
+func (r *queryResolver) Subscription(ctx context.Context, args struct{ ID graphql.ID }) (*subscriptionResolver, error) {
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := r.service.GetSubscription(ctx, id)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return r.subscription(sub), nil
+}
+
+func (r *queryResolver) Subscriptions(ctx context.Context, args subscriptionsArgs) (*connectionResolver, error) {
+	filter, err := args.filter()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.listSubscriptions(ctx, filter)
+}
+
+func (r *Resolver) listSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*connectionResolver, error) {
+	page, err := r.service.ListSubscriptions(ctx, filter)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &connectionResolver{root: r, page: page}, nil
+}
+
+func (r *queryResolver) User(args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userResolver{root: r.Resolver, id: id}, nil
+}
+
+func (r *queryResolver) Summary(ctx context.Context, args struct {
+	Input struct {
+		StartDate   string
+		EndDate     string
+		UserID      *graphql.ID
+		ServiceName *string
+		Source      string
+	}
+}) (*summaryResolver, error) {
+	userID, err := parseOptionalID("userId", args.Input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.summary(ctx, summaryArgs{
+		StartDate:   args.Input.StartDate,
+		EndDate:     args.Input.EndDate,
+		ServiceName: args.Input.ServiceName,
+		Source:      args.Input.Source,
+	}, userID)
+}
+
+func (r *Resolver) summary(ctx context.Context, args summaryArgs, userID *uuid.UUID) (*summaryResolver, error) {
+	startDate, err := parseMonth("startDate", args.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseMonth("endDate", args.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	calculate := r.service.CalculateSummary
+	if args.Source == "LEDGER" {
+		calculate = r.service.CalculateLedgerSummary
+	}
+
+	summary, err := calculate(ctx, startDate, endDate, userID, args.ServiceName)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &summaryResolver{summary}, nil
+}
+
+func (r *queryResolver) Overlaps(ctx context.Context) ([]*overlapResolver, error) {
+	overlaps, err := r.service.ListOverlaps(ctx)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*overlapResolver, 0, len(overlaps))
+	for _, o := range overlaps {
+		resolvers = append(resolvers, &overlapResolver{root: r.Resolver, overlap: o})
+	}
+
+	return resolvers, nil
+}
+
+func (r *queryResolver) Charges(ctx context.Context, args struct {
+	Filter *struct {
+		UserID         *graphql.ID
+		SubscriptionID *graphql.ID
+		ServiceName    *string
+		From           *string
+		To             *string
+	}
+}) ([]*chargeResolver, error) {
+	var filter model.ChargeFilter
+	if in := args.Filter; in != nil {
+		var err error
+		if filter.UserID, err = parseOptionalID("userId", in.UserID); err != nil {
+			return nil, err
+		}
+		if filter.SubscriptionID, err = parseOptionalID("subscriptionId", in.SubscriptionID); err != nil {
+			return nil, err
+		}
+		if filter.From, filter.To, err = (periodArgs{From: in.From, To: in.To}).parse(); err != nil {
+			return nil, err
+		}
+		filter.ServiceName = in.ServiceName
+	}
+
+	return r.allCharges(ctx, filter)
+}
+
+func (r *queryResolver) Adjustments(ctx context.Context, args struct{ SubscriptionID graphql.ID }) ([]*adjustmentResolver, error) {
+	subscriptionID, err := parseID("subscriptionId", args.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := r.service.ListAdjustments(ctx, subscriptionID)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*adjustmentResolver, 0, len(adjustments))
+	for _, a := range adjustments {
+		resolvers = append(resolvers, &adjustmentResolver{root: r.Resolver, adjustment: a})
+	}
+
+	return resolvers, nil
+}
+
+func (r *mutationResolver) CreateSubscription(ctx context.Context, args struct {
+	Input subscriptionInput
+	Force bool
+}) (*createPayloadResolver, error) {
+	sub, err := args.Input.subscription(uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.service.CreateSubscription(ctx, sub, model.WriteOptions{Force: args.Force})
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &createPayloadResolver{subscription: r.subscription(created.Subscription), warnings: created.Warnings}, nil
+}
+
+func (r *mutationResolver) UpdateSubscription(ctx context.Context, args struct {
+	ID             graphql.ID
+	Input          subscriptionInput
+	IfMatchVersion *int32
+	Force          bool
+}) (*subscriptionResolver, error) {
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := args.Input.subscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.service.UpdateSubscription(ctx, sub, writeOptions(args.Force, args.IfMatchVersion)); err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	updated, err := r.service.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return r.subscription(updated), nil
+}
+
+func (r *mutationResolver) DeleteSubscription(ctx context.Context, args struct {
+	ID             graphql.ID
+	IfMatchVersion *int32
+}) (bool, error) {
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.DeleteSubscription(ctx, id, writeOptions(false, args.IfMatchVersion)); err != nil {
+		return false, fail(ctx, err)
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SetBudget(ctx context.Context, args struct {
+	Input struct {
+		UserID       graphql.ID
+		Category     *string
+		MonthlyLimit int32
+	}
+}) (*budgetResolver, error) {
+	userID, err := parseID("userId", args.Input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := r.service.SetBudget(ctx, &model.Budget{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Category:     args.Input.Category,
+		MonthlyLimit: int(args.Input.MonthlyLimit),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &budgetResolver{budget}, nil
+}
+
+func (r *mutationResolver) DeleteBudget(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.DeleteBudget(ctx, id); err != nil {
+		return false, fail(ctx, err)
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) CreateAdjustment(ctx context.Context, args struct {
+	SubscriptionID graphql.ID
+	Input          struct {
+		Kind   string
+		Amount int32
+		Date   string
+		Reason string
+	}
+}) (*adjustmentResolver, error) {
+	subscriptionID, err := parseID("subscriptionId", args.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseMonth("date", args.Input.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	adj, err := r.service.CreateAdjustment(ctx, &model.Adjustment{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Kind:           enumValue(args.Input.Kind),
+		Amount:         int(args.Input.Amount),
+		Date:           date,
+		Reason:         args.Input.Reason,
+		CreatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &adjustmentResolver{root: r.Resolver, adjustment: adj}, nil
+}
+
+func (r *mutationResolver) DeleteAdjustment(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.DeleteAdjustment(ctx, id); err != nil {
+		return false, fail(ctx, err)
+	}
+
+	return true, nil
+}
+
+// writeOptions собирает параметры проверок записи
+func writeOptions(force bool, ifMatchVersion *int32) model.WriteOptions {
+	opts := model.WriteOptions{Force: force}
+	if ifMatchVersion != nil {
+		version := int(*ifMatchVersion)
+		opts.IfMatch = &version
+	}
+
+	return opts
+}
+
+// loadSubscription загружает подписку через загрузчик запроса; nil — подписки нет
+func (r *Resolver) loadSubscription(ctx context.Context, id uuid.UUID) (*subscriptionResolver, error) {
+	sub, err := stateFrom(ctx).subscriptions.Load(ctx, id)()
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+	if sub == nil {
+		return nil, nil
+	}
+
+	return r.subscription(sub), nil
+}
+
+func (r *Resolver) subscription(sub *model.Subscription) *subscriptionResolver {
+	return &subscriptionResolver{root: r, sub: sub}
+}
+
+// allCharges возвращает все строки журнала по фильтру: списки журнала в схеме не постраничные
+func (r *Resolver) allCharges(ctx context.Context, filter model.ChargeFilter) ([]*chargeResolver, error) {
+	var resolvers []*chargeResolver
+	err := r.service.ExportCharges(ctx, filter, func(charge *model.Charge) error {
+		resolvers = append(resolvers, &chargeResolver{root: r, charge: charge})
+		return nil
+	})
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) charges(charges []*model.Charge) []*chargeResolver {
+	resolvers := make([]*chargeResolver, 0, len(charges))
+	for _, c := range charges {
+		resolvers = append(resolvers, &chargeResolver{root: r, charge: c})
+	}
+
+	return resolvers
+}
+
+// isNotFound сообщает, что запись не найдена
+func isNotFound(err error) bool {
+	return err != nil && service.KindOf(err) == service.KindNotFound
+}
@@ -0,0 +1,252 @@
+package graphqlapi
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type subscriptionResolver struct {
+	root *Resolver
+	sub  *model.Subscription
+}
+
+func (r *subscriptionResolver) ID() graphql.ID      { return graphql.ID(r.sub.ID.String()) }
+func (r *subscriptionResolver) ServiceName() string { return r.sub.ServiceName }
+func (r *subscriptionResolver) Price() int32        { return int32(r.sub.Price) }
+func (r *subscriptionResolver) UserID() graphql.ID  { return graphql.ID(r.sub.UserID.String()) }
+func (r *subscriptionResolver) User() *userResolver {
+	return &userResolver{root: r.root, id: r.sub.UserID}
+}
+func (r *subscriptionResolver) StartDate() string       { return formatMonth(r.sub.StartDate) }
+func (r *subscriptionResolver) EndDate() *string        { return formatOptionalMonth(r.sub.EndDate) }
+func (r *subscriptionResolver) Category() *string       { return r.sub.Category }
+func (r *subscriptionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.sub.CreatedAt} }
+func (r *subscriptionResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.sub.UpdatedAt} }
+func (r *subscriptionResolver) Version() int32          { return int32(r.sub.Version) }
+
+// Charges загружает журнал подписки через загрузчик запроса: журналы всех подписок
+// ответа читаются одним запросом на период
+func (r *subscriptionResolver) Charges(ctx context.Context, args periodArgs) ([]*chargeResolver, error) {
+	from, to, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	key := chargesKey{subscriptionID: r.sub.ID}
+	if from != nil {
+		key.from = *from
+	}
+	if to != nil {
+		key.to = *to
+	}
+
+	charges, err := stateFrom(ctx).charges.Load(ctx, key)()
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return r.root.charges(charges), nil
+}
+
+type connectionResolver struct {
+	root *Resolver
+	page *model.SubscriptionPage
+}
+
+func (r *connectionResolver) Items() []*subscriptionResolver {
+	items := make([]*subscriptionResolver, 0, len(r.page.Items))
+	for _, sub := range r.page.Items {
+		items = append(items, r.root.subscription(sub))
+	}
+
+	return items
+}
+
+func (r *connectionResolver) NextCursor() *string {
+	return r.page.NextCursor
+}
+
+type userResolver struct {
+	root *Resolver
+	id   uuid.UUID
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return graphql.ID(r.id.String())
+}
+
+func (r *userResolver) Subscriptions(ctx context.Context, args subscriptionsArgs) (*connectionResolver, error) {
+	filter, err := args.filter()
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = &r.id
+
+	return r.root.listSubscriptions(ctx, filter)
+}
+
+func (r *userResolver) Summary(ctx context.Context, args summaryArgs) (*summaryResolver, error) {
+	return r.root.summary(ctx, args, &r.id)
+}
+
+func (r *userResolver) Budgets(ctx context.Context) ([]*budgetResolver, error) {
+	budgets, err := r.root.service.ListBudgets(ctx, r.id)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*budgetResolver, 0, len(budgets))
+	for _, b := range budgets {
+		resolvers = append(resolvers, &budgetResolver{b})
+	}
+
+	return resolvers, nil
+}
+
+func (r *userResolver) BudgetReport(ctx context.Context, args struct {
+	StartDate string
+	EndDate   string
+}) ([]*budgetMonthResolver, error) {
+	startDate, err := parseMonth("startDate", args.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseMonth("endDate", args.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	months, err := r.root.service.BudgetReport(ctx, r.id, startDate, endDate)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*budgetMonthResolver, 0, len(months))
+	for _, m := range months {
+		resolvers = append(resolvers, &budgetMonthResolver{m})
+	}
+
+	return resolvers, nil
+}
+
+func (r *userResolver) Charges(ctx context.Context, args periodArgs) ([]*chargeResolver, error) {
+	from, to, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.root.allCharges(ctx, model.ChargeFilter{UserID: &r.id, From: from, To: to})
+}
+
+type summaryResolver struct {
+	summary *model.SummaryResponse
+}
+
+func (r *summaryResolver) TotalAmount() int32       { return int32(r.summary.TotalAmount) }
+func (r *summaryResolver) Count() int32             { return int32(r.summary.Count) }
+func (r *summaryResolver) AdjustmentsAmount() int32 { return int32(r.summary.AdjustmentsAmount) }
+
+type overlapResolver struct {
+	root    *Resolver
+	overlap *model.SubscriptionOverlap
+}
+
+func (r *overlapResolver) UserID() graphql.ID { return graphql.ID(r.overlap.UserID.String()) }
+func (r *overlapResolver) User() *userResolver {
+	return &userResolver{root: r.root, id: r.overlap.UserID}
+}
+func (r *overlapResolver) ServiceName() string  { return r.overlap.ServiceName }
+func (r *overlapResolver) OverlapStart() string { return formatMonth(r.overlap.OverlapStart) }
+func (r *overlapResolver) OverlapEnd() *string  { return formatOptionalMonth(r.overlap.OverlapEnd) }
+
+func (r *overlapResolver) Subscription(ctx context.Context) (*subscriptionResolver, error) {
+	return r.root.loadSubscription(ctx, r.overlap.SubscriptionID)
+}
+
+func (r *overlapResolver) Overlapping(ctx context.Context) (*subscriptionResolver, error) {
+	return r.root.loadSubscription(ctx, r.overlap.OverlappingID)
+}
+
+type chargeResolver struct {
+	root   *Resolver
+	charge *model.Charge
+}
+
+func (r *chargeResolver) ID() graphql.ID { return graphql.ID(r.charge.ID.String()) }
+func (r *chargeResolver) SubscriptionID() graphql.ID {
+	return graphql.ID(r.charge.SubscriptionID.String())
+}
+func (r *chargeResolver) UserID() graphql.ID      { return graphql.ID(r.charge.UserID.String()) }
+func (r *chargeResolver) ServiceName() string     { return r.charge.ServiceName }
+func (r *chargeResolver) Category() *string       { return r.charge.Category }
+func (r *chargeResolver) Month() string           { return formatMonth(r.charge.Month) }
+func (r *chargeResolver) Amount() int32           { return int32(r.charge.Amount) }
+func (r *chargeResolver) Kind() string            { return enumName(r.charge.Kind) }
+func (r *chargeResolver) Reason() *string         { return r.charge.Reason }
+func (r *chargeResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.charge.CreatedAt} }
+
+func (r *chargeResolver) Subscription(ctx context.Context) (*subscriptionResolver, error) {
+	return r.root.loadSubscription(ctx, r.charge.SubscriptionID)
+}
+
+type budgetResolver struct {
+	budget *model.Budget
+}
+
+func (r *budgetResolver) ID() graphql.ID          { return graphql.ID(r.budget.ID.String()) }
+func (r *budgetResolver) UserID() graphql.ID      { return graphql.ID(r.budget.UserID.String()) }
+func (r *budgetResolver) Category() *string       { return r.budget.Category }
+func (r *budgetResolver) MonthlyLimit() int32     { return int32(r.budget.MonthlyLimit) }
+func (r *budgetResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.budget.CreatedAt} }
+func (r *budgetResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.budget.UpdatedAt} }
+
+type budgetMonthResolver struct {
+	month *model.BudgetMonth
+}
+
+func (r *budgetMonthResolver) Month() string        { return formatMonth(r.month.Month) }
+func (r *budgetMonthResolver) BudgetID() graphql.ID { return graphql.ID(r.month.BudgetID.String()) }
+func (r *budgetMonthResolver) Category() *string    { return r.month.Category }
+func (r *budgetMonthResolver) MonthlyLimit() int32  { return int32(r.month.MonthlyLimit) }
+func (r *budgetMonthResolver) Actual() int32        { return int32(r.month.Actual) }
+func (r *budgetMonthResolver) OverBudget() bool     { return r.month.OverBudget }
+
+type adjustmentResolver struct {
+	root       *Resolver
+	adjustment *model.Adjustment
+}
+
+func (r *adjustmentResolver) ID() graphql.ID { return graphql.ID(r.adjustment.ID.String()) }
+func (r *adjustmentResolver) SubscriptionID() graphql.ID {
+	return graphql.ID(r.adjustment.SubscriptionID.String())
+}
+func (r *adjustmentResolver) Kind() string   { return enumName(r.adjustment.Kind) }
+func (r *adjustmentResolver) Amount() int32  { return int32(r.adjustment.Amount) }
+func (r *adjustmentResolver) Date() string   { return formatMonth(r.adjustment.Date) }
+func (r *adjustmentResolver) Reason() string { return r.adjustment.Reason }
+func (r *adjustmentResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.adjustment.CreatedAt}
+}
+
+func (r *adjustmentResolver) Subscription(ctx context.Context) (*subscriptionResolver, error) {
+	return r.root.loadSubscription(ctx, r.adjustment.SubscriptionID)
+}
+
+type createPayloadResolver struct {
+	subscription *subscriptionResolver
+	warnings     []string
+}
+
+func (r *createPayloadResolver) Subscription() *subscriptionResolver { return r.subscription }
+
+func (r *createPayloadResolver) Warnings() []string {
+	if r.warnings == nil {
+		return []string{}
+	}
+
+	return r.warnings
+}
@@ -0,0 +1,508 @@
+package grpcapi
+
+import (
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	subscriptionv1 "github.com/ZnNr/subscription-service/pkg/api/subscription/v1"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// parseID разбирает обязательный идентификатор из поля field
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidField(codeInvalidID, field, "must be UUID")
+	}
+
+	return id, nil
+}
+
+// parseOptionalID разбирает необязательный идентификатор: пустая строка — uuid.Nil
+func parseOptionalID(field, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidField(codeInvalidParameter, field, "must be UUID")
+	}
+
+	return id, nil
+}
+
+// parseIDFilter разбирает идентификатор фильтра; nil — фильтр не задан
+func parseIDFilter(field string, value *string) (*uuid.UUID, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return nil, invalidField(codeInvalidParameter, field, "must be UUID")
+	}
+
+	return &id, nil
+}
+
+// parseMonth переводит месяц в первое число месяца (UTC); nil — месяц не задан
+func parseMonth(field string, m *subscriptionv1.Month) (*time.Time, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	if m.Month < 1 || m.Month > 12 || m.Year < 1 || m.Year > 9999 {
+		return nil, invalidField(codeInvalidDateFormat, field, "must have month 1-12 and year 1-9999")
+	}
+
+	t := time.Date(int(m.Year), time.Month(m.Month), 1, 0, 0, 0, 0, time.UTC)
+	return &t, nil
+}
+
+// parseRequiredMonth разбирает месяц, без которого запрос не выполняется
+func parseRequiredMonth(field string, m *subscriptionv1.Month) (time.Time, error) {
+	if m == nil {
+		return time.Time{}, invalidField(codeValidationFailed, field, "is required")
+	}
+
+	t, err := parseMonth(field, m)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return *t, nil
+}
+
+func toMonth(t time.Time) *subscriptionv1.Month {
+	return &subscriptionv1.Month{Year: int32(t.Year()), Month: int32(t.Month())}
+}
+
+func toOptionalMonth(t *time.Time) *subscriptionv1.Month {
+	if t == nil {
+		return nil
+	}
+
+	return toMonth(*t)
+}
+
+// parseSubscriptionInput разбирает данные подписки; prefix — путь к ним в запросе.
+// Обязательность полей и цена проверяются сервисом, как и для REST API.
+func parseSubscriptionInput(id uuid.UUID, in *subscriptionv1.SubscriptionInput, prefix string) (*model.Subscription, error) {
+	if in == nil {
+		return nil, invalidField(codeValidationFailed, strings.TrimSuffix(prefix, "."), "is required")
+	}
+
+	userID, err := parseOptionalID(prefix+"user_id", in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, err := parseMonth(prefix+"start_date", in.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseMonth(prefix+"end_date", in.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &model.Subscription{
+		ID:          id,
+		ServiceName: in.ServiceName,
+		Price:       int(in.Price),
+		UserID:      userID,
+		EndDate:     endDate,
+		Category:    in.Category,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if startDate != nil {
+		sub.StartDate = *startDate
+	}
+
+	return sub, nil
+}
+
+// parseSubscriptionPatch строит документ изменения из полей update_mask: поле из маски
+// без значения очищается, как null в JSON Merge Patch
+func parseSubscriptionPatch(in *subscriptionv1.SubscriptionInput, paths []string) (*model.SubscriptionPatch, error) {
+	if in == nil {
+		in = &subscriptionv1.SubscriptionInput{}
+	}
+
+	patch := &model.SubscriptionPatch{}
+	for _, path := range paths {
+		switch path {
+		case "service_name":
+			patch.ServiceName = model.Optional[string]{Set: true, Value: in.ServiceName}
+		case "price":
+			patch.Price = model.Optional[int]{Set: true, Value: int(in.Price)}
+		case "user_id":
+			userID, err := parseOptionalID("subscription.user_id", in.UserId)
+			if err != nil {
+				return nil, err
+			}
+			patch.UserID = model.Optional[uuid.UUID]{Set: true, Value: userID}
+		case "start_date", "end_date":
+			m := in.StartDate
+			if path == "end_date" {
+				m = in.EndDate
+			}
+			t, err := parseMonth("subscription."+path, m)
+			if err != nil {
+				return nil, err
+			}
+			value := model.Optional[time.Time]{Set: true, Null: t == nil}
+			if t != nil {
+				value.Value = *t
+			}
+			if path == "start_date" {
+				patch.StartDate = value
+			} else {
+				patch.EndDate = value
+			}
+		case "category":
+			patch.Category = model.Optional[string]{Set: true, Null: in.Category == nil}
+			if in.Category != nil {
+				patch.Category.Value = *in.Category
+			}
+		default:
+			return nil, invalidField(codeInvalidParameter, "update_mask", fmt.Sprintf("unknown field %q", path))
+		}
+	}
+
+	return patch, nil
+}
+
+// writeOptions собирает параметры проверок записи
+func writeOptions(force bool, ifMatchVersion *int32) model.WriteOptions {
+	opts := model.WriteOptions{Force: force}
+	if ifMatchVersion != nil {
+		version := int(*ifMatchVersion)
+		opts.IfMatch = &version
+	}
+
+	return opts
+}
+
+func toSubscription(sub *model.Subscription) *subscriptionv1.Subscription {
+	return &subscriptionv1.Subscription{
+		Id:          sub.ID.String(),
+		ServiceName: sub.ServiceName,
+		Price:       int64(sub.Price),
+		UserId:      sub.UserID.String(),
+		StartDate:   toMonth(sub.StartDate),
+		EndDate:     toOptionalMonth(sub.EndDate),
+		Category:    sub.Category,
+		CreatedAt:   timestamppb.New(sub.CreatedAt),
+		UpdatedAt:   timestamppb.New(sub.UpdatedAt),
+		Version:     int32(sub.Version),
+	}
+}
+
+func toSubscriptions(subs []*model.Subscription) []*subscriptionv1.Subscription {
+	out := make([]*subscriptionv1.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		out = append(out, toSubscription(sub))
+	}
+
+	return out
+}
+
+// subscriptionStatuses — значения фильтра status
+var subscriptionStatuses = map[subscriptionv1.SubscriptionStatus]string{
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED: "",
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE:      model.SubscriptionStatusActive,
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_ENDED:       model.SubscriptionStatusEnded,
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_FUTURE:      model.SubscriptionStatusFuture,
+}
+
+// parseSubscriptionFilter разбирает фильтры списка подписок; значения проверяет сервис
+func parseSubscriptionFilter(in *subscriptionv1.SubscriptionFilter, sort []*subscriptionv1.SortField) (model.SubscriptionFilter, error) {
+	var filter model.SubscriptionFilter
+	if in == nil {
+		in = &subscriptionv1.SubscriptionFilter{}
+	}
+
+	userID, err := parseIDFilter("filter.user_id", in.UserId)
+	if err != nil {
+		return filter, err
+	}
+	filter.UserID = userID
+	filter.ServiceNames = in.ServiceNames
+	filter.Search = in.Query
+
+	months := []struct {
+		field string
+		value *subscriptionv1.Month
+		dest  **time.Time
+	}{
+		{"filter.active_at", in.ActiveAt, &filter.ActiveAt},
+		{"filter.start_from", in.StartFrom, &filter.StartFrom},
+		{"filter.start_to", in.StartTo, &filter.StartTo},
+		{"filter.end_from", in.EndFrom, &filter.EndFrom},
+		{"filter.end_to", in.EndTo, &filter.EndTo},
+	}
+	for _, m := range months {
+		if *m.dest, err = parseMonth(m.field, m.value); err != nil {
+			return filter, err
+		}
+	}
+
+	if in.PriceMin != nil {
+		priceMin := int(*in.PriceMin)
+		filter.PriceMin = &priceMin
+	}
+	if in.PriceMax != nil {
+		priceMax := int(*in.PriceMax)
+		filter.PriceMax = &priceMax
+	}
+
+	status, ok := subscriptionStatuses[in.Status]
+	if !ok {
+		return filter, invalidField(codeInvalidParameter, "filter.status", "unknown status")
+	}
+	filter.Status = status
+
+	if in.UpdatedSince != nil {
+		updatedSince := in.UpdatedSince.AsTime()
+		filter.UpdatedSince = &updatedSince
+	}
+
+	for _, f := range sort {
+		filter.Sort = append(filter.Sort, model.SortField{Field: f.Field, Desc: f.Desc})
+	}
+
+	return filter, nil
+}
+
+// batchOperations и batchStatuses переводят значения перечислений пакетных операций
+var (
+	batchOperations = map[subscriptionv1.BatchOperationType]string{
+		subscriptionv1.BatchOperationType_BATCH_OPERATION_TYPE_CREATE: model.BatchOpCreate,
+		subscriptionv1.BatchOperationType_BATCH_OPERATION_TYPE_UPDATE: model.BatchOpUpdate,
+		subscriptionv1.BatchOperationType_BATCH_OPERATION_TYPE_DELETE: model.BatchOpDelete,
+	}
+	batchStatuses = map[string]subscriptionv1.BatchItemStatus{
+		model.BatchStatusOK:         subscriptionv1.BatchItemStatus_BATCH_ITEM_STATUS_OK,
+		model.BatchStatusFailed:     subscriptionv1.BatchItemStatus_BATCH_ITEM_STATUS_FAILED,
+		model.BatchStatusRolledBack: subscriptionv1.BatchItemStatus_BATCH_ITEM_STATUS_ROLLED_BACK,
+		model.BatchStatusSkipped:    subscriptionv1.BatchItemStatus_BATCH_ITEM_STATUS_SKIPPED,
+	}
+)
+
+// parseBatchOperation проверяет состав полей операции пакета и разбирает её данные
+func parseBatchOperation(index int, in *subscriptionv1.BatchOperation) (model.BatchOperation, error) {
+	prefix := fmt.Sprintf("operations[%d]", index)
+
+	opName, ok := batchOperations[in.Op]
+	if !ok {
+		return model.BatchOperation{}, invalidField(service.ErrInvalidBatchOperation.Code, prefix+".op", "must be one of create, update, delete")
+	}
+
+	op := model.BatchOperation{Op: opName}
+	if in.IfMatchVersion != nil {
+		version := int(*in.IfMatchVersion)
+		op.Version = &version
+	}
+
+	if opName == model.BatchOpCreate {
+		op.ID = uuid.New()
+	} else {
+		id, err := parseID(prefix+".id", in.Id)
+		if err != nil {
+			return op, err
+		}
+		op.ID = id
+	}
+
+	if opName == model.BatchOpDelete {
+		return op, nil
+	}
+
+	if in.Subscription == nil {
+		return op, invalidField(service.ErrInvalidBatchOperation.Code, prefix+".subscription", "is required")
+	}
+
+	sub, err := parseSubscriptionInput(op.ID, in.Subscription, prefix+".subscription.")
+	if err != nil {
+		return op, err
+	}
+	op.Subscription = sub
+
+	return op, nil
+}
+
+func toBatchResponse(resp *model.BatchResponse) *subscriptionv1.BatchSubscriptionsResponse {
+	out := &subscriptionv1.BatchSubscriptionsResponse{Committed: resp.Committed}
+	ops := make(map[string]subscriptionv1.BatchOperationType, len(batchOperations))
+	for op, name := range batchOperations {
+		ops[name] = op
+	}
+
+	for _, r := range resp.Results {
+		result := &subscriptionv1.BatchItemResult{
+			Index:    int32(r.Index),
+			Op:       ops[r.Op],
+			Id:       r.ID.String(),
+			Status:   batchStatuses[r.Status],
+			Error:    r.Error,
+			Code:     r.Code,
+			Warnings: r.Warnings,
+		}
+		if r.Subscription != nil {
+			result.Subscription = toSubscription(r.Subscription)
+		}
+		out.Results = append(out.Results, result)
+	}
+
+	return out
+}
+
+// importStatuses переводит статусы строк импорта
+var importStatuses = map[string]subscriptionv1.ImportRowStatus{
+	model.ImportStatusValid:      subscriptionv1.ImportRowStatus_IMPORT_ROW_STATUS_VALID,
+	model.ImportStatusInvalid:    subscriptionv1.ImportRowStatus_IMPORT_ROW_STATUS_INVALID,
+	model.ImportStatusCreated:    subscriptionv1.ImportRowStatus_IMPORT_ROW_STATUS_CREATED,
+	model.ImportStatusFailed:     subscriptionv1.ImportRowStatus_IMPORT_ROW_STATUS_FAILED,
+	model.ImportStatusRolledBack: subscriptionv1.ImportRowStatus_IMPORT_ROW_STATUS_ROLLED_BACK,
+	model.ImportStatusSkipped:    subscriptionv1.ImportRowStatus_IMPORT_ROW_STATUS_SKIPPED,
+}
+
+// parseImportRow разбирает строку импорта; ошибки разбора попадают в отчет по строке
+func parseImportRow(row int, in *subscriptionv1.SubscriptionInput) model.ImportRow {
+	sub, err := parseSubscriptionInput(uuid.New(), in, "")
+	if err != nil {
+		return model.ImportRow{Row: row, Errors: fieldViolations(err)}
+	}
+
+	return model.ImportRow{Row: row, Subscription: sub}
+}
+
+func toImportReport(report *model.ImportReport) *subscriptionv1.ImportReport {
+	out := &subscriptionv1.ImportReport{
+		DryRun:  report.DryRun,
+		Total:   int32(report.Total),
+		Valid:   int32(report.Valid),
+		Invalid: int32(report.Invalid),
+		Created: int32(report.Created),
+	}
+	if report.ResumeFrom != nil {
+		out.ResumeFrom = int32(*report.ResumeFrom)
+	}
+
+	for _, r := range report.Rows {
+		result := &subscriptionv1.ImportRowResult{
+			Row:      int32(r.Row),
+			Status:   importStatuses[r.Status],
+			Code:     r.Code,
+			Error:    r.Error,
+			Warnings: r.Warnings,
+		}
+		if r.ID != nil {
+			result.Id = r.ID.String()
+		}
+		for _, v := range r.Errors {
+			result.Errors = append(result.Errors, &subscriptionv1.FieldViolation{Field: v.Field, Message: v.Message})
+		}
+		out.Rows = append(out.Rows, result)
+	}
+
+	return out
+}
+
+func toSummary(summary *model.SummaryResponse) *subscriptionv1.Summary {
+	return &subscriptionv1.Summary{
+		TotalAmount:       int64(summary.TotalAmount),
+		Count:             int32(summary.Count),
+		AdjustmentsAmount: int64(summary.AdjustmentsAmount),
+	}
+}
+
+func toOverlap(o *model.SubscriptionOverlap) *subscriptionv1.SubscriptionOverlap {
+	return &subscriptionv1.SubscriptionOverlap{
+		UserId:         o.UserID.String(),
+		ServiceName:    o.ServiceName,
+		SubscriptionId: o.SubscriptionID.String(),
+		OverlappingId:  o.OverlappingID.String(),
+		OverlapStart:   toMonth(o.OverlapStart),
+		OverlapEnd:     toOptionalMonth(o.OverlapEnd),
+	}
+}
+
+func toBudget(b *model.Budget) *subscriptionv1.Budget {
+	return &subscriptionv1.Budget{
+		Id:           b.ID.String(),
+		UserId:       b.UserID.String(),
+		Category:     b.Category,
+		MonthlyLimit: int64(b.MonthlyLimit),
+		CreatedAt:    timestamppb.New(b.CreatedAt),
+		UpdatedAt:    timestamppb.New(b.UpdatedAt),
+	}
+}
+
+func toBudgetMonth(m *model.BudgetMonth) *subscriptionv1.BudgetMonth {
+	return &subscriptionv1.BudgetMonth{
+		Month:        toMonth(m.Month),
+		BudgetId:     m.BudgetID.String(),
+		Category:     m.Category,
+		MonthlyLimit: int64(m.MonthlyLimit),
+		Actual:       int64(m.Actual),
+		OverBudget:   m.OverBudget,
+	}
+}
+
+// adjustmentKinds и chargeKinds переводят виды корректировок и строк журнала
+var (
+	adjustmentKinds = map[subscriptionv1.AdjustmentKind]string{
+		subscriptionv1.AdjustmentKind_ADJUSTMENT_KIND_REFUND: model.AdjustmentRefund,
+		subscriptionv1.AdjustmentKind_ADJUSTMENT_KIND_CREDIT: model.AdjustmentCredit,
+		subscriptionv1.AdjustmentKind_ADJUSTMENT_KIND_CHARGE: model.AdjustmentCharge,
+	}
+	chargeKinds = map[string]subscriptionv1.ChargeKind{
+		model.ChargeSubscription: subscriptionv1.ChargeKind_CHARGE_KIND_SUBSCRIPTION,
+		model.AdjustmentRefund:   subscriptionv1.ChargeKind_CHARGE_KIND_REFUND,
+		model.AdjustmentCredit:   subscriptionv1.ChargeKind_CHARGE_KIND_CREDIT,
+		model.AdjustmentCharge:   subscriptionv1.ChargeKind_CHARGE_KIND_CHARGE,
+	}
+)
+
+func toCharge(c *model.Charge) *subscriptionv1.Charge {
+	return &subscriptionv1.Charge{
+		Id:             c.ID.String(),
+		SubscriptionId: c.SubscriptionID.String(),
+		UserId:         c.UserID.String(),
+		ServiceName:    c.ServiceName,
+		Category:       c.Category,
+		Month:          toMonth(c.Month),
+		Amount:         int64(c.Amount),
+		Kind:           chargeKinds[c.Kind],
+		Reason:         c.Reason,
+		CreatedAt:      timestamppb.New(c.CreatedAt),
+	}
+}
+
+func toAdjustment(a *model.Adjustment) *subscriptionv1.Adjustment {
+	kind := subscriptionv1.AdjustmentKind_ADJUSTMENT_KIND_UNSPECIFIED
+	for k, name := range adjustmentKinds {
+		if name == a.Kind {
+			kind = k
+		}
+	}
+
+	return &subscriptionv1.Adjustment{
+		Id:             a.ID.String(),
+		SubscriptionId: a.SubscriptionID.String(),
+		Kind:           kind,
+		Amount:         int64(a.Amount),
+		Date:           toMonth(a.Date),
+		Reason:         a.Reason,
+		CreatedAt:      timestamppb.New(a.CreatedAt),
+	}
+}
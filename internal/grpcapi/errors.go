@@ -0,0 +1,141 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain — домен причин ошибок в errdetails.ErrorInfo
+const errorDomain = "subscription-service"
+
+// Коды ошибок, которые выявляются при разборе запроса, до вызова сервиса (те же, что в REST API)
+const (
+	codeInvalidID         = "invalid_id"
+	codeInvalidParameter  = "invalid_parameter"
+	codeInvalidDateFormat = "invalid_date_format"
+	codeValidationFailed  = "validation_failed"
+)
+
+// codeAlreadyExists — код ошибки сервиса, которым описывается repository.ErrAlreadyExists
+const codeAlreadyExists = "already_exists"
+
+// statusCodes — код статуса gRPC для каждой категории ошибки сервиса.
+// Конфликты уточняются по коду ошибки в statusCode.
+var statusCodes = map[service.ErrorKind]codes.Code{
+	service.KindInvalid:            codes.InvalidArgument,
+	service.KindNotFound:           codes.NotFound,
+	service.KindConflict:           codes.Aborted,
+	service.KindPreconditionFailed: codes.Aborted,
+	service.KindUnprocessable:      codes.FailedPrecondition,
+	service.KindInternal:           codes.Internal,
+}
+
+// requestError — ошибка разбора запроса с нарушениями по полям
+type requestError struct {
+	code       string
+	violations []model.FieldViolation
+}
+
+func (e *requestError) Error() string {
+	parts := make([]string, 0, len(e.violations))
+	for _, v := range e.violations {
+		parts = append(parts, v.Field+" "+v.Message)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// invalidField — ошибка разбора одного поля запроса
+func invalidField(code, field, message string) error {
+	return &requestError{code: code, violations: []model.FieldViolation{{Field: field, Message: message}}}
+}
+
+// fieldViolations — нарушения по полям из ошибки разбора запроса
+func fieldViolations(err error) []model.FieldViolation {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.violations
+	}
+
+	return []model.FieldViolation{{Message: err.Error()}}
+}
+
+// toStatus переводит ошибку в статус gRPC: код выбирается по категории ошибки сервиса,
+// машиночитаемый код передается в ErrorInfo. Текст внутренних ошибок клиенту не передается.
+func toStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		st := status.New(codes.InvalidArgument, reqErr.Error())
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range reqErr.violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Message,
+			})
+		}
+		return withDetails(st, &errdetails.ErrorInfo{Reason: reqErr.code, Domain: errorDomain}, badRequest)
+	}
+
+	described := service.Describe(err)
+	code := statusCode(described)
+
+	info := &errdetails.ErrorInfo{Reason: described.Code, Domain: errorDomain}
+
+	// При пересечении подписок ErrorInfo содержит идентификаторы конфликтующих подписок
+	var overlapErr *service.OverlapError
+	if errors.As(err, &overlapErr) {
+		ids := make([]string, 0, len(overlapErr.Overlaps))
+		for _, o := range overlapErr.Overlaps {
+			ids = append(ids, o.ID.String())
+		}
+		info.Metadata = map[string]string{"conflicts": strings.Join(ids, ",")}
+	}
+
+	return withDetails(status.New(code, described.Message), info)
+}
+
+// statusCode выбирает код статуса gRPC для ошибки сервиса. Ошибки категории conflict различаются
+// по смыслу: ALREADY_EXISTS означает только существующую запись, пересечение подписок — нарушение
+// условия над состоянием данных, а незавершенный запрос с тем же ключом можно повторить позже.
+func statusCode(described service.ServiceError) codes.Code {
+	switch described.Code {
+	case codeAlreadyExists:
+		return codes.AlreadyExists
+	case service.ErrSubscriptionOverlap.Code:
+		return codes.FailedPrecondition
+	case service.ErrIdempotencyRequestInProgress.Code:
+		return codes.Aborted
+	}
+
+	if code, ok := statusCodes[described.Kind]; ok {
+		return code
+	}
+
+	return codes.Internal
+}
+
+// withDetails добавляет к статусу подробности; если их не удалось упаковать, статус возвращается без них
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
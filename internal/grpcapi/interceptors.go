@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// requestIDKey — ключ идентификатора запроса в метаданных запроса и ответа
+	requestIDKey = "x-request-id"
+	// maxRequestIDLength — более длинный x-request-id клиента заменяется своим
+	maxRequestIDLength = 128
+)
+
+// requestID берет идентификатор запроса из метаданных x-request-id или генерирует новый
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDKey); len(ids) > 0 && ids[0] != "" && len(ids[0]) <= maxRequestIDLength {
+			return ids[0]
+		}
+	}
+
+	return uuid.NewString()
+}
+
+// UnaryInterceptor присваивает запросу идентификатор (x-request-id в метаданных ответа),
+// восстанавливается после паники, переводит ошибки сервиса в статусы gRPC и пишет запрос в лог
+func UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		id := requestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+		start := time.Now()
+		defer func() {
+			err = finishCall(info.FullMethod, id, start, recover(), err)
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor — UnaryInterceptor для потоковых методов
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		id := requestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestIDKey, id))
+
+		start := time.Now()
+		defer func() {
+			err = finishCall(info.FullMethod, id, start, recover(), err)
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// finishCall переводит ошибку или панику обработчика в статус и пишет запрос в лог.
+// Внутренние ошибки не отдаются клиенту и попадают только в лог.
+func finishCall(method, id string, start time.Time, panicValue any, err error) error {
+	entry := logger.GetLogger().WithFields(logrus.Fields{
+		"method":     method,
+		"duration":   time.Since(start),
+		"request_id": id,
+	})
+
+	if panicValue != nil {
+		entry.WithFields(logrus.Fields{
+			"code":  codes.Internal.String(),
+			"error": fmt.Sprintf("panic: %v", panicValue),
+			"stack": string(debug.Stack()),
+		}).Error("gRPC request")
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	if err == nil {
+		entry.WithField("code", codes.OK.String()).Info("gRPC request")
+		return nil
+	}
+
+	// Исходный текст ошибки (в том числе подробности ошибок репозитория) — только в логе
+	st := toStatus(err)
+	entry = entry.WithFields(logrus.Fields{"code": st.Code().String(), "error": err.Error()})
+	if st.Code() == codes.Internal {
+		entry.Error("gRPC request")
+	} else {
+		entry.Info("gRPC request")
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,532 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	subscriptionv1 "github.com/ZnNr/subscription-service/pkg/api/subscription/v1"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// idempotentReplayedKey — ключ метаданных ответа, повторенного по ключу идемпотентности
+const idempotentReplayedKey = "idempotent-replayed"
+
+// Server реализует gRPC API поверх того же service.Service, что и REST API
+type Server struct {
+	subscriptionv1.UnimplementedSubscriptionServiceServer
+	service service.Service
+}
+
+func NewServer(svc service.Service) *Server {
+	return &Server{service: svc}
+}
+
+// NewGRPCServer создает gRPC-сервер с зарегистрированным API подписок и server reflection
+func NewGRPCServer(svc service.Service, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(StreamInterceptor()),
+	}, opts...)
+
+	server := grpc.NewServer(opts...)
+	subscriptionv1.RegisterSubscriptionServiceServer(server, NewServer(svc))
+	reflection.Register(server)
+
+	return server
+}
+
+// CreateSubscription создает подписку. Запрос с idempotency_key выполняется один раз:
+// повтор с тем же ключом и теми же данными получает сохраненный ответ.
+func (s *Server) CreateSubscription(ctx context.Context, req *subscriptionv1.CreateSubscriptionRequest) (*subscriptionv1.CreateSubscriptionResponse, error) {
+	sub, err := parseSubscriptionInput(uuid.New(), req.Subscription, "subscription.")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IdempotencyKey == "" {
+		return s.createSubscription(ctx, sub, req.Force)
+	}
+
+	fingerprint, err := createFingerprint(req)
+	if err != nil {
+		return nil, err
+	}
+
+	record, lease, err := s.service.BeginIdempotentRequest(ctx, req.IdempotencyKey, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if record != nil {
+		resp := &subscriptionv1.CreateSubscriptionResponse{}
+		if err := proto.Unmarshal(record.Body, resp); err != nil {
+			return nil, err
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(idempotentReplayedKey, "true"))
+		return resp, nil
+	}
+
+	resp, err := s.createSubscription(ctx, sub, req.Force)
+	if err != nil {
+		// Неудачный запрос не сохраняется: ключ освобождается для повтора
+		_ = s.service.FinishIdempotentRequest(ctx, req.IdempotencyKey, lease, http.StatusInternalServerError, nil, nil)
+		return nil, err
+	}
+
+	body, err := proto.Marshal(resp)
+	if err == nil {
+		err = s.service.FinishIdempotentRequest(ctx, req.IdempotencyKey, lease, http.StatusCreated, nil, body)
+	}
+	if err != nil {
+		_ = s.service.FinishIdempotentRequest(ctx, req.IdempotencyKey, lease, http.StatusInternalServerError, nil, nil)
+	}
+
+	return resp, nil
+}
+
+func (s *Server) createSubscription(ctx context.Context, sub *model.Subscription, force bool) (*subscriptionv1.CreateSubscriptionResponse, error) {
+	created, err := s.service.CreateSubscription(ctx, sub, model.WriteOptions{Force: force})
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionv1.CreateSubscriptionResponse{
+		Subscription: toSubscription(created.Subscription),
+		Warnings:     created.Warnings,
+	}, nil
+}
+
+// createFingerprint — хеш запроса на создание без ключа идемпотентности
+func createFingerprint(req *subscriptionv1.CreateSubscriptionRequest) (string, error) {
+	withoutKey := proto.Clone(req).(*subscriptionv1.CreateSubscriptionRequest)
+	withoutKey.IdempotencyKey = ""
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(withoutKey)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(subscriptionv1.SubscriptionService_CreateSubscription_FullMethodName + "\n"))
+	hash.Write(data)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *Server) GetSubscription(ctx context.Context, req *subscriptionv1.GetSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.service.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscription(sub), nil
+}
+
+func (s *Server) UpdateSubscription(ctx context.Context, req *subscriptionv1.UpdateSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := parseSubscriptionInput(id, req.Subscription, "subscription.")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.UpdateSubscription(ctx, sub, writeOptions(req.Force, req.IfMatchVersion)); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.service.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscription(updated), nil
+}
+
+// PatchSubscription изменяет поля подписки из update_mask
+func (s *Server) PatchSubscription(ctx context.Context, req *subscriptionv1.PatchSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := parseSubscriptionPatch(req.Subscription, req.UpdateMask.GetPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.service.PatchSubscription(ctx, id, patch, writeOptions(req.Force, req.IfMatchVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscription(sub), nil
+}
+
+func (s *Server) DeleteSubscription(ctx context.Context, req *subscriptionv1.DeleteSubscriptionRequest) (*emptypb.Empty, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteSubscription(ctx, id, writeOptions(false, req.IfMatchVersion)); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// batchModes — режимы выполнения пакета; по умолчанию atomic
+var batchModes = map[subscriptionv1.BatchMode]string{
+	subscriptionv1.BatchMode_BATCH_MODE_UNSPECIFIED: model.BatchModeAtomic,
+	subscriptionv1.BatchMode_BATCH_MODE_ATOMIC:      model.BatchModeAtomic,
+	subscriptionv1.BatchMode_BATCH_MODE_BEST_EFFORT: model.BatchModeBestEffort,
+}
+
+// BatchSubscriptions выполняет пакет операций. Если пакет отменен (atomic), статус ошибки
+// соответствует ошибке операции, а результаты операций передаются в подробностях статуса.
+func (s *Server) BatchSubscriptions(ctx context.Context, req *subscriptionv1.BatchSubscriptionsRequest) (*subscriptionv1.BatchSubscriptionsResponse, error) {
+	mode, ok := batchModes[req.Mode]
+	if !ok {
+		return nil, invalidField(codeInvalidParameter, "mode", "must be one of atomic, best_effort")
+	}
+
+	if len(req.Operations) == 0 {
+		return nil, invalidField(codeValidationFailed, "operations", "is required")
+	}
+
+	ops := make([]model.BatchOperation, 0, len(req.Operations))
+	for i, o := range req.Operations {
+		op, err := parseBatchOperation(i, o)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+
+	resp, err := s.service.Batch(ctx, ops, mode, model.WriteOptions{Force: req.Force})
+	if err != nil {
+		var batchErr *service.BatchError
+		if !errors.As(err, &batchErr) || service.KindOf(batchErr.Err) == service.KindInternal {
+			return nil, err
+		}
+		return nil, withDetails(toStatus(batchErr.Err), toBatchResponse(resp)).Err()
+	}
+
+	return toBatchResponse(resp), nil
+}
+
+func (s *Server) ListSubscriptions(ctx context.Context, req *subscriptionv1.ListSubscriptionsRequest) (*subscriptionv1.ListSubscriptionsResponse, error) {
+	filter, err := parseSubscriptionFilter(req.Filter, req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PageSize < 0 {
+		return nil, invalidField(codeInvalidParameter, "page_size", "must be a positive number")
+	}
+	filter.Limit = int(req.PageSize)
+	filter.Cursor = req.PageToken
+
+	page, err := s.service.ListSubscriptions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &subscriptionv1.ListSubscriptionsResponse{Subscriptions: toSubscriptions(page.Items)}
+	if page.NextCursor != nil {
+		resp.NextPageToken = *page.NextCursor
+	}
+
+	return resp, nil
+}
+
+// ExportSubscriptions передает потоком все подписки, подходящие под фильтр.
+// Без sort подписки упорядочены по updated_at, как в выгрузке NDJSON.
+func (s *Server) ExportSubscriptions(req *subscriptionv1.ExportSubscriptionsRequest, stream grpc.ServerStreamingServer[subscriptionv1.Subscription]) error {
+	filter, err := parseSubscriptionFilter(req.Filter, req.Sort)
+	if err != nil {
+		return err
+	}
+
+	filter.ByUpdate = len(filter.Sort) == 0
+
+	return s.service.ExportSubscriptions(stream.Context(), filter, func(sub *model.Subscription) error {
+		return stream.Send(toSubscription(sub))
+	})
+}
+
+// ImportSubscriptions импортирует подписки. Если импорт не выполнен, статус ошибки
+// соответствует её причине, а отчет по строкам передается в подробностях статуса.
+func (s *Server) ImportSubscriptions(ctx context.Context, req *subscriptionv1.ImportSubscriptionsRequest) (*subscriptionv1.ImportReport, error) {
+	if req.ResumeFrom < 0 {
+		return nil, invalidField(codeInvalidParameter, "resume_from", "must be a positive number")
+	}
+
+	rows := make([]model.ImportRow, 0, len(req.Rows))
+	for i, in := range req.Rows {
+		rows = append(rows, parseImportRow(i+1, in))
+	}
+
+	report, err := s.service.ImportSubscriptions(ctx, rows, model.ImportOptions{
+		DryRun:     req.DryRun,
+		ResumeFrom: int(req.ResumeFrom),
+		Force:      req.Force,
+	})
+	if err != nil {
+		if report == nil || service.KindOf(err) == service.KindInternal {
+			return nil, err
+		}
+		return nil, withDetails(toStatus(err), toImportReport(report)).Err()
+	}
+
+	return toImportReport(report), nil
+}
+
+func (s *Server) CalculateSummary(ctx context.Context, req *subscriptionv1.CalculateSummaryRequest) (*subscriptionv1.Summary, error) {
+	startDate, err := parseRequiredMonth("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseRequiredMonth("end_date", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := parseIDFilter("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	calculate := s.service.CalculateSummary
+	switch req.Source {
+	case subscriptionv1.SummarySource_SUMMARY_SOURCE_UNSPECIFIED, subscriptionv1.SummarySource_SUMMARY_SOURCE_SUBSCRIPTIONS:
+	case subscriptionv1.SummarySource_SUMMARY_SOURCE_LEDGER:
+		calculate = s.service.CalculateLedgerSummary
+	default:
+		return nil, invalidField(codeInvalidParameter, "source", "must be one of subscriptions, ledger")
+	}
+
+	summary, err := calculate(ctx, startDate, endDate, userID, req.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSummary(summary), nil
+}
+
+func (s *Server) ListOverlaps(ctx context.Context, _ *emptypb.Empty) (*subscriptionv1.ListOverlapsResponse, error) {
+	overlaps, err := s.service.ListOverlaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &subscriptionv1.ListOverlapsResponse{}
+	for _, o := range overlaps {
+		resp.Overlaps = append(resp.Overlaps, toOverlap(o))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) SetBudget(ctx context.Context, req *subscriptionv1.SetBudgetRequest) (*subscriptionv1.Budget, error) {
+	userID, err := parseOptionalID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if userID == uuid.Nil {
+		return nil, invalidField(codeValidationFailed, "user_id", "is required")
+	}
+
+	budget, err := s.service.SetBudget(ctx, &model.Budget{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Category:     req.Category,
+		MonthlyLimit: int(req.MonthlyLimit),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toBudget(budget), nil
+}
+
+func (s *Server) ListBudgets(ctx context.Context, req *subscriptionv1.ListBudgetsRequest) (*subscriptionv1.ListBudgetsResponse, error) {
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.service.ListBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &subscriptionv1.ListBudgetsResponse{}
+	for _, b := range budgets {
+		resp.Budgets = append(resp.Budgets, toBudget(b))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) DeleteBudget(ctx context.Context, req *subscriptionv1.DeleteBudgetRequest) (*emptypb.Empty, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteBudget(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) GetBudgetReport(ctx context.Context, req *subscriptionv1.GetBudgetReportRequest) (*subscriptionv1.GetBudgetReportResponse, error) {
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, err := parseRequiredMonth("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseRequiredMonth("end_date", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	months, err := s.service.BudgetReport(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &subscriptionv1.GetBudgetReportResponse{}
+	for _, m := range months {
+		resp.Months = append(resp.Months, toBudgetMonth(m))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ListCharges(ctx context.Context, req *subscriptionv1.ListChargesRequest) (*subscriptionv1.ListChargesResponse, error) {
+	var filter model.ChargeFilter
+	var err error
+
+	if filter.UserID, err = parseIDFilter("user_id", req.UserId); err != nil {
+		return nil, err
+	}
+	if filter.SubscriptionID, err = parseIDFilter("subscription_id", req.SubscriptionId); err != nil {
+		return nil, err
+	}
+	if filter.From, err = parseMonth("from", req.From); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseMonth("to", req.To); err != nil {
+		return nil, err
+	}
+	filter.ServiceName = req.ServiceName
+
+	resp := &subscriptionv1.ListChargesResponse{}
+	err = s.service.ExportCharges(ctx, filter, func(charge *model.Charge) error {
+		resp.Charges = append(resp.Charges, toCharge(charge))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *Server) RebuildCharges(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.service.RebuildCharges(ctx); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) CreateAdjustment(ctx context.Context, req *subscriptionv1.CreateAdjustmentRequest) (*subscriptionv1.Adjustment, error) {
+	subscriptionID, err := parseID("subscription_id", req.SubscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseRequiredMonth("date", req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Вид корректировки проверяет сервис; неизвестное значение передается пустым
+	adj, err := s.service.CreateAdjustment(ctx, &model.Adjustment{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Kind:           adjustmentKinds[req.Kind],
+		Amount:         int(req.Amount),
+		Date:           date,
+		Reason:         req.Reason,
+		CreatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toAdjustment(adj), nil
+}
+
+func (s *Server) ListAdjustments(ctx context.Context, req *subscriptionv1.ListAdjustmentsRequest) (*subscriptionv1.ListAdjustmentsResponse, error) {
+	subscriptionID, err := parseID("subscription_id", req.SubscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.service.ListAdjustments(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &subscriptionv1.ListAdjustmentsResponse{}
+	for _, a := range adjustments {
+		resp.Adjustments = append(resp.Adjustments, toAdjustment(a))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) DeleteAdjustment(ctx context.Context, req *subscriptionv1.DeleteAdjustmentRequest) (*emptypb.Empty, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteAdjustment(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
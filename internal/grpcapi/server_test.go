@@ -0,0 +1,391 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/internal/service"
+	subscriptionv1 "github.com/ZnNr/subscription-service/pkg/api/subscription/v1"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// stubService реализует только те методы service.Service, которые вызывают тесты
+type stubService struct {
+	service.Service
+	mock.Mock
+}
+
+func (m *stubService) CreateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) (*model.CreateSubscriptionResponse, error) {
+	args := m.Called(ctx, sub, opts)
+	resp, _ := args.Get(0).(*model.CreateSubscriptionResponse)
+	return resp, args.Error(1)
+}
+
+func (m *stubService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*model.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *stubService) PatchSubscription(ctx context.Context, id uuid.UUID, patch *model.SubscriptionPatch, opts model.WriteOptions) (*model.Subscription, error) {
+	args := m.Called(ctx, id, patch, opts)
+	sub, _ := args.Get(0).(*model.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *stubService) ExportSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error {
+	args := m.Called(ctx, filter)
+	for _, sub := range args.Get(0).([]*model.Subscription) {
+		if err := fn(sub); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *stubService) Batch(ctx context.Context, ops []model.BatchOperation, mode string, opts model.WriteOptions) (*model.BatchResponse, error) {
+	args := m.Called(ctx, ops, mode, opts)
+	resp, _ := args.Get(0).(*model.BatchResponse)
+	return resp, args.Error(1)
+}
+
+func (m *stubService) BeginIdempotentRequest(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, uuid.UUID, error) {
+	args := m.Called(ctx, key, fingerprint)
+	record, _ := args.Get(0).(*model.IdempotencyRecord)
+	return record, args.Get(1).(uuid.UUID), args.Error(2)
+}
+
+func (m *stubService) FinishIdempotentRequest(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error {
+	args := m.Called(ctx, key, lease, statusCode, headers, body)
+	return args.Error(0)
+}
+
+// setupTestClient запускает сервер в памяти и возвращает клиента к нему
+func setupTestClient(t *testing.T, svc service.Service) (subscriptionv1.SubscriptionServiceClient, *grpc.ClientConn) {
+	logger.Init("error", "json")
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(svc)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return subscriptionv1.NewSubscriptionServiceClient(conn), conn
+}
+
+func testSubscription() *model.Subscription {
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	return &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     &endDate,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+		Version:     1,
+	}
+}
+
+// errorInfo — ErrorInfo из подробностей статуса ошибки
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	require.Fail(t, "status has no ErrorInfo")
+	return nil
+}
+
+func TestGetSubscription(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	sub := testSubscription()
+	svc.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDKey, "req-1")
+	resp, err := client.GetSubscription(ctx, &subscriptionv1.GetSubscriptionRequest{Id: sub.ID.String()}, grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, sub.ID.String(), resp.Id)
+	assert.Equal(t, int64(599), resp.Price)
+	assert.Equal(t, &subscriptionv1.Month{Year: 2025, Month: 1}, resp.StartDate)
+	assert.Equal(t, int32(12), resp.EndDate.Month)
+	assert.Equal(t, []string{"req-1"}, header.Get(requestIDKey))
+}
+
+func TestErrorStatuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		reason  string
+		message string
+	}{
+		{name: "not found", err: service.ErrNotFound, code: codes.NotFound, reason: "subscription_not_found", message: service.ErrNotFound.Message},
+		{name: "already exists", err: repository.ErrAlreadyExists, code: codes.AlreadyExists, reason: "already_exists", message: "record already exists"},
+		{name: "overlap", err: service.ErrSubscriptionOverlap, code: codes.FailedPrecondition, reason: "subscription_overlap", message: service.ErrSubscriptionOverlap.Message},
+		{name: "in progress", err: service.ErrIdempotencyRequestInProgress, code: codes.Aborted, reason: "idempotency_request_in_progress", message: service.ErrIdempotencyRequestInProgress.Message},
+		{name: "precondition", err: service.ErrPreconditionFailed, code: codes.Aborted, reason: "precondition_failed", message: service.ErrPreconditionFailed.Message},
+		{name: "unprocessable", err: service.ErrIdempotencyKeyReused, code: codes.FailedPrecondition, reason: "idempotency_key_reused", message: service.ErrIdempotencyKeyReused.Message},
+		{name: "internal", err: errors.New("connection refused"), code: codes.Internal, reason: "internal_error", message: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(stubService)
+			client, _ := setupTestClient(t, svc)
+
+			id := uuid.New()
+			svc.On("GetSubscription", mock.Anything, id).Return(nil, tt.err)
+
+			_, err := client.GetSubscription(context.Background(), &subscriptionv1.GetSubscriptionRequest{Id: id.String()})
+
+			st := status.Convert(err)
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+			assert.Equal(t, tt.reason, errorInfo(t, err).Reason)
+		})
+	}
+}
+
+func TestCreateSubscription_InvalidRequest(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	_, err := client.CreateSubscription(context.Background(), &subscriptionv1.CreateSubscriptionRequest{
+		Subscription: &subscriptionv1.SubscriptionInput{
+			ServiceName: "Netflix",
+			Price:       599,
+			UserId:      uuid.NewString(),
+			StartDate:   &subscriptionv1.Month{Year: 2025, Month: 13},
+		},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, codeInvalidDateFormat, errorInfo(t, err).Reason)
+
+	var badRequest *errdetails.BadRequest
+	for _, detail := range status.Convert(err).Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = d
+		}
+	}
+	require.NotNil(t, badRequest)
+	assert.Equal(t, "subscription.start_date", badRequest.FieldViolations[0].Field)
+	svc.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateSubscription_OverlapConflicts(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	existing := testSubscription()
+	svc.On("CreateSubscription", mock.Anything, mock.Anything, model.WriteOptions{}).
+		Return(nil, &service.OverlapError{Overlaps: []*model.Subscription{existing}})
+
+	_, err := client.CreateSubscription(context.Background(), &subscriptionv1.CreateSubscriptionRequest{
+		Subscription: &subscriptionv1.SubscriptionInput{
+			ServiceName: "Netflix",
+			Price:       599,
+			UserId:      existing.UserID.String(),
+			StartDate:   &subscriptionv1.Month{Year: 2025, Month: 3},
+		},
+	})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	info := errorInfo(t, err)
+	assert.Equal(t, service.ErrSubscriptionOverlap.Code, info.Reason)
+	assert.Equal(t, existing.ID.String(), info.Metadata["conflicts"])
+}
+
+func TestCreateSubscription_IdempotentReplay(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	stored, err := proto.Marshal(&subscriptionv1.CreateSubscriptionResponse{
+		Subscription: &subscriptionv1.Subscription{Id: "stored"},
+	})
+	require.NoError(t, err)
+
+	svc.On("BeginIdempotentRequest", mock.Anything, "key-1", mock.AnythingOfType("string")).
+		Return(&model.IdempotencyRecord{StatusCode: 201, Body: stored}, uuid.Nil, nil)
+
+	var header metadata.MD
+	resp, err := client.CreateSubscription(context.Background(), &subscriptionv1.CreateSubscriptionRequest{
+		Subscription: &subscriptionv1.SubscriptionInput{
+			ServiceName: "Netflix",
+			Price:       599,
+			UserId:      uuid.NewString(),
+			StartDate:   &subscriptionv1.Month{Year: 2025, Month: 1},
+		},
+		IdempotencyKey: "key-1",
+	}, grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, "stored", resp.Subscription.Id)
+	assert.Equal(t, []string{"true"}, header.Get(idempotentReplayedKey))
+	svc.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateFingerprint_IgnoresKey(t *testing.T) {
+	req := &subscriptionv1.CreateSubscriptionRequest{
+		Subscription:   &subscriptionv1.SubscriptionInput{ServiceName: "Netflix", Price: 599},
+		IdempotencyKey: "a",
+	}
+	first, err := createFingerprint(req)
+	require.NoError(t, err)
+
+	req.IdempotencyKey = "b"
+	second, err := createFingerprint(req)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	req.Subscription.Price = 600
+	third, err := createFingerprint(req)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, third)
+}
+
+func TestPatchSubscription_UpdateMask(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	sub := testSubscription()
+	version := int32(1)
+	svc.On("PatchSubscription", mock.Anything, sub.ID, &model.SubscriptionPatch{
+		Price:     model.Optional[int]{Set: true, Value: 799},
+		StartDate: model.Optional[time.Time]{Set: true, Value: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		EndDate:   model.Optional[time.Time]{Set: true, Null: true},
+	}, model.WriteOptions{IfMatch: intPtr(1)}).Return(sub, nil)
+
+	_, err := client.PatchSubscription(context.Background(), &subscriptionv1.PatchSubscriptionRequest{
+		Id: sub.ID.String(),
+		Subscription: &subscriptionv1.SubscriptionInput{
+			Price:       799,
+			ServiceName: "ignored",
+			StartDate:   &subscriptionv1.Month{Year: 2025, Month: 2},
+		},
+		// end_date в маске без значения очищается, service_name вне маски не меняется
+		UpdateMask:     &fieldmaskpb.FieldMask{Paths: []string{"price", "start_date", "end_date"}},
+		IfMatchVersion: &version,
+	})
+
+	require.NoError(t, err)
+	svc.AssertExpectations(t)
+
+	_, err = client.PatchSubscription(context.Background(), &subscriptionv1.PatchSubscriptionRequest{
+		Id:         sub.ID.String(),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, codeInvalidParameter, errorInfo(t, err).Reason)
+}
+
+func TestBatchSubscriptions_AtomicFailureCarriesResults(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	id := uuid.New()
+	svc.On("Batch", mock.Anything, mock.Anything, model.BatchModeAtomic, model.WriteOptions{}).Return(&model.BatchResponse{
+		Results: []*model.BatchItemResult{{Index: 0, Op: model.BatchOpDelete, ID: id, Status: model.BatchStatusFailed, Code: "subscription_not_found"}},
+	}, &service.BatchError{Index: 0, Err: service.ErrNotFound})
+
+	_, err := client.BatchSubscriptions(context.Background(), &subscriptionv1.BatchSubscriptionsRequest{
+		Operations: []*subscriptionv1.BatchOperation{{Op: subscriptionv1.BatchOperationType_BATCH_OPERATION_TYPE_DELETE, Id: id.String()}},
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	var resp *subscriptionv1.BatchSubscriptionsResponse
+	for _, detail := range status.Convert(err).Details() {
+		if d, ok := detail.(*subscriptionv1.BatchSubscriptionsResponse); ok {
+			resp = d
+		}
+	}
+	require.NotNil(t, resp)
+	assert.Equal(t, subscriptionv1.BatchItemStatus_BATCH_ITEM_STATUS_FAILED, resp.Results[0].Status)
+}
+
+func TestExportSubscriptions(t *testing.T) {
+	svc := new(stubService)
+	client, _ := setupTestClient(t, svc)
+
+	first, second := testSubscription(), testSubscription()
+	svc.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{
+		Status:   model.SubscriptionStatusActive,
+		ByUpdate: true,
+	}).Return([]*model.Subscription{first, second}, nil)
+
+	stream, err := client.ExportSubscriptions(context.Background(), &subscriptionv1.ExportSubscriptionsRequest{
+		Filter: &subscriptionv1.SubscriptionFilter{Status: subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE},
+	})
+	require.NoError(t, err)
+
+	var ids []string
+	for {
+		sub, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, sub.Id)
+	}
+
+	assert.Equal(t, []string{first.ID.String(), second.ID.String()}, ids)
+}
+
+func TestReflection(t *testing.T) {
+	_, conn := setupTestClient(t, new(stubService))
+
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}))
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, s := range resp.GetListServicesResponse().Service {
+		services = append(services, s.Name)
+	}
+	assert.Contains(t, services, subscriptionv1.SubscriptionService_ServiceDesc.ServiceName)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// CreateAdjustment записывает корректировку по подписке
+// @Summary Добавить корректировку
+// @Description Записывает возврат (refund), кредит (credit) или разовое списание (charge) по подписке. Возвраты и кредиты уменьшают сумму в отчетах, разовые списания — увеличивают
+// @Tags adjustments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки"
+// @Param input body model.CreateAdjustmentRequest true "Данные корректировки"
+// @Success 201 {object} model.Adjustment
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Router /subscriptions/{id}/adjustments [post]
+func (h *Handler) CreateAdjustment(c *gin.Context) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	var req model.CreateAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBindError(c, err)
+		return
+	}
+
+	date, err := parseMonthYear(req.Date)
+	if err != nil {
+		RespondInvalid(c, codeInvalidDateFormat, "invalid date format, expected MM-YYYY")
+		return
+	}
+
+	adj, err := h.service.CreateAdjustment(c.Request.Context(), &model.Adjustment{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Kind:           req.Kind,
+		Amount:         req.Amount,
+		Date:           date,
+		Reason:         req.Reason,
+		CreatedAt:      time.Now().UTC(),
+	})
+
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, adj)
+}
+
+// ListAdjustments возвращает корректировки подписки
+// @Summary Список корректировок
+// @Description Возвращает возвраты, кредиты и разовые списания по подписке
+// @Tags adjustments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки"
+// @Success 200 {array} model.Adjustment
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Router /subscriptions/{id}/adjustments [get]
+func (h *Handler) ListAdjustments(c *gin.Context) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	adjustments, err := h.service.ListAdjustments(c.Request.Context(), subscriptionID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if adjustments == nil {
+		adjustments = []*model.Adjustment{}
+	}
+
+	c.JSON(http.StatusOK, adjustments)
+}
+
+// DeleteAdjustment удаляет корректировку
+// @Summary Удалить корректировку
+// @Description Удаляет корректировку по её ID
+// @Tags adjustments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID корректировки"
+// @Success 204 "Корректировка удалена"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Корректировка не найдена"
+// @Router /adjustments/{id} [delete]
+func (h *Handler) DeleteAdjustment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondInvalid(c, codeInvalidID, "invalid adjustment id")
+		return
+	}
+
+	if err := h.service.DeleteAdjustment(c.Request.Context(), id); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAdjustmentHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	subID := uuid.New()
+	requestBody := map[string]interface{}{
+		"kind":   "refund",
+		"amount": 599,
+		"date":   "03-2025",
+		"reason": "service outage",
+	}
+
+	mockService.On("CreateAdjustment", mock.Anything, mock.MatchedBy(func(adj *model.Adjustment) bool {
+		return adj.SubscriptionID == subID && adj.Kind == model.AdjustmentRefund &&
+			adj.Date.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(&model.Adjustment{ID: uuid.New(), SubscriptionID: subID, Kind: "refund", Amount: 599}, nil)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/"+subID.String()+"/adjustments", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateAdjustmentHandler_InvalidKind(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	requestBody := map[string]interface{}{
+		"kind":   "bonus",
+		"amount": 100,
+		"date":   "03-2025",
+		"reason": "x",
+	}
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/"+uuid.New().String()+"/adjustments", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateAdjustment", mock.Anything, mock.Anything)
+}
+
+func TestCreateAdjustmentHandler_SubscriptionNotFound(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	requestBody := map[string]interface{}{
+		"kind":   "credit",
+		"amount": 100,
+		"date":   "03-2025",
+		"reason": "goodwill",
+	}
+
+	mockService.On("CreateAdjustment", mock.Anything, mock.Anything).Return(nil, service.ErrNotFound)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/"+uuid.New().String()+"/adjustments", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteAdjustmentHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	adjID := uuid.New()
+	mockService.On("DeleteAdjustment", mock.Anything, adjID).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/adjustments/"+adjID.String(), nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// BatchSubscriptions выполняет пакет операций над подписками
+// @Summary Пакетное изменение подписок
+// @Description Выполняет операции create/update/delete в одной транзакции. В режиме atomic (по умолчанию) ошибка любой операции
+// @Description отменяет весь пакет: ответ — application/problem+json со статусом и кодом ошибки этой операции, результаты
+// @Description всех операций — в поле results. В режиме best_effort неудачные операции откатываются
+// @Description по отдельности, остальные сохраняются; ответ 200 с результатом каждой операции
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body model.BatchRequest true "Операции"
+// @Param force query bool false "Сохранять подписки, несмотря на пересечения с существующими"
+// @Success 200 {object} model.BatchResponse
+// @Failure 400 {object} model.Problem "Неверный запрос или неверные данные операции (atomic)"
+// @Failure 404 {object} model.Problem "Подписка операции не найдена (atomic)"
+// @Failure 409 {object} model.Problem "Подписка операции пересекается с существующей (atomic)"
+// @Failure 412 {object} model.Problem "Версия подписки не совпала с version операции (atomic)"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /subscriptions/batch [post]
+func (h *Handler) BatchSubscriptions(c *gin.Context) {
+	opts, err := ParseWriteOptions(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	var req model.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBindError(c, err)
+		return
+	}
+
+	ops := make([]model.BatchOperation, 0, len(req.Operations))
+	for i, o := range req.Operations {
+		op, err := parseBatchOperation(o)
+		if err != nil {
+			RespondInvalid(c, service.ErrInvalidBatchOperation.Code, fmt.Sprintf("operations[%d]: %s", i, err))
+			return
+		}
+		ops = append(ops, op)
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = model.BatchModeAtomic
+	}
+
+	resp, err := h.service.Batch(c.Request.Context(), ops, mode, opts)
+	if err != nil {
+		var batchErr *service.BatchError
+		if !errors.As(err, &batchErr) {
+			RespondError(c, err)
+			return
+		}
+		problem := errorProblem(c, batchErr.Err)
+		problem.Detail = fmt.Sprintf("operations[%d]: %s", batchErr.Index, problem.Detail)
+		problem.Results = resp.Results
+		writeProblem(c, problem)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseBatchOperation проверяет состав полей операции и разбирает её данные
+func parseBatchOperation(o model.BatchOperationRequest) (model.BatchOperation, error) {
+	op := model.BatchOperation{Op: o.Op, Version: o.Version}
+
+	if o.Op == model.BatchOpCreate {
+		op.ID = uuid.New()
+	} else {
+		if o.ID == nil {
+			return op, errors.New("id is required")
+		}
+		op.ID = *o.ID
+	}
+
+	if o.Op == model.BatchOpDelete {
+		return op, nil
+	}
+
+	if o.Data == nil {
+		return op, errors.New("data is required")
+	}
+
+	startDate, err := parseMonthYear(o.Data.StartDate)
+	if err != nil {
+		return op, errors.New("invalid start_date format, expected MM-YYYY")
+	}
+
+	var endDate *time.Time
+	if o.Data.EndDate != nil {
+		ed, err := parseMonthYear(*o.Data.EndDate)
+		if err != nil {
+			return op, errors.New("invalid end_date format, expected MM-YYYY")
+		}
+		endDate = &ed
+	}
+
+	op.Subscription = &model.Subscription{
+		ID:          op.ID,
+		ServiceName: o.Data.ServiceName,
+		Price:       o.Data.Price,
+		UserID:      o.Data.UserID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    o.Data.Category,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	return op, nil
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSubscriptionsHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	userID := uuid.New()
+	updatedID := uuid.New()
+	deletedID := uuid.New()
+
+	body := map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "create", "data": map[string]interface{}{
+				"service_name": "Netflix", "price": 599, "user_id": userID.String(), "start_date": "01-2025",
+			}},
+			{"op": "update", "id": updatedID.String(), "data": map[string]interface{}{
+				"service_name": "Spotify", "price": 299, "user_id": userID.String(), "start_date": "02-2025", "end_date": "12-2025",
+			}},
+			{"op": "delete", "id": deletedID.String()},
+		},
+	}
+
+	mockService.On("Batch", mock.Anything, mock.MatchedBy(func(ops []model.BatchOperation) bool {
+		return len(ops) == 3 &&
+			ops[0].Op == model.BatchOpCreate && ops[0].ID != uuid.Nil && ops[0].Subscription.ID == ops[0].ID &&
+			ops[1].ID == updatedID && ops[1].Subscription.EndDate.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) &&
+			ops[2].ID == deletedID && ops[2].Subscription == nil
+	}), model.BatchModeAtomic, model.WriteOptions{}).Return(&model.BatchResponse{
+		Committed: true,
+		Results: []*model.BatchItemResult{
+			{Index: 0, Op: "create", Status: model.BatchStatusOK},
+			{Index: 1, Op: "update", ID: updatedID, Status: model.BatchStatusOK},
+			{Index: 2, Op: "delete", ID: deletedID, Status: model.BatchStatusOK},
+		},
+	}, nil)
+
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/batch", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.BatchResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Committed)
+	assert.Len(t, response.Results, 3)
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchSubscriptionsHandler_AtomicFailure(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	deletedID := uuid.New()
+	mockService.On("Batch", mock.Anything, mock.Anything, model.BatchModeAtomic, model.WriteOptions{}).
+		Return(&model.BatchResponse{
+			Committed: false,
+			Results: []*model.BatchItemResult{
+				{Index: 0, Op: "delete", ID: deletedID, Status: model.BatchStatusFailed, Error: service.ErrNotFound.Error()},
+			},
+		}, &service.BatchError{Index: 0, Err: service.ErrNotFound})
+
+	body := `{"operations": [{"op": "delete", "id": "` + deletedID.String() + `"}]}`
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem model.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, service.ErrNotFound.Code, problem.Code)
+	assert.Equal(t, "operations[0]: "+service.ErrNotFound.Message, problem.Detail)
+	require.Len(t, problem.Results, 1)
+	assert.Equal(t, model.BatchStatusFailed, problem.Results[0].Status)
+}
+
+func TestBatchSubscriptionsHandler_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"No operations", `{"operations": []}`},
+		{"Unknown op", `{"operations": [{"op": "upsert"}]}`},
+		{"Unknown mode", `{"mode": "partial", "operations": [{"op": "delete", "id": "` + uuid.New().String() + `"}]}`},
+		{"Delete without id", `{"operations": [{"op": "delete"}]}`},
+		{"Create without data", `{"operations": [{"op": "create"}]}`},
+		{"Invalid date", `{"operations": [{"op": "create", "data": {"service_name": "Netflix", "price": 599, "user_id": "` +
+			uuid.New().String() + `", "start_date": "2025-01"}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService)
+			router := setupTestRouter(handler)
+
+			req, _ := http.NewRequest("POST", "/api/v1/subscriptions/batch", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "Batch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// SetBudget создает или обновляет бюджет пользователя
+// @Summary Задать бюджет
+// @Description Задает месячный лимит расходов пользователя на подписки (общий или для категории). Повторный вызов для той же категории обновляет лимит
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param input body model.SetBudgetRequest true "Параметры бюджета"
+// @Success 200 {object} model.Budget
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /budgets [post]
+func (h *Handler) SetBudget(c *gin.Context) {
+	var req model.SetBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBindError(c, err)
+		return
+	}
+
+	budget, err := h.service.SetBudget(c.Request.Context(), &model.Budget{
+		ID:           uuid.New(),
+		UserID:       req.UserID,
+		Category:     req.Category,
+		MonthlyLimit: req.MonthlyLimit,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	})
+
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// ListBudgets возвращает бюджеты пользователя
+// @Summary Список бюджетов
+// @Description Возвращает все бюджеты пользователя
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param user_id query string true "ID пользователя"
+// @Success 200 {array} model.Budget
+// @Failure 400 {object} model.Problem "Неверный ID пользователя"
+// @Router /budgets [get]
+func (h *Handler) ListBudgets(c *gin.Context) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, "invalid user_id")
+		return
+	}
+
+	budgets, err := h.service.ListBudgets(c.Request.Context(), userID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if budgets == nil {
+		budgets = []*model.Budget{}
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// DeleteBudget удаляет бюджет
+// @Summary Удалить бюджет
+// @Description Удаляет бюджет по его ID
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "ID бюджета"
+// @Success 204 "Бюджет удален"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Бюджет не найден"
+// @Router /budgets/{id} [delete]
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondInvalid(c, codeInvalidID, "invalid budget id")
+		return
+	}
+
+	if err := h.service.DeleteBudget(c.Request.Context(), id); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// BudgetReport сравнивает помесячные расходы с бюджетами
+// @Summary Отчет по бюджетам
+// @Description Для каждого месяца периода и каждого бюджета пользователя возвращает фактические расходы и признак превышения лимита
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param input body model.BudgetReportRequest true "Параметры отчета"
+// @Success 200 {array} model.BudgetMonth
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Router /budgets/report [post]
+func (h *Handler) BudgetReport(c *gin.Context) {
+	var req model.BudgetReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBindError(c, err)
+		return
+	}
+
+	startDate, err := parseMonthYear(req.StartDate)
+	if err != nil {
+		RespondInvalid(c, codeInvalidDateFormat, "invalid start_date format, expected MM-YYYY")
+		return
+	}
+
+	endDate, err := parseMonthYear(req.EndDate)
+	if err != nil {
+		RespondInvalid(c, codeInvalidDateFormat, "invalid end_date format, expected MM-YYYY")
+		return
+	}
+
+	report, err := h.service.BudgetReport(c.Request.Context(), req.UserID, startDate, endDate)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetBudgetHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	userID := uuid.New()
+	category := "streaming"
+	requestBody := map[string]interface{}{
+		"user_id":       userID.String(),
+		"category":      category,
+		"monthly_limit": 1500,
+	}
+
+	mockService.On("SetBudget", mock.Anything, mock.MatchedBy(func(b *model.Budget) bool {
+		return b.UserID == userID && *b.Category == category && b.MonthlyLimit == 1500
+	})).Return(&model.Budget{ID: uuid.New(), UserID: userID, Category: &category, MonthlyLimit: 1500}, nil)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/budgets", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.Budget
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1500, response.MonthlyLimit)
+	mockService.AssertExpectations(t)
+}
+
+func TestListBudgetsHandler_InvalidUserID(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/budgets?user_id=not-a-uuid", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListBudgets", mock.Anything, mock.Anything)
+}
+
+func TestDeleteBudgetHandler_NotFound(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	budgetID := uuid.New()
+	mockService.On("DeleteBudget", mock.Anything, budgetID).Return(service.ErrBudgetNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/budgets/"+budgetID.String(), nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBudgetReportHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	userID := uuid.New()
+	requestBody := map[string]interface{}{
+		"user_id":    userID.String(),
+		"start_date": "01-2025",
+		"end_date":   "03-2025",
+	}
+
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockService.On("BudgetReport", mock.Anything, userID, startDate, endDate).Return([]*model.BudgetMonth{
+		{Month: startDate, MonthlyLimit: 1000, Actual: 1200, OverBudget: true},
+	}, nil)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/budgets/report", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []model.BudgetMonth
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.True(t, response[0].OverBudget)
+	mockService.AssertExpectations(t)
+}
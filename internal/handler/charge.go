@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+// ListCharges возвращает журнал ожидаемых списаний
+// @Summary Журнал списаний
+// @Description Возвращает помесячные списания, сгенерированные из подписок, с возможностью фильтрации и постраничной выдачи.
+// @Description Если передан limit или cursor, ответ — объект {items, next_cursor}. Без них ответ — массив всех подходящих
+// @Description строк (режим совместимости); для большого журнала используйте limit и cursor.
+// @Tags charges
+// @Accept json
+// @Produce json
+// @Param user_id query string false "ID пользователя"
+// @Param subscription_id query string false "ID подписки"
+// @Param service_name query string false "Название сервиса"
+// @Param from query string false "Начальный месяц (MM-YYYY)"
+// @Param to query string false "Конечный месяц (MM-YYYY)"
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 500)"
+// @Param cursor query string false "Курсор из next_cursor предыдущей страницы"
+// @Success 200 {object} model.ChargePage
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Router /charges [get]
+func (h *Handler) ListCharges(c *gin.Context) {
+	var filter model.ChargeFilter
+
+	if uid := c.Query("user_id"); uid != "" {
+		parsed, err := uuid.Parse(uid)
+		if err != nil {
+			RespondInvalid(c, codeInvalidParameter, "invalid user_id")
+			return
+		}
+		filter.UserID = &parsed
+	}
+
+	if sid := c.Query("subscription_id"); sid != "" {
+		parsed, err := uuid.Parse(sid)
+		if err != nil {
+			RespondInvalid(c, codeInvalidParameter, "invalid subscription_id")
+			return
+		}
+		filter.SubscriptionID = &parsed
+	}
+
+	if sn := c.Query("service_name"); sn != "" {
+		filter.ServiceName = &sn
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, err := parseMonthYear(from)
+		if err != nil {
+			RespondInvalid(c, codeInvalidDateFormat, "invalid from format, expected MM-YYYY")
+			return
+		}
+		filter.From = &parsed
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, err := parseMonthYear(to)
+		if err != nil {
+			RespondInvalid(c, codeInvalidDateFormat, "invalid to format, expected MM-YYYY")
+			return
+		}
+		filter.To = &parsed
+	}
+
+	limit, hasLimit := c.GetQuery("limit")
+	cursor, hasCursor := c.GetQuery("cursor")
+
+	// Старые клиенты ожидают массив всех строк журнала, как до постраничной выдачи
+	if !hasLimit && !hasCursor {
+		writeJSONArray(c, func(write func(*model.Charge) error) error {
+			return h.service.ExportCharges(c.Request.Context(), filter, write)
+		})
+		return
+	}
+
+	if hasLimit {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 {
+			RespondInvalid(c, codeInvalidParameter, "invalid limit, expected positive integer")
+			return
+		}
+		filter.Limit = parsed
+	}
+	filter.Cursor = cursor
+
+	page, err := h.service.ListCharges(c.Request.Context(), filter)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// RebuildCharges пересоздает журнал списаний
+// @Summary Пересоздать журнал списаний
+// @Description Пересоздает журнал списаний по всем подпискам и продлевает горизонт бессрочных подписок
+// @Tags charges
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.MessageResponse "Журнал пересоздан"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /charges/rebuild [post]
+func (h *Handler) RebuildCharges(c *gin.Context) {
+	if err := h.service.RebuildCharges(c.Request.Context()); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.MessageResponse{Message: "charges rebuilt"})
+}
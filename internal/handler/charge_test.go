@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListChargesHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	userID := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockService.On("ExportCharges", mock.Anything, model.ChargeFilter{UserID: &userID, From: &from, To: &to}, mock.Anything).
+		Return([]*model.Charge{
+			{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", Month: from, Amount: 599},
+		}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/charges?user_id="+userID.String()+"&from=01-2025&to=03-2025", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []model.Charge
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, 599, response[0].Amount)
+	mockService.AssertExpectations(t)
+}
+
+func TestListChargesHandler_Paginated(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	next := "next-page"
+	mockService.On("ListCharges", mock.Anything, model.ChargeFilter{Limit: 1, Cursor: "page"}).
+		Return(&model.ChargePage{
+			Items:      []*model.Charge{{ID: uuid.New(), ServiceName: "Netflix", Amount: 599}},
+			NextCursor: &next,
+		}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/charges?limit=1&cursor=page", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.ChargePage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, next, *response.NextCursor)
+	mockService.AssertNotCalled(t, "ExportCharges", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListChargesHandler_InvalidLimit(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/charges?limit=0", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListCharges", mock.Anything, mock.Anything)
+}
+
+func TestListChargesHandler_InvalidMonth(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/charges?from=2025-01", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListCharges", mock.Anything, mock.Anything)
+}
+
+func TestCalculateSummaryHandler_LedgerSource(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	requestBody := map[string]interface{}{
+		"start_date": "01-2025",
+		"end_date":   "12-2025",
+		"source":     "ledger",
+	}
+
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	mockService.On("CalculateLedgerSummary", mock.Anything, startDate, endDate, (*uuid.UUID)(nil), (*string)(nil)).
+		Return(&model.SummaryResponse{TotalAmount: 7188, Count: 1}, nil)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/summary", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertNotCalled(t, "CalculateSummary", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertExpectations(t)
+}
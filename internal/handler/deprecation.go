@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// Deprecation помечает ответы устаревшей версии API: заголовок Deprecation (RFC 9745) —
+// дата, с которой версия устарела, Sunset (RFC 8594) — дата, после которой её могут
+// отключить, Link — адрес версии, которая её заменяет
+func Deprecation(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", link)
+		c.Next()
+	}
+}
@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/export"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// subscriptionColumns — столбцы выгрузки списка подписок
+var subscriptionColumns = []export.Column[*model.Subscription]{
+	{Key: "id", Titles: map[string]string{"en": "ID", "ru": "ID"}, Value: func(s *model.Subscription) any { return s.ID }},
+	{Key: "service_name", Titles: map[string]string{"en": "Service", "ru": "Сервис"}, Value: func(s *model.Subscription) any { return s.ServiceName }},
+	{Key: "price", Titles: map[string]string{"en": "Price", "ru": "Стоимость"}, Value: func(s *model.Subscription) any { return s.Price }},
+	{Key: "user_id", Titles: map[string]string{"en": "User ID", "ru": "ID пользователя"}, Value: func(s *model.Subscription) any { return s.UserID }},
+	{Key: "start_date", Titles: map[string]string{"en": "Start date", "ru": "Дата начала"}, Value: func(s *model.Subscription) any { return s.StartDate }},
+	{Key: "end_date", Titles: map[string]string{"en": "End date", "ru": "Дата окончания"}, Value: func(s *model.Subscription) any { return s.EndDate }},
+	{Key: "category", Titles: map[string]string{"en": "Category", "ru": "Категория"}, Value: func(s *model.Subscription) any { return s.Category }},
+	{Key: "created_at", Titles: map[string]string{"en": "Created", "ru": "Создана"}, Value: func(s *model.Subscription) any { return s.CreatedAt }},
+	{Key: "updated_at", Titles: map[string]string{"en": "Updated", "ru": "Изменена"}, Value: func(s *model.Subscription) any { return s.UpdatedAt }},
+	{Key: "version", Titles: map[string]string{"en": "Version", "ru": "Версия"}, Value: func(s *model.Subscription) any { return s.Version }},
+}
+
+var defaultSubscriptionColumns = []string{"id", "service_name", "price", "user_id", "start_date", "end_date", "category"}
+
+// summaryRow — строка выгрузки сводки: параметры расчета и результат
+type summaryRow struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Request   *model.SummaryRequest
+	Summary   *model.SummaryResponse
+}
+
+// summaryColumns — столбцы выгрузки сводки
+var summaryColumns = []export.Column[*summaryRow]{
+	{Key: "start_date", Titles: map[string]string{"en": "Period start", "ru": "Начало периода"}, Value: func(r *summaryRow) any { return r.StartDate }},
+	{Key: "end_date", Titles: map[string]string{"en": "Period end", "ru": "Конец периода"}, Value: func(r *summaryRow) any { return r.EndDate }},
+	{Key: "user_id", Titles: map[string]string{"en": "User ID", "ru": "ID пользователя"}, Value: func(r *summaryRow) any {
+		if r.Request.UserID == nil {
+			return nil
+		}
+		return *r.Request.UserID
+	}},
+	{Key: "service_name", Titles: map[string]string{"en": "Service", "ru": "Сервис"}, Value: func(r *summaryRow) any { return r.Request.ServiceName }},
+	{Key: "source", Titles: map[string]string{"en": "Source", "ru": "Источник"}, Value: func(r *summaryRow) any {
+		if r.Request.Source == "" {
+			return "subscriptions"
+		}
+		return r.Request.Source
+	}},
+	{Key: "total_amount", Titles: map[string]string{"en": "Total amount", "ru": "Итого"}, Value: func(r *summaryRow) any { return r.Summary.TotalAmount }},
+	{Key: "adjustments_amount", Titles: map[string]string{"en": "Adjustments", "ru": "Корректировки"}, Value: func(r *summaryRow) any { return r.Summary.AdjustmentsAmount }},
+	{Key: "count", Titles: map[string]string{"en": "Count", "ru": "Количество"}, Value: func(r *summaryRow) any { return r.Summary.Count }},
+}
+
+var defaultSummaryColumns = []string{"start_date", "end_date", "total_amount", "adjustments_amount", "count"}
+
+// exportFormat выбирает формат ответа: параметр format или заголовок Accept (первый из
+// поддерживаемых типов). ok == false — ответ в JSON, как обычно.
+func exportFormat(c *gin.Context) (format export.Format, ok bool, err error) {
+	if value := c.Query("format"); value != "" {
+		if strings.EqualFold(value, "json") {
+			return "", false, nil
+		}
+		format, err := export.ParseFormat(value)
+		return format, err == nil, err
+	}
+
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" {
+			return "", false, nil
+		}
+		if format, ok := export.FormatForMIMEType(mediaType); ok {
+			return format, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// exportLocale — локаль выгрузки из параметра locale или заголовка Accept-Language (по умолчанию en)
+func exportLocale(c *gin.Context) (export.Locale, error) {
+	if value := c.Query("locale"); value != "" {
+		return export.ParseLocale(value)
+	}
+
+	language, _, _ := strings.Cut(c.GetHeader("Accept-Language"), ",")
+	language, _, _ = strings.Cut(language, ";")
+	if locale, err := export.ParseLocale(strings.TrimSpace(language)); err == nil {
+		return locale, nil
+	}
+
+	return export.LocaleEN, nil
+}
+
+// exportResponseWriter задает заголовки выгрузки при первой записи и сразу отправляет
+// записанное клиенту. Пока ничего не записано, на ошибку можно ответить problem+json.
+type exportResponseWriter struct {
+	c          *gin.Context
+	controller *http.ResponseController
+	format     export.Format
+	filename   string
+	started    bool
+}
+
+// extendDeadline продлевает срок записи ответа, чтобы общий WriteTimeout сервера не оборвал
+// большую выгрузку. Не все ResponseWriter поддерживают дедлайны (например, в тестах) — тогда
+// срок не продлевается.
+func (w *exportResponseWriter) extendDeadline() {
+	_ = w.controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+}
+
+func (w *exportResponseWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.format.ContentType())
+		w.c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": w.filename + "." + string(w.format),
+		}))
+		w.c.Status(http.StatusOK)
+	}
+
+	w.extendDeadline()
+	n, err := w.c.Writer.Write(data)
+	w.c.Writer.Flush()
+	return n, err
+}
+
+// writeExport выгружает строки, которые produce передает в write, в формате format.
+// Столбцы выбираются параметром columns (по умолчанию — defaults), оформление — локалью.
+func writeExport[T any](c *gin.Context, format export.Format, all []export.Column[T], defaults []string, filename string, produce func(write func(T) error) error) {
+	columns, err := export.SelectColumns(all, c.Query("columns"), defaults)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error()+", expected "+strings.Join(export.ColumnKeys(all), ", "))
+		return
+	}
+
+	locale, err := exportLocale(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	out := &exportResponseWriter{c: c, controller: http.NewResponseController(c.Writer), format: format, filename: filename}
+	table, err := export.NewTable(out, format, locale, columns)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	// XLSX ничего не пишет до Close, поэтому срок продлевается и на каждой прочитанной строке
+	err = produce(func(item T) error {
+		out.extendDeadline()
+		return table.Write(item)
+	})
+	if err == nil {
+		err = table.Close()
+	}
+
+	if err != nil {
+		if !out.started {
+			if errors.Is(err, export.ErrTooManyRows) {
+				RespondProblem(c, http.StatusUnprocessableEntity, codeExportTooLarge, err.Error())
+				return
+			}
+			RespondError(c, err)
+			return
+		}
+		// Часть выгрузки уже отправлена: статус не изменить, ошибка попадает в лог
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// writeJSONArray отправляет элементы, которые produce передает в write, JSON-массивом по мере
+// чтения, продлевая срок записи перед каждым элементом. Пока ничего не отправлено, на ошибку
+// можно ответить problem+json; после этого массив обрывается, а ошибка попадает в лог.
+func writeJSONArray[T any](c *gin.Context, produce func(write func(T) error) error) {
+	controller := http.NewResponseController(c.Writer)
+	started := false
+
+	err := produce(func(item T) error {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		separator := ","
+		if !started {
+			started = true
+			separator = "["
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.Status(http.StatusOK)
+		}
+
+		// Не все ResponseWriter поддерживают дедлайны (например, в тестах) — тогда срок не продлевается
+		_ = controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		if _, err := c.Writer.WriteString(separator); err != nil {
+			return err
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+
+	if err != nil {
+		if !started {
+			RespondError(c, err)
+			return
+		}
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+
+	if !started {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte("[]"))
+		return
+	}
+	_, _ = c.Writer.WriteString("]")
+}
+
+// ndjsonContentType — тип потоковой выгрузки: по одному JSON-объекту на строку
+const ndjsonContentType = "application/x-ndjson"
+
+// exportWriteTimeout — сколько сервер ждет отправки очередной записи выгрузки (NDJSON, CSV, XLSX)
+// и списка подписок без постраничной выдачи.
+// Срок продлевается перед каждой записью, поэтому общий WriteTimeout сервера выгрузку не обрывает.
+const exportWriteTimeout = 30 * time.Second
+
+// ExportSubscriptions выгружает подписки потоком в формате NDJSON
+// @Summary Потоковая выгрузка подписок (NDJSON)
+// @Description Отдает все подписки, подходящие под фильтры, по одному JSON-объекту на строку (application/x-ndjson).
+// @Description Строки читаются из базы курсором и отправляются клиенту по мере чтения, не накапливаясь в памяти сервера.
+// @Description Подписки упорядочены по updated_at, затем по id. Для инкрементальной выгрузки передайте в updated_since
+// @Description наибольший updated_at из предыдущей выгрузки: граница включается, поэтому записи на ней придут повторно.
+// @Tags subscriptions
+// @Produce application/x-ndjson
+// @Param updated_since query string false "Только подписки, измененные в этот момент или позже (RFC 3339, например 2025-01-31T00:00:00Z)"
+// @Param user_id query string false "ID пользователя для фильтрации"
+// @Param service_name query []string false "Название сервиса; можно передать несколько раз" collectionFormat(multi)
+// @Param q query string false "Подстрока названия сервиса или категории без учета регистра"
+// @Param active_at query string false "Месяц, в котором подписка действует (MM-YYYY)"
+// @Param price_min query int false "Минимальная цена"
+// @Param price_max query int false "Максимальная цена"
+// @Param start_from query string false "Начало не раньше месяца (MM-YYYY)"
+// @Param start_to query string false "Начало не позже месяца (MM-YYYY)"
+// @Param end_from query string false "Окончание не раньше месяца (MM-YYYY)"
+// @Param end_to query string false "Окончание не позже месяца (MM-YYYY)"
+// @Param status query string false "Статус относительно текущего месяца" Enums(active, ended, future)
+// @Success 200 {object} model.Subscription "Подписки, по одной на строку"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Router /subscriptions/export [get]
+func (h *Handler) ExportSubscriptions(c *gin.Context) {
+	filter, err := parseSubscriptionFilter(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	// Порядок фиксирован: по нему клиент находит границу следующей инкрементальной выгрузки
+	if len(filter.Sort) > 0 {
+		RespondInvalid(c, codeInvalidParameter, "sort is not supported, export is ordered by updated_at")
+		return
+	}
+	filter.ByUpdate = true
+
+	if value := c.Query("updated_since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			RespondInvalid(c, codeInvalidParameter, "invalid updated_since, expected RFC 3339 timestamp")
+			return
+		}
+		filter.UpdatedSince = &since
+	}
+
+	controller := http.NewResponseController(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+	started := false
+	start := func() {
+		started = true
+		c.Header("Content-Type", ndjsonContentType)
+		c.Status(http.StatusOK)
+	}
+
+	err = h.service.ExportSubscriptions(c.Request.Context(), filter, func(sub *model.Subscription) error {
+		if !started {
+			start()
+		}
+		// Не все ResponseWriter поддерживают дедлайны (например, в тестах) — тогда срок не продлевается
+		_ = controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		if err := encoder.Encode(sub); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+
+	if err != nil {
+		if !started {
+			RespondError(c, err)
+			return
+		}
+		// Часть выгрузки уже отправлена: статус не изменить, ошибка попадает в лог
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+
+	if !started {
+		start()
+		c.Writer.WriteHeaderNow()
+	}
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func exportTestSubscriptions() []*model.Subscription {
+	return []*model.Subscription{{
+		ID:          uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba"),
+		ServiceName: "Netflix",
+		Price:       1599,
+		UserID:      uuid.MustParse("70601fee-2bf1-4721-ae6f-7636e79a0cba"),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}
+}
+
+func TestListSubscriptionsHandler_CSVByAccept(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{ServiceNames: []string{"Netflix"}}, mock.Anything).
+		Return(exportTestSubscriptions(), nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?service_name=Netflix&columns=service_name,price,start_date,end_date", nil)
+	req.Header.Set("Accept", "text/csv")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=subscriptions.csv`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "Service,Price,Start date,End date\nNetflix,\"1,599\",2025-01-01,\n", w.Body.String())
+}
+
+func TestListSubscriptionsHandler_XLSX(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{}, mock.Anything).
+		Return(exportTestSubscriptions(), nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/subscriptions?format=xlsx&locale=ru", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+
+	file, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("Sheet1")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Сервис", rows[0][1])
+	assert.Equal(t, "01.01.2025", rows[1][4])
+}
+
+func TestListSubscriptionsHandler_ExportErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "unknown format", url: "/api/v1/subscriptions?format=pdf"},
+		{name: "unknown column", url: "/api/v1/subscriptions?format=csv&columns=id,password"},
+		{name: "unknown locale", url: "/api/v1/subscriptions?format=csv&locale=de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			router := setupTestRouter(NewHandler(mockService))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListSubscriptionsHandler_ExportServiceError(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, service.ErrInvalidSort)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/subscriptions?format=csv", nil))
+
+	// Выгрузка еще не началась — ответ с ошибкой, а не пустой CSV
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+func TestCalculateSummaryHandler_CSV(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("CalculateSummary", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.SummaryResponse{TotalAmount: 12000, Count: 3, AdjustmentsAmount: -500}, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{"start_date": "01-2025", "end_date": "12-2025"})
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions/summary?locale=ru", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/csv")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "\xEF\xBB\xBFНачало периода;Конец периода;Итого;Корректировки;Количество\n"+
+		"01.01.2025;01.12.2025;12\u00a0000;-500;3\n", w.Body.String())
+}
+
+func TestExportSubscriptionsHandler_NDJSON(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	since := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	userID := uuid.MustParse("70601fee-2bf1-4721-ae6f-7636e79a0cba")
+	subs := append(exportTestSubscriptions(), &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Spotify",
+		Price:       299,
+		UserID:      userID,
+		StartDate:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	mockService.On("ExportSubscriptions", mock.Anything, mock.MatchedBy(func(filter model.SubscriptionFilter) bool {
+		return *filter.UserID == userID && filter.UpdatedSince.Equal(since) && filter.ByUpdate && filter.Sort == nil
+	}), mock.Anything).Return(subs, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/subscriptions/export?user_id="+userID.String()+"&updated_since=2025-01-31T15:00:00%2B03:00", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.True(t, w.Flushed)
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		var sub model.Subscription
+		require.NoError(t, json.Unmarshal([]byte(line), &sub))
+		assert.Equal(t, subs[i].ID, sub.ID)
+	}
+}
+
+func TestExportSubscriptionsHandler_Empty(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/subscriptions/export", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestExportSubscriptionsHandler_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "updated_since without time zone", url: "/api/v1/subscriptions/export?updated_since=2025-01-31T00:00:00"},
+		{name: "updated_since as month", url: "/api/v1/subscriptions/export?updated_since=01-2025"},
+		{name: "sort", url: "/api/v1/subscriptions/export?sort=-price"},
+		{name: "invalid filter", url: "/api/v1/subscriptions/export?price_min=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			router := setupTestRouter(NewHandler(mockService))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+			mockService.AssertNotCalled(t, "ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
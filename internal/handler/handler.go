@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"github.com/ZnNr/subscription-service/internal/model"
 	"github.com/ZnNr/subscription-service/internal/service"
 	"github.com/gin-gonic/gin"
@@ -8,6 +10,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,20 +29,31 @@ func NewHandler(service service.Service) *Handler {
 // @Accept json
 // @Produce json
 // @Param input body model.CreateSubscriptionRequest true "Данные подписки"
-// @Success 201 {object} model.Subscription
-// @Failure 400 {object} map[string]interface{} "Неверный запрос"
-// @Failure 500 {object} map[string]interface{} "Внутренняя ошибка сервера"
+// @Param force query bool false "Сохранить подписку, несмотря на пересечение с существующими"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом вернет сохраненный ответ"
+// @Success 201 {object} model.CreateSubscriptionResponse
+// @Header 201 {string} Idempotent-Replayed "true, если ответ взят из сохраненного по ключу идемпотентности"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 409 {object} model.Problem "Подписка пересекается с существующей или запрос с этим ключом еще выполняется"
+// @Failure 422 {object} model.Problem "Ключ идемпотентности уже использован с другим запросом или данные нарушают ограничения БД"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
 // @Router /subscriptions [post]
 func (h *Handler) CreateSubscription(c *gin.Context) {
+	opts, err := ParseWriteOptions(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
 	var req model.CreateSubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondBindError(c, err)
 		return
 	}
 
 	startDate, err := parseMonthYear(req.StartDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
+		RespondInvalid(c, codeInvalidDateFormat, "invalid start_date format, expected MM-YYYY")
 		return
 	}
 
@@ -47,7 +61,7 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 	if req.EndDate != nil {
 		ed, err := parseMonthYear(*req.EndDate)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
+			RespondInvalid(c, codeInvalidDateFormat, "invalid end_date format, expected MM-YYYY")
 			return
 		}
 		endDate = &ed
@@ -60,15 +74,17 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 		UserID:      req.UserID,
 		StartDate:   startDate,
 		EndDate:     endDate,
+		Category:    req.Category,
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
-	})
+	}, opts)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(c, err)
 		return
 	}
 
+	c.Header("ETag", FormatETag(sub.Version))
 	c.JSON(http.StatusCreated, sub)
 }
 
@@ -80,56 +96,156 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 // @Produce json
 // @Param id path string true "ID подписки"
 // @Success 200 {object} model.Subscription
-// @Failure 400 {object} map[string]interface{} "Неверный ID"
-// @Failure 404 {object} map[string]interface{} "Подписка не найдена"
+// @Header 200 {string} ETag "Версия подписки для If-Match"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
 // @Router /subscriptions/{id} [get]
 func (h *Handler) GetSubscription(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
+		RespondInvalid(c, codeInvalidID, "invalid subscription id")
 		return
 	}
 
 	sub, err := h.service.GetSubscription(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		RespondError(c, err)
 		return
 	}
 
+	c.Header("ETag", FormatETag(sub.Version))
 	c.JSON(http.StatusOK, sub)
 }
 
-// UpdateSubscription обновляет подписку
-// @Summary Обновить подписку
-// @Description Обновляет информацию о существующей подписке
+// UpdateSubscription полностью заменяет подписку
+// @Summary Заменить подписку
+// @Description Полностью заменяет подписку: передаются все обязательные поля, не переданные end_date и category очищаются
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param id path string true "ID подписки"
-// @Param input body model.UpdateSubscriptionRequest true "Обновленные данные"
-// @Success 200 {object} map[string]interface{} "Подписка обновлена"
-// @Failure 400 {object} map[string]interface{} "Неверный запрос"
-// @Failure 404 {object} map[string]interface{} "Подписка не найдена"
+// @Param input body model.UpdateSubscriptionRequest true "Новые данные подписки"
+// @Param force query bool false "Сохранить подписку, несмотря на пересечение с существующими"
+// @Param If-Match header string false "ETag подписки из GET; при несовпадении версии — 412"
+// @Success 200 {object} model.MessageResponse "Подписка обновлена"
+// @Header 200 {string} ETag "Новая версия подписки"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Failure 409 {object} model.Problem "Подписка пересекается с существующей"
+// @Failure 412 {object} model.Problem "Версия подписки не совпадает с If-Match"
 // @Router /subscriptions/{id} [put]
 func (h *Handler) UpdateSubscription(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
+		RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	opts, err := ParseWriteOptions(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
 		return
 	}
 
 	var req model.UpdateSubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondBindError(c, err)
+		return
+	}
+
+	startDate, err := parseMonthYear(req.StartDate)
+	if err != nil {
+		RespondInvalid(c, codeInvalidDateFormat, "invalid start_date format, expected MM-YYYY")
+		return
+	}
+
+	var endDate *time.Time
+	if req.EndDate != nil {
+		ed, err := parseMonthYear(*req.EndDate)
+		if err != nil {
+			RespondInvalid(c, codeInvalidDateFormat, "invalid end_date format, expected MM-YYYY")
+			return
+		}
+		endDate = &ed
+	}
+
+	sub := &model.Subscription{
+		ID:          id,
+		ServiceName: req.ServiceName,
+		Price:       req.Price,
+		UserID:      req.UserID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    req.Category,
+	}
+
+	if err := h.service.UpdateSubscription(c.Request.Context(), sub, opts); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.Header("ETag", FormatETag(sub.Version))
+
+	c.JSON(http.StatusOK, model.MessageResponse{Message: "subscription updated"})
+}
+
+// PatchSubscription частично изменяет подписку
+// @Summary Изменить подписку
+// @Description Частично изменяет подписку по правилам JSON Merge Patch (RFC 7396): отсутствующие поля не меняются,
+// @Description null очищает поле (например, "end_date": null делает подписку бессрочной). Результат проверяется так же, как при создании
+// @Tags subscriptions
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path string true "ID подписки"
+// @Param input body model.SubscriptionPatchRequest true "Изменяемые поля"
+// @Param force query bool false "Сохранить подписку, несмотря на пересечение с существующими"
+// @Param If-Match header string false "ETag подписки из GET; при несовпадении версии — 412"
+// @Success 200 {object} model.Subscription
+// @Header 200 {string} ETag "Новая версия подписки"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Failure 409 {object} model.Problem "Подписка пересекается с существующей"
+// @Failure 412 {object} model.Problem "Версия подписки не совпадает с If-Match"
+// @Failure 415 {object} model.Problem "Неподдерживаемый Content-Type"
+// @Router /subscriptions/{id} [patch]
+func (h *Handler) PatchSubscription(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	if ct := c.ContentType(); ct != "application/merge-patch+json" && ct != "application/json" {
+		RespondProblem(c, http.StatusUnsupportedMediaType, codeUnsupportedMediaType, "content type must be application/merge-patch+json")
+		return
+	}
+
+	opts, err := ParseWriteOptions(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	var req model.SubscriptionPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBindError(c, err)
 		return
 	}
 
-	if err := h.service.UpdateSubscription(c.Request.Context(), id, &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	patch, err := subscriptionPatch(&req)
+	if err != nil {
+		RespondInvalid(c, codeInvalidDateFormat, err.Error())
+		return
+	}
+
+	sub, err := h.service.PatchSubscription(c.Request.Context(), id, patch, opts)
+	if err != nil {
+		RespondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "subscription updated"})
+	c.Header("ETag", FormatETag(sub.Version))
+	c.JSON(http.StatusOK, sub)
 }
 
 // DeleteSubscription удаляет подписку
@@ -139,19 +255,27 @@ func (h *Handler) UpdateSubscription(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "ID подписки"
+// @Param If-Match header string false "ETag подписки из GET; при несовпадении версии — 412"
 // @Success 204 "Подписка удалена"
-// @Failure 400 {object} map[string]interface{} "Неверный ID"
-// @Failure 404 {object} map[string]interface{} "Подписка не найдена"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Failure 412 {object} model.Problem "Версия подписки не совпадает с If-Match"
 // @Router /subscriptions/{id} [delete]
 func (h *Handler) DeleteSubscription(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
+		RespondInvalid(c, codeInvalidID, "invalid subscription id")
 		return
 	}
 
-	if err := h.service.DeleteSubscription(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	opts, err := ParseWriteOptions(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	if err := h.service.DeleteSubscription(c.Request.Context(), id, opts); err != nil {
+		RespondError(c, err)
 		return
 	}
 
@@ -160,74 +284,333 @@ func (h *Handler) DeleteSubscription(c *gin.Context) {
 
 // ListSubscriptions возвращает список подписок
 // @Summary Список подписок
-// @Description Возвращает список подписок с возможностью фильтрации
+// @Description Возвращает список подписок с возможностью фильтрации и постраничной выдачи.
+// @Description Если передан limit или cursor, ответ — объект {items, next_cursor}. Без них ответ — массив всех подходящих
+// @Description подписок (режим совместимости); для больших списков используйте limit и cursor.
 // @Tags subscriptions
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param user_id query string false "ID пользователя для фильтрации"
-// @Param service_name query string false "Название сервиса для фильтрации"
-// @Success 200 {array} model.Subscription
+// @Param service_name query []string false "Название сервиса; можно передать несколько раз" collectionFormat(multi)
+// @Param q query string false "Подстрока названия сервиса или категории без учета регистра"
+// @Param active_at query string false "Месяц, в котором подписка действует (MM-YYYY)"
+// @Param price_min query int false "Минимальная цена"
+// @Param price_max query int false "Максимальная цена"
+// @Param start_from query string false "Начало не раньше месяца (MM-YYYY)"
+// @Param start_to query string false "Начало не позже месяца (MM-YYYY)"
+// @Param end_from query string false "Окончание не раньше месяца (MM-YYYY)"
+// @Param end_to query string false "Окончание не позже месяца (MM-YYYY)"
+// @Param status query string false "Статус относительно текущего месяца" Enums(active, ended, future)
+// @Param sort query string false "Поля сортировки через запятую, '-' — по убыванию: price, start_date, end_date, service_name, created_at (например -price,service_name)"
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 500)"
+// @Param cursor query string false "Курсор из next_cursor предыдущей страницы (действителен только при том же sort)"
+// @Param format query string false "Выгрузка в файл (вместо Accept: text/csv или Accept: application/vnd.openxmlformats-officedocument.spreadsheetml.sheet); выгружаются все подходящие подписки, limit и cursor не учитываются. CSV отправляется потоком; XLSX собирается целиком и вмещает не больше 100000 подписок, иначе ответ 422 export_too_large" Enums(json, csv, xlsx)
+// @Param columns query string false "Столбцы выгрузки через запятую: id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at, version"
+// @Param locale query string false "Оформление выгрузки: en — разделитель ',', даты YYYY-MM-DD; ru — разделитель ';', даты DD.MM.YYYY (по умолчанию из Accept-Language)" Enums(en, ru)
+// @Success 200 {object} model.SubscriptionPage
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 422 {object} model.Problem "Выгрузка XLSX не помещается в ограничение строк"
 // @Router /subscriptions [get]
 func (h *Handler) ListSubscriptions(c *gin.Context) {
-	var userID *uuid.UUID
-	if uid := c.Query("user_id"); uid != "" {
-		if parsed, err := uuid.Parse(uid); err == nil {
-			userID = &parsed
-		}
+	filter, err := parseSubscriptionFilter(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	format, exporting, err := exportFormat(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	// Выгрузка в CSV/XLSX содержит все подходящие подписки, limit и cursor не учитываются
+	if exporting {
+		writeExport(c, format, subscriptionColumns, defaultSubscriptionColumns, "subscriptions", func(write func(*model.Subscription) error) error {
+			return h.service.ExportSubscriptions(c.Request.Context(), filter, write)
+		})
+		return
 	}
 
-	var serviceName *string
-	if sn := c.Query("service_name"); sn != "" {
-		serviceName = &sn
+	limit, hasLimit := c.GetQuery("limit")
+	cursor, hasCursor := c.GetQuery("cursor")
+
+	// Старые клиенты ожидают массив всех подписок, как до постраничной выдачи.
+	// Массив отправляется потоком, чтобы большая таблица не упиралась в WriteTimeout сервера
+	if !hasLimit && !hasCursor {
+		writeJSONArray(c, func(write func(*model.Subscription) error) error {
+			return h.service.ExportSubscriptions(c.Request.Context(), filter, write)
+		})
+		return
+	}
+
+	if hasLimit {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 {
+			RespondInvalid(c, codeInvalidParameter, "invalid limit, expected positive integer")
+			return
+		}
+		filter.Limit = parsed
 	}
+	filter.Cursor = cursor
 
-	subscriptions, err := h.service.ListSubscriptions(c.Request.Context(), userID, serviceName)
+	page, err := h.service.ListSubscriptions(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, subscriptions)
+	c.JSON(http.StatusOK, page)
 }
 
 // CalculateSummary считает суммарную стоимость подписок
 // @Summary Сумма подписок
-// @Description Рассчитывает суммарную стоимость подписок за период с фильтрацией
+// @Description Рассчитывает суммарную стоимость подписок за период с фильтрацией. При source=ledger сумма считается по журналу списаний
 // @Tags subscriptions
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param input body model.SummaryRequest true "Параметры расчета"
+// @Param format query string false "Выгрузка в файл (вместо заголовка Accept)" Enums(json, csv, xlsx)
+// @Param columns query string false "Столбцы выгрузки через запятую: start_date, end_date, user_id, service_name, source, total_amount, adjustments_amount, count"
+// @Param locale query string false "Оформление выгрузки (по умолчанию из Accept-Language)" Enums(en, ru)
 // @Success 200 {object} model.SummaryResponse
-// @Failure 400 {object} map[string]interface{} "Неверный запрос"
+// @Failure 400 {object} model.Problem "Неверный запрос"
 // @Router /subscriptions/summary [post]
 func (h *Handler) CalculateSummary(c *gin.Context) {
 	var req model.SummaryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondBindError(c, err)
 		return
 	}
 
 	startDate, err := parseMonthYear(req.StartDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
+		RespondInvalid(c, codeInvalidDateFormat, "invalid start_date format, expected MM-YYYY")
 		return
 	}
 
 	endDate, err := parseMonthYear(req.EndDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
+		RespondInvalid(c, codeInvalidDateFormat, "invalid end_date format, expected MM-YYYY")
 		return
 	}
 
-	summary, err := h.service.CalculateSummary(c.Request.Context(), startDate, endDate, req.UserID, req.ServiceName)
+	format, exporting, err := exportFormat(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	calculate := h.service.CalculateSummary
+	if req.Source == "ledger" {
+		calculate = h.service.CalculateLedgerSummary
+	}
+
+	summary, err := calculate(c.Request.Context(), startDate, endDate, req.UserID, req.ServiceName)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if exporting {
+		writeExport(c, format, summaryColumns, defaultSummaryColumns, "summary", func(write func(*summaryRow) error) error {
+			return write(&summaryRow{StartDate: startDate, EndDate: endDate, Request: &req, Summary: summary})
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
 
+// ListOverlaps возвращает отчет о пересекающихся подписках
+// @Summary Пересекающиеся подписки
+// @Description Возвращает все пары подписок одного пользователя на один сервис с пересекающимися периодами
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.SubscriptionOverlap
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /subscriptions/overlaps [get]
+func (h *Handler) ListOverlaps(c *gin.Context) {
+	overlaps, err := h.service.ListOverlaps(c.Request.Context())
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if overlaps == nil {
+		overlaps = []*model.SubscriptionOverlap{}
+	}
+
+	c.JSON(http.StatusOK, overlaps)
+}
+
+// ParseWriteOptions читает параметр force из query-строки и ожидаемую версию из заголовка If-Match
+func ParseWriteOptions(c *gin.Context) (model.WriteOptions, error) {
+	var opts model.WriteOptions
+
+	if f := c.Query("force"); f != "" {
+		force, err := strconv.ParseBool(f)
+		if err != nil {
+			return opts, errors.New("invalid force parameter, expected boolean")
+		}
+		opts.Force = force
+	}
+
+	// If-Match: * подходит для любой версии существующей подписки
+	if ifMatch := strings.TrimSpace(c.GetHeader("If-Match")); ifMatch != "" && ifMatch != "*" {
+		version, err := parseETag(ifMatch)
+		if err != nil {
+			return opts, err
+		}
+		opts.IfMatch = &version
+	}
+
+	return opts, nil
+}
+
+// FormatETag возвращает ETag для версии подписки
+func FormatETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseETag разбирает строгий ETag вида "3", выданный FormatETag
+func parseETag(etag string) (int, error) {
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil || !strings.HasPrefix(etag, `"`) {
+		return 0, errors.New(`invalid If-Match header, expected ETag like "3"`)
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return 0, errors.New(`invalid If-Match header, expected ETag like "3"`)
+	}
+
+	return version, nil
+}
+
+// subscriptionPatch переводит документ v1 в model.SubscriptionPatch, разбирая месяцы MM-YYYY
+func subscriptionPatch(req *model.SubscriptionPatchRequest) (*model.SubscriptionPatch, error) {
+	startDate, err := ParsePatchMonth("start_date", req.StartDate, parseMonthParam)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := ParsePatchMonth("end_date", req.EndDate, parseMonthParam)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SubscriptionPatch{
+		ServiceName: req.ServiceName,
+		Price:       req.Price,
+		UserID:      req.UserID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    req.Category,
+	}, nil
+}
+
+// ParsePatchMonth разбирает месяц из поля name документа JSON Merge Patch функцией parseMonth
+// версии API; отсутствие поля и null сохраняются
+func ParsePatchMonth(name string, value model.Optional[string], parseMonth func(name, value string) (time.Time, error)) (model.Optional[time.Time], error) {
+	month := model.Optional[time.Time]{Set: value.Set, Null: value.Null}
+	if !value.Set || value.Null {
+		return month, nil
+	}
+
+	parsed, err := parseMonth(name, value.Value)
+	if err != nil {
+		return month, err
+	}
+	month.Value = parsed
+
+	return month, nil
+}
+
+// parseSubscriptionFilter разбирает параметры фильтрации списка подписок v1 из query-строки
+func parseSubscriptionFilter(c *gin.Context) (model.SubscriptionFilter, error) {
+	return ParseSubscriptionFilter(c, parseMonthParam)
+}
+
+// ParseSubscriptionFilter разбирает общие для всех версий API параметры фильтрации и сортировки
+// списка подписок из query-строки. Месяцы разбирает parseMonth в формате версии API; его ошибка
+// возвращается как есть. Параметры страницы разбирает вызывающий.
+func ParseSubscriptionFilter(c *gin.Context, parseMonth func(name, value string) (time.Time, error)) (model.SubscriptionFilter, error) {
+	var filter model.SubscriptionFilter
+
+	if uid := c.Query("user_id"); uid != "" {
+		parsed, err := uuid.Parse(uid)
+		if err != nil {
+			return filter, errors.New("invalid user_id")
+		}
+		filter.UserID = &parsed
+	}
+
+	for _, sn := range c.QueryArray("service_name") {
+		if sn != "" {
+			filter.ServiceNames = append(filter.ServiceNames, sn)
+		}
+	}
+
+	if q := c.Query("q"); q != "" {
+		filter.Search = &q
+	}
+
+	months := []struct {
+		param string
+		dest  **time.Time
+	}{
+		{"active_at", &filter.ActiveAt},
+		{"start_from", &filter.StartFrom},
+		{"start_to", &filter.StartTo},
+		{"end_from", &filter.EndFrom},
+		{"end_to", &filter.EndTo},
+	}
+	for _, m := range months {
+		if v := c.Query(m.param); v != "" {
+			parsed, err := parseMonth(m.param, v)
+			if err != nil {
+				return filter, err
+			}
+			*m.dest = &parsed
+		}
+	}
+
+	prices := []struct {
+		param string
+		dest  **int
+	}{
+		{"price_min", &filter.PriceMin},
+		{"price_max", &filter.PriceMax},
+	}
+	for _, p := range prices {
+		if v := c.Query(p.param); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s, expected integer", p.param)
+			}
+			*p.dest = &parsed
+		}
+	}
+
+	filter.Status = c.Query("status")
+
+	if sort := c.Query("sort"); sort != "" {
+		for _, field := range strings.Split(sort, ",") {
+			field = strings.TrimSpace(field)
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimLeft(field, "+-")
+			if field == "" {
+				return filter, errors.New("invalid sort, expected comma-separated fields like -price,service_name")
+			}
+			filter.Sort = append(filter.Sort, model.SortField{Field: field, Desc: desc})
+		}
+	}
+
+	return filter, nil
+}
+
 // ValidateMonthYear проверяет формат "MM-YYYY"
 func ValidateMonthYear(dateStr string) bool {
 	// Регулярное выражение для формата MM-YYYY
@@ -235,6 +618,16 @@ func ValidateMonthYear(dateStr string) bool {
 	return re.MatchString(dateStr)
 }
 
+// parseMonthParam разбирает месяц "MM-YYYY" из параметра name
+func parseMonthParam(name, value string) (time.Time, error) {
+	parsed, err := parseMonthYear(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s format, expected MM-YYYY", name)
+	}
+
+	return parsed, nil
+}
+
 // parseMonthYear
 func parseMonthYear(dateStr string) (time.Time, error) {
 	if !ValidateMonthYear(dateStr) {
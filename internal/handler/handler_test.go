@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/ZnNr/subscription-service/internal/model"
 	"github.com/ZnNr/subscription-service/internal/service"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockService реализует интерфейс service.Service
@@ -22,12 +24,12 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) CreateSubscription(ctx context.Context, sub *model.Subscription) (*model.Subscription, error) {
-	args := m.Called(ctx, sub)
+func (m *MockService) CreateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) (*model.CreateSubscriptionResponse, error) {
+	args := m.Called(ctx, sub, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.Subscription), args.Error(1)
+	return args.Get(0).(*model.CreateSubscriptionResponse), args.Error(1)
 }
 
 func (m *MockService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
@@ -38,22 +40,58 @@ func (m *MockService) GetSubscription(ctx context.Context, id uuid.UUID) (*model
 	return args.Get(0).(*model.Subscription), args.Error(1)
 }
 
-func (m *MockService) UpdateSubscription(ctx context.Context, id uuid.UUID, req *model.UpdateSubscriptionRequest) error {
-	args := m.Called(ctx, id, req)
+func (m *MockService) UpdateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) error {
+	args := m.Called(ctx, sub, opts)
 	return args.Error(0)
 }
 
-func (m *MockService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockService) PatchSubscription(ctx context.Context, id uuid.UUID, patch *model.SubscriptionPatch, opts model.WriteOptions) (*model.Subscription, error) {
+	args := m.Called(ctx, id, patch, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Subscription), args.Error(1)
+}
+
+func (m *MockService) Batch(ctx context.Context, ops []model.BatchOperation, mode string, opts model.WriteOptions) (*model.BatchResponse, error) {
+	args := m.Called(ctx, ops, mode, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BatchResponse), args.Error(1)
+}
+
+func (m *MockService) ImportSubscriptions(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions) (*model.ImportReport, error) {
+	args := m.Called(ctx, rows, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportReport), args.Error(1)
+}
+
+func (m *MockService) DeleteSubscription(ctx context.Context, id uuid.UUID, opts model.WriteOptions) error {
+	args := m.Called(ctx, id, opts)
 	return args.Error(0)
 }
 
-func (m *MockService) ListSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string) ([]*model.Subscription, error) {
-	args := m.Called(ctx, userID, serviceName)
+func (m *MockService) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Subscription), args.Error(1)
+	return args.Get(0).(*model.SubscriptionPage), args.Error(1)
+}
+
+func (m *MockService) ExportSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error {
+	args := m.Called(ctx, filter, fn)
+	if subs, ok := args.Get(0).([]*model.Subscription); ok {
+		for _, sub := range subs {
+			if err := fn(sub); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
 }
 
 func (m *MockService) CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
@@ -64,22 +102,209 @@ func (m *MockService) CalculateSummary(ctx context.Context, startDate, endDate t
 	return args.Get(0).(*model.SummaryResponse), args.Error(1)
 }
 
+func (m *MockService) ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SubscriptionOverlap), args.Error(1)
+}
+
+func (m *MockService) SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SubscriptionMatch), args.Error(1)
+}
+
+func (m *MockService) SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error) {
+	args := m.Called(ctx, prefix, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ServiceNameSuggestion), args.Error(1)
+}
+
+func (m *MockService) SetBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error) {
+	args := m.Called(ctx, budget)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Budget), args.Error(1)
+}
+
+func (m *MockService) ListBudgets(ctx context.Context, userID uuid.UUID) ([]*model.Budget, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Budget), args.Error(1)
+}
+
+func (m *MockService) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockService) BudgetReport(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]*model.BudgetMonth, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BudgetMonth), args.Error(1)
+}
+
+func (m *MockService) ListCharges(ctx context.Context, filter model.ChargeFilter) (*model.ChargePage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ChargePage), args.Error(1)
+}
+
+func (m *MockService) ExportCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error {
+	args := m.Called(ctx, filter, fn)
+	if charges, ok := args.Get(0).([]*model.Charge); ok {
+		for _, charge := range charges {
+			if err := fn(charge); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockService) RebuildCharges(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockService) CalculateLedgerSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
+	args := m.Called(ctx, startDate, endDate, userID, serviceName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SummaryResponse), args.Error(1)
+}
+
+func (m *MockService) CreateAdjustment(ctx context.Context, adj *model.Adjustment) (*model.Adjustment, error) {
+	args := m.Called(ctx, adj)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Adjustment), args.Error(1)
+}
+
+func (m *MockService) ListAdjustments(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Adjustment, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Adjustment), args.Error(1)
+}
+
+func (m *MockService) DeleteAdjustment(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockService) BeginIdempotentRequest(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, uuid.UUID, error) {
+	args := m.Called(ctx, key, fingerprint)
+	record, _ := args.Get(0).(*model.IdempotencyRecord)
+	return record, args.Get(1).(uuid.UUID), args.Error(2)
+}
+
+func (m *MockService) FinishIdempotentRequest(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error {
+	args := m.Called(ctx, key, lease, statusCode, headers, body)
+	return args.Error(0)
+}
+
+func (m *MockService) CreateWebhook(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Webhook), args.Error(1)
+}
+
+func (m *MockService) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Webhook), args.Error(1)
+}
+
+func (m *MockService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockService) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockService) GetWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockService) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookDelivery), args.Error(1)
+}
+
 var _ service.Service = (*MockService)(nil)
 
 func setupTestRouter(handler *Handler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(RequestID())
 
 	api := router.Group("/api/v1")
 	{
 		subscriptions := api.Group("/subscriptions")
 		{
-			subscriptions.POST("", handler.CreateSubscription)
+			subscriptions.POST("", handler.Idempotency(), handler.CreateSubscription)
 			subscriptions.GET("", handler.ListSubscriptions)
+			subscriptions.GET("/overlaps", handler.ListOverlaps)
+			subscriptions.GET("/export", handler.ExportSubscriptions)
+			subscriptions.POST("/batch", handler.BatchSubscriptions)
+			subscriptions.POST("/import", handler.ImportSubscriptions)
 			subscriptions.GET("/:id", handler.GetSubscription)
 			subscriptions.PUT("/:id", handler.UpdateSubscription)
+			subscriptions.PATCH("/:id", handler.PatchSubscription)
 			subscriptions.DELETE("/:id", handler.DeleteSubscription)
 			subscriptions.POST("/summary", handler.CalculateSummary)
+			subscriptions.POST("/:id/adjustments", handler.CreateAdjustment)
+			subscriptions.GET("/:id/adjustments", handler.ListAdjustments)
+		}
+
+		api.DELETE("/adjustments/:id", handler.DeleteAdjustment)
+
+		budgets := api.Group("/budgets")
+		{
+			budgets.POST("", handler.SetBudget)
+			budgets.GET("", handler.ListBudgets)
+			budgets.DELETE("/:id", handler.DeleteBudget)
+			budgets.POST("/report", handler.BudgetReport)
+		}
+
+		charges := api.Group("/charges")
+		{
+			charges.GET("", handler.ListCharges)
+			charges.POST("/rebuild", handler.RebuildCharges)
 		}
 	}
 
@@ -110,8 +335,8 @@ func TestCreateSubscriptionHandler(t *testing.T) {
 		UpdatedAt:   time.Now().UTC(),
 	}
 
-	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription")).
-		Return(expectedSub, nil)
+	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{}).
+		Return(&model.CreateSubscriptionResponse{Subscription: expectedSub}, nil)
 
 	jsonBody, _ := json.Marshal(requestBody)
 	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBuffer(jsonBody))
@@ -143,6 +368,7 @@ func TestGetSubscriptionHandler(t *testing.T) {
 		Price:       599,
 		UserID:      uuid.New(),
 		StartDate:   time.Now().UTC(),
+		Version:     5,
 	}
 
 	mockService.On("GetSubscription", mock.Anything, subID).
@@ -161,6 +387,7 @@ func TestGetSubscriptionHandler(t *testing.T) {
 
 	assert.Equal(t, expectedSub.ID, response.ID)
 	assert.Equal(t, expectedSub.ServiceName, response.ServiceName)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
 	mockService.AssertExpectations(t)
 }
 
@@ -187,10 +414,10 @@ func TestListSubscriptionsHandler(t *testing.T) {
 		},
 	}
 
-	mockService.On("ListSubscriptions",
+	mockService.On("ExportSubscriptions",
 		mock.Anything, // context.Context
-		&userID,       // *uuid.UUID
-		mock.MatchedBy(func(s *string) bool { return s == nil }), // *string (nil)
+		model.SubscriptionFilter{UserID: &userID},
+		mock.Anything,
 	).Return(expectedSubs, nil)
 
 	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?user_id="+userID.String(), nil)
@@ -208,6 +435,242 @@ func TestListSubscriptionsHandler(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestListSubscriptionsHandler_Paged(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	nextCursor := "next-page"
+	mockService.On("ListSubscriptions", mock.Anything, model.SubscriptionFilter{Limit: 1, Cursor: "current-page"}).
+		Return(&model.SubscriptionPage{
+			Items:      []*model.Subscription{{ID: uuid.New(), ServiceName: "Netflix"}},
+			NextCursor: &nextCursor,
+		}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?limit=1&cursor=current-page", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.SubscriptionPage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, nextCursor, *response.NextCursor)
+	mockService.AssertExpectations(t)
+}
+
+func TestListSubscriptionsHandler_LegacyReturnsAll(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	// Без limit и cursor массив не обрезается до размера страницы
+	subs := make([]*model.Subscription, service.MaxPageSize+1)
+	for i := range subs {
+		subs[i] = &model.Subscription{ID: uuid.New(), ServiceName: "Netflix"}
+	}
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{}, mock.Anything).Return(subs, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []model.Subscription
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, service.MaxPageSize+1)
+	mockService.AssertNotCalled(t, "ListSubscriptions", mock.Anything, mock.Anything)
+}
+
+// deadlineRecorder — httptest.ResponseRecorder, который запоминает продления срока записи
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines int
+}
+
+func (r *deadlineRecorder) SetWriteDeadline(time.Time) error {
+	r.deadlines++
+	return nil
+}
+
+func TestListSubscriptionsHandler_LegacyStreams(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	subs := []*model.Subscription{{ID: uuid.New(), ServiceName: "Netflix"}, {ID: uuid.New(), ServiceName: "Spotify"}}
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{}, mock.Anything).Return(subs, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions", nil)
+
+	w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	// Срок записи продлевается перед каждой подпиской
+	assert.Equal(t, len(subs), w.deadlines)
+	var response []model.Subscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Spotify", response[1].ServiceName)
+}
+
+func TestListSubscriptionsHandler_LegacyError(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{}, mock.Anything).
+		Return(nil, errors.New("connection refused"))
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+}
+
+func TestListSubscriptionsHandler_LegacyEmpty(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{}, mock.Anything).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+}
+
+func TestListSubscriptionsHandler_Filters(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	activeAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	priceMax := 1000
+	search := "flix"
+	mockService.On("ExportSubscriptions", mock.Anything, model.SubscriptionFilter{
+		ServiceNames: []string{"Netflix", "Spotify"},
+		Search:       &search,
+		ActiveAt:     &activeAt,
+		PriceMax:     &priceMax,
+		Status:       model.SubscriptionStatusActive,
+	}, mock.Anything).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET",
+		"/api/v1/subscriptions?service_name=Netflix&service_name=Spotify&q=flix&active_at=03-2025&price_max=1000&status=active", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListSubscriptionsHandler_Sort(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("ListSubscriptions", mock.Anything, model.SubscriptionFilter{
+		Sort:  []model.SortField{{Field: "price", Desc: true}, {Field: "service_name"}},
+		Limit: 20,
+	}).Return(&model.SubscriptionPage{Items: []*model.Subscription{}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?sort=-price,service_name&limit=20", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListSubscriptionsHandler_InvalidFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Invalid user_id", "user_id=not-a-uuid"},
+		{"Invalid active_at", "active_at=2025-03"},
+		{"Invalid price_min", "price_min=cheap"},
+		{"Invalid end_to", "end_to=13-2025"},
+		{"Empty sort field", "sort=price,,service_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService)
+			router := setupTestRouter(handler)
+
+			req, _ := http.NewRequest("GET", "/api/v1/subscriptions?"+tt.query, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "ListSubscriptions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListSubscriptionsHandler_ServiceValidationError(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, service.ErrInvalidStatus)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?status=paused", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListSubscriptionsHandler_InvalidLimit(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?limit=0", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListSubscriptions", mock.Anything, mock.Anything)
+}
+
+func TestListSubscriptionsHandler_InvalidCursor(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("ListSubscriptions", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidCursor)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions?cursor=garbage", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestCalculateSummaryHandler(t *testing.T) {
 	mockService := new(MockService)
 	handler := NewHandler(mockService)
@@ -262,15 +725,22 @@ func TestUpdateSubscriptionHandler(t *testing.T) {
 	router := setupTestRouter(handler)
 
 	subID := uuid.New()
+	userID := uuid.New()
 
 	requestBody := map[string]interface{}{
-		"price": 699,
+		"service_name": "Netflix",
+		"price":        699,
+		"user_id":      userID.String(),
+		"start_date":   "01-2025",
 	}
 
 	mockService.On("UpdateSubscription",
 		mock.Anything, // context.Context
-		subID,
-		mock.AnythingOfType("*model.UpdateSubscriptionRequest"),
+		mock.MatchedBy(func(s *model.Subscription) bool {
+			return s.ID == subID && s.Price == 699 && s.UserID == userID && s.EndDate == nil &&
+				s.StartDate.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		}),
+		model.WriteOptions{},
 	).Return(nil)
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -285,6 +755,119 @@ func TestUpdateSubscriptionHandler(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestUpdateSubscriptionHandler_MissingRequiredFields(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	// PUT — полная замена, частичное тело отклоняется
+	req, _ := http.NewRequest("PUT", "/api/v1/subscriptions/"+uuid.New().String(), bytes.NewBufferString(`{"price": 699}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscriptionHandler_NotFound(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("UpdateSubscription", mock.Anything, mock.Anything, mock.Anything).Return(service.ErrNotFound)
+
+	body := `{"service_name": "Netflix", "price": 699, "user_id": "` + uuid.New().String() + `", "start_date": "01-2025"}`
+	req, _ := http.NewRequest("PUT", "/api/v1/subscriptions/"+uuid.New().String(), bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPatchSubscriptionHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	subID := uuid.New()
+	updated := &model.Subscription{ID: subID, ServiceName: "Netflix", Price: 599}
+
+	mockService.On("PatchSubscription",
+		mock.Anything,
+		subID,
+		mock.MatchedBy(func(p *model.SubscriptionPatch) bool {
+			return p.EndDate.Set && p.EndDate.Null && !p.Price.Set && !p.ServiceName.Set
+		}),
+		model.WriteOptions{},
+	).Return(updated, nil)
+
+	req, _ := http.NewRequest("PATCH", "/api/v1/subscriptions/"+subID.String(), bytes.NewBufferString(`{"end_date": null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.Subscription
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.EndDate)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchSubscriptionHandler_ValidationError(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	mockService.On("PatchSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, service.ErrServiceNameRequired)
+
+	req, _ := http.NewRequest("PATCH", "/api/v1/subscriptions/"+uuid.New().String(), bytes.NewBufferString(`{"service_name": null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPatchSubscriptionHandler_InvalidMonth(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	req, _ := http.NewRequest("PATCH", "/api/v1/subscriptions/"+uuid.New().String(), bytes.NewBufferString(`{"start_date": "2025-01"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_date_format"`)
+	assert.Contains(t, w.Body.String(), "invalid start_date format, expected MM-YYYY")
+	mockService.AssertNotCalled(t, "PatchSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchSubscriptionHandler_UnsupportedContentType(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	req, _ := http.NewRequest("PATCH", "/api/v1/subscriptions/"+uuid.New().String(), bytes.NewBufferString(`end_date=`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	mockService.AssertNotCalled(t, "PatchSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestDeleteSubscriptionHandler(t *testing.T) {
 	mockService := new(MockService)
 	handler := NewHandler(mockService)
@@ -295,6 +878,7 @@ func TestDeleteSubscriptionHandler(t *testing.T) {
 	mockService.On("DeleteSubscription",
 		mock.Anything, // context.Context вместо *gin.Context
 		subID,
+		model.WriteOptions{},
 	).Return(nil)
 
 	req, _ := http.NewRequest("DELETE", "/api/v1/subscriptions/"+subID.String(), nil)
@@ -305,3 +889,170 @@ func TestDeleteSubscriptionHandler(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestDeleteSubscriptionHandler_IfMatch(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	subID := uuid.New()
+	version := 4
+
+	mockService.On("DeleteSubscription", mock.Anything, subID, model.WriteOptions{IfMatch: &version}).
+		Return(service.ErrPreconditionFailed)
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/subscriptions/"+subID.String(), nil)
+	req.Header.Set("If-Match", `"4"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSubscriptionHandler_IfMatch(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	subID := uuid.New()
+	version := 2
+
+	mockService.On("UpdateSubscription", mock.Anything, mock.Anything, model.WriteOptions{IfMatch: &version}).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Subscription).Version = 3
+		}).
+		Return(nil)
+
+	body := `{"service_name": "Netflix", "price": 699, "user_id": "` + uuid.New().String() + `", "start_date": "01-2025"}`
+	req, _ := http.NewRequest("PUT", "/api/v1/subscriptions/"+subID.String(), bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSubscriptionHandler_InvalidIfMatch(t *testing.T) {
+	for _, ifMatch := range []string{"2", `W/"2"`, `"abc"`} {
+		t.Run(ifMatch, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService)
+			router := setupTestRouter(handler)
+
+			body := `{"service_name": "Netflix", "price": 699, "user_id": "` + uuid.New().String() + `", "start_date": "01-2025"}`
+			req, _ := http.NewRequest("PUT", "/api/v1/subscriptions/"+uuid.New().String(), bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", ifMatch)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateSubscriptionHandler_Overlap(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	conflictID := uuid.New()
+	requestBody := map[string]interface{}{
+		"service_name": "Yandex Plus",
+		"price":        400,
+		"user_id":      uuid.New().String(),
+		"start_date":   "07-2025",
+	}
+
+	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{}).
+		Return(nil, &service.OverlapError{Overlaps: []*model.Subscription{{ID: conflictID}}})
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response struct {
+		Conflicts []uuid.UUID `json:"conflicts"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []uuid.UUID{conflictID}, response.Conflicts)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSubscriptionHandler_Force(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	requestBody := map[string]interface{}{
+		"service_name": "Yandex Plus",
+		"price":        400,
+		"user_id":      uuid.New().String(),
+		"start_date":   "07-2025",
+	}
+
+	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{Force: true}).
+		Return(&model.CreateSubscriptionResponse{
+			Subscription: &model.Subscription{ID: uuid.New(), ServiceName: "Yandex Plus"},
+			Warnings:     []string{"overlaps with subscription"},
+		}, nil)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions?force=true", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response model.CreateSubscriptionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Yandex Plus", response.ServiceName)
+	assert.Len(t, response.Warnings, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestListOverlapsHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService)
+	router := setupTestRouter(handler)
+
+	overlaps := []*model.SubscriptionOverlap{
+		{
+			UserID:         uuid.New(),
+			ServiceName:    "Netflix",
+			SubscriptionID: uuid.New(),
+			OverlappingID:  uuid.New(),
+			OverlapStart:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	mockService.On("ListOverlaps", mock.Anything).Return(overlaps, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscriptions/overlaps", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []model.SubscriptionOverlap
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, overlaps[0].SubscriptionID, response[0].SubscriptionID)
+	mockService.AssertExpectations(t)
+}
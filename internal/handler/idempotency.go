@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotentRequestSize — наибольший размер тела запроса с ключом идемпотентности
+	maxIdempotentRequestSize = 1 << 20
+)
+
+// idempotentResponseHeaders — заголовки ответа (в канонической форме), которые сохраняются вместе
+// с ним и повторяются при воспроизведении. Остальные (например, X-Request-ID) относятся
+// к конкретному запросу.
+var idempotentResponseHeaders = map[string]bool{
+	"Content-Type": true,
+	"Location":     true,
+	"Etag":         true,
+	"Warning":      true,
+}
+
+// responseRecorder дублирует тело ответа, чтобы сохранить его по ключу идемпотентности
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency делает запрос с заголовком Idempotency-Key идемпотентным: первый ответ
+// сохраняется, а повтор с тем же ключом и тем же телом получает его без повторного выполнения.
+// Запросы без заголовка обрабатываются как обычно.
+func (h *Handler) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestSize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				RespondProblem(c, http.StatusRequestEntityTooLarge, codePayloadTooLarge, fmt.Sprintf("request body is larger than %d bytes", maxIdempotentRequestSize))
+				return
+			}
+			RespondInvalid(c, codeMalformedBody, "failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, lease, err := h.service.BeginIdempotentRequest(c.Request.Context(), key, requestFingerprint(c, body))
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		if record != nil {
+			for name, value := range record.Headers {
+				if idempotentResponseHeaders[http.CanonicalHeaderKey(name)] {
+					c.Header(name, value)
+				}
+			}
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, c.Writer.Header().Get("Content-Type"), record.Body)
+			c.Abort()
+			return
+		}
+
+		// Ответ сохраняется и после отключения клиента: запрос уже выполнен
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Если обработчик запаниковал, ключ освобождается, чтобы повтор запроса не ждал
+		// истечения срока блокировки; паника продолжается до Recovery
+		finished := false
+		defer func() {
+			if !finished {
+				_ = h.service.FinishIdempotentRequest(ctx, key, lease, http.StatusInternalServerError, nil, nil)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		finished = true
+
+		headers := make(map[string]string)
+		for name := range recorder.Header() {
+			if idempotentResponseHeaders[name] {
+				headers[name] = recorder.Header().Get(name)
+			}
+		}
+
+		// Ответ уже отправлен клиенту; если сохранить его не удалось, ключ освобождается,
+		// чтобы повтор запроса не ждал истечения срока хранения. Ключ, который после истечения
+		// блокировки занял повтор, не затрагивается: у повтора другой токен аренды
+		if err := h.service.FinishIdempotentRequest(ctx, key, lease, recorder.Status(), headers, recorder.body.Bytes()); err != nil {
+			_ = h.service.FinishIdempotentRequest(ctx, key, lease, http.StatusInternalServerError, nil, nil)
+		}
+	}
+}
+
+// requestFingerprint — хеш метода, пути, параметров и тела запроса
+func requestFingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "?" + c.Request.URL.RawQuery + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newIdempotentCreateRequest(key string) *http.Request {
+	body := `{"service_name":"Netflix","price":599,"user_id":"` + uuid.New().String() + `","start_date":"01-2025"}`
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	return req
+}
+
+func TestCreateSubscription_IdempotencyKeyStoresResponse(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	lease := uuid.New()
+	mockService.On("BeginIdempotentRequest", mock.Anything, "key-1", mock.AnythingOfType("string")).Return(nil, lease, nil)
+	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{}).
+		Return(&model.CreateSubscriptionResponse{Subscription: &model.Subscription{ID: uuid.New(), Version: 1}}, nil)
+	mockService.On("FinishIdempotentRequest", mock.Anything, "key-1", lease, http.StatusCreated, mock.MatchedBy(func(headers map[string]string) bool {
+		_, hasRequestID := headers["X-Request-Id"]
+		return headers["Etag"] == `"1"` && headers["Content-Type"] == "application/json; charset=utf-8" && !hasRequestID
+	}), mock.MatchedBy(func(body []byte) bool {
+		return bytes.Contains(body, []byte(`"version":1`))
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentCreateRequest("key-1"))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSubscription_IdempotencyKeyReplay(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("BeginIdempotentRequest", mock.Anything, "key-1", mock.AnythingOfType("string")).Return(&model.IdempotencyRecord{
+		StatusCode: http.StatusCreated,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8", "Etag": `"1"`, "X-Request-Id": "original"},
+		Body:       []byte(`{"id":"stored"}`),
+	}, uuid.Nil, nil)
+
+	req := newIdempotentCreateRequest("key-1")
+	req.Header.Set("X-Request-ID", "retry")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":"stored"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	// Идентификатор запроса — текущего, а не сохраненного
+	assert.Equal(t, "retry", w.Header().Get("X-Request-ID"))
+	mockService.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateSubscription_IdempotencyLeaseLost(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	// Срок блокировки истек, и ключ занял повтор запроса: ответ не сохраняется,
+	// а освобождение с прежним токеном не трогает чужую запись
+	lease := uuid.New()
+	mockService.On("BeginIdempotentRequest", mock.Anything, "key-1", mock.AnythingOfType("string")).Return(nil, lease, nil)
+	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{}).
+		Return(&model.CreateSubscriptionResponse{Subscription: &model.Subscription{ID: uuid.New(), Version: 1}}, nil)
+	mockService.On("FinishIdempotentRequest", mock.Anything, "key-1", lease, http.StatusCreated, mock.Anything, mock.Anything).
+		Return(service.ErrIdempotencyRequestInProgress)
+	mockService.On("FinishIdempotentRequest", mock.Anything, "key-1", lease, http.StatusInternalServerError, map[string]string(nil), []byte(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentCreateRequest("key-1"))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSubscription_IdempotencyKeyReleasedOnPanic(t *testing.T) {
+	mockService := new(MockService)
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.POST("/api/v1/subscriptions", NewHandler(mockService).Idempotency(), func(c *gin.Context) {
+		panic("handler failed")
+	})
+
+	lease := uuid.New()
+	mockService.On("BeginIdempotentRequest", mock.Anything, "key-1", mock.AnythingOfType("string")).Return(nil, lease, nil)
+	mockService.On("FinishIdempotentRequest", mock.Anything, "key-1", lease, http.StatusInternalServerError, map[string]string(nil), []byte(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentCreateRequest("key-1"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSubscription_IdempotencyKeyBodyTooLarge(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", strings.NewReader(`{"service_name":"`+strings.Repeat("a", maxIdempotentRequestSize)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockService.AssertNotCalled(t, "BeginIdempotentRequest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateSubscription_IdempotencyKeyErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "reused with different body", err: service.ErrIdempotencyKeyReused, wantStatus: http.StatusUnprocessableEntity},
+		{name: "in progress", err: service.ErrIdempotencyRequestInProgress, wantStatus: http.StatusConflict},
+		{name: "invalid key", err: service.ErrInvalidIdempotencyKey, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			router := setupTestRouter(NewHandler(mockService))
+
+			mockService.On("BeginIdempotentRequest", mock.Anything, "key-1", mock.AnythingOfType("string")).Return(nil, uuid.Nil, tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newIdempotentCreateRequest("key-1"))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
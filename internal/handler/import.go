@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/importer"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxImportSize — наибольший размер тела запроса импорта
+	maxImportSize = 10 << 20
+	// maxImportRows — наибольшее число строк в одном файле импорта
+	maxImportRows = 10000
+	// importWriteTimeout — сколько сервер ждет ответа после начала обработки очередной части
+	// импорта (500 строк, по несколько запросов к базе на строку). Срок продлевается перед каждой
+	// частью, поэтому общий WriteTimeout сервера не обрывает импорт большого файла.
+	importWriteTimeout = time.Minute
+)
+
+// ImportSubscriptions создает подписки из файла CSV или JSON
+// @Summary Импорт подписок из CSV или JSON
+// @Description Принимает файл в поле file (multipart/form-data) или телом запроса (Content-Type: text/csv или application/json).
+// @Description CSV — с заголовком; столбцы сопоставляются с полями по имени: service_name, price, user_id, start_date, end_date, category,
+// @Description остальные столбцы пропускаются; разделитель (',', ';' или табуляция) определяется по заголовку. JSON — массив объектов,
+// @Description как в POST /subscriptions. Каждая строка проверяется по правилам создания подписки; если хотя бы одна строка
+// @Description не прошла проверку, ничего не сохраняется (422). Строки сохраняются частями по 500 в отдельных транзакциях:
+// @Description при ошибке сохраняются только предыдущие части, а resume_from в отчете указывает, с какой строки повторить импорт.
+// @Tags subscriptions
+// @Accept text/csv,json,mpfd
+// @Produce json
+// @Param file formData file false "Файл CSV или JSON"
+// @Param format query string false "Формат файла, если его нельзя определить по Content-Type или расширению" Enums(csv, json)
+// @Param dry_run query bool false "Только проверить строки, ничего не сохраняя"
+// @Param resume_from query int false "Номер строки данных (с 1), с которой продолжить импорт; предыдущие строки пропускаются"
+// @Param force query bool false "Сохранять подписки, несмотря на пересечение с существующими"
+// @Success 200 {object} model.ImportReport
+// @Failure 400 {object} model.Problem "Файл не удалось разобрать"
+// @Failure 409 {object} model.ImportReport "Строка пересекается с существующей подпиской; предыдущие части сохранены"
+// @Failure 413 {object} model.Problem "Файл слишком большой"
+// @Failure 415 {object} model.Problem "Неизвестный формат файла"
+// @Failure 422 {object} model.ImportReport "Строки не прошли проверку, ничего не сохранено"
+// @Router /subscriptions/import [post]
+func (h *Handler) ImportSubscriptions(c *gin.Context) {
+	opts, err := parseImportOptions(c)
+	if err != nil {
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	body, format, err := importSource(c)
+	if err != nil {
+		respondImportReadError(c, err)
+		return
+	}
+	defer body.Close()
+
+	records, err := importer.Read(body, format, maxImportRows)
+	if err != nil {
+		respondImportReadError(c, err)
+		return
+	}
+
+	rows := make([]model.ImportRow, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, parseImportRecord(record))
+	}
+
+	// Не все ResponseWriter поддерживают дедлайны (например, в тестах) — тогда срок не продлевается
+	controller := http.NewResponseController(c.Writer)
+	extendDeadline := func() {
+		_ = controller.SetWriteDeadline(time.Now().Add(importWriteTimeout))
+	}
+	extendDeadline()
+	opts.OnChunk = extendDeadline
+
+	report, err := h.service.ImportSubscriptions(c.Request.Context(), rows, opts)
+	if err != nil {
+		if report == nil {
+			RespondError(c, err)
+			return
+		}
+		if service.KindOf(err) == service.KindInternal {
+			_ = c.Error(err)
+		}
+		c.JSON(errorStatus(err), report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// errUnsupportedImportFormat — формат файла не удалось определить
+var errUnsupportedImportFormat = errors.New("unsupported file format, expected text/csv or application/json (or format=csv|json)")
+
+// importSource возвращает содержимое файла импорта и его формат. Формат берется из параметра format,
+// иначе — из расширения имени файла или Content-Type части multipart, иначе — из Content-Type запроса.
+func importSource(c *gin.Context) (io.ReadCloser, importer.Format, error) {
+	var format importer.Format
+	if value := c.Query("format"); value != "" {
+		parsed, err := importer.ParseFormat(value)
+		if err != nil {
+			return nil, "", err
+		}
+		format = parsed
+	}
+
+	if c.ContentType() != "multipart/form-data" {
+		if format == "" {
+			detected, ok := importer.FormatForMIMEType(c.ContentType())
+			if !ok {
+				return nil, "", errUnsupportedImportFormat
+			}
+			format = detected
+		}
+		return c.Request.Body, format, nil
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format == "" {
+		detected, ok := importer.FormatForFilename(header.Filename)
+		if !ok {
+			mediaType, _, _ := mime.ParseMediaType(header.Header.Get("Content-Type"))
+			if detected, ok = importer.FormatForMIMEType(mediaType); !ok {
+				return nil, "", errUnsupportedImportFormat
+			}
+		}
+		format = detected
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return file, format, nil
+}
+
+// respondImportReadError отвечает на ошибку чтения файла импорта
+func respondImportReadError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		RespondProblem(c, http.StatusRequestEntityTooLarge, codePayloadTooLarge, fmt.Sprintf("file is larger than %d bytes", maxImportSize))
+	case errors.Is(err, importer.ErrTooManyRows):
+		RespondProblem(c, http.StatusRequestEntityTooLarge, codePayloadTooLarge, err.Error())
+	case errors.Is(err, errUnsupportedImportFormat):
+		RespondProblem(c, http.StatusUnsupportedMediaType, codeUnsupportedMediaType, err.Error())
+	case errors.Is(err, importer.ErrUnknownFormat):
+		RespondInvalid(c, codeInvalidParameter, err.Error())
+	default:
+		RespondInvalid(c, codeMalformedBody, err.Error())
+	}
+}
+
+// parseImportOptions разбирает параметры dry_run, resume_from и force
+func parseImportOptions(c *gin.Context) (model.ImportOptions, error) {
+	var opts model.ImportOptions
+
+	writeOpts, err := ParseWriteOptions(c)
+	if err != nil {
+		return opts, err
+	}
+	opts.Force = writeOpts.Force
+
+	if value := c.Query("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, errors.New("invalid dry_run parameter, expected boolean")
+		}
+		opts.DryRun = dryRun
+	}
+
+	if value := c.Query("resume_from"); value != "" {
+		resumeFrom, err := strconv.Atoi(value)
+		if err != nil || resumeFrom < 1 {
+			return opts, errors.New("invalid resume_from, expected positive integer")
+		}
+		opts.ResumeFrom = resumeFrom
+	}
+
+	return opts, nil
+}
+
+// parseImportRecord проверяет строку импорта по тем же правилам, что и тело POST /subscriptions,
+// и разбирает даты. Все нарушения строки собираются вместе; подписка строится, только если их нет.
+func parseImportRecord(record importer.Record) model.ImportRow {
+	row := model.ImportRow{Row: record.Row, Errors: record.Errors}
+	req := record.Request
+
+	// Поле, значение которого не удалось разобрать, не проверяется повторно
+	invalid := func(field string) bool {
+		return slices.ContainsFunc(row.Errors, func(v model.FieldViolation) bool { return v.Field == field })
+	}
+
+	var validationErrs validator.ValidationErrors
+	if err := binding.Validator.ValidateStruct(&req); errors.As(err, &validationErrs) {
+		for _, violation := range fieldViolations(validationErrs) {
+			if !invalid(violation.Field) {
+				row.Errors = append(row.Errors, violation)
+			}
+		}
+	}
+
+	var startDate time.Time
+	if req.StartDate != "" {
+		parsed, err := parseMonthYear(req.StartDate)
+		if err != nil {
+			row.Errors = append(row.Errors, model.FieldViolation{Field: "start_date", Message: "must be in MM-YYYY format"})
+		}
+		startDate = parsed
+	}
+
+	var endDate *time.Time
+	if req.EndDate != nil {
+		parsed, err := parseMonthYear(*req.EndDate)
+		if err != nil {
+			row.Errors = append(row.Errors, model.FieldViolation{Field: "end_date", Message: "must be in MM-YYYY format"})
+		}
+		endDate = &parsed
+	}
+
+	if len(row.Errors) > 0 {
+		return row
+	}
+
+	row.Subscription = &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: req.ServiceName,
+		Price:       req.Price,
+		UserID:      req.UserID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    req.Category,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	return row
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importTestUserID = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+
+func TestImportSubscriptionsHandler_CSV(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	var rows []model.ImportRow
+	mockService.On("ImportSubscriptions", mock.Anything, mock.Anything, mock.MatchedBy(func(opts model.ImportOptions) bool {
+		return opts.DryRun && opts.ResumeFrom == 2 && !opts.Force && opts.OnChunk != nil
+	})).
+		Run(func(args mock.Arguments) { rows = args.Get(1).([]model.ImportRow) }).
+		Return(&model.ImportReport{DryRun: true, Total: 3}, nil)
+
+	body := "service_name;price;user_id;start_date;end_date\n" +
+		"Netflix;599;" + importTestUserID + ";01-2025;12-2025\n" +
+		";0;" + importTestUserID + ";2025-01;\n" +
+		"Spotify;abc;" + importTestUserID + ";01-2025;13-2025\n"
+
+	req := httptest.NewRequest("POST", "/api/v1/subscriptions/import?dry_run=true&resume_from=2", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, 1, rows[0].Row)
+	assert.Empty(t, rows[0].Errors)
+	require.NotNil(t, rows[0].Subscription)
+	assert.Equal(t, "Netflix", rows[0].Subscription.ServiceName)
+	assert.Equal(t, uuid.MustParse(importTestUserID), rows[0].Subscription.UserID)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), rows[0].Subscription.StartDate)
+	assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), *rows[0].Subscription.EndDate)
+
+	// Нарушения правил тела POST /subscriptions и формата дат собираются вместе
+	assert.Nil(t, rows[1].Subscription)
+	assert.ElementsMatch(t, []model.FieldViolation{
+		{Field: "service_name", Message: "is required"},
+		{Field: "price", Message: "is required"},
+		{Field: "start_date", Message: "must be in MM-YYYY format"},
+	}, rows[1].Errors)
+
+	// Неразобранная цена не проверяется повторно правилом required
+	assert.Nil(t, rows[2].Subscription)
+	assert.Equal(t, []model.FieldViolation{
+		{Field: "price", Message: "must be integer"},
+		{Field: "end_date", Message: "must be in MM-YYYY format"},
+	}, rows[2].Errors)
+}
+
+func TestImportSubscriptionsHandler_MultipartJSON(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("ImportSubscriptions", mock.Anything, mock.MatchedBy(func(rows []model.ImportRow) bool {
+		return len(rows) == 1 && rows[0].Subscription != nil && rows[0].Subscription.Price == 599
+	}), mock.MatchedBy(func(opts model.ImportOptions) bool {
+		return opts.Force && !opts.DryRun && opts.ResumeFrom == 0
+	})).Return(&model.ImportReport{Total: 1, Valid: 1, Created: 1}, nil)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "subscriptions.json")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(`[{"service_name": "Netflix", "price": 599, "user_id": "` + importTestUserID + `", "start_date": "01-2025"}]`))
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest("POST", "/api/v1/subscriptions/import?force=true", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report model.ImportReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 1, report.Created)
+	mockService.AssertExpectations(t)
+}
+
+func TestImportSubscriptionsHandler_ExtendsWriteDeadline(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	// Сервис сообщает о начале каждой части строк, обработчик продлевает срок записи ответа
+	mockService.On("ImportSubscriptions", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(2).(model.ImportOptions)
+			opts.OnChunk()
+			opts.OnChunk()
+		}).
+		Return(&model.ImportReport{}, nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/subscriptions/import", strings.NewReader(`[]`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// Первый раз — после чтения файла, затем перед каждой частью
+	assert.Equal(t, 3, w.deadlines)
+}
+
+func TestImportSubscriptionsHandler_ReportWithError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "invalid rows", err: service.ErrImportInvalid, status: http.StatusUnprocessableEntity},
+		{name: "overlap", err: &service.ImportError{Row: 1, Err: &service.OverlapError{}}, status: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			router := setupTestRouter(NewHandler(mockService))
+
+			resumeFrom := 1
+			mockService.On("ImportSubscriptions", mock.Anything, mock.Anything, mock.Anything).
+				Return(&model.ImportReport{Total: 1, ResumeFrom: &resumeFrom}, tt.err)
+
+			req := httptest.NewRequest("POST", "/api/v1/subscriptions/import", strings.NewReader(`[]`))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Вместо problem+json возвращается отчет, чтобы клиент видел результат каждой строки
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+			var report model.ImportReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, 1, *report.ResumeFrom)
+		})
+	}
+}
+
+func TestImportSubscriptionsHandler_BadRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        string
+		status      int
+		code        string
+	}{
+		{name: "unknown content type", url: "/api/v1/subscriptions/import", contentType: "text/plain", body: "x", status: http.StatusUnsupportedMediaType, code: codeUnsupportedMediaType},
+		{name: "unknown format", url: "/api/v1/subscriptions/import?format=xml", contentType: "text/csv", body: "x", status: http.StatusBadRequest, code: codeInvalidParameter},
+		{name: "invalid dry_run", url: "/api/v1/subscriptions/import?dry_run=maybe", contentType: "text/csv", body: "x", status: http.StatusBadRequest, code: codeInvalidParameter},
+		{name: "invalid resume_from", url: "/api/v1/subscriptions/import?resume_from=0", contentType: "text/csv", body: "x", status: http.StatusBadRequest, code: codeInvalidParameter},
+		{name: "missing column", url: "/api/v1/subscriptions/import", contentType: "text/csv", body: "service_name,price\n", status: http.StatusBadRequest, code: codeMalformedBody},
+		{name: "malformed json", url: "/api/v1/subscriptions/import", contentType: "application/json", body: `[{"price": }]`, status: http.StatusBadRequest, code: codeMalformedBody},
+		{name: "too large", url: "/api/v1/subscriptions/import", contentType: "application/json", body: `[` + strings.Repeat(" ", maxImportSize) + `]`, status: http.StatusRequestEntityTooLarge, code: codePayloadTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			router := setupTestRouter(NewHandler(mockService))
+
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+
+			var problem model.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.code, problem.Code)
+			mockService.AssertNotCalled(t, "ImportSubscriptions", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
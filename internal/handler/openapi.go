@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	// Ошибки проверки по схеме не содержат саму схему и значение: они попадают в ответ и в лог
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+// OpenAPIValidator проверяет запросы по спецификации Swagger 2.0, которую отдает /swagger
+// (docs.SwaggerInfo.ReadDoc()). Запрос, не соответствующий спецификации, отклоняется
+// с 400 validation_failed и списком нарушений. При validateResponses проверяются и JSON-ответы:
+// расхождения со спецификацией записываются в лог, ответ клиенту не меняется.
+// Запросы, для которых в спецификации нет операции, не проверяются.
+func OpenAPIValidator(spec string, validateResponses bool) (gin.HandlerFunc, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal([]byte(spec), &doc2); err != nil {
+		return nil, fmt.Errorf("parse swagger spec: %w", err)
+	}
+
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("convert swagger spec: %w", err)
+	}
+
+	// Операции сопоставляются только по пути: хост и схема из спецификации не учитываются
+	doc.Servers = openapi3.Servers{{URL: doc2.BasePath}}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid swagger spec: %w", err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build spec router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:            true,
+		IncludeResponseStatus: true,
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	}
+
+	// Файл импорта передается и как multipart/form-data, и «сырым» телом CSV или JSON;
+	// Swagger 2.0 описывает только поле формы, поэтому тело такого запроса проверяет обработчик
+	fileOptions := *options
+	fileOptions.ExcludeRequestBody = true
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if uploadsFile(route.Operation) {
+			input.Options = &fileOptions
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			problem := newProblem(c, http.StatusBadRequest, codeValidationFailed, "request does not match the API specification")
+			problem.Errors = specViolations(err)
+			writeProblem(c, problem)
+			return
+		}
+
+		if !validateResponses || streamsEvents(route.Operation) {
+			c.Next()
+			return
+		}
+
+		recorder := &specRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.skipped {
+			return
+		}
+
+		if err := validateResponse(c, input, recorder); err != nil {
+			logger.GetLogger().WithFields(logrus.Fields{
+				"method":     c.Request.Method,
+				"path":       route.Path,
+				"status":     recorder.Status(),
+				"request_id": c.GetString(requestIDKey),
+				"error":      err.Error(),
+			}).Warn("Response does not match the API specification")
+		}
+	}, nil
+}
+
+// specRecorder запоминает тело ответа для проверки по спецификации. Схемы описывают только JSON,
+// поэтому ответы других типов (CSV, XLSX, NDJSON) не проверяются и не накапливаются: выгрузка
+// идет клиенту потоком, как без проверки. Тип ответа определяется при первой записи тела.
+type specRecorder struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	decided bool
+	skipped bool
+}
+
+func (w *specRecorder) Write(data []byte) (int, error) {
+	if w.records() {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *specRecorder) WriteString(s string) (int, error) {
+	if w.records() {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap открывает исходный writer для http.ResponseController: выгрузки продлевают срок записи
+func (w *specRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *specRecorder) records() bool {
+	if !w.decided {
+		w.decided = true
+		w.skipped = !jsonContentType(w.Header().Get("Content-Type"))
+	}
+	return !w.skipped
+}
+
+// jsonContentType — ответ в JSON, в том числе ошибка application/problem+json
+func jsonContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, model.ProblemContentType)
+}
+
+// validateResponse проверяет записанный ответ. Ответы с ошибкой (application/problem+json)
+// проверяются по схеме, описанной в спецификации для application/json.
+func validateResponse(c *gin.Context, input *openapi3filter.RequestValidationInput, recorder *specRecorder) error {
+	header := recorder.Header().Clone()
+	if strings.HasPrefix(header.Get("Content-Type"), model.ProblemContentType) {
+		header.Set("Content-Type", "application/json")
+	}
+
+	return openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 recorder.Status(),
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options:                input.Options,
+	})
+}
+
+// streamsEvents — операция отдает поток text/event-stream: он не заканчивается,
+// поэтому его нельзя накопить и проверить целиком
+func streamsEvents(operation *openapi3.Operation) bool {
+	response := operation.Responses.Status(http.StatusOK)
+	return response != nil && response.Value != nil && response.Value.Content.Get("text/event-stream") != nil
+}
+
+// uploadsFile — операция принимает файл (formData file в спецификации)
+func uploadsFile(operation *openapi3.Operation) bool {
+	if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return false
+	}
+	return operation.RequestBody.Value.GetMediaType("multipart/form-data") != nil
+}
+
+// specViolations — нарушения спецификации по полям: имя параметра или путь в теле запроса
+func specViolations(err error) []model.FieldViolation {
+	var violations []model.FieldViolation
+
+	// errors.As здесь не подходит: RequestError разворачивается во вложенный MultiError ошибок схемы
+	if multi, ok := err.(openapi3.MultiError); ok {
+		for _, e := range multi {
+			violations = append(violations, specViolations(e)...)
+		}
+		return violations
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []model.FieldViolation{{Message: err.Error()}}
+	}
+
+	field := ""
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+
+	schemaErrs := schemaErrors(requestErr.Err)
+	if len(schemaErrs) == 0 {
+		message := requestErr.Reason
+		if message == "" && requestErr.Err != nil {
+			message = requestErr.Err.Error()
+		}
+		return []model.FieldViolation{{Field: field, Message: message}}
+	}
+
+	for _, schemaErr := range schemaErrs {
+		path := field
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 && requestErr.Parameter == nil {
+			path = strings.Join(pointer, ".")
+		}
+		violations = append(violations, model.FieldViolation{Field: path, Message: schemaErr.Reason})
+	}
+
+	return violations
+}
+
+// schemaErrors — ошибки проверки по схеме, в том числе собранные в MultiError
+func schemaErrors(err error) []*openapi3.SchemaError {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var result []*openapi3.SchemaError
+		for _, e := range multi {
+			result = append(result, schemaErrors(e)...)
+		}
+		return result
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []*openapi3.SchemaError{schemaErr}
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/docs"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupValidatedRouter — маршруты v1 с проверкой запросов и ответов по docs/swagger.json;
+// hook собирает предупреждения о расхождении ответов со спецификацией
+func setupValidatedRouter(t *testing.T, svc *MockService) (*gin.Engine, *test.Hook) {
+	t.Helper()
+
+	logger.Init("info", "json")
+	hook := test.NewLocal(logger.GetLogger())
+
+	validator, err := OpenAPIValidator(docs.SwaggerInfo.ReadDoc(), true)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	NewHandler(svc).SetupRoutes(router, validator)
+
+	return router, hook
+}
+
+func sendJSON(router *gin.Engine, method, path, contentType string, body any) *httptest.ResponseRecorder {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func specWarnings(hook *test.Hook) []string {
+	var warnings []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message+": "+entry.Data["error"].(string))
+		}
+	}
+	return warnings
+}
+
+func TestOpenAPIValidator_ResponsesMatchSpec(t *testing.T) {
+	svc := new(MockService)
+	router, hook := setupValidatedRouter(t, svc)
+
+	category := "video"
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	sub := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       400,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     &endDate,
+		Category:    &category,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+		Version:     1,
+	}
+	missing := uuid.New()
+	next := "cursor"
+
+	svc.On("CreateSubscription", mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.CreateSubscriptionResponse{Subscription: sub, Warnings: []string{"overlap"}}, nil)
+	svc.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+	svc.On("GetSubscription", mock.Anything, missing).Return(nil, repository.ErrNotFound)
+	svc.On("UpdateSubscription", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	svc.On("PatchSubscription", mock.Anything, sub.ID, mock.Anything, mock.Anything).Return(sub, nil)
+	svc.On("DeleteSubscription", mock.Anything, sub.ID, mock.Anything).Return(nil)
+	svc.On("ListSubscriptions", mock.Anything, mock.Anything).
+		Return(&model.SubscriptionPage{Items: []*model.Subscription{sub}, NextCursor: &next}, nil)
+	svc.On("CalculateSummary", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.SummaryResponse{TotalAmount: 2400, Count: 1}, nil)
+	svc.On("ListOverlaps", mock.Anything).Return([]*model.SubscriptionOverlap{}, nil)
+
+	input := map[string]any{
+		"service_name": "Yandex Plus",
+		"price":        400,
+		"user_id":      sub.UserID,
+		"start_date":   "07-2025",
+		"end_date":     "12-2025",
+		"category":     "video",
+	}
+	requests := []struct {
+		method, path, contentType string
+		body                      any
+		status                    int
+	}{
+		{http.MethodPost, "/api/v1/subscriptions?force=true", "application/json", input, http.StatusCreated},
+		{http.MethodGet, "/api/v1/subscriptions/" + sub.ID.String(), "", nil, http.StatusOK},
+		{http.MethodGet, "/api/v1/subscriptions/" + missing.String(), "", nil, http.StatusNotFound},
+		{http.MethodPut, "/api/v1/subscriptions/" + sub.ID.String(), "application/json", input, http.StatusOK},
+		{http.MethodPatch, "/api/v1/subscriptions/" + sub.ID.String(), "application/merge-patch+json", map[string]any{"end_date": nil}, http.StatusOK},
+		{http.MethodDelete, "/api/v1/subscriptions/" + sub.ID.String(), "", nil, http.StatusNoContent},
+		{http.MethodGet, "/api/v1/subscriptions?limit=10&status=active", "", nil, http.StatusOK},
+		{http.MethodPost, "/api/v1/subscriptions/summary", "application/json", map[string]any{"start_date": "01-2025", "end_date": "12-2025"}, http.StatusOK},
+		{http.MethodGet, "/api/v1/subscriptions/overlaps", "", nil, http.StatusOK},
+	}
+
+	for _, r := range requests {
+		w := sendJSON(router, r.method, r.path, r.contentType, r.body)
+		assert.Equal(t, r.status, w.Code, "%s %s: %s", r.method, r.path, w.Body.String())
+	}
+
+	assert.Empty(t, specWarnings(hook))
+}
+
+func TestOpenAPIValidator_NonJSONBodies(t *testing.T) {
+	svc := new(MockService)
+	router, hook := setupValidatedRouter(t, svc)
+
+	svc.On("ImportSubscriptions", mock.Anything, mock.Anything, mock.Anything).Return(&model.ImportReport{Total: 1, Rows: []*model.ImportRowResult{}}, nil)
+	svc.On("ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything).Return(exportTestSubscriptions(), nil)
+
+	// Файл импорта без multipart: в спецификации его не описать, тело проверяет обработчик
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/import",
+		strings.NewReader("service_name;price;user_id;start_date\nNetflix;599;"+importTestUserID+";01-2025\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Ответы CSV и NDJSON не проверяются: схемы описывают только JSON
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions", nil)
+	req.Header.Set("Accept", "text/csv")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = sendJSON(router, http.MethodGet, "/api/v1/subscriptions/export", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Empty(t, specWarnings(hook))
+}
+
+func TestOpenAPIValidator_StreamsExports(t *testing.T) {
+	svc := new(MockService)
+	router, hook := setupValidatedRouter(t, svc)
+
+	svc.On("ExportSubscriptions", mock.Anything, mock.Anything, mock.Anything).Return(exportTestSubscriptions(), nil)
+
+	exports := []struct {
+		path, contentType string
+	}{
+		{"/api/v1/subscriptions/export", ndjsonContentType},
+		{"/api/v1/subscriptions?format=csv", "text/csv"},
+		{"/api/v1/subscriptions?format=xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	}
+
+	for _, e := range exports {
+		w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, e.path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, e.path)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), e.contentType), e.path)
+		assert.NotEmpty(t, w.Body.Bytes(), e.path)
+		// Проверка ответов не перехватывает выгрузку: срок записи продлевается через нее
+		assert.Positive(t, w.deadlines, e.path)
+	}
+
+	assert.Empty(t, specWarnings(hook))
+}
+
+func TestSpecRecorder_SkipsNonJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for contentType, recorded := range map[string]bool{
+		"text/csv":                        false,
+		ndjsonContentType:                 false,
+		"application/json; charset=utf-8": true,
+		model.ProblemContentType:          true,
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		recorder := &specRecorder{ResponseWriter: c.Writer}
+		recorder.Header().Set("Content-Type", contentType)
+
+		_, err := recorder.WriteString("{}")
+		require.NoError(t, err)
+
+		assert.Equal(t, !recorded, recorder.skipped, contentType)
+		assert.Equal(t, recorded, recorder.body.Len() > 0, contentType)
+	}
+}
+
+func TestOpenAPIValidator_RejectsRequest(t *testing.T) {
+	svc := new(MockService)
+	router, _ := setupValidatedRouter(t, svc)
+
+	w := sendJSON(router, http.MethodPost, "/api/v1/subscriptions?force=maybe", "application/json", map[string]any{
+		"service_name": "Yandex Plus",
+		"price":        "400",
+		"start_date":   "07-2025",
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem model.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, codeValidationFailed, problem.Code)
+
+	fields := make(map[string]string)
+	for _, v := range problem.Errors {
+		fields[v.Field] = v.Message
+	}
+	assert.Contains(t, fields, "force")
+	assert.Contains(t, fields, "price")
+	assert.Contains(t, fields, "user_id")
+	svc.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOpenAPIValidator_RejectsEnumValue(t *testing.T) {
+	router, _ := setupValidatedRouter(t, new(MockService))
+
+	w := sendJSON(router, http.MethodGet, "/api/v1/subscriptions?status=paused", "", nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"status"`)
+}
+
+func TestOpenAPIValidator_LogsResponseMismatch(t *testing.T) {
+	logger.Init("info", "json")
+	hook := test.NewLocal(logger.GetLogger())
+
+	validator, err := OpenAPIValidator(docs.SwaggerInfo.ReadDoc(), true)
+	require.NoError(t, err)
+
+	// Обработчик, ответ которого расходится со спецификацией: у подписки нет обязательной схемы полей
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/subscriptions/:id", validator, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": 42})
+	})
+
+	w := sendJSON(router, http.MethodGet, "/api/v1/subscriptions/"+uuid.NewString(), "", nil)
+
+	// Ответ клиенту не меняется, расхождение попадает в лог
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":42}`, w.Body.String())
+	require.Len(t, specWarnings(hook), 1)
+	assert.Equal(t, "/subscriptions/{id}", hook.LastEntry().Data["path"])
+}
+
+func TestOpenAPIValidator_SkipsUnknownRoutes(t *testing.T) {
+	validator, err := OpenAPIValidator(docs.SwaggerInfo.ReadDoc(), false)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/graphql", validator, func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := sendJSON(router, http.MethodPost, "/graphql", "application/json", map[string]any{"query": "{}"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// problemTypePrefix — префикс URI типа ошибки; полный URI — префикс и код ошибки
+const problemTypePrefix = "urn:subscription-service:problem:"
+
+// Коды ошибок, которые выявляются при разборе запроса, до вызова сервиса
+const (
+	codeInvalidID            = "invalid_id"
+	codeInvalidParameter     = "invalid_parameter"
+	codeInvalidDateFormat    = "invalid_date_format"
+	codeMalformedBody        = "malformed_body"
+	codeValidationFailed     = "validation_failed"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codePayloadTooLarge      = "payload_too_large"
+	codeExportTooLarge       = "export_too_large"
+)
+
+// errorStatuses — HTTP-статус ответа для каждой категории ошибки сервиса
+var errorStatuses = map[service.ErrorKind]int{
+	service.KindInvalid:            http.StatusBadRequest,
+	service.KindNotFound:           http.StatusNotFound,
+	service.KindConflict:           http.StatusConflict,
+	service.KindPreconditionFailed: http.StatusPreconditionFailed,
+	service.KindUnprocessable:      http.StatusUnprocessableEntity,
+	service.KindInternal:           http.StatusInternalServerError,
+}
+
+func init() {
+	// Нарушения правил проверки называют поля так же, как они называются в JSON
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// errorStatus — HTTP-статус ответа на ошибку сервиса
+func errorStatus(err error) int {
+	if status, ok := errorStatuses[service.KindOf(err)]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// newProblem собирает тело ответа с ошибкой для текущего запроса
+func newProblem(c *gin.Context, status int, code, detail string) *model.Problem {
+	return &model.Problem{
+		Type:      problemTypePrefix + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: c.GetString(requestIDKey),
+	}
+}
+
+// writeProblem отправляет ответ application/problem+json и прерывает обработку запроса
+func writeProblem(c *gin.Context, problem *model.Problem) {
+	c.Header("Content-Type", model.ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// RespondProblem отвечает ошибкой, выявленной при разборе запроса
+func RespondProblem(c *gin.Context, status int, code, detail string) {
+	writeProblem(c, newProblem(c, status, code, detail))
+}
+
+// RespondInvalid отвечает 400 на неверный параметр запроса
+func RespondInvalid(c *gin.Context, code, detail string) {
+	RespondProblem(c, http.StatusBadRequest, code, detail)
+}
+
+// RespondError отвечает на ошибку сервиса статусом и кодом по её категории.
+// Текст внутренних ошибок и подробности ошибок репозитория (например, имя нарушенного
+// ограничения) клиенту не передаются — они попадают в лог запроса.
+// При пересечении подписок ответ содержит список конфликтующих подписок.
+func RespondError(c *gin.Context, err error) {
+	writeProblem(c, errorProblem(c, err))
+}
+
+// errorProblem собирает тело ответа на ошибку сервиса
+func errorProblem(c *gin.Context, err error) *model.Problem {
+	described := service.Describe(err)
+	if service.Concealed(err) {
+		_ = c.Error(err)
+	}
+
+	problem := newProblem(c, errorStatus(err), described.Code, described.Message)
+
+	var overlapErr *service.OverlapError
+	if errors.As(err, &overlapErr) {
+		problem.Conflicts = make([]uuid.UUID, 0, len(overlapErr.Overlaps))
+		for _, o := range overlapErr.Overlaps {
+			problem.Conflicts = append(problem.Conflicts, o.ID)
+		}
+	}
+
+	return problem
+}
+
+// RespondBindError отвечает 400 на ошибку разбора тела запроса. Для нарушений правил
+// проверки (binding) ответ содержит список нарушений по полям.
+func RespondBindError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		problem := newProblem(c, http.StatusBadRequest, codeValidationFailed, "request body failed validation")
+		problem.Errors = fieldViolations(validationErrs)
+		writeProblem(c, problem)
+	case errors.As(err, &typeErr):
+		problem := newProblem(c, http.StatusBadRequest, codeValidationFailed, "request body failed validation")
+		problem.Errors = []model.FieldViolation{{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be %s", typeErr.Type.String()),
+		}}
+		writeProblem(c, problem)
+	case errors.Is(err, io.EOF):
+		RespondInvalid(c, codeMalformedBody, "request body is empty")
+	default:
+		RespondInvalid(c, codeMalformedBody, err.Error())
+	}
+}
+
+// fieldViolations — нарушения правил проверки по полям
+func fieldViolations(validationErrs validator.ValidationErrors) []model.FieldViolation {
+	violations := make([]model.FieldViolation, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		violations = append(violations, model.FieldViolation{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Message: violationMessage(fieldErr),
+		})
+	}
+
+	return violations
+}
+
+// violationMessage — описание нарушенного правила проверки
+func violationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fieldErr.Param()
+	case "max":
+		return "must be at most " + fieldErr.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	default:
+		return fmt.Sprintf("failed the %q rule", fieldErr.Tag())
+	}
+}
+
+// fieldPath убирает из пути поля имя корневой структуры: "CreateSubscriptionRequest.price" → "price"
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+
+	return namespace
+}
+
+// jsonFieldName — имя поля структуры в JSON
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateSubscriptionHandler_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "validation error", err: service.ErrInvalidEndDate, wantStatus: http.StatusBadRequest, wantCode: "invalid_end_date"},
+		{name: "duplicate", err: fmt.Errorf("%w: subscriptions_pkey", repository.ErrAlreadyExists), wantStatus: http.StatusConflict, wantCode: "already_exists"},
+		{name: "constraint violation", err: fmt.Errorf("%w: subscriptions_price_check", repository.ErrConstraintViolation), wantStatus: http.StatusUnprocessableEntity, wantCode: "constraint_violation"},
+		{name: "internal error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantCode: "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			router := setupTestRouter(NewHandler(mockService))
+
+			mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{}).
+				Return(nil, tt.err)
+
+			body := `{"service_name":"Netflix","price":599,"user_id":"` + uuid.New().String() + `","start_date":"01-2025"}`
+			req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem model.Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantCode, problem.Code)
+			assert.Equal(t, "urn:subscription-service:problem:"+tt.wantCode, problem.Type)
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, http.StatusText(tt.wantStatus), problem.Title)
+			assert.Equal(t, "/api/v1/subscriptions", problem.Instance)
+			assert.NotEmpty(t, problem.RequestID)
+			// Имена ограничений и текст внутренних ошибок клиенту не передаются
+			assert.NotContains(t, problem.Detail, "subscriptions_")
+			assert.NotContains(t, problem.Detail, "connection refused")
+		})
+	}
+}
+
+func TestGetSubscriptionHandler_ErrorStatus(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	missingID := uuid.New()
+	brokenID := uuid.New()
+	mockService.On("GetSubscription", mock.Anything, missingID).Return(nil, service.ErrNotFound)
+	mockService.On("GetSubscription", mock.Anything, brokenID).Return(nil, errors.New("connection refused"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/subscriptions/"+missingID.String(), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Ошибка базы данных — не повод отвечать 404
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/subscriptions/"+brokenID.String(), nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestCreateSubscriptionHandler_InternalErrorDetailHidden(t *testing.T) {
+	mockService := new(MockService)
+	router := setupTestRouter(NewHandler(mockService))
+
+	mockService.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*model.Subscription"), model.WriteOptions{}).
+		Return(nil, errors.New("pq: password authentication failed for user \"postgres\""))
+
+	body := `{"service_name":"Netflix","price":599,"user_id":"` + uuid.New().String() + `","start_date":"01-2025"}`
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-42")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem model.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, "internal server error", problem.Detail)
+	assert.Equal(t, "req-42", problem.RequestID)
+	assert.Equal(t, "req-42", w.Header().Get("X-Request-ID"))
+}
+
+func TestCreateSubscriptionHandler_FieldViolations(t *testing.T) {
+	router := setupTestRouter(NewHandler(new(MockService)))
+
+	body := `{"service_name":"Netflix","price":0,"start_date":"01-2025"}`
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var problem model.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "validation_failed", problem.Code)
+	assert.ElementsMatch(t, []model.FieldViolation{
+		{Field: "price", Message: "is required"},
+		{Field: "user_id", Message: "is required"},
+	}, problem.Errors)
+}
+
+func TestCreateSubscriptionHandler_FieldTypeViolation(t *testing.T) {
+	router := setupTestRouter(NewHandler(new(MockService)))
+
+	body := `{"service_name":"Netflix","price":"cheap","user_id":"` + uuid.New().String() + `","start_date":"01-2025"}`
+	req, _ := http.NewRequest("POST", "/api/v1/subscriptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem model.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "validation_failed", problem.Code)
+	assert.Equal(t, []model.FieldViolation{{Field: "price", Message: "must be int"}}, problem.Errors)
+}
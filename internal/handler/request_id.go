@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	// requestIDKey — ключ идентификатора запроса в gin.Context (его же пишет в лог logger.GinLogger)
+	requestIDKey = "request_id"
+	// maxRequestIDLength — более длинный X-Request-ID клиента заменяется своим
+	maxRequestIDLength = 128
+)
+
+// RequestID присваивает запросу идентификатор: берет его из заголовка X-Request-ID
+// или генерирует новый, и возвращает в заголовке ответа
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
@@ -4,17 +4,42 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func (h *Handler) SetupRoutes(router *gin.Engine) {
-	api := router.Group("/api/v1")
+// SetupRoutes регистрирует маршруты /api/v1; middleware применяются ко всем маршрутам версии
+// (например, Deprecation). Формат запросов и ответов v1 не меняется — новое появляется в /api/v2.
+func (h *Handler) SetupRoutes(router *gin.Engine, middleware ...gin.HandlerFunc) {
+	api := router.Group("/api/v1", middleware...)
 	{
 		subscriptions := api.Group("/subscriptions")
 		{
-			subscriptions.POST("", h.CreateSubscription)
+			subscriptions.POST("", h.Idempotency(), h.CreateSubscription)
 			subscriptions.GET("", h.ListSubscriptions)
+			subscriptions.GET("/overlaps", h.ListOverlaps)
+			subscriptions.GET("/export", h.ExportSubscriptions)
+			subscriptions.POST("/batch", h.BatchSubscriptions)
+			subscriptions.POST("/import", h.ImportSubscriptions)
 			subscriptions.GET("/:id", h.GetSubscription)
 			subscriptions.PUT("/:id", h.UpdateSubscription)
+			subscriptions.PATCH("/:id", h.PatchSubscription)
 			subscriptions.DELETE("/:id", h.DeleteSubscription)
 			subscriptions.POST("/summary", h.CalculateSummary)
+			subscriptions.POST("/:id/adjustments", h.CreateAdjustment)
+			subscriptions.GET("/:id/adjustments", h.ListAdjustments)
+		}
+
+		api.DELETE("/adjustments/:id", h.DeleteAdjustment)
+
+		budgets := api.Group("/budgets")
+		{
+			budgets.POST("", h.SetBudget)
+			budgets.GET("", h.ListBudgets)
+			budgets.DELETE("/:id", h.DeleteBudget)
+			budgets.POST("/report", h.BudgetReport)
+		}
+
+		charges := api.Group("/charges")
+		{
+			charges.GET("", h.ListCharges)
+			charges.POST("/rebuild", h.RebuildCharges)
 		}
 	}
 
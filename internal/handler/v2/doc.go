@@ -0,0 +1,10 @@
+// Package v2 — REST API версии 2: ответы в конвертах {data, pagination}, обязательная
+// постраничная выдача списков и месяцы в формате ISO 8601 (YYYY-MM). Работает поверх того
+// же service.Service, что и v1; ошибки — application/problem+json с теми же кодами.
+//
+// @title Subscription Service API
+// @version 2.0
+// @description REST API for managing user subscriptions, version 2
+// @host localhost:8080
+// @BasePath /api/v2
+package v2
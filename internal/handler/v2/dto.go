@@ -0,0 +1,315 @@
+package v2
+
+import (
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// monthLayout — формат месяцев в запросах и ответах v2 (ISO 8601)
+const monthLayout = "2006-01"
+
+// Subscription — подписка в ответах v2. Необязательные поля передаются как null, а не пропускаются.
+type Subscription struct {
+	ID          uuid.UUID `json:"id"`
+	ServiceName string    `json:"service_name" example:"Yandex Plus"`
+	Price       int       `json:"price" example:"400"`
+	UserID      uuid.UUID `json:"user_id"`
+	StartDate   string    `json:"start_date" example:"2025-07"`
+	EndDate     *string   `json:"end_date" example:"2025-12" extensions:"x-nullable"`
+	Category    *string   `json:"category" extensions:"x-nullable"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int       `json:"version" example:"1"`
+}
+
+// SubscriptionRequest — данные подписки для создания (POST) и полной замены (PUT)
+type SubscriptionRequest struct {
+	ServiceName string    `json:"service_name" binding:"required" example:"Yandex Plus"`
+	Price       int       `json:"price" binding:"required,min=1" example:"400"`
+	UserID      uuid.UUID `json:"user_id" binding:"required"`
+	StartDate   string    `json:"start_date" binding:"required" example:"2025-07"`
+	EndDate     *string   `json:"end_date,omitempty" example:"2025-12"`
+	Category    *string   `json:"category,omitempty"`
+}
+
+// SubscriptionPatch — документ JSON Merge Patch для подписки с месяцами в формате YYYY-MM
+type SubscriptionPatch struct {
+	ServiceName model.Optional[string]    `json:"service_name" swaggertype:"string" extensions:"x-nullable"`
+	Price       model.Optional[int]       `json:"price" swaggertype:"integer" extensions:"x-nullable"`
+	UserID      model.Optional[uuid.UUID] `json:"user_id" swaggertype:"string" format:"uuid" extensions:"x-nullable"`
+	StartDate   model.Optional[string]    `json:"start_date" swaggertype:"string" example:"2025-07" extensions:"x-nullable"`
+	EndDate     model.Optional[string]    `json:"end_date" swaggertype:"string" example:"2025-12" extensions:"x-nullable"`
+	Category    model.Optional[string]    `json:"category" swaggertype:"string" extensions:"x-nullable"`
+}
+
+// SummaryRequest — параметры расчета суммы подписок за период
+type SummaryRequest struct {
+	StartDate   string     `json:"start_date" binding:"required" example:"2025-01"`
+	EndDate     string     `json:"end_date" binding:"required" example:"2025-12"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	ServiceName *string    `json:"service_name,omitempty"`
+	// Source — источник данных: "subscriptions" (по умолчанию) или "ledger" (журнал списаний)
+	Source string `json:"source,omitempty" binding:"omitempty,oneof=subscriptions ledger"`
+}
+
+// Summary — сумма подписок за период
+type Summary struct {
+	StartDate   string `json:"start_date" example:"2025-01"`
+	EndDate     string `json:"end_date" example:"2025-12" extensions:"x-nullable"`
+	TotalAmount int    `json:"total_amount" example:"4800"`
+	Count       int    `json:"count" example:"2"`
+	// AdjustmentsAmount — сумма возвратов, кредитов и разовых списаний за период (уже учтена в TotalAmount)
+	AdjustmentsAmount int `json:"adjustments_amount" example:"0"`
+}
+
+// Overlap — пара подписок одного пользователя на один сервис с пересекающимися периодами
+type Overlap struct {
+	UserID         uuid.UUID `json:"user_id"`
+	ServiceName    string    `json:"service_name" example:"Yandex Plus"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	OverlappingID  uuid.UUID `json:"overlapping_id"`
+	OverlapStart   string    `json:"overlap_start" example:"2025-07"`
+	OverlapEnd     *string   `json:"overlap_end" example:"2025-12" extensions:"x-nullable"`
+}
+
+// SubscriptionEvent — событие ленты изменений подписок (поле data события SSE)
+type SubscriptionEvent struct {
+	ID         int64     `json:"id" example:"1760000000000001"`
+	Type       string    `json:"type" enums:"subscription.created,subscription.updated,subscription.cancelled,subscription.deleted"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// Subscription — подписка после изменения; для удаления — её последнее состояние
+	Subscription Subscription `json:"subscription"`
+}
+
+// WebhookRequest — регистрация вебхука
+type WebhookRequest struct {
+	URL string `json:"url" binding:"required" example:"https://example.com/hooks/subscriptions"`
+	// Secret — ключ подписи HMAC-SHA256, не короче 16 символов; в ответах не возвращается
+	Secret     string   `json:"secret" binding:"required" example:"5f2b8c0e9a4d4e71b3c6"`
+	EventTypes []string `json:"event_types" binding:"required" enums:"subscription.created,subscription.updated,subscription.cancelled,subscription.deleted"`
+}
+
+// Webhook — зарегистрированный вебхук
+type Webhook struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url" example:"https://example.com/hooks/subscriptions"`
+	EventTypes []string  `json:"event_types" enums:"subscription.created,subscription.updated,subscription.cancelled,subscription.deleted"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookDelivery — доставка одного события на вебхук
+type WebhookDelivery struct {
+	ID        uuid.UUID `json:"id"`
+	WebhookID uuid.UUID `json:"webhook_id"`
+	EventID   uuid.UUID `json:"event_id"`
+	EventType string    `json:"event_type" enums:"subscription.created,subscription.updated,subscription.cancelled,subscription.deleted"`
+	// Payload — тело запроса, которое получает вебхук
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+	Status  string          `json:"status" enums:"pending,succeeded,failed"`
+	// Attempts — число попыток с момента постановки в очередь или ручного повтора
+	Attempts      int        `json:"attempts" example:"1"`
+	NextAttemptAt *time.Time `json:"next_attempt_at" extensions:"x-nullable"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at" extensions:"x-nullable"`
+	// AttemptLog — журнал попыток, от ранних к поздним; только в ответе с одной доставкой
+	AttemptLog []WebhookAttempt `json:"attempt_log,omitempty"`
+}
+
+// WebhookAttempt — попытка доставки
+type WebhookAttempt struct {
+	AttemptedAt time.Time `json:"attempted_at"`
+	// ResponseStatus — HTTP-статус ответа получателя; null, если ответа не было
+	ResponseStatus *int `json:"response_status" example:"503" extensions:"x-nullable"`
+	// Error — причина неудачи; null для успешной попытки
+	Error      *string `json:"error" example:"unexpected response status 503" extensions:"x-nullable"`
+	DurationMs int     `json:"duration_ms" example:"120"`
+}
+
+// SubscriptionMatch — подписка, найденная поиском по названию сервиса
+type SubscriptionMatch struct {
+	Subscription Subscription `json:"subscription"`
+	// Score — сходство названия сервиса с запросом от 0 до 1
+	Score float64 `json:"score" example:"0.83"`
+	// Highlights — фрагменты service_name, похожие на слова запроса
+	Highlights []Highlight `json:"highlights"`
+}
+
+// Highlight — фрагмент строки; offset и length — в символах Unicode, а не в байтах
+type Highlight struct {
+	Offset int `json:"offset" example:"0"`
+	Length int `json:"length" example:"7"`
+}
+
+// ServiceName — название сервиса для автодополнения
+type ServiceName struct {
+	ServiceName string `json:"service_name" example:"YouTube Premium"`
+	// Subscriptions — число подписок с этим названием
+	Subscriptions int `json:"subscriptions" example:"12"`
+}
+
+// Pagination — параметры страницы списка; next_cursor равен null на последней странице
+type Pagination struct {
+	Limit      int     `json:"limit" example:"50"`
+	NextCursor *string `json:"next_cursor" extensions:"x-nullable"`
+}
+
+// SubscriptionResponse — ответ с одной подпиской
+type SubscriptionResponse struct {
+	Data Subscription `json:"data"`
+	// Warnings — предупреждения о сохранении подписки (например, при force=true)
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SubscriptionListResponse — страница списка подписок
+type SubscriptionListResponse struct {
+	Data       []Subscription `json:"data"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// WebhookResponse — ответ с одним вебхуком
+type WebhookResponse struct {
+	Data Webhook `json:"data"`
+}
+
+// WebhookListResponse — список вебхуков
+type WebhookListResponse struct {
+	Data []Webhook `json:"data"`
+}
+
+// WebhookDeliveryResponse — ответ с одной доставкой и журналом её попыток
+type WebhookDeliveryResponse struct {
+	Data WebhookDelivery `json:"data"`
+}
+
+// WebhookDeliveryListResponse — последние доставки вебхука, от новых к старым
+type WebhookDeliveryListResponse struct {
+	Data []WebhookDelivery `json:"data"`
+}
+
+// SummaryResponse — ответ с суммой подписок
+type SummaryResponse struct {
+	Data Summary `json:"data"`
+}
+
+// SubscriptionSearchResponse — результаты поиска подписок, от более похожих к менее похожим
+type SubscriptionSearchResponse struct {
+	Data []SubscriptionMatch `json:"data"`
+}
+
+// ServiceNameListResponse — названия сервисов для автодополнения, от частых к редким
+type ServiceNameListResponse struct {
+	Data []ServiceName `json:"data"`
+}
+
+// OverlapListResponse — отчет о пересекающихся подписках
+type OverlapListResponse struct {
+	Data []Overlap `json:"data"`
+}
+
+func formatMonth(t time.Time) string {
+	return t.Format(monthLayout)
+}
+
+func formatOptionalMonth(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	formatted := formatMonth(*t)
+	return &formatted
+}
+
+func toSubscription(sub *model.Subscription) Subscription {
+	return Subscription{
+		ID:          sub.ID,
+		ServiceName: sub.ServiceName,
+		Price:       sub.Price,
+		UserID:      sub.UserID,
+		StartDate:   formatMonth(sub.StartDate),
+		EndDate:     formatOptionalMonth(sub.EndDate),
+		Category:    sub.Category,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+		Version:     sub.Version,
+	}
+}
+
+func toSubscriptions(subs []*model.Subscription) []Subscription {
+	items := make([]Subscription, 0, len(subs))
+	for _, sub := range subs {
+		items = append(items, toSubscription(sub))
+	}
+
+	return items
+}
+
+func toSubscriptionEvent(e model.SubscriptionEvent) SubscriptionEvent {
+	return SubscriptionEvent{
+		ID:           e.ID,
+		Type:         e.Type,
+		OccurredAt:   e.OccurredAt,
+		Subscription: toSubscription(e.Subscription),
+	}
+}
+
+func toOverlap(o *model.SubscriptionOverlap) Overlap {
+	return Overlap{
+		UserID:         o.UserID,
+		ServiceName:    o.ServiceName,
+		SubscriptionID: o.SubscriptionID,
+		OverlappingID:  o.OverlappingID,
+		OverlapStart:   formatMonth(o.OverlapStart),
+		OverlapEnd:     formatOptionalMonth(o.OverlapEnd),
+	}
+}
+
+func toSubscriptionMatch(m *model.SubscriptionMatch) SubscriptionMatch {
+	highlights := make([]Highlight, 0, len(m.Highlights))
+	for _, h := range m.Highlights {
+		highlights = append(highlights, Highlight{Offset: h.Offset, Length: h.Length})
+	}
+
+	return SubscriptionMatch{
+		Subscription: toSubscription(m.Subscription),
+		Score:        m.Score,
+		Highlights:   highlights,
+	}
+}
+
+func toWebhook(w *model.Webhook) Webhook {
+	return Webhook{
+		ID:         w.ID,
+		URL:        w.URL,
+		EventTypes: w.EventTypes,
+		CreatedAt:  w.CreatedAt,
+	}
+}
+
+func toWebhookDelivery(d *model.WebhookDelivery) WebhookDelivery {
+	delivery := WebhookDelivery{
+		ID:            d.ID,
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+
+	for _, a := range d.AttemptLog {
+		delivery.AttemptLog = append(delivery.AttemptLog, WebhookAttempt{
+			AttemptedAt:    a.AttemptedAt,
+			ResponseStatus: a.ResponseStatus,
+			Error:          a.Error,
+			DurationMs:     a.Duration,
+		})
+	}
+
+	return delivery
+}
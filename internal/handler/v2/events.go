@@ -0,0 +1,142 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/handler"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// eventResetType — событие SSE о том, что часть изменений пропущена и список нужно загрузить заново
+const eventResetType = "reset"
+
+// Параметры потока событий
+const (
+	// eventsRetry — через сколько браузер переподключается после обрыва (поле retry)
+	eventsRetry = 3 * time.Second
+	// eventsKeepAlive — как часто отправляется комментарий, чтобы прокси не закрывали тихое соединение
+	eventsKeepAlive = 15 * time.Second
+	// eventsWriteTimeout — сколько сервер ждет отправки очередного события. Срок продлевается
+	// перед каждой записью, поэтому общий WriteTimeout сервера поток не обрывает.
+	eventsWriteTimeout = 30 * time.Second
+)
+
+// StreamEvents отправляет изменения подписок как Server-Sent Events
+// @Summary Лента изменений подписок (SSE)
+// @Description Поток text/event-stream: событие на каждое создание, изменение и удаление подписки. Поле event — тип события
+// @Description (subscription.created, subscription.updated, subscription.cancelled, subscription.deleted), id — номер события, data — SubscriptionEvent.
+// @Description После переподключения браузер передает Last-Event-ID, и сервер сначала отправляет пропущенные события из журнала
+// @Description последних изменений. Если их там уже нет, приходит событие reset: нужно заново загрузить список подписок.
+// @Description Каждые 15 секунд отправляется комментарий keep-alive.
+// @Tags subscriptions
+// @Produce text/event-stream
+// @Param user_id query string false "Только события подписок этого пользователя"
+// @Param Last-Event-ID header string false "ID последнего полученного события"
+// @Success 200 {object} SubscriptionEvent "Поток событий"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Router /subscriptions/events [get]
+func (h *Handler) StreamEvents(c *gin.Context) {
+	var userID *uuid.UUID
+	if value := c.Query("user_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			handler.RespondInvalid(c, codeInvalidParameter, "invalid user_id")
+			return
+		}
+		userID = &id
+	}
+
+	// Нечитаемый Last-Event-ID не позволяет продолжить ленту — клиент получит reset
+	var lastEventID *int64
+	if value := c.GetHeader("Last-Event-ID"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			id = -1
+		}
+		lastEventID = &id
+	}
+
+	listener := h.events.Subscribe(lastEventID)
+	defer h.events.Unsubscribe(listener)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	stream := &eventStream{w: c.Writer, controller: http.NewResponseController(c.Writer)}
+	stream.write(fmt.Sprintf("retry: %d\n\n", eventsRetry.Milliseconds()))
+
+	if listener.Reset {
+		stream.write(fmt.Sprintf("id: %d\nevent: %s\ndata: {}\n\n", listener.LastID, eventResetType))
+	}
+
+	send := func(event model.SubscriptionEvent) {
+		if userID != nil && event.Subscription.UserID != *userID {
+			return
+		}
+		stream.event(event)
+	}
+
+	for _, event := range listener.Missed {
+		send(event)
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for stream.err == nil {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-listener.Events:
+			// Слушатель отключен (остановка сервера или клиент не успевал читать):
+			// клиент переподключится и продолжит с Last-Event-ID
+			if !ok {
+				return
+			}
+			send(event)
+		case <-keepAlive.C:
+			stream.write(": keep-alive\n\n")
+		}
+	}
+}
+
+// eventStream записывает события SSE и сразу отправляет их клиенту; после первой ошибки
+// записи (клиент отключился) остальные записи пропускаются
+type eventStream struct {
+	w          gin.ResponseWriter
+	controller *http.ResponseController
+	err        error
+}
+
+func (s *eventStream) event(event model.SubscriptionEvent) {
+	data, err := json.Marshal(toSubscriptionEvent(event))
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	s.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data))
+}
+
+func (s *eventStream) write(frame string) {
+	if s.err != nil {
+		return
+	}
+
+	// Не все ResponseWriter поддерживают дедлайны (например, в тестах) — тогда срок не продлевается
+	_ = s.controller.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+	if _, err := io.WriteString(s.w, frame); err != nil {
+		s.err = err
+		return
+	}
+	s.w.Flush()
+}
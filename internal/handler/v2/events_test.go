@@ -0,0 +1,124 @@
+package v2
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/events"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventsRouter(broker *events.Broker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(new(stubService), broker).SetupRoutes(router)
+	return router
+}
+
+func publishEvent(broker *events.Broker, eventType string, sub *model.Subscription) model.SubscriptionEvent {
+	return broker.Publish(model.SubscriptionEvent{Type: eventType, Subscription: sub})
+}
+
+func TestStreamEvents_ResumesForUser(t *testing.T) {
+	broker := events.NewBroker(0)
+	router := eventsRouter(broker)
+
+	sub := newSubscription()
+	other := newSubscription()
+	seen := publishEvent(broker, model.SubscriptionEventCreated, sub)
+	publishEvent(broker, model.SubscriptionEventCreated, other)
+	updated := publishEvent(broker, model.SubscriptionEventUpdated, sub)
+
+	// Отключенные слушатели получают только пропущенные события, и поток сразу завершается
+	broker.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/subscriptions/events?user_id="+sub.UserID.String(), nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(seen.ID, 10))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	data, _ := json.Marshal(toSubscriptionEvent(updated))
+	assert.Equal(t, "retry: 3000\n\n"+
+		"id: "+strconv.FormatInt(updated.ID, 10)+"\nevent: subscription.updated\ndata: "+string(data)+"\n\n", w.Body.String())
+	assert.Contains(t, string(data), `"start_date":"2025-07"`)
+}
+
+func TestStreamEvents_Reset(t *testing.T) {
+	broker := events.NewBroker(0)
+	router := eventsRouter(broker)
+
+	last := publishEvent(broker, model.SubscriptionEventDeleted, newSubscription())
+	broker.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/subscriptions/events", nil)
+	req.Header.Set("Last-Event-ID", "not-a-number")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "retry: 3000\n\nid: "+strconv.FormatInt(last.ID, 10)+"\nevent: reset\ndata: {}\n\n", w.Body.String())
+}
+
+func TestStreamEvents_Live(t *testing.T) {
+	broker := events.NewBroker(0)
+	server := httptest.NewServer(eventsRouter(broker))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v2/subscriptions/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+	readFrame := func() string {
+		var frame strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return frame.String()
+			}
+			frame.WriteString(line)
+		}
+	}
+
+	// Заголовки и retry отправлены после подключения слушателя: следующее событие не потеряется
+	assert.Equal(t, "retry: 3000\n", readFrame())
+
+	sub := newSubscription()
+	created := publishEvent(broker, model.SubscriptionEventCreated, sub)
+
+	frame := readFrame()
+	assert.True(t, strings.HasPrefix(frame, "id: "+strconv.FormatInt(created.ID, 10)+"\nevent: subscription.created\ndata: "), frame)
+
+	var event SubscriptionEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSuffix(frame[strings.Index(frame, "data: ")+len("data: "):], "\n")), &event))
+	assert.Equal(t, created.ID, event.ID)
+	assert.Equal(t, sub.ID, event.Subscription.ID)
+
+	// Остановка сервера отключает слушателей, поток завершается
+	broker.Close()
+	_, err = reader.ReadString('\n')
+	assert.Error(t, err)
+}
+
+func TestStreamEvents_InvalidUserID(t *testing.T) {
+	router := eventsRouter(events.NewBroker(0))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/subscriptions/events?user_id=42", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), codeInvalidParameter)
+}
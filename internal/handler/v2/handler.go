@@ -0,0 +1,513 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/events"
+	"github.com/ZnNr/subscription-service/internal/handler"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Коды ошибок, которые выявляются при разборе запроса, до вызова сервиса (те же, что в v1)
+const (
+	codeInvalidID            = "invalid_id"
+	codeInvalidParameter     = "invalid_parameter"
+	codeInvalidDateFormat    = "invalid_date_format"
+	codeUnsupportedMediaType = "unsupported_media_type"
+)
+
+type Handler struct {
+	service service.Service
+	// idempotency — та же обработка Idempotency-Key, что и в v1
+	idempotency gin.HandlerFunc
+	// events — лента изменений для GET /subscriptions/events
+	events *events.Broker
+}
+
+func NewHandler(svc service.Service, broker *events.Broker) *Handler {
+	return &Handler{
+		service:     svc,
+		idempotency: handler.NewHandler(svc).Idempotency(),
+		events:      broker,
+	}
+}
+
+// SetupRoutes регистрирует маршруты /api/v2; middleware применяются ко всем маршрутам версии.
+// Бюджеты, журнал, корректировки, пакетные операции, импорт и выгрузка пока доступны только в v1.
+func (h *Handler) SetupRoutes(router *gin.Engine, middleware ...gin.HandlerFunc) {
+	api := router.Group("/api/v2", middleware...)
+	{
+		subscriptions := api.Group("/subscriptions")
+		{
+			subscriptions.POST("", h.idempotency, h.CreateSubscription)
+			subscriptions.GET("", h.ListSubscriptions)
+			subscriptions.GET("/overlaps", h.ListOverlaps)
+			subscriptions.GET("/search", h.SearchSubscriptions)
+			subscriptions.GET("/service-names", h.SuggestServiceNames)
+			subscriptions.GET("/events", h.StreamEvents)
+			subscriptions.POST("/summary", h.CalculateSummary)
+			subscriptions.GET("/:id", h.GetSubscription)
+			subscriptions.PUT("/:id", h.UpdateSubscription)
+			subscriptions.PATCH("/:id", h.PatchSubscription)
+			subscriptions.DELETE("/:id", h.DeleteSubscription)
+		}
+
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("", h.CreateWebhook)
+			webhooks.GET("", h.ListWebhooks)
+			webhooks.DELETE("/:id", h.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", h.ListWebhookDeliveries)
+			webhooks.GET("/:id/deliveries/:delivery_id", h.GetWebhookDelivery)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", h.RedeliverWebhookDelivery)
+		}
+	}
+}
+
+// CreateSubscription создает новую подписку
+// @Summary Создать подписку
+// @Description Создает новую запись о подписке пользователя. Месяцы — в формате YYYY-MM
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body SubscriptionRequest true "Данные подписки"
+// @Param force query bool false "Сохранить подписку, несмотря на пересечение с существующими"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом вернет сохраненный ответ"
+// @Success 201 {object} SubscriptionResponse
+// @Header 201 {string} ETag "Версия подписки для If-Match"
+// @Header 201 {string} Location "Адрес созданной подписки"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 409 {object} model.Problem "Подписка пересекается с существующей или запрос с этим ключом еще выполняется"
+// @Failure 422 {object} model.Problem "Ключ идемпотентности уже использован с другим запросом или данные нарушают ограничения БД"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /subscriptions [post]
+func (h *Handler) CreateSubscription(c *gin.Context) {
+	opts, err := handler.ParseWriteOptions(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.RespondBindError(c, err)
+		return
+	}
+
+	sub, err := req.subscription(uuid.New())
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidDateFormat, err.Error())
+		return
+	}
+
+	created, err := h.service.CreateSubscription(c.Request.Context(), sub, opts)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Header("ETag", handler.FormatETag(created.Version))
+	c.Header("Location", "/api/v2/subscriptions/"+created.ID.String())
+	c.JSON(http.StatusCreated, SubscriptionResponse{Data: toSubscription(created.Subscription), Warnings: created.Warnings})
+}
+
+// GetSubscription получает подписку по ID
+// @Summary Получить подписку
+// @Description Возвращает подписку по её ID
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "ID подписки"
+// @Success 200 {object} SubscriptionResponse
+// @Header 200 {string} ETag "Версия подписки для If-Match"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Router /subscriptions/{id} [get]
+func (h *Handler) GetSubscription(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	sub, err := h.service.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Header("ETag", handler.FormatETag(sub.Version))
+	c.JSON(http.StatusOK, SubscriptionResponse{Data: toSubscription(sub)})
+}
+
+// UpdateSubscription полностью заменяет подписку
+// @Summary Заменить подписку
+// @Description Полностью заменяет подписку: не переданные end_date и category очищаются. Ответ — подписка после изменения
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки"
+// @Param input body SubscriptionRequest true "Новые данные подписки"
+// @Param force query bool false "Сохранить подписку, несмотря на пересечение с существующими"
+// @Param If-Match header string false "ETag подписки из GET; при несовпадении версии — 412"
+// @Success 200 {object} SubscriptionResponse
+// @Header 200 {string} ETag "Новая версия подписки"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Failure 409 {object} model.Problem "Подписка пересекается с существующей"
+// @Failure 412 {object} model.Problem "Версия подписки не совпадает с If-Match"
+// @Router /subscriptions/{id} [put]
+func (h *Handler) UpdateSubscription(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	opts, err := handler.ParseWriteOptions(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.RespondBindError(c, err)
+		return
+	}
+
+	sub, err := req.subscription(id)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidDateFormat, err.Error())
+		return
+	}
+
+	if err := h.service.UpdateSubscription(c.Request.Context(), sub, opts); err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	updated, err := h.service.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Header("ETag", handler.FormatETag(updated.Version))
+	c.JSON(http.StatusOK, SubscriptionResponse{Data: toSubscription(updated)})
+}
+
+// PatchSubscription частично изменяет подписку
+// @Summary Изменить подписку
+// @Description Частично изменяет подписку по правилам JSON Merge Patch (RFC 7396): отсутствующие поля не меняются, null очищает поле
+// @Tags subscriptions
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path string true "ID подписки"
+// @Param input body SubscriptionPatch true "Изменяемые поля"
+// @Param force query bool false "Сохранить подписку, несмотря на пересечение с существующими"
+// @Param If-Match header string false "ETag подписки из GET; при несовпадении версии — 412"
+// @Success 200 {object} SubscriptionResponse
+// @Header 200 {string} ETag "Новая версия подписки"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Failure 409 {object} model.Problem "Подписка пересекается с существующей"
+// @Failure 412 {object} model.Problem "Версия подписки не совпадает с If-Match"
+// @Failure 415 {object} model.Problem "Неподдерживаемый Content-Type"
+// @Router /subscriptions/{id} [patch]
+func (h *Handler) PatchSubscription(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	if ct := c.ContentType(); ct != "application/merge-patch+json" && ct != "application/json" {
+		handler.RespondProblem(c, http.StatusUnsupportedMediaType, codeUnsupportedMediaType, "content type must be application/merge-patch+json")
+		return
+	}
+
+	opts, err := handler.ParseWriteOptions(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	var req SubscriptionPatch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.RespondBindError(c, err)
+		return
+	}
+
+	patch, err := req.patch()
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidDateFormat, err.Error())
+		return
+	}
+
+	sub, err := h.service.PatchSubscription(c.Request.Context(), id, patch, opts)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Header("ETag", handler.FormatETag(sub.Version))
+	c.JSON(http.StatusOK, SubscriptionResponse{Data: toSubscription(sub)})
+}
+
+// DeleteSubscription удаляет подписку
+// @Summary Удалить подписку
+// @Description Удаляет подписку по её ID
+// @Tags subscriptions
+// @Param id path string true "ID подписки"
+// @Param If-Match header string false "ETag подписки из GET; при несовпадении версии — 412"
+// @Success 204 "Подписка удалена"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Подписка не найдена"
+// @Failure 412 {object} model.Problem "Версия подписки не совпадает с If-Match"
+// @Router /subscriptions/{id} [delete]
+func (h *Handler) DeleteSubscription(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid subscription id")
+		return
+	}
+
+	opts, err := handler.ParseWriteOptions(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	if err := h.service.DeleteSubscription(c.Request.Context(), id, opts); err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSubscriptions возвращает страницу списка подписок
+// @Summary Список подписок
+// @Description Возвращает страницу подписок с фильтрацией. Список всегда постраничный: курсор следующей страницы — pagination.next_cursor
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "ID пользователя для фильтрации"
+// @Param service_name query []string false "Название сервиса; можно передать несколько раз" collectionFormat(multi)
+// @Param q query string false "Подстрока названия сервиса или категории без учета регистра"
+// @Param active_at query string false "Месяц, в котором подписка действует (YYYY-MM)"
+// @Param price_min query int false "Минимальная цена"
+// @Param price_max query int false "Максимальная цена"
+// @Param start_from query string false "Начало не раньше месяца (YYYY-MM)"
+// @Param start_to query string false "Начало не позже месяца (YYYY-MM)"
+// @Param end_from query string false "Окончание не раньше месяца (YYYY-MM)"
+// @Param end_to query string false "Окончание не позже месяца (YYYY-MM)"
+// @Param status query string false "Статус относительно текущего месяца" Enums(active, ended, future)
+// @Param updated_since query string false "Подписки, измененные в этот момент или позже (RFC 3339)"
+// @Param sort query string false "Поля сортировки через запятую, '-' — по убыванию: price, start_date, end_date, service_name, created_at"
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 500)"
+// @Param cursor query string false "Курсор из pagination.next_cursor предыдущей страницы (действителен только при том же sort)"
+// @Success 200 {object} SubscriptionListResponse
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Router /subscriptions [get]
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	filter, err := parseSubscriptionFilter(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	page, err := h.service.ListSubscriptions(c.Request.Context(), filter)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SubscriptionListResponse{
+		Data:       toSubscriptions(page.Items),
+		Pagination: Pagination{Limit: pageSize(filter.Limit), NextCursor: page.NextCursor},
+	})
+}
+
+// CalculateSummary считает суммарную стоимость подписок
+// @Summary Сумма подписок
+// @Description Рассчитывает суммарную стоимость подписок за период с фильтрацией. При source=ledger сумма считается по журналу списаний
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body SummaryRequest true "Параметры расчета"
+// @Success 200 {object} SummaryResponse
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Router /subscriptions/summary [post]
+func (h *Handler) CalculateSummary(c *gin.Context) {
+	var req SummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.RespondBindError(c, err)
+		return
+	}
+
+	startDate, err := parseMonth("start_date", req.StartDate)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidDateFormat, err.Error())
+		return
+	}
+
+	endDate, err := parseMonth("end_date", req.EndDate)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidDateFormat, err.Error())
+		return
+	}
+
+	calculate := h.service.CalculateSummary
+	if req.Source == "ledger" {
+		calculate = h.service.CalculateLedgerSummary
+	}
+
+	summary, err := calculate(c.Request.Context(), startDate, endDate, req.UserID, req.ServiceName)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SummaryResponse{Data: Summary{
+		StartDate:         formatMonth(startDate),
+		EndDate:           formatMonth(endDate),
+		TotalAmount:       summary.TotalAmount,
+		Count:             summary.Count,
+		AdjustmentsAmount: summary.AdjustmentsAmount,
+	}})
+}
+
+// ListOverlaps возвращает отчет о пересекающихся подписках
+// @Summary Пересекающиеся подписки
+// @Description Возвращает все пары подписок одного пользователя на один сервис с пересекающимися периодами
+// @Tags subscriptions
+// @Produce json
+// @Success 200 {object} OverlapListResponse
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /subscriptions/overlaps [get]
+func (h *Handler) ListOverlaps(c *gin.Context) {
+	overlaps, err := h.service.ListOverlaps(c.Request.Context())
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	data := make([]Overlap, 0, len(overlaps))
+	for _, o := range overlaps {
+		data = append(data, toOverlap(o))
+	}
+
+	c.JSON(http.StatusOK, OverlapListResponse{Data: data})
+}
+
+// subscription собирает подписку из тела запроса; обязательность полей и цену проверяет binding
+func (req *SubscriptionRequest) subscription(id uuid.UUID) (*model.Subscription, error) {
+	startDate, err := parseMonth("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseOptionalMonth("end_date", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Subscription{
+		ID:          id,
+		ServiceName: req.ServiceName,
+		Price:       req.Price,
+		UserID:      req.UserID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    req.Category,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// patch переводит документ v2 в model.SubscriptionPatch, разбирая месяцы YYYY-MM
+func (req *SubscriptionPatch) patch() (*model.SubscriptionPatch, error) {
+	startDate, err := handler.ParsePatchMonth("start_date", req.StartDate, parseMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := handler.ParsePatchMonth("end_date", req.EndDate, parseMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SubscriptionPatch{
+		ServiceName: req.ServiceName,
+		Price:       req.Price,
+		UserID:      req.UserID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Category:    req.Category,
+	}, nil
+}
+
+// parseSubscriptionFilter разбирает параметры фильтрации и страницы из query-строки
+func parseSubscriptionFilter(c *gin.Context) (model.SubscriptionFilter, error) {
+	filter, err := handler.ParseSubscriptionFilter(c, parseMonth)
+	if err != nil {
+		return filter, err
+	}
+
+	if v := c.Query("updated_since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("invalid updated_since format, expected RFC 3339 timestamp")
+		}
+		filter.UpdatedSince = &since
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return filter, errors.New("invalid limit, expected positive integer")
+		}
+		filter.Limit = limit
+	}
+	filter.Cursor = c.Query("cursor")
+
+	return filter, nil
+}
+
+// pageSize — размер страницы, который применит сервис к запрошенному limit
+func pageSize(limit int) int {
+	if limit == 0 {
+		return service.DefaultPageSize
+	}
+
+	return min(limit, service.MaxPageSize)
+}
+
+func parseMonth(name, value string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s format, expected YYYY-MM", name)
+	}
+
+	return t, nil
+}
+
+func parseOptionalMonth(name string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	t, err := parseMonth(name, *value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,354 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	docsv2 "github.com/ZnNr/subscription-service/docs/v2"
+	"github.com/ZnNr/subscription-service/internal/events"
+	"github.com/ZnNr/subscription-service/internal/handler"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubService реализует только те методы service.Service, которые вызывают тесты
+type stubService struct {
+	service.Service
+	mock.Mock
+}
+
+func (m *stubService) CreateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) (*model.CreateSubscriptionResponse, error) {
+	args := m.Called(ctx, sub, opts)
+	resp, _ := args.Get(0).(*model.CreateSubscriptionResponse)
+	return resp, args.Error(1)
+}
+
+func (m *stubService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*model.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *stubService) UpdateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) error {
+	return m.Called(ctx, sub, opts).Error(0)
+}
+
+func (m *stubService) PatchSubscription(ctx context.Context, id uuid.UUID, patch *model.SubscriptionPatch, opts model.WriteOptions) (*model.Subscription, error) {
+	args := m.Called(ctx, id, patch, opts)
+	sub, _ := args.Get(0).(*model.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *stubService) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	args := m.Called(ctx, filter)
+	page, _ := args.Get(0).(*model.SubscriptionPage)
+	return page, args.Error(1)
+}
+
+func (m *stubService) CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
+	args := m.Called(ctx, startDate, endDate, userID, serviceName)
+	summary, _ := args.Get(0).(*model.SummaryResponse)
+	return summary, args.Error(1)
+}
+
+func setupRouter(svc service.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(svc, events.NewBroker(0)).SetupRoutes(router)
+	return router
+}
+
+func doJSON(router *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func newSubscription() *model.Subscription {
+	return &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       400,
+		UserID:      uuid.New(),
+		StartDate:   month(2025, time.July),
+		CreatedAt:   time.Date(2025, time.July, 3, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2025, time.July, 3, 10, 0, 0, 0, time.UTC),
+		Version:     1,
+	}
+}
+
+func TestCreateSubscription(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	sub := newSubscription()
+	endDate := month(2025, time.December)
+	sub.EndDate = &endDate
+
+	svc.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(s *model.Subscription) bool {
+		return s.StartDate.Equal(sub.StartDate) && s.EndDate != nil && s.EndDate.Equal(endDate)
+	}), model.WriteOptions{}).Return(&model.CreateSubscriptionResponse{Subscription: sub}, nil)
+
+	w := doJSON(router, http.MethodPost, "/api/v2/subscriptions", map[string]any{
+		"service_name": sub.ServiceName,
+		"price":        sub.Price,
+		"user_id":      sub.UserID,
+		"start_date":   "2025-07",
+		"end_date":     "2025-12",
+	})
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	assert.Equal(t, "/api/v2/subscriptions/"+sub.ID.String(), w.Header().Get("Location"))
+	assert.JSONEq(t, `{"data":{
+		"id":"`+sub.ID.String()+`",
+		"service_name":"Yandex Plus",
+		"price":400,
+		"user_id":"`+sub.UserID.String()+`",
+		"start_date":"2025-07",
+		"end_date":"2025-12",
+		"category":null,
+		"created_at":"2025-07-03T10:00:00Z",
+		"updated_at":"2025-07-03T10:00:00Z",
+		"version":1
+	}}`, w.Body.String())
+}
+
+func TestCreateSubscription_V1DateFormat(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	w := doJSON(router, http.MethodPost, "/api/v2/subscriptions", map[string]any{
+		"service_name": "Yandex Plus",
+		"price":        400,
+		"user_id":      uuid.New(),
+		"start_date":   "07-2025",
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, model.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem model.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, codeInvalidDateFormat, problem.Code)
+	assert.Equal(t, "/api/v2/subscriptions", problem.Instance)
+	svc.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscription_ReturnsSubscription(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	sub := newSubscription()
+	sub.Version = 2
+	svc.On("UpdateSubscription", mock.Anything, mock.MatchedBy(func(s *model.Subscription) bool {
+		return s.ID == sub.ID && s.Price == 500
+	}), model.WriteOptions{}).Return(nil)
+	svc.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+
+	w := doJSON(router, http.MethodPut, "/api/v2/subscriptions/"+sub.ID.String(), map[string]any{
+		"service_name": sub.ServiceName,
+		"price":        500,
+		"user_id":      sub.UserID,
+		"start_date":   "2025-07",
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	var resp SubscriptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, sub.ID, resp.Data.ID)
+}
+
+func TestPatchSubscription_ConvertsMonths(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	sub := newSubscription()
+	svc.On("PatchSubscription", mock.Anything, sub.ID, mock.MatchedBy(func(p *model.SubscriptionPatch) bool {
+		return p.StartDate.Set && p.StartDate.Value.Equal(month(2025, time.August)) && p.EndDate.Set && p.EndDate.Null && !p.Price.Set
+	}), model.WriteOptions{}).Return(sub, nil)
+
+	w := doJSON(router, http.MethodPatch, "/api/v2/subscriptions/"+sub.ID.String(), map[string]any{
+		"start_date": "2025-08",
+		"end_date":   nil,
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestListSubscriptions_Envelope(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	sub := newSubscription()
+	next := "next-page"
+	svc.On("ListSubscriptions", mock.Anything, mock.MatchedBy(func(f model.SubscriptionFilter) bool {
+		return f.ActiveAt != nil && f.ActiveAt.Equal(month(2025, time.September)) && f.Limit == 0
+	})).Return(&model.SubscriptionPage{Items: []*model.Subscription{sub}, NextCursor: &next}, nil)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions?active_at=2025-09", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp SubscriptionListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "2025-07", resp.Data[0].StartDate)
+	assert.Equal(t, Pagination{Limit: service.DefaultPageSize, NextCursor: &next}, resp.Pagination)
+}
+
+func TestListSubscriptions_LastPage(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	svc.On("ListSubscriptions", mock.Anything, mock.MatchedBy(func(f model.SubscriptionFilter) bool {
+		return f.Limit == 1000
+	})).Return(&model.SubscriptionPage{}, nil)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions?limit=1000", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[],"pagination":{"limit":500,"next_cursor":null}}`, w.Body.String())
+}
+
+func TestListSubscriptions_InvalidMonth(t *testing.T) {
+	router := setupRouter(new(stubService))
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions?start_from=01-2025", nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid start_from format, expected YYYY-MM")
+}
+
+func TestCalculateSummary(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	svc.On("CalculateSummary", mock.Anything, month(2025, time.January), month(2025, time.December), (*uuid.UUID)(nil), (*string)(nil)).
+		Return(&model.SummaryResponse{TotalAmount: 4800, Count: 1}, nil)
+
+	w := doJSON(router, http.MethodPost, "/api/v2/subscriptions/summary", map[string]any{
+		"start_date": "2025-01",
+		"end_date":   "2025-12",
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"start_date":"2025-01","end_date":"2025-12","total_amount":4800,"count":1,"adjustments_amount":0}}`, w.Body.String())
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	logger.Init("info", "json")
+	hook := test.NewLocal(logger.GetLogger())
+
+	validator, err := handler.OpenAPIValidator(docsv2.SwaggerInfov2.ReadDoc(), true)
+	require.NoError(t, err)
+
+	// Закрытая лента сразу завершает поток событий
+	broker := events.NewBroker(0)
+	broker.Close()
+
+	svc := new(stubService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(svc, broker).SetupRoutes(router, validator)
+
+	sub := newSubscription()
+	next := "cursor"
+	svc.On("CreateSubscription", mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.CreateSubscriptionResponse{Subscription: sub, Warnings: []string{"overlap"}}, nil)
+	svc.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+	svc.On("UpdateSubscription", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	svc.On("PatchSubscription", mock.Anything, sub.ID, mock.Anything, mock.Anything).Return(sub, nil)
+	svc.On("ListSubscriptions", mock.Anything, mock.Anything).
+		Return(&model.SubscriptionPage{Items: []*model.Subscription{sub}, NextCursor: &next}, nil)
+	svc.On("CalculateSummary", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.SummaryResponse{TotalAmount: 2400, Count: 1}, nil)
+
+	webhook := newWebhook()
+	delivery := newWebhookDelivery(webhook.ID)
+	svc.On("CreateWebhook", mock.Anything, mock.Anything).Return(webhook, nil)
+	svc.On("ListWebhooks", mock.Anything).Return([]*model.Webhook{webhook}, nil)
+	svc.On("ListWebhookDeliveries", mock.Anything, webhook.ID).Return([]*model.WebhookDelivery{delivery}, nil)
+	svc.On("GetWebhookDelivery", mock.Anything, webhook.ID, delivery.ID).Return(delivery, nil)
+	svc.On("RedeliverWebhookDelivery", mock.Anything, webhook.ID, delivery.ID).Return(delivery, nil)
+	deliveryPath := "/api/v2/webhooks/" + webhook.ID.String() + "/deliveries/" + delivery.ID.String()
+
+	svc.On("SearchSubscriptions", mock.Anything, mock.Anything).
+		Return([]*model.SubscriptionMatch{{Subscription: sub, Score: 0.8, Highlights: []model.Highlight{{Offset: 0, Length: 6}}}}, nil)
+	svc.On("SuggestServiceNames", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*model.ServiceNameSuggestion{{ServiceName: sub.ServiceName, Subscriptions: 1}}, nil)
+
+	input := map[string]any{
+		"service_name": sub.ServiceName,
+		"price":        sub.Price,
+		"user_id":      sub.UserID,
+		"start_date":   "2025-07",
+	}
+	requests := []struct {
+		method, path string
+		body         any
+		status       int
+	}{
+		{http.MethodPost, "/api/v2/subscriptions", input, http.StatusCreated},
+		{http.MethodGet, "/api/v2/subscriptions/" + sub.ID.String(), nil, http.StatusOK},
+		{http.MethodPut, "/api/v2/subscriptions/" + sub.ID.String(), input, http.StatusOK},
+		{http.MethodPatch, "/api/v2/subscriptions/" + sub.ID.String(), map[string]any{"category": nil}, http.StatusOK},
+		{http.MethodGet, "/api/v2/subscriptions?limit=10", nil, http.StatusOK},
+		{http.MethodPost, "/api/v2/subscriptions/summary", map[string]any{"start_date": "2025-01", "end_date": "2025-12"}, http.StatusOK},
+		{http.MethodGet, "/api/v2/subscriptions/events?user_id=" + sub.UserID.String(), nil, http.StatusOK},
+		{http.MethodGet, "/api/v2/subscriptions/search?q=yandex&limit=5", nil, http.StatusOK},
+		{http.MethodGet, "/api/v2/subscriptions/service-names?prefix=Yan", nil, http.StatusOK},
+		{http.MethodPost, "/api/v2/webhooks", map[string]any{"url": webhook.URL, "secret": webhook.Secret, "event_types": webhook.EventTypes}, http.StatusCreated},
+		{http.MethodGet, "/api/v2/webhooks", nil, http.StatusOK},
+		{http.MethodGet, "/api/v2/webhooks/" + webhook.ID.String() + "/deliveries", nil, http.StatusOK},
+		{http.MethodGet, deliveryPath, nil, http.StatusOK},
+		{http.MethodPost, deliveryPath + "/redeliver", nil, http.StatusAccepted},
+		{http.MethodGet, "/api/v2/subscriptions/not-a-uuid", nil, http.StatusBadRequest},
+	}
+
+	for _, r := range requests {
+		w := doJSON(router, r.method, r.path, r.body)
+		assert.Equal(t, r.status, w.Code, "%s %s: %s", r.method, r.path, w.Body.String())
+	}
+
+	for _, entry := range hook.AllEntries() {
+		assert.NotEqual(t, logrus.WarnLevel, entry.Level, "%s: %v", entry.Message, entry.Data)
+	}
+
+	// Тело, не соответствующее спецификации, отклоняется до обработчика
+	w := doJSON(router, http.MethodPost, "/api/v2/subscriptions", map[string]any{
+		"service_name": sub.ServiceName,
+		"price":        "400",
+		"user_id":      sub.UserID,
+		"start_date":   "2025-07",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"price"`)
+}
@@ -0,0 +1,109 @@
+package v2
+
+import (
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/handler"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SearchSubscriptions ищет подписки по названию сервиса с учетом опечаток
+// @Summary Поиск подписок по названию сервиса
+// @Description Нечеткий поиск по service_name без учета регистра: находит названия, похожие на запрос целиком
+// @Description («Youtube premium» — «YouTube Premium», «Yotube» — «YouTube Premium») или содержащие похожее слово.
+// @Description Сходство считается по триграммам (pg_trgm); результаты упорядочены по убыванию score.
+// @Description highlights — фрагменты названия, похожие на слова запроса, для подсветки.
+// @Tags subscriptions
+// @Produce json
+// @Param q query string true "Искомое название сервиса (до 255 символов)"
+// @Param user_id query string false "ID пользователя для фильтрации"
+// @Param limit query int false "Число результатов (по умолчанию 20, максимум 100)"
+// @Success 200 {object} SubscriptionSearchResponse
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /subscriptions/search [get]
+func (h *Handler) SearchSubscriptions(c *gin.Context) {
+	userID, limit, err := parseSearchParams(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	matches, err := h.service.SearchSubscriptions(c.Request.Context(), model.SubscriptionSearch{
+		Query:  c.Query("q"),
+		UserID: userID,
+		Limit:  limit,
+	})
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	data := make([]SubscriptionMatch, 0, len(matches))
+	for _, m := range matches {
+		data = append(data, toSubscriptionMatch(m))
+	}
+
+	c.JSON(http.StatusOK, SubscriptionSearchResponse{Data: data})
+}
+
+// SuggestServiceNames возвращает названия сервисов для автодополнения
+// @Summary Автодополнение названий сервисов
+// @Description Возвращает различные названия сервисов, начинающиеся с prefix без учета регистра, — сначала те, у которых больше подписок.
+// @Description Варианты написания одного сервиса («YouTube Premium», «Youtube premium») возвращаются отдельно.
+// @Tags subscriptions
+// @Produce json
+// @Param prefix query string true "Начало названия сервиса"
+// @Param user_id query string false "ID пользователя для фильтрации"
+// @Param limit query int false "Число названий (по умолчанию 20, максимум 100)"
+// @Success 200 {object} ServiceNameListResponse
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /subscriptions/service-names [get]
+func (h *Handler) SuggestServiceNames(c *gin.Context) {
+	userID, limit, err := parseSearchParams(c)
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidParameter, err.Error())
+		return
+	}
+
+	suggestions, err := h.service.SuggestServiceNames(c.Request.Context(), c.Query("prefix"), userID, limit)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	data := make([]ServiceName, 0, len(suggestions))
+	for _, s := range suggestions {
+		data = append(data, ServiceName{ServiceName: s.ServiceName, Subscriptions: s.Subscriptions})
+	}
+
+	c.JSON(http.StatusOK, ServiceNameListResponse{Data: data})
+}
+
+// parseSearchParams разбирает параметры user_id и limit поиска и автодополнения
+func parseSearchParams(c *gin.Context) (*uuid.UUID, int, error) {
+	var userID *uuid.UUID
+	if value := c.Query("user_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return nil, 0, errors.New("invalid user_id")
+		}
+		userID = &parsed
+	}
+
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return nil, 0, errors.New("invalid limit, expected positive integer")
+		}
+		limit = parsed
+	}
+
+	return userID, limit, nil
+}
@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *stubService) SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error) {
+	args := m.Called(ctx, search)
+	matches, _ := args.Get(0).([]*model.SubscriptionMatch)
+	return matches, args.Error(1)
+}
+
+func (m *stubService) SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error) {
+	args := m.Called(ctx, prefix, userID, limit)
+	suggestions, _ := args.Get(0).([]*model.ServiceNameSuggestion)
+	return suggestions, args.Error(1)
+}
+
+func TestSearchSubscriptions(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	sub := newSubscription()
+	sub.ServiceName = "YouTube Premium"
+	svc.On("SearchSubscriptions", mock.Anything, model.SubscriptionSearch{Query: "yotube premum", UserID: &sub.UserID, Limit: 5}).
+		Return([]*model.SubscriptionMatch{{
+			Subscription: sub,
+			Score:        0.52,
+			Highlights:   []model.Highlight{{Offset: 0, Length: 7}, {Offset: 8, Length: 7}},
+		}}, nil)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions/search?q=yotube+premum&limit=5&user_id="+sub.UserID.String(), nil)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data []struct {
+			Subscription map[string]any `json:"subscription"`
+			Score        float64        `json:"score"`
+			Highlights   []Highlight    `json:"highlights"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "YouTube Premium", resp.Data[0].Subscription["service_name"])
+	assert.Equal(t, "2025-07", resp.Data[0].Subscription["start_date"])
+	assert.Equal(t, 0.52, resp.Data[0].Score)
+	assert.Equal(t, []Highlight{{Offset: 0, Length: 7}, {Offset: 8, Length: 7}}, resp.Data[0].Highlights)
+}
+
+func TestSearchSubscriptions_Errors(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+	svc.On("SearchSubscriptions", mock.Anything, model.SubscriptionSearch{}).Return(nil, service.ErrSearchQueryRequired)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions/search", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"search_query_required"`)
+
+	w = doJSON(router, http.MethodGet, "/api/v2/subscriptions/search?q=netflix&limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_parameter"`)
+
+	w = doJSON(router, http.MethodGet, "/api/v2/subscriptions/search?q=netflix&user_id=42", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid user_id")
+}
+
+func TestSuggestServiceNames(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+	svc.On("SuggestServiceNames", mock.Anything, "you", (*uuid.UUID)(nil), 0).
+		Return([]*model.ServiceNameSuggestion{
+			{ServiceName: "YouTube Premium", Subscriptions: 12},
+			{ServiceName: "Youtube premium", Subscriptions: 3},
+		}, nil)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions/service-names?prefix=you", nil)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"data":[
+		{"service_name":"YouTube Premium","subscriptions":12},
+		{"service_name":"Youtube premium","subscriptions":3}
+	]}`, w.Body.String())
+}
+
+func TestSuggestServiceNames_Empty(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+	svc.On("SuggestServiceNames", mock.Anything, "zzz", (*uuid.UUID)(nil), 0).Return(nil, nil)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/subscriptions/service-names?prefix=zzz", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[]}`, w.Body.String())
+}
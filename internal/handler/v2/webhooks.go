@@ -0,0 +1,194 @@
+package v2
+
+import (
+	"github.com/ZnNr/subscription-service/internal/handler"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateWebhook регистрирует вебхук
+// @Summary Зарегистрировать вебхук
+// @Description Регистрирует адрес, на который POST-запросом отправляются события выбранных типов. Тело запроса — JSON
+// @Description {id, type, occurred_at, data}, где data — подписка (как в v1). Заголовок X-Webhook-Signature: t=<unix-время>,v1=<hex HMAC-SHA256 от "<t>.<тело>">
+// @Description с ключом secret. Успешная доставка — ответ 2xx; иначе попытка повторяется с экспоненциальной задержкой.
+// @Description URL — https-адрес в публичной сети: адреса loopback, частных и link-local сетей отклоняются (webhook_url_not_allowed).
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param input body WebhookRequest true "Адрес, ключ подписи и типы событий"
+// @Success 201 {object} WebhookResponse
+// @Header 201 {string} Location "Адрес созданного вебхука"
+// @Failure 400 {object} model.Problem "Неверный запрос"
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.RespondBindError(c, err)
+		return
+	}
+
+	created, err := h.service.CreateWebhook(c.Request.Context(), &model.Webhook{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v2/webhooks/"+created.ID.String())
+	c.JSON(http.StatusCreated, WebhookResponse{Data: toWebhook(created)})
+}
+
+// ListWebhooks возвращает зарегистрированные вебхуки
+// @Summary Список вебхуков
+// @Description Возвращает все зарегистрированные вебхуки; ключи подписи не возвращаются
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} WebhookListResponse
+// @Failure 500 {object} model.Problem "Внутренняя ошибка сервера"
+// @Router /webhooks [get]
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.service.ListWebhooks(c.Request.Context())
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	data := make([]Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		data = append(data, toWebhook(w))
+	}
+
+	c.JSON(http.StatusOK, WebhookListResponse{Data: data})
+}
+
+// DeleteWebhook удаляет вебхук
+// @Summary Удалить вебхук
+// @Description Удаляет вебхук вместе с журналом доставок; недоставленные события не отправляются
+// @Tags webhooks
+// @Param id path string true "ID вебхука"
+// @Success 204 "Вебхук удален"
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Вебхук не найден"
+// @Router /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid webhook id")
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), id); err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries возвращает журнал доставок вебхука
+// @Summary Доставки вебхука
+// @Description Возвращает последние 100 доставок вебхука, от новых к старым
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Success 200 {object} WebhookDeliveryListResponse
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Вебхук не найден"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) ListWebhookDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid webhook id")
+		return
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(c.Request.Context(), id)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	data := make([]WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		data = append(data, toWebhookDelivery(d))
+	}
+
+	c.JSON(http.StatusOK, WebhookDeliveryListResponse{Data: data})
+}
+
+// GetWebhookDelivery возвращает доставку с журналом попыток
+// @Summary Доставка вебхука
+// @Description Возвращает доставку вместе с журналом попыток: время, статус ответа, ошибка и длительность каждой попытки
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Param delivery_id path string true "ID доставки"
+// @Success 200 {object} WebhookDeliveryResponse
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Доставка не найдена"
+// @Router /webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *Handler) GetWebhookDelivery(c *gin.Context) {
+	webhookID, deliveryID, ok := parseDeliveryPath(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.GetWebhookDelivery(c.Request.Context(), webhookID, deliveryID)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookDeliveryResponse{Data: toWebhookDelivery(delivery)})
+}
+
+// RedeliverWebhookDelivery повторяет доставку
+// @Summary Повторить доставку
+// @Description Возвращает доставку в очередь с новым набором попыток; первая попытка выполняется в течение нескольких секунд.
+// @Description Получатель получит то же событие (тот же id) еще раз
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Param delivery_id path string true "ID доставки"
+// @Success 202 {object} WebhookDeliveryResponse
+// @Failure 400 {object} model.Problem "Неверный ID"
+// @Failure 404 {object} model.Problem "Доставка не найдена"
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handler) RedeliverWebhookDelivery(c *gin.Context) {
+	webhookID, deliveryID, ok := parseDeliveryPath(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.RedeliverWebhookDelivery(c.Request.Context(), webhookID, deliveryID)
+	if err != nil {
+		handler.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, WebhookDeliveryResponse{Data: toWebhookDelivery(delivery)})
+}
+
+// parseDeliveryPath разбирает ID вебхука и доставки из пути; при ошибке ответ уже отправлен
+func parseDeliveryPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid webhook id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		handler.RespondInvalid(c, codeInvalidID, "invalid delivery id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return webhookID, deliveryID, true
+}
@@ -0,0 +1,168 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *stubService) CreateWebhook(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	created, _ := args.Get(0).(*model.Webhook)
+	return created, args.Error(1)
+}
+
+func (m *stubService) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	args := m.Called(ctx)
+	webhooks, _ := args.Get(0).([]*model.Webhook)
+	return webhooks, args.Error(1)
+}
+
+func (m *stubService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *stubService) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID)
+	deliveries, _ := args.Get(0).([]*model.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *stubService) GetWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	delivery, _ := args.Get(0).(*model.WebhookDelivery)
+	return delivery, args.Error(1)
+}
+
+func (m *stubService) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	delivery, _ := args.Get(0).(*model.WebhookDelivery)
+	return delivery, args.Error(1)
+}
+
+func newWebhook() *model.Webhook {
+	return &model.Webhook{
+		ID:         uuid.New(),
+		URL:        "https://example.com/hooks",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{model.SubscriptionEventCreated, model.SubscriptionEventCancelled},
+		CreatedAt:  time.Date(2025, time.July, 3, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func newWebhookDelivery(webhookID uuid.UUID) *model.WebhookDelivery {
+	status := http.StatusServiceUnavailable
+	message := "unexpected response status 503"
+	next := time.Date(2025, time.July, 3, 10, 1, 0, 0, time.UTC)
+
+	return &model.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		EventID:       uuid.New(),
+		EventType:     model.SubscriptionEventCreated,
+		Payload:       json.RawMessage(`{"type":"subscription.created"}`),
+		Status:        model.WebhookDeliveryPending,
+		Attempts:      1,
+		NextAttemptAt: &next,
+		CreatedAt:     time.Date(2025, time.July, 3, 10, 0, 0, 0, time.UTC),
+		AttemptLog: []*model.WebhookAttempt{{
+			AttemptedAt:    time.Date(2025, time.July, 3, 10, 0, 30, 0, time.UTC),
+			ResponseStatus: &status,
+			Error:          &message,
+			Duration:       120,
+		}},
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	webhook := newWebhook()
+	svc.On("CreateWebhook", mock.Anything, mock.MatchedBy(func(w *model.Webhook) bool {
+		return w.URL == webhook.URL && w.Secret == webhook.Secret && len(w.EventTypes) == 2
+	})).Return(webhook, nil)
+
+	w := doJSON(router, http.MethodPost, "/api/v2/webhooks", map[string]any{
+		"url":         webhook.URL,
+		"secret":      webhook.Secret,
+		"event_types": webhook.EventTypes,
+	})
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v2/webhooks/"+webhook.ID.String(), w.Header().Get("Location"))
+	// Ключ подписи в ответ не попадает
+	assert.NotContains(t, w.Body.String(), webhook.Secret)
+	assert.JSONEq(t, `{"data":{"id":"`+webhook.ID.String()+`","url":"https://example.com/hooks",
+		"event_types":["subscription.created","subscription.cancelled"],"created_at":"2025-07-03T10:00:00Z"}}`, w.Body.String())
+}
+
+func TestCreateWebhook_Invalid(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	svc.On("CreateWebhook", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidWebhookSecret)
+
+	w := doJSON(router, http.MethodPost, "/api/v2/webhooks", map[string]any{
+		"url":         "https://example.com/hooks",
+		"secret":      "short",
+		"event_types": []string{model.SubscriptionEventCreated},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_webhook_secret")
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	webhook := newWebhook()
+	delivery := newWebhookDelivery(webhook.ID)
+	svc.On("ListWebhookDeliveries", mock.Anything, webhook.ID).Return([]*model.WebhookDelivery{delivery}, nil)
+	svc.On("GetWebhookDelivery", mock.Anything, webhook.ID, delivery.ID).Return(delivery, nil)
+	svc.On("RedeliverWebhookDelivery", mock.Anything, webhook.ID, delivery.ID).Return(delivery, nil)
+
+	w := doJSON(router, http.MethodGet, "/api/v2/webhooks/"+webhook.ID.String()+"/deliveries", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var list WebhookDeliveryListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, delivery.ID, list.Data[0].ID)
+	assert.JSONEq(t, `{"type":"subscription.created"}`, string(list.Data[0].Payload))
+
+	w = doJSON(router, http.MethodGet, "/api/v2/webhooks/"+webhook.ID.String()+"/deliveries/"+delivery.ID.String(), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"attempt_log":[{"attempted_at":"2025-07-03T10:00:30Z","response_status":503,"error":"unexpected response status 503","duration_ms":120}]`)
+	assert.Contains(t, w.Body.String(), `"delivered_at":null`)
+
+	w = doJSON(router, http.MethodPost, "/api/v2/webhooks/"+webhook.ID.String()+"/deliveries/"+delivery.ID.String()+"/redeliver", nil)
+	assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	w = doJSON(router, http.MethodGet, "/api/v2/webhooks/"+webhook.ID.String()+"/deliveries/42", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid delivery id")
+}
+
+func TestDeleteWebhook_NotFound(t *testing.T) {
+	svc := new(stubService)
+	router := setupRouter(svc)
+
+	id := uuid.New()
+	svc.On("DeleteWebhook", mock.Anything, id).Return(service.ErrWebhookNotFound)
+
+	w := doJSON(router, http.MethodDelete, "/api/v2/webhooks/"+id.String(), nil)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "webhook_not_found")
+}
@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// utf8BOM — метка порядка байтов UTF-8, которую Excel пишет в начало CSV
+const utf8BOM = "\xEF\xBB\xBF"
+
+// csvDelimiters — допустимые разделители полей; используется тот, которого больше в заголовке
+var csvDelimiters = []rune{',', ';', '\t'}
+
+// readCSV читает CSV с заголовком. Столбцы сопоставляются с полями по имени без учета
+// регистра ("Service_Name", "start date"); неизвестные столбцы пропускаются.
+func readCSV(r io.Reader, maxRows int) ([]Record, error) {
+	buffered := bufio.NewReader(r)
+	if bom, _ := buffered.Peek(len(utf8BOM)); string(bom) == utf8BOM {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = detectDelimiter(buffered)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := mapColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for row := 1; ; row++ {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if row > maxRows {
+			return nil, tooManyRows(maxRows)
+		}
+
+		records = append(records, csvRecord(row, columns, values))
+	}
+
+	return records, nil
+}
+
+// detectDelimiter выбирает разделитель по первой строке файла
+func detectDelimiter(r *bufio.Reader) rune {
+	head, _ := r.Peek(r.Size())
+	line, _, _ := bytes.Cut(head, []byte("\n"))
+
+	best, bestCount := csvDelimiters[0], 0
+	for _, d := range csvDelimiters {
+		if count := bytes.Count(line, []byte(string(d))); count > bestCount {
+			best, bestCount = d, count
+		}
+	}
+
+	return best
+}
+
+// mapColumns возвращает поле для каждого столбца заголовка ("" — столбец не импортируется)
+func mapColumns(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	for i, title := range header {
+		name := strings.ToLower(strings.TrimSpace(title))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+		if !slices.Contains(fields, name) {
+			continue
+		}
+		if slices.Contains(columns, name) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateColumn, name)
+		}
+		columns[i] = name
+	}
+
+	for _, required := range requiredColumns {
+		if !slices.Contains(columns, required) {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, required)
+		}
+	}
+
+	return columns, nil
+}
+
+// csvRecord заполняет запрос значениями строки; пустые ячейки оставляют поле пустым
+func csvRecord(row int, columns, values []string) Record {
+	record := Record{Row: row}
+	req := &record.Request
+
+	for i, field := range columns {
+		if field == "" || i >= len(values) {
+			continue
+		}
+		value := strings.TrimSpace(values[i])
+		if value == "" {
+			continue
+		}
+
+		switch field {
+		case fieldServiceName:
+			req.ServiceName = value
+		case fieldPrice:
+			// Разряды могут быть разделены пробелами, в том числе неразрывными
+			price, err := strconv.Atoi(strings.NewReplacer(" ", "", "\u00a0", "").Replace(value))
+			if err != nil {
+				record.Errors = append(record.Errors, model.FieldViolation{Field: field, Message: "must be integer"})
+				continue
+			}
+			req.Price = price
+		case fieldUserID:
+			userID, err := uuid.Parse(value)
+			if err != nil {
+				record.Errors = append(record.Errors, model.FieldViolation{Field: field, Message: "must be UUID"})
+				continue
+			}
+			req.UserID = userID
+		case fieldStartDate:
+			req.StartDate = value
+		case fieldEndDate:
+			req.EndDate = &value
+		case fieldCategory:
+			req.Category = &value
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,101 @@
+// Package importer читает подписки для массового импорта из файлов CSV и JSON
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"io"
+	"path"
+	"strings"
+)
+
+// Format — формат файла импорта
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+var (
+	ErrUnknownFormat    = errors.New("format must be one of csv, json")
+	ErrEmptyFile        = errors.New("file contains no header")
+	ErrMissingColumn    = errors.New("missing required column")
+	ErrDuplicateColumn  = errors.New("duplicate column")
+	ErrTooManyRows      = errors.New("too many rows")
+	ErrInvalidJSONShape = errors.New("expected JSON array of subscriptions")
+)
+
+// Поля CreateSubscriptionRequest, которые заполняются из файла
+const (
+	fieldServiceName = "service_name"
+	fieldPrice       = "price"
+	fieldUserID      = "user_id"
+	fieldStartDate   = "start_date"
+	fieldEndDate     = "end_date"
+	fieldCategory    = "category"
+)
+
+// fields — поля в порядке CreateSubscriptionRequest
+var fields = []string{fieldServiceName, fieldPrice, fieldUserID, fieldStartDate, fieldEndDate, fieldCategory}
+
+// requiredColumns — столбцы, без которых CSV не принимается
+var requiredColumns = []string{fieldServiceName, fieldPrice, fieldUserID, fieldStartDate}
+
+// Record — строка файла импорта, разобранная в запрос на создание подписки.
+// Errors содержит значения, которые не удалось привести к типу поля; такие поля остаются пустыми.
+type Record struct {
+	// Row — номер строки данных, начиная с 1 (заголовок CSV не считается)
+	Row     int
+	Request model.CreateSubscriptionRequest
+	Errors  []model.FieldViolation
+}
+
+// ParseFormat разбирает значение параметра format
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+
+	return "", ErrUnknownFormat
+}
+
+// FormatForMIMEType возвращает формат импорта для MIME-типа из заголовка Content-Type
+func FormatForMIMEType(mimeType string) (Format, bool) {
+	switch strings.ToLower(mimeType) {
+	case "text/csv", "application/csv":
+		return FormatCSV, true
+	case "application/json":
+		return FormatJSON, true
+	}
+
+	return "", false
+}
+
+// FormatForFilename возвращает формат импорта по расширению имени файла
+func FormatForFilename(name string) (Format, bool) {
+	format, err := ParseFormat(strings.TrimPrefix(path.Ext(name), "."))
+	return format, err == nil
+}
+
+// Read читает все строки файла в формате format. Если строк больше maxRows, возвращается ErrTooManyRows.
+// Ошибки в значениях отдельных полей не прерывают чтение, а попадают в Record.Errors.
+func Read(r io.Reader, format Format, maxRows int) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, maxRows)
+	case FormatJSON:
+		return readJSON(r, maxRows)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// tooManyRows — ошибка превышения числа строк
+func tooManyRows(maxRows int) error {
+	return fmt.Errorf("%w: at most %d rows are allowed", ErrTooManyRows, maxRows)
+}
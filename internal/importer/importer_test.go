@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserID = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+
+func TestReadCSV(t *testing.T) {
+	input := "\xEF\xBB\xBFid;Service Name;PRICE;user_id;start_date;end_date;category\n" +
+		"1;Netflix;1 599;" + testUserID + ";01-2025;12-2025;video\n" +
+		"2;Spotify;299;" + testUserID + ";02-2025;;\n" +
+		"3;Kinopoisk;free;not-a-uuid;03-2025\n"
+
+	records, err := Read(strings.NewReader(input), FormatCSV, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	endDate, category := "12-2025", "video"
+	assert.Equal(t, Record{Row: 1, Request: model.CreateSubscriptionRequest{
+		ServiceName: "Netflix",
+		Price:       1599,
+		UserID:      uuid.MustParse(testUserID),
+		StartDate:   "01-2025",
+		EndDate:     &endDate,
+		Category:    &category,
+	}}, records[0])
+
+	// Пустые ячейки оставляют необязательные поля пустыми
+	assert.Nil(t, records[1].Request.EndDate)
+	assert.Nil(t, records[1].Request.Category)
+	assert.Empty(t, records[1].Errors)
+
+	assert.Equal(t, 3, records[2].Row)
+	assert.Equal(t, "Kinopoisk", records[2].Request.ServiceName)
+	assert.Equal(t, []model.FieldViolation{
+		{Field: "price", Message: "must be integer"},
+		{Field: "user_id", Message: "must be UUID"},
+	}, records[2].Errors)
+}
+
+func TestReadCSV_HeaderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{name: "empty", input: "", err: ErrEmptyFile},
+		{name: "missing column", input: "service_name,price,start_date\nNetflix,599,01-2025\n", err: ErrMissingColumn},
+		{name: "duplicate column", input: "service_name,price,user_id,start_date,Price\n", err: ErrDuplicateColumn},
+		{name: "too many rows", input: "service_name,price,user_id,start_date\na,1,,\nb,1,,\nc,1,,\n", err: ErrTooManyRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input), FormatCSV, 2)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	input := `[
+		{"service_name": "Netflix", "price": 599, "user_id": "` + testUserID + `", "start_date": "01-2025", "end_date": null, "id": "ignored"},
+		{"service_name": 42, "price": "599", "user_id": "bad", "start_date": "02-2025", "category": "music"}
+	]`
+
+	records, err := Read(strings.NewReader(input), FormatJSON, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, Record{Row: 1, Request: model.CreateSubscriptionRequest{
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.MustParse(testUserID),
+		StartDate:   "01-2025",
+	}}, records[0])
+
+	assert.Equal(t, "02-2025", records[1].Request.StartDate)
+	assert.Equal(t, "music", *records[1].Request.Category)
+	assert.Equal(t, []model.FieldViolation{
+		{Field: "service_name", Message: "must be string"},
+		{Field: "price", Message: "must be integer"},
+		{Field: "user_id", Message: "must be UUID"},
+	}, records[1].Errors)
+}
+
+func TestReadJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{name: "object instead of array", input: `{"service_name": "Netflix"}`, err: ErrInvalidJSONShape},
+		{name: "array of numbers", input: `[1, 2]`, err: ErrInvalidJSONShape},
+		{name: "too many rows", input: `[{}, {}, {}]`, err: ErrTooManyRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input), FormatJSON, 2)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestFormatDetection(t *testing.T) {
+	format, ok := FormatForFilename("subscriptions.CSV")
+	assert.True(t, ok)
+	assert.Equal(t, FormatCSV, format)
+
+	format, ok = FormatForMIMEType("application/json")
+	assert.True(t, ok)
+	assert.Equal(t, FormatJSON, format)
+
+	_, ok = FormatForFilename("subscriptions.xlsx")
+	assert.False(t, ok)
+
+	_, err := ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
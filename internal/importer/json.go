@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// readJSON читает массив объектов с полями CreateSubscriptionRequest.
+// Массив разбирается по одному элементу; неизвестные поля пропускаются.
+func readJSON(r io.Reader, maxRows int) ([]Record, error) {
+	decoder := json.NewDecoder(r)
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, ErrInvalidJSONShape
+	}
+
+	var records []Record
+	for row := 1; decoder.More(); row++ {
+		if row > maxRows {
+			return nil, tooManyRows(maxRows)
+		}
+
+		var object map[string]json.RawMessage
+		if err := decoder.Decode(&object); err != nil {
+			if _, ok := err.(*json.UnmarshalTypeError); ok {
+				return nil, fmt.Errorf("row %d: %w", row, ErrInvalidJSONShape)
+			}
+			return nil, err
+		}
+
+		records = append(records, jsonRecord(row, object))
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// jsonRecord заполняет запрос полями объекта; отсутствующие поля и null оставляют поле пустым
+func jsonRecord(row int, object map[string]json.RawMessage) Record {
+	record := Record{Row: row}
+	req := &record.Request
+
+	invalid := func(field, message string) {
+		record.Errors = append(record.Errors, model.FieldViolation{Field: field, Message: message})
+	}
+
+	for _, field := range fields {
+		raw, ok := object[field]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+
+		switch field {
+		case fieldPrice:
+			if err := json.Unmarshal(raw, &req.Price); err != nil {
+				invalid(field, "must be integer")
+			}
+		case fieldUserID:
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				invalid(field, "must be UUID")
+				continue
+			}
+			userID, err := uuid.Parse(value)
+			if err != nil {
+				invalid(field, "must be UUID")
+				continue
+			}
+			req.UserID = userID
+		default:
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				invalid(field, "must be string")
+				continue
+			}
+			switch field {
+			case fieldServiceName:
+				req.ServiceName = value
+			case fieldStartDate:
+				req.StartDate = value
+			case fieldEndDate:
+				req.EndDate = &value
+			case fieldCategory:
+				req.Category = &value
+			}
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,13 @@
+-- budgets.sql
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS category VARCHAR(100);
+
+CREATE TABLE IF NOT EXISTS budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    category VARCHAR(100),
+    monthly_limit INTEGER NOT NULL CHECK (monthly_limit > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budgets_user_category ON budgets(user_id, (COALESCE(category, '')));
@@ -0,0 +1,15 @@
+-- charges.sql
+CREATE TABLE IF NOT EXISTS charges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255) NOT NULL,
+    category VARCHAR(100),
+    month DATE NOT NULL,
+    amount INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (subscription_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_charges_user_month ON charges(user_id, month);
+CREATE INDEX IF NOT EXISTS idx_charges_month ON charges(month);
@@ -0,0 +1,13 @@
+-- adjustments.sql
+CREATE TABLE IF NOT EXISTS adjustments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('refund', 'credit', 'charge')),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    date DATE NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_adjustments_subscription_id ON adjustments(subscription_id);
+CREATE INDEX IF NOT EXISTS idx_adjustments_date ON adjustments(date);
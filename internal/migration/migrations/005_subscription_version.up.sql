@@ -0,0 +1,2 @@
+-- subscription_version.sql
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- subscriptions_updated_at_index.sql
+CREATE INDEX IF NOT EXISTS idx_subscriptions_updated_at ON subscriptions(updated_at, id);
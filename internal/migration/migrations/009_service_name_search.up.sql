@@ -0,0 +1,8 @@
+-- service_name_search.sql
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Нечеткий поиск по названию сервиса (операторы % и <% расширения pg_trgm)
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_name_trgm ON subscriptions USING GIN (LOWER(service_name) gin_trgm_ops);
+
+-- Автодополнение по началу названия (LIKE 'prefix%')
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_name_prefix ON subscriptions(LOWER(service_name) text_pattern_ops);
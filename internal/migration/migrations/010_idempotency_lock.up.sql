@@ -0,0 +1,4 @@
+-- idempotency_lock.sql
+-- Срок, до которого ключ занят выполняющимся запросом: если процесс упал, не сохранив ответ,
+-- повтор запроса занимает ключ после этого срока. У существующих записей срок уже истек.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
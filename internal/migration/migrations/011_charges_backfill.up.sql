@@ -0,0 +1,10 @@
+-- charges_backfill.sql
+-- Журнал списаний для подписок, созданных до его появления: заполняются только подписки
+-- без строк в журнале. Горизонт бессрочных подписок — 12 месяцев вперед, как у сервиса;
+-- дальше его продлевает сервер.
+INSERT INTO charges (subscription_id, user_id, service_name, category, month, amount)
+SELECT s.id, s.user_id, s.service_name, s.category, m::date, s.price
+FROM subscriptions s,
+    generate_series(s.start_date, COALESCE(s.end_date, (date_trunc('month', CURRENT_DATE) + interval '12 months')::date), interval '1 month') AS m
+WHERE NOT EXISTS (SELECT 1 FROM charges c WHERE c.subscription_id = s.id)
+ON CONFLICT (subscription_id, month) DO NOTHING;
@@ -0,0 +1,4 @@
+-- idempotency_lease.sql
+-- Токен аренды ключа: запрос, занявший ключ, сохраняет ответ или освобождает ключ только со своим
+-- токеном. Если срок блокировки истек и ключ занял повтор, ответ первого запроса не перезапишет его.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS lease UUID;
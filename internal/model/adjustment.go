@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Виды корректировок: возврат и кредит уменьшают расходы, разовое списание — увеличивает
+const (
+	AdjustmentRefund = "refund"
+	AdjustmentCredit = "credit"
+	AdjustmentCharge = "charge"
+)
+
+// Adjustment — возврат, кредит или разовое списание по подписке за месяц Date
+type Adjustment struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	Kind           string    `json:"kind" db:"kind"`
+	Amount         int       `json:"amount" db:"amount"`
+	Date           time.Time `json:"date" db:"date"`
+	Reason         string    `json:"reason" db:"reason"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// SignedAmount возвращает сумму корректировки со знаком: отрицательную для возвратов и кредитов
+func (a *Adjustment) SignedAmount() int {
+	if a.Kind == AdjustmentCharge {
+		return a.Amount
+	}
+	return -a.Amount
+}
+
+type CreateAdjustmentRequest struct {
+	Kind   string `json:"kind" binding:"required,oneof=refund credit charge"`
+	Amount int    `json:"amount" binding:"required,min=1"`
+	Date   string `json:"date" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
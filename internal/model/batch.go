@@ -0,0 +1,68 @@
+package model
+
+import "github.com/google/uuid"
+
+// Операции пакетного изменения подписок
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+// Режимы выполнения пакета
+const (
+	// BatchModeAtomic — все операции применяются вместе или не применяется ни одна
+	BatchModeAtomic = "atomic"
+	// BatchModeBestEffort — неудачные операции откатываются по отдельности, остальные сохраняются
+	BatchModeBestEffort = "best_effort"
+)
+
+// Статусы результата операции пакета
+const (
+	BatchStatusOK         = "ok"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back"
+	BatchStatusSkipped    = "skipped"
+)
+
+type BatchRequest struct {
+	// Mode — режим выполнения, по умолчанию atomic
+	Mode       string                  `json:"mode,omitempty" binding:"omitempty,oneof=atomic best_effort"`
+	Operations []BatchOperationRequest `json:"operations" binding:"required,min=1,max=500,dive"`
+}
+
+// BatchOperationRequest — операция пакета: для create передается data, для update — id и data
+// (полная замена, как в PUT), для delete — id. Version для update и delete работает как If-Match
+type BatchOperationRequest struct {
+	Op      string                     `json:"op" binding:"required,oneof=create update delete"`
+	ID      *uuid.UUID                 `json:"id,omitempty"`
+	Version *int                       `json:"version,omitempty"`
+	Data    *UpdateSubscriptionRequest `json:"data,omitempty"`
+}
+
+// BatchOperation — разобранная операция пакета
+type BatchOperation struct {
+	Op           string
+	ID           uuid.UUID
+	Version      *int
+	Subscription *Subscription
+}
+
+// BatchItemResult — результат одной операции пакета
+type BatchItemResult struct {
+	Index  int       `json:"index"`
+	Op     string    `json:"op"`
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	// Code — машиночитаемый код ошибки операции (как в поле code ответа application/problem+json)
+	Code         string        `json:"code,omitempty"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Warnings     []string      `json:"warnings,omitempty"`
+}
+
+type BatchResponse struct {
+	// Committed — были ли сохранены изменения
+	Committed bool               `json:"committed"`
+	Results   []*BatchItemResult `json:"results"`
+}
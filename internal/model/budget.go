@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Budget — месячный лимит расходов пользователя на подписки.
+// Если Category не задана, лимит действует на все подписки пользователя.
+type Budget struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+	Category     *string   `json:"category,omitempty" db:"category"`
+	MonthlyLimit int       `json:"monthly_limit" db:"monthly_limit"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SetBudgetRequest struct {
+	UserID       uuid.UUID `json:"user_id" binding:"required"`
+	Category     *string   `json:"category,omitempty"`
+	MonthlyLimit int       `json:"monthly_limit" binding:"required,min=1"`
+}
+
+type BudgetReportRequest struct {
+	UserID    uuid.UUID `json:"user_id" binding:"required"`
+	StartDate string    `json:"start_date" binding:"required"`
+	EndDate   string    `json:"end_date" binding:"required"`
+}
+
+// MonthlyCost — суммарная стоимость активных в месяце подписок
+type MonthlyCost struct {
+	Month  time.Time `json:"month"`
+	Amount int       `json:"amount"`
+}
+
+// BudgetMonth — сравнение фактических расходов за месяц с бюджетом
+type BudgetMonth struct {
+	Month        time.Time `json:"month"`
+	BudgetID     uuid.UUID `json:"budget_id"`
+	Category     *string   `json:"category,omitempty"`
+	MonthlyLimit int       `json:"monthly_limit"`
+	Actual       int       `json:"actual"`
+	OverBudget   bool      `json:"over_budget"`
+}
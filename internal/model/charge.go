@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Виды строк журнала: регулярное списание по подписке или одна из корректировок (Adjustment*)
+const ChargeSubscription = "subscription"
+
+// Charge — строка журнала: ожидаемое списание по подписке за месяц или корректировка со знаком
+type Charge struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	ServiceName    string    `json:"service_name" db:"service_name"`
+	Category       *string   `json:"category,omitempty" db:"category"`
+	Month          time.Time `json:"month" db:"month"`
+	Amount         int       `json:"amount" db:"amount"`
+	Kind           string    `json:"kind" db:"kind"`
+	Reason         *string   `json:"reason,omitempty" db:"reason"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+type ChargeFilter struct {
+	UserID         *uuid.UUID
+	SubscriptionID *uuid.UUID
+	// SubscriptionIDs — строки любой из этих подписок
+	SubscriptionIDs []uuid.UUID
+	ServiceName     *string
+	From            *time.Time
+	To              *time.Time
+	// Limit — размер страницы, Cursor — непрозрачный курсор из next_cursor предыдущей страницы
+	Limit  int
+	Cursor string
+}
+
+// ChargePage — страница журнала списаний
+type ChargePage struct {
+	Items      []*Charge `json:"items"`
+	NextCursor *string   `json:"next_cursor,omitempty"`
+}
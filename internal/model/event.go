@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Типы событий об изменении подписок
+const (
+	SubscriptionEventCreated = "subscription.created"
+	SubscriptionEventUpdated = "subscription.updated"
+	// SubscriptionEventCancelled — у подписки появилась дата окончания
+	SubscriptionEventCancelled = "subscription.cancelled"
+	SubscriptionEventDeleted   = "subscription.deleted"
+)
+
+// SubscriptionEventTypes — все типы событий об изменении подписок
+var SubscriptionEventTypes = []string{
+	SubscriptionEventCreated,
+	SubscriptionEventUpdated,
+	SubscriptionEventCancelled,
+	SubscriptionEventDeleted,
+}
+
+// SubscriptionEvent — изменение подписки, о котором сообщает лента событий
+type SubscriptionEvent struct {
+	// ID возрастает с каждым событием; по нему клиент продолжает ленту после переподключения
+	ID   int64
+	Type string
+	// Subscription — состояние подписки после изменения; для удаления — последнее сохраненное
+	Subscription *Subscription
+	OccurredAt   time.Time
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// IdempotencyRecord — сохраненный запрос с заголовком Idempotency-Key.
+// Пока запрос выполняется, StatusCode равен 0, а ответ не сохранен; если запрос не завершился
+// до LockedUntil, ключ может занять его повтор.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Headers     map[string]string
+	Body        []byte
+	CreatedAt   time.Time
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed сообщает, сохранен ли уже ответ на запрос
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
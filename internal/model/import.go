@@ -0,0 +1,67 @@
+package model
+
+import "github.com/google/uuid"
+
+// Статусы строки импорта
+const (
+	// ImportStatusValid — строка прошла все проверки (пробный запуск)
+	ImportStatusValid = "valid"
+	// ImportStatusInvalid — строка не прошла проверку данных; при обычном запуске ничего не сохраняется
+	ImportStatusInvalid = "invalid"
+	// ImportStatusCreated — подписка создана
+	ImportStatusCreated = "created"
+	// ImportStatusFailed — строка прошла проверку данных, но подписку не удалось сохранить
+	ImportStatusFailed = "failed"
+	// ImportStatusRolledBack — подписка была создана, но её часть импорта отменена из-за ошибки другой строки
+	ImportStatusRolledBack = "rolled_back"
+	// ImportStatusSkipped — строка не обрабатывалась
+	ImportStatusSkipped = "skipped"
+)
+
+// ImportRow — разобранная строка файла импорта. Если при разборе найдены ошибки,
+// Errors непусто, а Subscription равно nil.
+type ImportRow struct {
+	// Row — номер строки данных в файле, начиная с 1 (заголовок CSV не считается)
+	Row          int
+	Subscription *Subscription
+	Errors       []FieldViolation
+}
+
+// ImportOptions задает режим импорта
+type ImportOptions struct {
+	// DryRun — только проверить строки, ничего не сохраняя
+	DryRun bool
+	// ResumeFrom — номер строки, с которой продолжить импорт; строки до нее пропускаются
+	ResumeFrom int
+	// Force разрешает сохранять подписки, пересекающиеся с существующими
+	Force bool
+	// OnChunk, если задан, вызывается перед обработкой каждой части строк: обработчик HTTP
+	// продлевает в нем срок записи ответа, чтобы долгий импорт не оборвал WriteTimeout сервера
+	OnChunk func()
+}
+
+// ImportRowResult — результат обработки одной строки импорта
+type ImportRowResult struct {
+	Row    int        `json:"row"`
+	Status string     `json:"status" enums:"valid,invalid,created,failed,rolled_back,skipped"`
+	ID     *uuid.UUID `json:"id,omitempty"`
+	// Code и Error — код и описание ошибки строки (как в ответе application/problem+json)
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Errors — ошибки разбора и проверки по полям строки
+	Errors   []FieldViolation `json:"errors,omitempty"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// ImportReport — отчет об импорте подписок
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+	// Total — число строк данных в файле
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	Created int `json:"created"`
+	// ResumeFrom — номер строки, с которой нужно повторить импорт после ошибки (параметр resume_from)
+	ResumeFrom *int               `json:"resume_from,omitempty"`
+	Rows       []*ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Optional — поле документа JSON Merge Patch (RFC 7396).
+// Set — поле присутствует в документе, Null — передано значение null (поле нужно очистить).
+// Отсутствующее поле не вызывает UnmarshalJSON и остается с Set == false.
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
+
+// SubscriptionPatchRequest — документ JSON Merge Patch для подписки в API v1 (месяцы MM-YYYY):
+// отсутствующие поля не меняются, null очищает поле.
+type SubscriptionPatchRequest struct {
+	ServiceName Optional[string]    `json:"service_name" swaggertype:"string" extensions:"x-nullable"`
+	Price       Optional[int]       `json:"price" swaggertype:"integer" extensions:"x-nullable"`
+	UserID      Optional[uuid.UUID] `json:"user_id" swaggertype:"string" format:"uuid" extensions:"x-nullable"`
+	StartDate   Optional[string]    `json:"start_date" swaggertype:"string" example:"07-2025" extensions:"x-nullable"`
+	EndDate     Optional[string]    `json:"end_date" swaggertype:"string" example:"12-2025" extensions:"x-nullable"`
+	Category    Optional[string]    `json:"category" swaggertype:"string" extensions:"x-nullable"`
+}
+
+// SubscriptionPatch — частичное изменение подписки: меняются только поля с Set, поле с Null
+// очищается. Обработчики каждого API строят его из документа в своем формате.
+type SubscriptionPatch struct {
+	ServiceName Optional[string]
+	Price       Optional[int]
+	UserID      Optional[uuid.UUID]
+	StartDate   Optional[time.Time]
+	EndDate     Optional[time.Time]
+	Category    Optional[string]
+}
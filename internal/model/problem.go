@@ -0,0 +1,33 @@
+package model
+
+import "github.com/google/uuid"
+
+// ProblemContentType — тип содержимого ответов с ошибкой (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem — тело ответа с ошибкой в формате RFC 7807.
+// Code — стабильный машиночитаемый код ошибки; клиентам следует опираться на него, а не на текст detail.
+type Problem struct {
+	// Type — URI типа ошибки, однозначно соответствует Code
+	Type   string `json:"type" example:"urn:subscription-service:problem:invalid_end_date"`
+	Title  string `json:"title" example:"Bad Request"`
+	Status int    `json:"status" example:"400"`
+	Detail string `json:"detail,omitempty" example:"end date cannot be before start date"`
+	// Instance — путь запроса, при обработке которого возникла ошибка
+	Instance string `json:"instance,omitempty" example:"/api/v1/subscriptions"`
+	Code     string `json:"code" enums:"invalid_id,invalid_parameter,invalid_date_format,malformed_body,validation_failed,unsupported_media_type,service_name_required,invalid_price,user_id_required,start_date_required,invalid_end_date,invalid_period,invalid_limit,invalid_cursor,invalid_price_filter,invalid_price_range,invalid_start_range,invalid_end_range,invalid_status,invalid_sort,invalid_batch_operation,search_query_required,search_query_too_long,payload_too_large,export_too_large,invalid_adjustment_kind,invalid_adjustment_amount,adjustment_date_required,adjustment_reason_required,invalid_budget_limit,invalid_idempotency_key,invalid_webhook_url,webhook_url_not_allowed,invalid_webhook_secret,invalid_event_type,subscription_not_found,adjustment_not_found,budget_not_found,webhook_not_found,webhook_delivery_not_found,not_found,subscription_overlap,already_exists,idempotency_request_in_progress,precondition_failed,idempotency_key_reused,import_invalid,constraint_violation,internal_error"`
+	// RequestID — идентификатор запроса (заголовок X-Request-ID) для поиска в логах
+	RequestID string `json:"request_id,omitempty"`
+	// Errors — нарушения правил проверки по полям тела запроса (для code=validation_failed)
+	Errors []FieldViolation `json:"errors,omitempty"`
+	// Conflicts — подписки, с которыми пересекается сохраняемая (для code=subscription_overlap)
+	Conflicts []uuid.UUID `json:"conflicts,omitempty"`
+	// Results — результаты операций пакета, отмененного из-за ошибки одной из них (POST /subscriptions/batch)
+	Results []*BatchItemResult `json:"results,omitempty"`
+}
+
+// FieldViolation — нарушение правила проверки одним полем тела запроса
+type FieldViolation struct {
+	Field   string `json:"field" example:"price"`
+	Message string `json:"message" example:"must be at least 1"`
+}
@@ -0,0 +1,6 @@
+package model
+
+// MessageResponse — ответ без данных: сообщение о выполненном действии
+type MessageResponse struct {
+	Message string `json:"message" example:"subscription updated"`
+}
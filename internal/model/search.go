@@ -0,0 +1,33 @@
+package model
+
+import "github.com/google/uuid"
+
+// SubscriptionSearch — параметры нечеткого поиска подписок по названию сервиса
+type SubscriptionSearch struct {
+	// Query — искомое название; допускаются опечатки, другой регистр и часть названия
+	Query  string
+	UserID *uuid.UUID
+	Limit  int
+}
+
+// SubscriptionMatch — подписка, найденная нечетким поиском
+type SubscriptionMatch struct {
+	Subscription *Subscription `json:"subscription"`
+	// Score — сходство названия сервиса с запросом от 0 до 1 по триграммам
+	Score float64 `json:"score"`
+	// Highlights — фрагменты названия сервиса, похожие на слова запроса
+	Highlights []Highlight `json:"highlights"`
+}
+
+// Highlight — фрагмент строки: Offset и Length считаются в символах, а не в байтах
+type Highlight struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// ServiceNameSuggestion — название сервиса для автодополнения
+type ServiceNameSuggestion struct {
+	ServiceName string `json:"service_name"`
+	// Subscriptions — число подписок с этим названием
+	Subscriptions int `json:"subscriptions"`
+}
@@ -12,8 +12,11 @@ type Subscription struct {
 	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
 	StartDate   time.Time  `json:"start_date" db:"start_date"`
 	EndDate     *time.Time `json:"end_date,omitempty" db:"end_date"`
+	Category    *string    `json:"category,omitempty" db:"category"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	// Version увеличивается при каждом изменении подписки и отдается в заголовке ETag
+	Version int `json:"version" db:"version"`
 }
 
 type CreateSubscriptionRequest struct {
@@ -22,13 +25,18 @@ type CreateSubscriptionRequest struct {
 	UserID      uuid.UUID `json:"user_id" binding:"required"`
 	StartDate   string    `json:"start_date" binding:"required"`
 	EndDate     *string   `json:"end_date,omitempty"`
+	Category    *string   `json:"category,omitempty"`
 }
 
+// UpdateSubscriptionRequest — полная замена подписки (PUT): поля, которые не переданы
+// (end_date, category), очищаются. Для частичного изменения используется SubscriptionPatch.
 type UpdateSubscriptionRequest struct {
-	ServiceName *string `json:"service_name,omitempty"`
-	Price       *int    `json:"price,omitempty"`
-	StartDate   *string `json:"start_date,omitempty"`
-	EndDate     *string `json:"end_date,omitempty"`
+	ServiceName string    `json:"service_name" binding:"required"`
+	Price       int       `json:"price" binding:"required,min=1"`
+	UserID      uuid.UUID `json:"user_id" binding:"required"`
+	StartDate   string    `json:"start_date" binding:"required"`
+	EndDate     *string   `json:"end_date,omitempty"`
+	Category    *string   `json:"category,omitempty"`
 }
 
 type SummaryRequest struct {
@@ -36,9 +44,88 @@ type SummaryRequest struct {
 	EndDate     string     `json:"end_date" binding:"required"`
 	UserID      *uuid.UUID `json:"user_id,omitempty"`
 	ServiceName *string    `json:"service_name,omitempty"`
+	// Source — источник данных: "subscriptions" (по умолчанию) или "ledger" (журнал списаний)
+	Source string `json:"source,omitempty" binding:"omitempty,oneof=subscriptions ledger"`
 }
 
 type SummaryResponse struct {
 	TotalAmount int `json:"total_amount"`
 	Count       int `json:"count"`
+	// AdjustmentsAmount — сумма возвратов, кредитов и разовых списаний за период (уже учтена в TotalAmount)
+	AdjustmentsAmount int `json:"adjustments_amount"`
+}
+
+// Статусы подписки относительно текущего месяца
+const (
+	SubscriptionStatusActive = "active"
+	SubscriptionStatusEnded  = "ended"
+	SubscriptionStatusFuture = "future"
+)
+
+// SubscriptionFilter — параметры выборки списка подписок
+type SubscriptionFilter struct {
+	// IDs — идентификаторы подписок, подходит любой из них
+	IDs    []uuid.UUID
+	UserID *uuid.UUID
+	// ServiceNames — точные названия сервисов, подходит любое из них
+	ServiceNames []string
+	// Search — подстрока названия сервиса или категории без учета регистра
+	Search *string
+	// ActiveAt — месяц, в котором подписка должна действовать
+	ActiveAt  *time.Time
+	PriceMin  *int
+	PriceMax  *int
+	StartFrom *time.Time
+	StartTo   *time.Time
+	EndFrom   *time.Time
+	EndTo     *time.Time
+	Status    string
+	// UpdatedSince — подписки, измененные в этот момент или позже (инкрементальная выгрузка)
+	UpdatedSince *time.Time
+	// Sort — порядок выдачи; если не задан, подписки сортируются от новых к старым
+	Sort []SortField
+	// ByUpdate упорядочивает подписки по updated_at, затем по id — порядок инкрементальной выгрузки.
+	// Задается только кодом выгрузки (через sort этот порядок недоступен) и не сочетается с Sort.
+	ByUpdate bool
+	// Limit — размер страницы, Cursor — непрозрачный курсор из next_cursor предыдущей страницы
+	Limit  int
+	Cursor string
+}
+
+// SortField — поле сортировки списка подписок и её направление
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// SubscriptionSortFields — поля, по которым разрешена сортировка списка подписок
+var SubscriptionSortFields = []string{"price", "start_date", "end_date", "service_name", "created_at"}
+
+// SubscriptionPage — страница списка подписок
+type SubscriptionPage struct {
+	Items      []*Subscription `json:"items"`
+	NextCursor *string         `json:"next_cursor,omitempty"`
+}
+
+// WriteOptions задает параметры проверок при создании, изменении и удалении подписки
+type WriteOptions struct {
+	// Force разрешает сохранить подписку, даже если она пересекается с уже существующими
+	Force bool
+	// IfMatch — ожидаемая версия подписки (заголовок If-Match); nil — без проверки версии
+	IfMatch *int
+}
+
+type CreateSubscriptionResponse struct {
+	*Subscription
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SubscriptionOverlap описывает пару подписок одного пользователя на один сервис с пересекающимися периодами
+type SubscriptionOverlap struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	ServiceName    string     `json:"service_name"`
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	OverlappingID  uuid.UUID  `json:"overlapping_id"`
+	OverlapStart   time.Time  `json:"overlap_start"`
+	OverlapEnd     *time.Time `json:"overlap_end,omitempty"`
 }
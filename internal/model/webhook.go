@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Статусы доставки вебхука
+const (
+	// WebhookDeliveryPending — доставка ждет очередной попытки
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliverySucceeded — получатель ответил статусом 2xx
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed — попытки исчерпаны; доставку можно повторить вручную
+	WebhookDeliveryFailed = "failed"
+)
+
+// Webhook — адрес, на который отправляются события об изменении подписок
+type Webhook struct {
+	ID  uuid.UUID `json:"id" db:"id"`
+	URL string    `json:"url" db:"url"`
+	// Secret — ключ подписи HMAC-SHA256; в ответах API не возвращается
+	Secret     string    `json:"-" db:"secret"`
+	EventTypes []string  `json:"event_types" db:"event_types"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookEvent — тело запроса, которое получает вебхук
+type WebhookEvent struct {
+	// ID — идентификатор события, одинаковый для всех вебхуков и повторных попыток
+	ID         uuid.UUID     `json:"id"`
+	Type       string        `json:"type"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	Data       *Subscription `json:"data"`
+}
+
+// WebhookDelivery — доставка одного события на один вебхук
+type WebhookDelivery struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	WebhookID uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	EventID   uuid.UUID       `json:"event_id" db:"event_id"`
+	EventType string          `json:"event_type" db:"event_type"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	Status    string          `json:"status" db:"status"`
+	// Attempts — число попыток с момента постановки в очередь (или ручного повтора)
+	Attempts      int        `json:"attempts" db:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	// AttemptLog — журнал попыток, от ранних к поздним; заполняется при запросе одной доставки
+	AttemptLog []*WebhookAttempt `json:"attempt_log,omitempty"`
+}
+
+// WebhookAttempt — одна попытка доставки
+type WebhookAttempt struct {
+	DeliveryID  uuid.UUID `json:"-" db:"delivery_id"`
+	AttemptedAt time.Time `json:"attempted_at" db:"attempted_at"`
+	// ResponseStatus — HTTP-статус ответа получателя; nil, если ответа не было
+	ResponseStatus *int `json:"response_status,omitempty" db:"response_status"`
+	// Error — причина неудачи: ошибка соединения или неуспешный статус
+	Error    *string `json:"error,omitempty" db:"error"`
+	Duration int     `json:"duration_ms" db:"duration_ms"`
+}
+
+// OutgoingWebhook — доставка, взятая в работу, вместе с адресом и ключом вебхука
+type OutgoingWebhook struct {
+	Delivery *WebhookDelivery
+	URL      string
+	Secret   string
+}
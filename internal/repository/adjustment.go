@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// signedAdjustmentAmount — выражение суммы корректировки со знаком (см. model.Adjustment.SignedAmount)
+const signedAdjustmentAmount = `CASE WHEN a.kind = 'charge' THEN a.amount ELSE -a.amount END`
+
+func (r *PostgresRepository) CreateAdjustment(ctx context.Context, adj *model.Adjustment) error {
+	query := `
+		INSERT INTO adjustments (id, subscription_id, kind, amount, date, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		adj.ID, adj.SubscriptionID, adj.Kind, adj.Amount, adj.Date, adj.Reason, adj.CreatedAt)
+
+	return translateError(err)
+}
+
+func (r *PostgresRepository) ListAdjustments(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Adjustment, error) {
+	query := `
+		SELECT id, subscription_id, kind, amount, date, reason, created_at
+		FROM adjustments WHERE subscription_id = $1
+		ORDER BY date, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []*model.Adjustment
+	for rows.Next() {
+		var adj model.Adjustment
+		if err := rows.Scan(&adj.ID, &adj.SubscriptionID, &adj.Kind, &adj.Amount, &adj.Date, &adj.Reason, &adj.CreatedAt); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, &adj)
+	}
+
+	return adjustments, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteAdjustment(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM adjustments WHERE id = $1", id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SumAdjustments считает сумму корректировок со знаком за период с фильтрацией по подпискам
+func (r *PostgresRepository) SumAdjustments(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(` + signedAdjustmentAmount + `), 0)
+		FROM adjustments a
+		JOIN subscriptions s ON s.id = a.subscription_id
+		WHERE a.date >= $1 AND a.date <= $2
+	`
+	args := []interface{}{startDate, endDate}
+	argIndex := 3
+
+	if userID != nil {
+		query += fmt.Sprintf(" AND s.user_id = $%d", argIndex)
+		args = append(args, *userID)
+		argIndex++
+	}
+
+	if serviceName != nil {
+		query += fmt.Sprintf(" AND s.service_name = $%d", argIndex)
+		args = append(args, *serviceName)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *PostgresRepositoryTestSuite) TestCreateAdjustment() {
+	adj := &model.Adjustment{
+		ID:             uuid.New(),
+		SubscriptionID: uuid.New(),
+		Kind:           model.AdjustmentRefund,
+		Amount:         599,
+		Date:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Reason:         "service outage",
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	s.mock.ExpectExec(`INSERT INTO adjustments`).
+		WithArgs(adj.ID, adj.SubscriptionID, adj.Kind, adj.Amount, adj.Date, adj.Reason, adj.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := s.repo.CreateAdjustment(s.ctx, adj)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestSumAdjustments() {
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	s.mock.ExpectQuery(`SELECT COALESCE\(SUM\(CASE WHEN a.kind = 'charge' THEN a.amount ELSE -a.amount END\), 0\) FROM adjustments a JOIN subscriptions s ON s.id = a.subscription_id WHERE a.date >= \$1 AND a.date <= \$2 AND s.user_id = \$3`).
+		WithArgs(startDate, endDate, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(-300))
+
+	total, err := s.repo.SumAdjustments(s.ctx, startDate, endDate, &userID, nil)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), -300, total)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// UpsertBudget создает бюджет или обновляет лимит существующего бюджета с той же парой (user_id, category)
+func (r *PostgresRepository) UpsertBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error) {
+	query := `
+		INSERT INTO budgets (id, user_id, category, monthly_limit, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, (COALESCE(category, ''))) DO UPDATE
+			SET monthly_limit = EXCLUDED.monthly_limit, updated_at = EXCLUDED.updated_at
+		RETURNING id, user_id, category, monthly_limit, created_at, updated_at
+	`
+
+	saved, err := scanBudget(r.db.QueryRowContext(ctx, query,
+		budget.ID, budget.UserID, budget.Category, budget.MonthlyLimit, budget.CreatedAt, budget.UpdatedAt))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return saved, nil
+}
+
+func (r *PostgresRepository) ListBudgets(ctx context.Context, userID uuid.UUID) ([]*model.Budget, error) {
+	query := `
+		SELECT id, user_id, category, monthly_limit, created_at, updated_at
+		FROM budgets WHERE user_id = $1
+		ORDER BY category NULLS FIRST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []*model.Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM budgets WHERE id = $1", id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// MonthlyCosts считает для каждого месяца периода суммарную стоимость подписок пользователя,
+// активных в этом месяце, с учетом корректировок за месяц. Если category задана,
+// учитываются только подписки этой категории.
+func (r *PostgresRepository) MonthlyCosts(ctx context.Context, userID uuid.UUID, category *string, startDate, endDate time.Time) ([]*model.MonthlyCost, error) {
+	args := []interface{}{startDate, endDate, userID}
+	categoryCondition := ""
+
+	if category != nil {
+		args = append(args, *category)
+		categoryCondition = fmt.Sprintf(" AND s.category = $%d", len(args))
+	}
+
+	query := `
+		SELECT m::date AS month,
+			COALESCE((
+				SELECT SUM(s.price) FROM subscriptions s
+				WHERE s.user_id = $3 AND s.start_date <= m AND (s.end_date IS NULL OR s.end_date >= m)` + categoryCondition + `
+			), 0) +
+			COALESCE((
+				SELECT SUM(` + signedAdjustmentAmount + `) FROM adjustments a
+				JOIN subscriptions s ON s.id = a.subscription_id
+				WHERE s.user_id = $3 AND a.date = m` + categoryCondition + `
+			), 0) AS amount
+		FROM generate_series($1::date, $2::date, interval '1 month') AS m
+		ORDER BY m
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var costs []*model.MonthlyCost
+	for rows.Next() {
+		var cost model.MonthlyCost
+		if err := rows.Scan(&cost.Month, &cost.Amount); err != nil {
+			return nil, err
+		}
+		costs = append(costs, &cost)
+	}
+
+	return costs, rows.Err()
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBudget(row rowScanner) (*model.Budget, error) {
+	var budget model.Budget
+	var category sql.NullString
+
+	err := row.Scan(
+		&budget.ID,
+		&budget.UserID,
+		&category,
+		&budget.MonthlyLimit,
+		&budget.CreatedAt,
+		&budget.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if category.Valid {
+		budget.Category = &category.String
+	}
+
+	return &budget, nil
+}
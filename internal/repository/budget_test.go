@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *PostgresRepositoryTestSuite) TestUpsertBudget() {
+	category := "streaming"
+	budget := &model.Budget{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Category:     &category,
+		MonthlyLimit: 1500,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "category", "monthly_limit", "created_at", "updated_at"}).
+		AddRow(budget.ID, budget.UserID, category, 1500, budget.CreatedAt, budget.UpdatedAt)
+
+	s.mock.ExpectQuery(`INSERT INTO budgets .* ON CONFLICT \(user_id, \(COALESCE\(category, ''\)\)\) DO UPDATE`).
+		WithArgs(budget.ID, budget.UserID, budget.Category, budget.MonthlyLimit, budget.CreatedAt, budget.UpdatedAt).
+		WillReturnRows(rows)
+
+	result, err := s.repo.UpsertBudget(s.ctx, budget)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), budget.ID, result.ID)
+	assert.Equal(s.T(), category, *result.Category)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestDeleteBudget_NotFound() {
+	budgetID := uuid.New()
+
+	s.mock.ExpectExec(`DELETE FROM budgets WHERE id = \$1`).
+		WithArgs(budgetID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.DeleteBudget(s.ctx, budgetID)
+
+	assert.Error(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestMonthlyCosts() {
+	userID := uuid.New()
+	category := "streaming"
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"month", "amount"}).
+		AddRow(startDate, 599).
+		AddRow(endDate, 0)
+
+	s.mock.ExpectQuery(`SELECT m::date AS month, .* FROM subscriptions s .* AND s.category = \$4 .* FROM adjustments a .* AND s.category = \$4 .* FROM generate_series\(\$1::date, \$2::date, interval '1 month'\) AS m ORDER BY m`).
+		WithArgs(startDate, endDate, userID, category).
+		WillReturnRows(rows)
+
+	result, err := s.repo.MonthlyCosts(s.ctx, userID, &category, startDate, endDate)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+	assert.Equal(s.T(), 599, result[0].Amount)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
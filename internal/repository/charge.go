@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+// insertChargesQuery генерирует по строке журнала на каждый месяц действия подписки.
+// Для бессрочных подписок строки создаются до месяца horizon ($1) включительно.
+const insertChargesQuery = `
+	INSERT INTO charges (subscription_id, user_id, service_name, category, month, amount)
+	SELECT s.id, s.user_id, s.service_name, s.category, m::date, s.price
+	FROM subscriptions s,
+		generate_series(s.start_date, COALESCE(s.end_date, $1::date), interval '1 month') AS m
+`
+
+// RegenerateCharges пересоздает журнал списаний одной подписки
+func (r *PostgresRepository) RegenerateCharges(ctx context.Context, subscriptionID uuid.UUID, horizon time.Time) error {
+	return r.inTx(ctx, func(tx *PostgresRepository) error {
+		if _, err := tx.db.ExecContext(ctx, "DELETE FROM charges WHERE subscription_id = $1", subscriptionID); err != nil {
+			return err
+		}
+
+		_, err := tx.db.ExecContext(ctx, insertChargesQuery+" WHERE s.id = $2", horizon, subscriptionID)
+		return err
+	})
+}
+
+// RebuildCharges пересоздает журнал списаний для всех подписок
+func (r *PostgresRepository) RebuildCharges(ctx context.Context, horizon time.Time) error {
+	return r.inTx(ctx, func(tx *PostgresRepository) error {
+		if _, err := tx.db.ExecContext(ctx, "DELETE FROM charges"); err != nil {
+			return err
+		}
+
+		_, err := tx.db.ExecContext(ctx, insertChargesQuery, horizon)
+		return err
+	})
+}
+
+// ExtendCharges дописывает в журнал недостающие списания бессрочных подписок до месяца horizon
+// включительно. Существующие строки не меняются, поэтому несколько экземпляров сервиса могут
+// продлевать журнал одновременно.
+func (r *PostgresRepository) ExtendCharges(ctx context.Context, horizon time.Time) error {
+	_, err := r.db.ExecContext(ctx, insertChargesQuery+`
+		WHERE s.end_date IS NULL
+		ON CONFLICT (subscription_id, month) DO NOTHING
+	`, horizon)
+	return err
+}
+
+// ledgerView объединяет регулярные списания журнала и корректировки со знаком в одну выборку
+const ledgerView = `
+	SELECT id, subscription_id, user_id, service_name, category, month, amount,
+		'subscription' AS kind, NULL::text AS reason, created_at
+	FROM charges
+	UNION ALL
+	SELECT a.id, a.subscription_id, s.user_id, s.service_name, s.category, a.date AS month,
+		` + signedAdjustmentAmount + ` AS amount, a.kind, a.reason, a.created_at
+	FROM adjustments a
+	JOIN subscriptions s ON s.id = a.subscription_id
+`
+
+// chargeOrder — порядок выдачи журнала; id замыкает его, чтобы курсор указывал на одну строку
+const chargeOrder = "month,service_name,created_at"
+
+// ListCharges возвращает страницу журнала в порядке месяца, названия сервиса и времени создания.
+// Пагинация ключевая, как у списка подписок: курсор хранит позицию последней строки страницы.
+func (r *PostgresRepository) ListCharges(ctx context.Context, filter model.ChargeFilter) (*model.ChargePage, error) {
+	query, args, err := chargeListQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну строку больше, чтобы понять, есть ли следующая страница
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, filter.Limit+1)
+
+	page := &model.ChargePage{Items: []*model.Charge{}}
+	err = r.queryCharges(ctx, query, args, func(charge *model.Charge) error {
+		page.Items = append(page.Items, charge)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		next := encodeCursor(newChargeCursor(page.Items[len(page.Items)-1]))
+		page.NextCursor = &next
+	}
+
+	return page, nil
+}
+
+// StreamCharges передает в fn все строки журнала, подходящие под фильтр, читая их из курсора
+// базы данных по одной. filter.Limit и filter.Cursor не применяются; ошибка fn прерывает чтение.
+func (r *PostgresRepository) StreamCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error {
+	filter.Cursor = ""
+
+	query, args, err := chargeListQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	return r.queryCharges(ctx, query, args, fn)
+}
+
+// chargeListQuery строит запрос журнала с условиями фильтра, позицией курсора и порядком (без LIMIT)
+func chargeListQuery(filter model.ChargeFilter) (string, []interface{}, error) {
+	query := `
+		SELECT id, subscription_id, user_id, service_name, category, month, amount, kind, reason, created_at
+		FROM (` + ledgerView + `) AS ledger WHERE 1=1
+	`
+	where, args := chargeFilterConditions(filter)
+	query += where
+
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+
+		values, err := decodeChargeCursor(cursor)
+		if err != nil {
+			return "", nil, err
+		}
+
+		n := len(args)
+		query += fmt.Sprintf(" AND (month, service_name, created_at, id) > ($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+		args = append(args, values...)
+	}
+
+	return query + " ORDER BY month, service_name, created_at, id", args, nil
+}
+
+// queryCharges выполняет запрос журнала и передает каждую строку в fn
+func (r *PostgresRepository) queryCharges(ctx context.Context, query string, args []interface{}, fn func(charge *model.Charge) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var charge model.Charge
+		var category, reason sql.NullString
+
+		err := rows.Scan(
+			&charge.ID,
+			&charge.SubscriptionID,
+			&charge.UserID,
+			&charge.ServiceName,
+			&category,
+			&charge.Month,
+			&charge.Amount,
+			&charge.Kind,
+			&reason,
+			&charge.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if category.Valid {
+			charge.Category = &category.String
+		}
+
+		if reason.Valid {
+			charge.Reason = &reason.String
+		}
+
+		if err := fn(&charge); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// newChargeCursor строит курсор по последней строке страницы журнала
+func newChargeCursor(last *model.Charge) pageCursor {
+	cursor := pageCursor{Sort: chargeOrder, ID: last.ID}
+	for _, value := range []interface{}{last.Month, last.ServiceName, last.CreatedAt} {
+		data, _ := json.Marshal(value)
+		cursor.Values = append(cursor.Values, data)
+	}
+
+	return cursor
+}
+
+// decodeChargeCursor возвращает аргументы условия "строка идет после курсора": месяц,
+// название сервиса, время создания и id последней строки предыдущей страницы
+func decodeChargeCursor(cursor pageCursor) ([]interface{}, error) {
+	if cursor.Sort != chargeOrder || len(cursor.Values) != 3 {
+		return nil, ErrInvalidCursor
+	}
+
+	var month, createdAt time.Time
+	var serviceName string
+	if json.Unmarshal(cursor.Values[0], &month) != nil ||
+		json.Unmarshal(cursor.Values[1], &serviceName) != nil ||
+		json.Unmarshal(cursor.Values[2], &createdAt) != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return []interface{}{month, serviceName, createdAt, cursor.ID}, nil
+}
+
+// CalculateLedgerSummary считает сумму списаний журнала за период вместе с корректировками
+func (r *PostgresRepository) CalculateLedgerSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0) as total_amount, COUNT(DISTINCT subscription_id) as count,
+			COALESCE(SUM(amount) FILTER (WHERE kind <> 'subscription'), 0) as adjustments_amount
+		FROM (` + ledgerView + `) AS ledger WHERE 1=1
+	`
+	where, args := chargeFilterConditions(model.ChargeFilter{
+		UserID:      userID,
+		ServiceName: serviceName,
+		From:        &startDate,
+		To:          &endDate,
+	})
+
+	row := r.db.QueryRowContext(ctx, query+where, args...)
+
+	var summary model.SummaryResponse
+	if err := row.Scan(&summary.TotalAmount, &summary.Count, &summary.AdjustmentsAmount); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// chargeFilterConditions строит условия WHERE и аргументы запроса для фильтра журнала
+func chargeFilterConditions(filter model.ChargeFilter) (string, []interface{}) {
+	where := ""
+	var args []interface{}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		where += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+
+	if filter.SubscriptionID != nil {
+		args = append(args, *filter.SubscriptionID)
+		where += fmt.Sprintf(" AND subscription_id = $%d", len(args))
+	}
+
+	if len(filter.SubscriptionIDs) > 0 {
+		args = append(args, pq.Array(filter.SubscriptionIDs))
+		where += fmt.Sprintf(" AND subscription_id = ANY($%d)", len(args))
+	}
+
+	if filter.ServiceName != nil {
+		args = append(args, *filter.ServiceName)
+		where += fmt.Sprintf(" AND service_name = $%d", len(args))
+	}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where += fmt.Sprintf(" AND month >= $%d", len(args))
+	}
+
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where += fmt.Sprintf(" AND month <= $%d", len(args))
+	}
+
+	return where, args
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *PostgresRepositoryTestSuite) TestRegenerateCharges() {
+	subID := uuid.New()
+	horizon := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM charges WHERE subscription_id = \$1`).
+		WithArgs(subID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectExec(`INSERT INTO charges .* generate_series\(s.start_date, COALESCE\(s.end_date, \$1::date\), interval '1 month'\) AS m WHERE s.id = \$2`).
+		WithArgs(horizon, subID).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	s.mock.ExpectCommit()
+
+	err := s.repo.RegenerateCharges(s.ctx, subID, horizon)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestExtendCharges() {
+	horizon := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectExec(`INSERT INTO charges .* COALESCE\(s.end_date, \$1::date\), interval '1 month'\) AS m WHERE s.end_date IS NULL ON CONFLICT \(subscription_id, month\) DO NOTHING`).
+		WithArgs(horizon).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	err := s.repo.ExtendCharges(s.ctx, horizon)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListCharges() {
+	userID := uuid.New()
+	subID := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "subscription_id", "user_id", "service_name", "category", "month", "amount", "kind", "reason", "created_at",
+	}).
+		AddRow(uuid.New(), subID, userID, "Netflix", nil, from, 599, "subscription", nil, createdAt).
+		AddRow(uuid.New(), subID, userID, "Netflix", nil, from, -599, "refund", "service outage", createdAt).
+		AddRow(uuid.New(), subID, userID, "Netflix", nil, from.AddDate(0, 1, 0), 599, "subscription", nil, createdAt)
+
+	s.mock.ExpectQuery(`SELECT .* FROM \( SELECT .* FROM charges UNION ALL SELECT .* FROM adjustments a .*\) AS ledger WHERE 1=1 AND user_id = \$1 AND month >= \$2 ORDER BY month, service_name, created_at, id LIMIT \$3`).
+		WithArgs(userID, from, 3).
+		WillReturnRows(rows)
+
+	page, err := s.repo.ListCharges(s.ctx, model.ChargeFilter{UserID: &userID, From: &from, Limit: 2})
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Items, 2)
+	assert.Equal(s.T(), subID, page.Items[0].SubscriptionID)
+	assert.Nil(s.T(), page.Items[0].Category)
+	assert.Nil(s.T(), page.Items[0].Reason)
+	assert.Equal(s.T(), model.AdjustmentRefund, page.Items[1].Kind)
+	assert.Equal(s.T(), -599, page.Items[1].Amount)
+	assert.Equal(s.T(), "service outage", *page.Items[1].Reason)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+
+	// Следующая страница начинается после последней строки текущей
+	s.Require().NotNil(page.NextCursor)
+	last := page.Items[1]
+
+	s.mock.ExpectQuery(`AS ledger WHERE 1=1 AND user_id = \$1 AND \(month, service_name, created_at, id\) > \(\$2, \$3, \$4, \$5\) ORDER BY month, service_name, created_at, id LIMIT \$6`).
+		WithArgs(userID, from, "Netflix", createdAt, last.ID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subscription_id", "user_id", "service_name", "category", "month", "amount", "kind", "reason", "created_at",
+		}))
+
+	page, err = s.repo.ListCharges(s.ctx, model.ChargeFilter{UserID: &userID, Limit: 2, Cursor: *page.NextCursor})
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), page.Items)
+	assert.Nil(s.T(), page.NextCursor)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListCharges_InvalidCursor() {
+	// Курсор списка подписок не подходит журналу
+	cursor := encodeCursor(pageCursor{Sort: "-created_at", Values: []json.RawMessage{[]byte(`"2025-01-01T00:00:00Z"`)}, ID: uuid.New()})
+
+	for _, value := range []string{"not-a-cursor", cursor} {
+		_, err := s.repo.ListCharges(s.ctx, model.ChargeFilter{Limit: 10, Cursor: value})
+
+		assert.ErrorIs(s.T(), err, ErrInvalidCursor)
+	}
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestStreamCharges_SubscriptionIDs() {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	month := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(`AS ledger WHERE 1=1 AND subscription_id = ANY\(\$1\) ORDER BY month, service_name, created_at, id$`).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subscription_id", "user_id", "service_name", "category", "month", "amount", "kind", "reason", "created_at",
+		}).
+			AddRow(uuid.New(), ids[0], uuid.New(), "Netflix", "video", month, 599, "subscription", nil, time.Now().UTC()).
+			AddRow(uuid.New(), ids[1], uuid.New(), "Spotify", nil, month, 299, "subscription", nil, time.Now().UTC()))
+
+	var charges []*model.Charge
+	err := s.repo.StreamCharges(s.ctx, model.ChargeFilter{SubscriptionIDs: ids}, func(charge *model.Charge) error {
+		charges = append(charges, charge)
+		return nil
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), charges, 2)
+	assert.Equal(s.T(), "video", *charges[0].Category)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestCalculateLedgerSummary() {
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) as total_amount, COUNT\(DISTINCT subscription_id\) as count, .* AS ledger WHERE 1=1 AND month >= \$1 AND month <= \$2`).
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"total_amount", "count", "adjustments_amount"}).AddRow(6589, 1, -599))
+
+	result, err := s.repo.CalculateLedgerSummary(s.ctx, startDate, endDate, nil, nil)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 6589, result.TotalAmount)
+	assert.Equal(s.T(), -599, result.AdjustmentsAmount)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor возвращается, если курсор пагинации не удалось разобрать
+// или он получен при другом порядке сортировки
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor — позиция последней строки страницы: значения полей сортировки и id.
+// Sort хранит порядок сортировки, при котором курсор был выдан.
+type pageCursor struct {
+	Sort   string            `json:"sort"`
+	Values []json.RawMessage `json:"values"`
+	ID     uuid.UUID         `json:"id"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// Ошибки репозитория, в которые переводятся ошибки драйвера
+var (
+	ErrNotFound            = errors.New("record not found")
+	ErrAlreadyExists       = errors.New("record already exists")
+	ErrConstraintViolation = errors.New("constraint violation")
+)
+
+// translateError переводит sql.ErrNoRows и нарушения ограничений Postgres в ошибки репозитория.
+// Имя нарушенного ограничения сохраняется в тексте ошибки.
+func translateError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, pqErr.Constraint)
+	case "check_violation", "foreign_key_violation", "not_null_violation", "exclusion_violation":
+		return fmt.Errorf("%w: %s", ErrConstraintViolation, pqErr.Constraint)
+	}
+
+	return err
+}
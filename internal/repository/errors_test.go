@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	otherErr := errors.New("connection refused")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "no rows", err: sql.ErrNoRows, want: ErrNotFound},
+		{name: "unique violation", err: &pq.Error{Code: "23505", Constraint: "subscriptions_pkey"}, want: ErrAlreadyExists},
+		{name: "check violation", err: &pq.Error{Code: "23514", Constraint: "adjustments_amount_check"}, want: ErrConstraintViolation},
+		{name: "foreign key violation", err: &pq.Error{Code: "23503", Constraint: "adjustments_subscription_id_fkey"}, want: ErrConstraintViolation},
+		{name: "other error", err: otherErr, want: otherErr},
+		{name: "nil", err: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, translateError(tt.err), tt.want)
+		})
+	}
+}
+
+func (s *PostgresRepositoryTestSuite) TestCreateSubscription_AlreadyExists() {
+	s.mock.ExpectQuery(`INSERT INTO subscriptions`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "subscriptions_pkey"})
+
+	err := s.repo.CreateSubscription(s.ctx, &model.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: 599, UserID: uuid.New()})
+
+	assert.ErrorIs(s.T(), err, ErrAlreadyExists)
+	assert.Contains(s.T(), err.Error(), "subscriptions_pkey")
+}
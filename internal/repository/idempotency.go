@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ClaimIdempotencyKey занимает ключ для нового запроса до lockedUntil с токеном аренды lease. Если
+// ключ свободен (или его срок истек), возвращается claimed == true. Ключ того же запроса, который
+// так и не завершился до lockedUntil (процесс упал или был остановлен), занимается заново с новым
+// токеном. Иначе возвращается уже сохраненная запись ключа.
+func (r *PostgresRepository) ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, lease uuid.UUID, now, lockedUntil, expiresAt time.Time) (*model.IdempotencyRecord, bool, error) {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", now); err != nil {
+		return nil, false, err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, fingerprint, lease, created_at, locked_until, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE
+		SET lease = EXCLUDED.lease, created_at = EXCLUDED.created_at, locked_until = EXCLUDED.locked_until, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.status_code IS NULL
+			AND idempotency_keys.fingerprint = EXCLUDED.fingerprint
+			AND idempotency_keys.locked_until < EXCLUDED.created_at
+	`, key, fingerprint, lease, now, lockedUntil, expiresAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if affected == 1 {
+		return nil, true, nil
+	}
+
+	record, err := r.getIdempotencyRecord(ctx, key)
+	return record, false, err
+}
+
+func (r *PostgresRepository) getIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	query := `
+		SELECT key, fingerprint, status_code, response_headers, response_body, created_at, locked_until, expires_at
+		FROM idempotency_keys WHERE key = $1
+	`
+
+	var record model.IdempotencyRecord
+	var statusCode sql.NullInt64
+	var headers []byte
+
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.LockedUntil,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if statusCode.Valid {
+		record.StatusCode = int(statusCode.Int64)
+	}
+
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &record.Headers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &record, nil
+}
+
+// CompleteIdempotencyKey сохраняет ответ на запрос, занявший ключ с токеном lease. Если ключ
+// с тех пор занял другой запрос (срок блокировки истек) или освободили, возвращается ErrNotFound.
+func (r *PostgresRepository) CompleteIdempotencyKey(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error {
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status_code = $1, response_headers = $2, response_body = $3
+		WHERE key = $4 AND lease = $5 AND status_code IS NULL
+	`, statusCode, encodedHeaders, body, key, lease)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey освобождает ключ, занятый с токеном lease, чтобы запрос можно было
+// повторить. Ключ, который уже занял другой запрос, не освобождается.
+func (r *PostgresRepository) ReleaseIdempotencyKey(ctx context.Context, key string, lease uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND lease = $2 AND status_code IS NULL", key, lease)
+	return err
+}
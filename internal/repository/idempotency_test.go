@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *PostgresRepositoryTestSuite) TestClaimIdempotencyKey_Claimed() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(time.Minute)
+	expiresAt := now.Add(24 * time.Hour)
+	lease := uuid.New()
+
+	s.mock.ExpectExec(`DELETE FROM idempotency_keys WHERE expires_at <= \$1`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`INSERT INTO idempotency_keys \(key, fingerprint, lease, created_at, locked_until, expires_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) `+
+		`ON CONFLICT \(key\) DO UPDATE SET lease = EXCLUDED.lease, created_at = EXCLUDED.created_at, locked_until = EXCLUDED.locked_until, expires_at = EXCLUDED.expires_at `+
+		`WHERE idempotency_keys.status_code IS NULL AND idempotency_keys.fingerprint = EXCLUDED.fingerprint AND idempotency_keys.locked_until < EXCLUDED.created_at`).
+		WithArgs("key-1", "fp", lease, now, lockedUntil, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	record, claimed, err := s.repo.ClaimIdempotencyKey(s.ctx, "key-1", "fp", lease, now, lockedUntil, expiresAt)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), claimed)
+	assert.Nil(s.T(), record)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestClaimIdempotencyKey_Existing() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(time.Minute)
+	expiresAt := now.Add(24 * time.Hour)
+	lease := uuid.New()
+
+	s.mock.ExpectExec(`DELETE FROM idempotency_keys WHERE expires_at <= \$1`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`INSERT INTO idempotency_keys`).
+		WithArgs("key-1", "fp", lease, now, lockedUntil, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery(`SELECT key, fingerprint, status_code, response_headers, response_body, created_at, locked_until, expires_at FROM idempotency_keys WHERE key = \$1`).
+		WithArgs("key-1").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "response_headers", "response_body", "created_at", "locked_until", "expires_at"}).
+			AddRow("key-1", "fp", 201, []byte(`{"Content-Type":"application/json"}`), []byte(`{"id":"1"}`), now, lockedUntil, expiresAt))
+
+	record, claimed, err := s.repo.ClaimIdempotencyKey(s.ctx, "key-1", "fp", lease, now, lockedUntil, expiresAt)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), claimed)
+	assert.Equal(s.T(), 201, record.StatusCode)
+	assert.Equal(s.T(), "application/json", record.Headers["Content-Type"])
+	assert.Equal(s.T(), []byte(`{"id":"1"}`), record.Body)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestCompleteIdempotencyKey() {
+	lease := uuid.New()
+
+	s.mock.ExpectExec(`UPDATE idempotency_keys SET status_code = \$1, response_headers = \$2, response_body = \$3 WHERE key = \$4 AND lease = \$5 AND status_code IS NULL`).
+		WithArgs(201, []byte(`{"ETag":"\"1\""}`), []byte(`{}`), "key-1", lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.CompleteIdempotencyKey(s.ctx, "key-1", lease, 201, map[string]string{"ETag": `"1"`}, []byte(`{}`))
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestCompleteIdempotencyKey_LeaseLost() {
+	lease := uuid.New()
+
+	// Ключ занял другой запрос: строка с этим токеном не обновляется
+	s.mock.ExpectExec(`UPDATE idempotency_keys SET .* WHERE key = \$4 AND lease = \$5 AND status_code IS NULL`).
+		WithArgs(201, []byte(`null`), []byte(`{}`), "key-1", lease).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.CompleteIdempotencyKey(s.ctx, "key-1", lease, 201, nil, []byte(`{}`))
+
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestReleaseIdempotencyKey() {
+	lease := uuid.New()
+
+	s.mock.ExpectExec(`DELETE FROM idempotency_keys WHERE key = \$1 AND lease = \$2 AND status_code IS NULL`).
+		WithArgs("key-1", lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.ReleaseIdempotencyKey(s.ctx, "key-1", lease)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
@@ -3,131 +3,355 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/ZnNr/subscription-service/internal/model"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"strings"
 	"time"
 )
 
+// ErrVersionConflict возвращается условной записью, если версия подписки уже изменилась
+var ErrVersionConflict = errors.New("subscription version conflict")
+
 type Repository interface {
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
 	CreateSubscription(ctx context.Context, sub *model.Subscription) error
 	GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
-	UpdateSubscription(ctx context.Context, id uuid.UUID, req *model.UpdateSubscriptionRequest) error
-	DeleteSubscription(ctx context.Context, id uuid.UUID) error
-	ListSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string) ([]*model.Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *model.Subscription, expectedVersion *int) error
+	DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion *int) error
+	ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error)
+	StreamSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error
 	CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error)
+	FindOverlappingSubscriptions(ctx context.Context, sub *model.Subscription) ([]*model.Subscription, error)
+	LockSubscriptionKey(ctx context.Context, userID uuid.UUID, serviceName string) error
+	ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error)
+	SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error)
+	SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error)
+	UpsertBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error)
+	ListBudgets(ctx context.Context, userID uuid.UUID) ([]*model.Budget, error)
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+	MonthlyCosts(ctx context.Context, userID uuid.UUID, category *string, startDate, endDate time.Time) ([]*model.MonthlyCost, error)
+	RegenerateCharges(ctx context.Context, subscriptionID uuid.UUID, horizon time.Time) error
+	RebuildCharges(ctx context.Context, horizon time.Time) error
+	ExtendCharges(ctx context.Context, horizon time.Time) error
+	ListCharges(ctx context.Context, filter model.ChargeFilter) (*model.ChargePage, error)
+	StreamCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error
+	CalculateLedgerSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error)
+	CreateAdjustment(ctx context.Context, adj *model.Adjustment) error
+	ListAdjustments(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Adjustment, error)
+	DeleteAdjustment(ctx context.Context, id uuid.UUID) error
+	SumAdjustments(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (int, error)
+	ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, lease uuid.UUID, now, lockedUntil, expiresAt time.Time) (*model.IdempotencyRecord, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key string, lease uuid.UUID) error
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) error
+	ListWebhooks(ctx context.Context) ([]*model.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	EnqueueWebhookDeliveries(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte, now time.Time) error
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutgoingWebhook, error)
+	RecordWebhookAttempt(ctx context.Context, attempt *model.WebhookAttempt, status string, nextAttemptAt, deliveredAt *time.Time) error
+	ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, id uuid.UUID, now time.Time) error
 }
 
 type PostgresRepository struct {
-	db *sql.DB
+	// db — *sql.DB или текущая транзакция, если репозиторий получен из WithTx
+	db   dbtx
+	conn *sql.DB
+	tx   *sql.Tx
+	// depth — уровень вложенности точек сохранения внутри tx
+	depth int
 }
 
 func NewPostgresRepository(db *sql.DB) *PostgresRepository {
-	return &PostgresRepository{db: db}
+	return &PostgresRepository{db: db, conn: db}
 }
 
 func (r *PostgresRepository) CreateSubscription(ctx context.Context, sub *model.Subscription) error {
 	query := `
-		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING version
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		sub.ID, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate, sub.CreatedAt, sub.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query,
+		sub.ID, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate, sub.Category, sub.CreatedAt, sub.UpdatedAt).
+		Scan(&sub.Version)
 
-	return err
+	return translateError(err)
 }
 
 func (r *PostgresRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		SELECT id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at, version
 		FROM subscriptions WHERE id = $1
 	`
 
-	row := r.db.QueryRowContext(ctx, query, id)
-	return scanSubscription(row)
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return sub, nil
 }
 
-func (r *PostgresRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, req *model.UpdateSubscriptionRequest) error {
-	// Build dynamic update query
-	query := "UPDATE subscriptions SET updated_at = $1"
-	args := []interface{}{time.Now().UTC()}
-	argIndex := 2
+// UpdateSubscription перезаписывает все изменяемые поля подписки sub.ID и увеличивает её версию.
+// Если expectedVersion задан, запись выполняется только при совпадении текущей версии —
+// проверка и запись атомарны. Новая версия записывается в sub.Version.
+func (r *PostgresRepository) UpdateSubscription(ctx context.Context, sub *model.Subscription, expectedVersion *int) error {
+	query := `
+		UPDATE subscriptions
+		SET service_name = $1, price = $2, user_id = $3, start_date = $4, end_date = $5, category = $6, updated_at = $7,
+			version = version + 1
+		WHERE id = $8 AND ($9::int IS NULL OR version = $9)
+		RETURNING version
+	`
 
-	if req.ServiceName != nil {
-		query += fmt.Sprintf(", service_name = $%d", argIndex)
-		args = append(args, *req.ServiceName)
-		argIndex++
+	err := r.db.QueryRowContext(ctx, query,
+		sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate, sub.Category, sub.UpdatedAt, sub.ID, expectedVersion).
+		Scan(&sub.Version)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.missingOrConflict(ctx, sub.ID, expectedVersion)
 	}
 
-	if req.Price != nil {
-		query += fmt.Sprintf(", price = $%d", argIndex)
-		args = append(args, *req.Price)
-		argIndex++
+	return translateError(err)
+}
+
+// DeleteSubscription удаляет подписку; если expectedVersion задан — только при совпадении версии
+func (r *PostgresRepository) DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion *int) error {
+	query := "DELETE FROM subscriptions WHERE id = $1 AND ($2::int IS NULL OR version = $2)"
+
+	result, err := r.db.ExecContext(ctx, query, id, expectedVersion)
+	if err != nil {
+		return translateError(err)
 	}
 
-	if req.StartDate != nil {
-		query += fmt.Sprintf(", start_date = $%d", argIndex)
-		args = append(args, *req.StartDate)
-		argIndex++
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
 
-	if req.EndDate != nil {
-		query += fmt.Sprintf(", end_date = $%d", argIndex)
-		args = append(args, *req.EndDate)
-		argIndex++
+	if affected == 0 {
+		return r.missingOrConflict(ctx, id, expectedVersion)
 	}
 
-	query += fmt.Sprintf(" WHERE id = $%d", argIndex)
-	args = append(args, id)
+	return nil
+}
 
-	_, err := r.db.ExecContext(ctx, query, args...)
-	return err
+// missingOrConflict объясняет, почему условная запись не затронула строку:
+// ErrNotFound — подписки нет, ErrVersionConflict — версия уже изменилась
+func (r *PostgresRepository) missingOrConflict(ctx context.Context, id uuid.UUID, expectedVersion *int) error {
+	if expectedVersion == nil {
+		return ErrNotFound
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM subscriptions WHERE id = $1)", id).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrNotFound
+	}
+
+	return ErrVersionConflict
 }
 
-func (r *PostgresRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	query := "DELETE FROM subscriptions WHERE id = $1"
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+// ListSubscriptions возвращает страницу подписок в порядке filter.Sort (по умолчанию от новых к старым).
+// Пагинация ключевая: курсор хранит позицию последней строки предыдущей страницы.
+func (r *PostgresRepository) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	query, args, sort, err := subscriptionListQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну строку больше, чтобы понять, есть ли следующая страница
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, filter.Limit+1)
+
+	page := &model.SubscriptionPage{Items: []*model.Subscription{}}
+	err = r.querySubscriptions(ctx, query, args, func(sub *model.Subscription) error {
+		page.Items = append(page.Items, sub)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		next := encodeCursor(newPageCursor(sort, last))
+		page.NextCursor = &next
+	}
+
+	return page, nil
 }
 
-func (r *PostgresRepository) ListSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string) ([]*model.Subscription, error) {
+// StreamSubscriptions передает в fn все подписки, подходящие под фильтр, в порядке filter.Sort
+// (при filter.ByUpdate — по updated_at), читая их из курсора базы данных по одной,
+// без загрузки всей выборки в память.
+// filter.Limit не применяется; ошибка fn прерывает чтение и возвращается.
+func (r *PostgresRepository) StreamSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error {
+	query, args, _, err := subscriptionListQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	return r.querySubscriptions(ctx, query, args, fn)
+}
+
+// querySubscriptions выполняет запрос, возвращающий подписки, и передает каждую строку в fn
+func (r *PostgresRepository) querySubscriptions(ctx context.Context, query string, args []interface{}, fn func(sub *model.Subscription) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sub, err := scanSubscriptionFromRows(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(sub); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// subscriptionListQuery строит запрос списка подписок с условиями фильтра, позицией курсора
+// и сортировкой (без LIMIT). Возвращает также итоговый порядок сортировки.
+func subscriptionListQuery(filter model.SubscriptionFilter) (string, []interface{}, []model.SortField, error) {
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		SELECT id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at, version
 		FROM subscriptions WHERE 1=1
 	`
 	var args []interface{}
 	argIndex := 1
 
-	if userID != nil {
+	if len(filter.IDs) > 0 {
+		query += fmt.Sprintf(" AND id = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.IDs))
+		argIndex++
+	}
+
+	if filter.UserID != nil {
 		query += fmt.Sprintf(" AND user_id = $%d", argIndex)
-		args = append(args, *userID)
+		args = append(args, *filter.UserID)
 		argIndex++
 	}
 
-	if serviceName != nil {
-		query += fmt.Sprintf(" AND service_name = $%d", argIndex)
-		args = append(args, *serviceName)
+	if len(filter.ServiceNames) > 0 {
+		query += fmt.Sprintf(" AND service_name = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ServiceNames))
+		argIndex++
 	}
 
-	query += " ORDER BY created_at DESC"
+	if filter.Search != nil {
+		query += fmt.Sprintf(" AND (service_name ILIKE $%d OR category ILIKE $%d)", argIndex, argIndex)
+		args = append(args, "%"+escapeLike(*filter.Search)+"%")
+		argIndex++
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
+	if filter.ActiveAt != nil {
+		query += fmt.Sprintf(" AND start_date <= $%d AND (end_date IS NULL OR end_date >= $%d)", argIndex, argIndex)
+		args = append(args, *filter.ActiveAt)
+		argIndex++
 	}
-	defer rows.Close()
 
-	var subscriptions []*model.Subscription
-	for rows.Next() {
-		sub, err := scanSubscriptionFromRows(rows)
+	if filter.PriceMin != nil {
+		query += fmt.Sprintf(" AND price >= $%d", argIndex)
+		args = append(args, *filter.PriceMin)
+		argIndex++
+	}
+
+	if filter.PriceMax != nil {
+		query += fmt.Sprintf(" AND price <= $%d", argIndex)
+		args = append(args, *filter.PriceMax)
+		argIndex++
+	}
+
+	if filter.StartFrom != nil {
+		query += fmt.Sprintf(" AND start_date >= $%d", argIndex)
+		args = append(args, *filter.StartFrom)
+		argIndex++
+	}
+
+	if filter.StartTo != nil {
+		query += fmt.Sprintf(" AND start_date <= $%d", argIndex)
+		args = append(args, *filter.StartTo)
+		argIndex++
+	}
+
+	if filter.EndFrom != nil {
+		query += fmt.Sprintf(" AND end_date >= $%d", argIndex)
+		args = append(args, *filter.EndFrom)
+		argIndex++
+	}
+
+	if filter.EndTo != nil {
+		query += fmt.Sprintf(" AND end_date <= $%d", argIndex)
+		args = append(args, *filter.EndTo)
+		argIndex++
+	}
+
+	if filter.UpdatedSince != nil {
+		query += fmt.Sprintf(" AND updated_at >= $%d", argIndex)
+		args = append(args, *filter.UpdatedSince)
+		argIndex++
+	}
+
+	// Статус считается относительно текущего месяца
+	switch filter.Status {
+	case model.SubscriptionStatusActive:
+		query += " AND start_date <= date_trunc('month', CURRENT_DATE) AND (end_date IS NULL OR end_date >= date_trunc('month', CURRENT_DATE))"
+	case model.SubscriptionStatusEnded:
+		query += " AND end_date < date_trunc('month', CURRENT_DATE)"
+	case model.SubscriptionStatusFuture:
+		query += " AND start_date > date_trunc('month', CURRENT_DATE)"
+	}
+
+	for _, f := range filter.Sort {
+		if _, ok := sortExpressions[f.Field]; !ok {
+			return "", nil, nil, ErrInvalidSort
+		}
+	}
+
+	sort := filter.Sort
+	switch {
+	case filter.ByUpdate && len(sort) > 0:
+		return "", nil, nil, ErrInvalidSort
+	case filter.ByUpdate:
+		sort = updateSort
+	case len(sort) == 0:
+		sort = defaultSort
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
 		if err != nil {
-			return nil, err
+			return "", nil, nil, err
 		}
-		subscriptions = append(subscriptions, sub)
+
+		condition, cursorArgs, err := keysetCondition(sort, cursor, argIndex)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		query += " AND " + condition
+		args = append(args, cursorArgs...)
 	}
 
-	return subscriptions, nil
+	return query + orderByClause(sort), args, sort, nil
+
 }
 
 func (r *PostgresRepository) CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
@@ -161,9 +385,90 @@ func (r *PostgresRepository) CalculateSummary(ctx context.Context, startDate, en
 	return &summary, nil
 }
 
+// LockSubscriptionKey блокирует подписки пользователя на сервис (без учета регистра названия)
+// до конца текущей транзакции. Проверка пересечений и запись выполняются под этой блокировкой,
+// поэтому две параллельные записи не сохранят пересекающиеся периоды. Вызывается только внутри
+// WithTx: вне транзакции блокировка снимается сразу.
+func (r *PostgresRepository) LockSubscriptionKey(ctx context.Context, userID uuid.UUID, serviceName string) error {
+	_, err := r.db.ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtextextended('subscription:' || $1::text || ':' || LOWER($2), 0))`,
+		userID, serviceName)
+	return err
+}
+
+// FindOverlappingSubscriptions возвращает подписки того же пользователя на тот же сервис,
+// период действия которых пересекается с периодом sub (сама sub исключается)
+func (r *PostgresRepository) FindOverlappingSubscriptions(ctx context.Context, sub *model.Subscription) ([]*model.Subscription, error) {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at, version
+		FROM subscriptions
+		WHERE user_id = $1 AND LOWER(service_name) = LOWER($2) AND id <> $3
+			AND ($4::date IS NULL OR start_date <= $4)
+			AND (end_date IS NULL OR end_date >= $5)
+		ORDER BY start_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sub.UserID, sub.ServiceName, sub.ID, sub.EndDate, sub.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*model.Subscription
+	for rows.Next() {
+		s, err := scanSubscriptionFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// ListOverlaps находит все пары пересекающихся подписок в базе
+func (r *PostgresRepository) ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error) {
+	query := `
+		SELECT a.user_id, a.service_name, a.id, b.id,
+			GREATEST(a.start_date, b.start_date) AS overlap_start,
+			LEAST(a.end_date, b.end_date) AS overlap_end
+		FROM subscriptions a
+		JOIN subscriptions b ON a.user_id = b.user_id
+			AND LOWER(a.service_name) = LOWER(b.service_name)
+			AND a.id < b.id
+			AND (a.end_date IS NULL OR a.end_date >= b.start_date)
+			AND (b.end_date IS NULL OR b.end_date >= a.start_date)
+		ORDER BY a.user_id, a.service_name, overlap_start
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overlaps []*model.SubscriptionOverlap
+	for rows.Next() {
+		var o model.SubscriptionOverlap
+		var overlapEnd sql.NullTime
+
+		if err := rows.Scan(&o.UserID, &o.ServiceName, &o.SubscriptionID, &o.OverlappingID, &o.OverlapStart, &overlapEnd); err != nil {
+			return nil, err
+		}
+
+		if overlapEnd.Valid {
+			o.OverlapEnd = &overlapEnd.Time
+		}
+		overlaps = append(overlaps, &o)
+	}
+
+	return overlaps, rows.Err()
+}
+
 func scanSubscription(row *sql.Row) (*model.Subscription, error) {
 	var sub model.Subscription
 	var endDate sql.NullTime
+	var category sql.NullString
 
 	err := row.Scan(
 		&sub.ID,
@@ -172,8 +477,10 @@ func scanSubscription(row *sql.Row) (*model.Subscription, error) {
 		&sub.UserID,
 		&sub.StartDate,
 		&endDate,
+		&category,
 		&sub.CreatedAt,
 		&sub.UpdatedAt,
+		&sub.Version,
 	)
 
 	if err != nil {
@@ -184,23 +491,33 @@ func scanSubscription(row *sql.Row) (*model.Subscription, error) {
 		sub.EndDate = &endDate.Time
 	}
 
+	if category.Valid {
+		sub.Category = &category.String
+	}
+
 	return &sub, nil
 }
 
-func scanSubscriptionFromRows(rows *sql.Rows) (*model.Subscription, error) {
+// scanSubscriptionFromRows читает подписку из текущей строки; extra — столбцы, следующие
+// за столбцами подписки (например, оценка сходства в поиске)
+func scanSubscriptionFromRows(rows *sql.Rows, extra ...any) (*model.Subscription, error) {
 	var sub model.Subscription
 	var endDate sql.NullTime
+	var category sql.NullString
 
-	err := rows.Scan(
+	dest := []any{
 		&sub.ID,
 		&sub.ServiceName,
 		&sub.Price,
 		&sub.UserID,
 		&sub.StartDate,
 		&endDate,
+		&category,
 		&sub.CreatedAt,
 		&sub.UpdatedAt,
-	)
+		&sub.Version,
+	}
+	err := rows.Scan(append(dest, extra...)...)
 
 	if err != nil {
 		return nil, err
@@ -210,5 +527,16 @@ func scanSubscriptionFromRows(rows *sql.Rows) (*model.Subscription, error) {
 		sub.EndDate = &endDate.Time
 	}
 
+	if category.Valid {
+		sub.Category = &category.String
+	}
+
 	return &sub, nil
 }
+
+// likeEscaper экранирует спецсимволы шаблона LIKE, чтобы строка поиска сравнивалась буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -46,16 +47,17 @@ func (s *PostgresRepositoryTestSuite) TestCreateSubscription() {
 		UpdatedAt:   time.Now().UTC(),
 	}
 
-	s.mock.ExpectExec(`INSERT INTO subscriptions`).
+	s.mock.ExpectQuery(`INSERT INTO subscriptions .* RETURNING version`).
 		WithArgs(
 			sub.ID, sub.ServiceName, sub.Price, sub.UserID,
-			sub.StartDate, sub.EndDate, sub.CreatedAt, sub.UpdatedAt,
+			sub.StartDate, sub.EndDate, sub.Category, sub.CreatedAt, sub.UpdatedAt,
 		).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
 
 	err := s.repo.CreateSubscription(s.ctx, sub)
 
 	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, sub.Version)
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
@@ -74,10 +76,10 @@ func (s *PostgresRepositoryTestSuite) TestGetSubscription() {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "service_name", "price", "user_id",
-		"start_date", "end_date", "created_at", "updated_at",
+		"start_date", "end_date", "category", "created_at", "updated_at", "version",
 	}).AddRow(
 		expectedSub.ID, expectedSub.ServiceName, expectedSub.Price, expectedSub.UserID,
-		expectedSub.StartDate, expectedSub.EndDate, expectedSub.CreatedAt, expectedSub.UpdatedAt,
+		expectedSub.StartDate, expectedSub.EndDate, expectedSub.Category, expectedSub.CreatedAt, expectedSub.UpdatedAt, 3,
 	)
 
 	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE id = \$1`).
@@ -90,6 +92,7 @@ func (s *PostgresRepositoryTestSuite) TestGetSubscription() {
 	assert.Equal(s.T(), expectedSub.ID, result.ID)
 	assert.Equal(s.T(), expectedSub.ServiceName, result.ServiceName)
 	assert.Equal(s.T(), expectedSub.Price, result.Price)
+	assert.Equal(s.T(), 3, result.Version)
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
@@ -100,47 +103,97 @@ func (s *PostgresRepositoryTestSuite) TestGetSubscription_NotFound() {
 		WithArgs(subID).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "service_name", "price", "user_id",
-			"start_date", "end_date", "created_at", "updated_at",
+			"start_date", "end_date", "category", "created_at", "updated_at", "version",
 		}))
 
 	result, err := s.repo.GetSubscription(s.ctx, subID)
 
-	assert.Error(s.T(), err)
+	assert.ErrorIs(s.T(), err, ErrNotFound)
 	assert.Nil(s.T(), result)
 
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
 func (s *PostgresRepositoryTestSuite) TestUpdateSubscription() {
-	subID := uuid.New()
-	updateReq := &model.UpdateSubscriptionRequest{
-		Price:       &[]int{699}[0],
-		ServiceName: &[]string{"Netflix Premium"}[0],
+	sub := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix Premium",
+		Price:       699,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Now().UTC(),
 	}
 
-	s.mock.ExpectExec(`UPDATE subscriptions SET updated_at = \$1, service_name = \$2, price = \$3 WHERE id = \$4`).
-		WithArgs(sqlmock.AnyArg(), "Netflix Premium", 699, subID).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	// end_date и category записываются всегда, nil очищает их
+	s.mock.ExpectQuery(`UPDATE subscriptions SET service_name = \$1, price = \$2, user_id = \$3, start_date = \$4, end_date = \$5, category = \$6, updated_at = \$7, version = version \+ 1 WHERE id = \$8 AND \(\$9::int IS NULL OR version = \$9\) RETURNING version`).
+		WithArgs("Netflix Premium", 699, sub.UserID, sub.StartDate, nil, nil, sub.UpdatedAt, sub.ID, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
 
-	err := s.repo.UpdateSubscription(s.ctx, subID, updateReq)
+	err := s.repo.UpdateSubscription(s.ctx, sub, nil)
 
 	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, sub.Version)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestUpdateSubscription_NotFound() {
+	s.mock.ExpectQuery(`UPDATE subscriptions SET`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+	err := s.repo.UpdateSubscription(s.ctx, &model.Subscription{ID: uuid.New()}, nil)
+
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestUpdateSubscription_VersionConflict() {
+	sub := &model.Subscription{ID: uuid.New()}
+	expected := 3
+
+	s.mock.ExpectQuery(`UPDATE subscriptions SET`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sub.ID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	s.mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM subscriptions WHERE id = \$1\)`).
+		WithArgs(sub.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err := s.repo.UpdateSubscription(s.ctx, sub, &expected)
+
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
 func (s *PostgresRepositoryTestSuite) TestDeleteSubscription() {
 	subID := uuid.New()
 
-	s.mock.ExpectExec(`DELETE FROM subscriptions WHERE id = \$1`).
-		WithArgs(subID).
+	s.mock.ExpectExec(`DELETE FROM subscriptions WHERE id = \$1 AND \(\$2::int IS NULL OR version = \$2\)`).
+		WithArgs(subID, nil).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err := s.repo.DeleteSubscription(s.ctx, subID)
+	err := s.repo.DeleteSubscription(s.ctx, subID, nil)
 
 	assert.NoError(s.T(), err)
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
+func (s *PostgresRepositoryTestSuite) TestDeleteSubscription_VersionConflict() {
+	subID := uuid.New()
+	expected := 2
+
+	s.mock.ExpectExec(`DELETE FROM subscriptions WHERE id = \$1`).
+		WithArgs(subID, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(subID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err := s.repo.DeleteSubscription(s.ctx, subID, &expected)
+
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
 func (s *PostgresRepositoryTestSuite) TestListSubscriptions() {
 	userID := uuid.New()
 	serviceName := "Netflix"
@@ -161,23 +214,229 @@ func (s *PostgresRepositoryTestSuite) TestListSubscriptions() {
 	// Ожидаем SQL запрос
 	rows := sqlmock.NewRows([]string{
 		"id", "service_name", "price", "user_id",
-		"start_date", "end_date", "created_at", "updated_at",
+		"start_date", "end_date", "category", "created_at", "updated_at", "version",
 	}).AddRow(
 		expectedSubs[0].ID, expectedSubs[0].ServiceName, expectedSubs[0].Price, expectedSubs[0].UserID,
-		expectedSubs[0].StartDate, expectedSubs[0].EndDate, expectedSubs[0].CreatedAt, expectedSubs[0].UpdatedAt,
+		expectedSubs[0].StartDate, expectedSubs[0].EndDate, expectedSubs[0].Category, expectedSubs[0].CreatedAt, expectedSubs[0].UpdatedAt, 1,
 	)
 
-	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND user_id = \$1 AND service_name = \$2 ORDER BY created_at DESC`).
-		WithArgs(userID, serviceName).
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND user_id = \$1 AND service_name = ANY\(\$2\) ORDER BY created_at DESC, id DESC LIMIT \$3`).
+		WithArgs(userID, pq.Array([]string{serviceName}), 11).
 		WillReturnRows(rows)
 
 	// Вызываем метод
-	result, err := s.repo.ListSubscriptions(s.ctx, &userID, &serviceName)
+	result, err := s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{UserID: &userID, ServiceNames: []string{serviceName}, Limit: 10})
 
 	// Проверяем
 	assert.NoError(s.T(), err)
-	assert.Len(s.T(), result, 1)
-	assert.Equal(s.T(), expectedSubs[0].ServiceName, result[0].ServiceName)
+	assert.Len(s.T(), result.Items, 1)
+	assert.Equal(s.T(), expectedSubs[0].ServiceName, result.Items[0].ServiceName)
+	assert.Nil(s.T(), result.NextCursor)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListSubscriptions_IDs() {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND id = ANY\(\$1\) ORDER BY created_at DESC, id DESC LIMIT \$2`).
+		WithArgs(pq.Array(ids), 3).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "service_name", "price", "user_id",
+			"start_date", "end_date", "category", "created_at", "updated_at", "version",
+		}))
+
+	result, err := s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{IDs: ids, Limit: 2})
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.Items)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestStreamSubscriptions() {
+	first, second := uuid.New(), uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "service_name", "price", "user_id",
+		"start_date", "end_date", "category", "created_at", "updated_at", "version",
+	}).
+		AddRow(first, "Netflix", 599, uuid.New(), start, nil, nil, start, start, 1).
+		AddRow(second, "Spotify", 299, uuid.New(), start, nil, nil, start, start, 1)
+
+	// Выгрузка читает всю выборку без LIMIT
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND price >= \$1 ORDER BY price ASC, id ASC$`).
+		WithArgs(100).
+		WillReturnRows(rows)
+
+	minPrice := 100
+	var ids []uuid.UUID
+	err := s.repo.StreamSubscriptions(s.ctx, model.SubscriptionFilter{
+		PriceMin: &minPrice,
+		Sort:     []model.SortField{{Field: "price"}},
+	}, func(sub *model.Subscription) error {
+		ids = append(ids, sub.ID)
+		return nil
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []uuid.UUID{first, second}, ids)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestStreamSubscriptions_UpdatedSince() {
+	since := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND user_id = \$1 AND updated_at >= \$2 ORDER BY updated_at ASC, id ASC$`).
+		WithArgs(userID, since).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "service_name", "price", "user_id",
+			"start_date", "end_date", "category", "created_at", "updated_at", "version",
+		}))
+
+	err := s.repo.StreamSubscriptions(s.ctx, model.SubscriptionFilter{
+		UserID:       &userID,
+		UpdatedSince: &since,
+		ByUpdate:     true,
+	}, func(sub *model.Subscription) error {
+		return nil
+	})
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestStreamSubscriptions_UpdatedAtNotSortable() {
+	for _, filter := range []model.SubscriptionFilter{
+		{Sort: []model.SortField{{Field: "updated_at"}}},
+		{Sort: []model.SortField{{Field: "price"}}, ByUpdate: true},
+	} {
+		err := s.repo.StreamSubscriptions(s.ctx, filter, func(sub *model.Subscription) error {
+			return nil
+		})
+
+		assert.ErrorIs(s.T(), err, ErrInvalidSort)
+	}
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListSubscriptions_RichFilter() {
+	activeAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	startFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTo := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	search := "50%_off"
+	priceMin, priceMax := 100, 1000
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 `+
+		`AND service_name = ANY\(\$1\) `+
+		`AND \(service_name ILIKE \$2 OR category ILIKE \$2\) `+
+		`AND start_date <= \$3 AND \(end_date IS NULL OR end_date >= \$3\) `+
+		`AND price >= \$4 AND price <= \$5 `+
+		`AND start_date >= \$6 AND end_date <= \$7 `+
+		`AND end_date < date_trunc\('month', CURRENT_DATE\) `+
+		`ORDER BY created_at DESC, id DESC LIMIT \$8`).
+		WithArgs(pq.Array([]string{"Netflix", "Spotify"}), `%50\%\_off%`, activeAt, priceMin, priceMax, startFrom, endTo, 11).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "service_name", "price", "user_id",
+			"start_date", "end_date", "category", "created_at", "updated_at", "version",
+		}))
+
+	page, err := s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{
+		ServiceNames: []string{"Netflix", "Spotify"},
+		Search:       &search,
+		ActiveAt:     &activeAt,
+		PriceMin:     &priceMin,
+		PriceMax:     &priceMax,
+		StartFrom:    &startFrom,
+		EndTo:        &endTo,
+		Status:       model.SubscriptionStatusEnded,
+		Limit:        10,
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), page.Items)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListSubscriptions_NextPage() {
+	createdAt := time.Date(2025, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	columns := []string{
+		"id", "service_name", "price", "user_id",
+		"start_date", "end_date", "category", "created_at", "updated_at", "version",
+	}
+
+	firstID, secondID := uuid.New(), uuid.New()
+	rows := sqlmock.NewRows(columns).
+		AddRow(firstID, "Netflix", 599, uuid.New(), createdAt, nil, nil, createdAt, createdAt, 1).
+		AddRow(secondID, "Spotify", 299, uuid.New(), createdAt, nil, nil, createdAt.Add(-time.Hour), createdAt, 1)
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 ORDER BY created_at DESC, id DESC LIMIT \$1`).
+		WithArgs(2).
+		WillReturnRows(rows)
+
+	page, err := s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{Limit: 1})
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Items, 1)
+	assert.NotNil(s.T(), page.NextCursor)
+
+	// Следующая страница начинается строго после последней строки текущей
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND \(created_at < \$1 OR \(created_at = \$1 AND id < \$2\)\) ORDER BY created_at DESC, id DESC LIMIT \$3`).
+		WithArgs(createdAt, firstID, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(secondID, "Spotify", 299, uuid.New(), createdAt, nil, nil, createdAt.Add(-time.Hour), createdAt, 1))
+
+	page, err = s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{Limit: 1, Cursor: *page.NextCursor})
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Items, 1)
+	assert.Equal(s.T(), secondID, page.Items[0].ID)
+	assert.Nil(s.T(), page.NextCursor)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListSubscriptions_SortedNextPage() {
+	now := time.Now().UTC()
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	sort := []model.SortField{{Field: "price", Desc: true}, {Field: "end_date"}}
+	columns := []string{
+		"id", "service_name", "price", "user_id",
+		"start_date", "end_date", "category", "created_at", "updated_at", "version",
+	}
+
+	firstID := uuid.New()
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 ` +
+		`ORDER BY price DESC, COALESCE\(end_date, '9999-12-01'::date\) ASC, id ASC LIMIT \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(firstID, "Netflix", 599, uuid.New(), now, endDate, nil, now, now, 1).
+			AddRow(uuid.New(), "Spotify", 299, uuid.New(), now, nil, nil, now, now, 1))
+
+	page, err := s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{Sort: sort, Limit: 1})
+
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), page.NextCursor)
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE 1=1 AND \(price < \$1 `+
+		`OR \(price = \$1 AND COALESCE\(end_date, '9999-12-01'::date\) > \$2\) `+
+		`OR \(price = \$1 AND COALESCE\(end_date, '9999-12-01'::date\) = \$2 AND id > \$3\)\) `+
+		`ORDER BY price DESC, COALESCE\(end_date, '9999-12-01'::date\) ASC, id ASC LIMIT \$4`).
+		WithArgs(599, endDate, firstID, 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	_, err = s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{Sort: sort, Limit: 1, Cursor: *page.NextCursor})
+	assert.NoError(s.T(), err)
+
+	// Курсор, выданный при другой сортировке, не принимается
+	_, err = s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{Limit: 1, Cursor: *page.NextCursor})
+	assert.ErrorIs(s.T(), err, ErrInvalidCursor)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListSubscriptions_InvalidCursor() {
+	_, err := s.repo.ListSubscriptions(s.ctx, model.SubscriptionFilter{Limit: 10, Cursor: "not a cursor"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidCursor)
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
@@ -201,6 +460,75 @@ func (s *PostgresRepositoryTestSuite) TestCalculateSummary() {
 	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
+func (s *PostgresRepositoryTestSuite) TestLockSubscriptionKey() {
+	userID := uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtextextended\('subscription:' \|\| \$1::text \|\| ':' \|\| LOWER\(\$2\), 0\)\)`).
+		WithArgs(userID, "Netflix").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.WithTx(s.ctx, func(repo Repository) error {
+		return repo.LockSubscriptionKey(s.ctx, userID, "Netflix")
+	})
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestFindOverlappingSubscriptions() {
+	sub := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	existingID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "service_name", "price", "user_id",
+		"start_date", "end_date", "category", "created_at", "updated_at", "version",
+	}).AddRow(
+		existingID, "netflix", 599, sub.UserID,
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), nil, nil, time.Now().UTC(), time.Now().UTC(), 1,
+	)
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions WHERE user_id = \$1 AND LOWER\(service_name\) = LOWER\(\$2\) AND id <> \$3`).
+		WithArgs(sub.UserID, sub.ServiceName, sub.ID, sub.EndDate, sub.StartDate).
+		WillReturnRows(rows)
+
+	result, err := s.repo.FindOverlappingSubscriptions(s.ctx, sub)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), existingID, result[0].ID)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestListOverlaps() {
+	userID := uuid.New()
+	firstID, secondID := uuid.New(), uuid.New()
+	overlapStart := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"user_id", "service_name", "id", "id", "overlap_start", "overlap_end",
+	}).AddRow(userID, "Netflix", firstID, secondID, overlapStart, nil)
+
+	s.mock.ExpectQuery(`SELECT .* FROM subscriptions a JOIN subscriptions b ON a.user_id = b.user_id`).
+		WillReturnRows(rows)
+
+	result, err := s.repo.ListOverlaps(s.ctx)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), firstID, result[0].SubscriptionID)
+	assert.Equal(s.T(), secondID, result[0].OverlappingID)
+	assert.Nil(s.T(), result[0].OverlapEnd)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
 func TestPostgresRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(PostgresRepositoryTestSuite))
 }
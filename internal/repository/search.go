@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+)
+
+// SearchSubscriptions ищет подписки по сходству названия сервиса с запросом (расширение pg_trgm).
+// Подходит название, похожее на запрос целиком (оператор %) или содержащее похожее на запрос
+// слово или его часть (оператор <%); оба условия используют триграммный индекс по LOWER(service_name).
+// Результаты упорядочены по убыванию сходства.
+func (r *PostgresRepository) SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error) {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, category, created_at, updated_at, version,
+			GREATEST(similarity(LOWER(service_name), LOWER($1)), word_similarity(LOWER($1), LOWER(service_name))) AS score
+		FROM subscriptions
+		WHERE (LOWER(service_name) % LOWER($1) OR LOWER($1) <% LOWER(service_name))
+	`
+	args := []interface{}{search.Query}
+
+	if search.UserID != nil {
+		args = append(args, *search.UserID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+
+	args = append(args, search.Limit)
+	query += fmt.Sprintf(" ORDER BY score DESC, service_name, id LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*model.SubscriptionMatch
+	for rows.Next() {
+		var match model.SubscriptionMatch
+		sub, err := scanSubscriptionFromRows(rows, &match.Score)
+		if err != nil {
+			return nil, err
+		}
+		match.Subscription = sub
+		matches = append(matches, &match)
+	}
+
+	return matches, rows.Err()
+}
+
+// SuggestServiceNames возвращает различные названия сервисов, начинающиеся с prefix без учета
+// регистра, — сначала самые частые
+func (r *PostgresRepository) SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error) {
+	query := `
+		SELECT service_name, COUNT(*) AS subscriptions
+		FROM subscriptions
+		WHERE LOWER(service_name) LIKE LOWER($1)
+	`
+	args := []interface{}{escapeLike(prefix) + "%"}
+
+	if userID != nil {
+		args = append(args, *userID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf(" GROUP BY service_name ORDER BY subscriptions DESC, service_name LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []*model.ServiceNameSuggestion
+	for rows.Next() {
+		var s model.ServiceNameSuggestion
+		if err := rows.Scan(&s.ServiceName, &s.Subscriptions); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &s)
+	}
+
+	return suggestions, rows.Err()
+}
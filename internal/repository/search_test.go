@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *PostgresRepositoryTestSuite) TestSearchSubscriptions() {
+	userID := uuid.New()
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(`SELECT .*, GREATEST\(similarity\(LOWER\(service_name\), LOWER\(\$1\)\), word_similarity\(LOWER\(\$1\), LOWER\(service_name\)\)\) AS score `+
+		`FROM subscriptions WHERE \(LOWER\(service_name\) % LOWER\(\$1\) OR LOWER\(\$1\) <% LOWER\(service_name\)\) `+
+		`AND user_id = \$2 ORDER BY score DESC, service_name, id LIMIT \$3`).
+		WithArgs("youtube premum", userID, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "price", "user_id", "start_date", "end_date", "category", "created_at", "updated_at", "version", "score"}).
+			AddRow(uuid.New(), "YouTube Premium", 299, userID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), nil, "video", createdAt, createdAt, 2, 0.6875))
+
+	matches, err := s.repo.SearchSubscriptions(s.ctx, model.SubscriptionSearch{Query: "youtube premum", UserID: &userID, Limit: 20})
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), matches, 1)
+	assert.Equal(s.T(), "YouTube Premium", matches[0].Subscription.ServiceName)
+	assert.Equal(s.T(), "video", *matches[0].Subscription.Category)
+	assert.Equal(s.T(), 2, matches[0].Subscription.Version)
+	assert.Equal(s.T(), 0.6875, matches[0].Score)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestSuggestServiceNames() {
+	s.mock.ExpectQuery(`SELECT service_name, COUNT\(\*\) AS subscriptions FROM subscriptions WHERE LOWER\(service_name\) LIKE LOWER\(\$1\) `+
+		`GROUP BY service_name ORDER BY subscriptions DESC, service_name LIMIT \$2`).
+		WithArgs(`100\%%`, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"service_name", "subscriptions"}).
+			AddRow("100% Music", 4))
+
+	suggestions, err := s.repo.SuggestServiceNames(s.ctx, "100%", nil, 10)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []*model.ServiceNameSuggestion{{ServiceName: "100% Music", Subscriptions: 4}}, suggestions)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
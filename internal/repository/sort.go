@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSort возвращается для поля сортировки не из белого списка
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// openEndDate подставляется вместо end_date бессрочных подписок,
+// чтобы при сортировке и сравнении с курсором они шли после всех подписок с датой окончания
+var openEndDate = time.Date(9999, 12, 1, 0, 0, 0, 0, time.UTC)
+
+// sortExpressions — SQL-выражения для разрешенных полей сортировки
+var sortExpressions = map[string]string{
+	"price":        "price",
+	"start_date":   "start_date",
+	"end_date":     "COALESCE(end_date, '9999-12-01'::date)",
+	"service_name": "service_name",
+	"created_at":   "created_at",
+}
+
+// updateSort — порядок инкрементальной выгрузки (SubscriptionFilter.ByUpdate); updated_at
+// не входит в sortExpressions, поэтому через filter.Sort этот порядок не задать
+var updateSort = []model.SortField{{Field: "updated_at"}}
+
+// sortExpression возвращает SQL-выражение поля сортировки, включая внутреннее updated_at
+func sortExpression(field string) string {
+	if field == "updated_at" {
+		return "updated_at"
+	}
+
+	return sortExpressions[field]
+}
+
+// defaultSort — порядок выдачи по умолчанию: от новых подписок к старым
+var defaultSort = []model.SortField{{Field: "created_at", Desc: true}}
+
+// sortSpec возвращает каноническую запись порядка сортировки, например "-price,service_name"
+func sortSpec(sort []model.SortField) string {
+	parts := make([]string, len(sort))
+	for i, f := range sort {
+		parts[i] = f.Field
+		if f.Desc {
+			parts[i] = "-" + f.Field
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// orderByClause строит ORDER BY; id добавляется последним для однозначного порядка
+func orderByClause(sort []model.SortField) string {
+	parts := make([]string, 0, len(sort)+1)
+	for _, f := range sort {
+		parts = append(parts, sortExpression(f.Field)+direction(f.Desc))
+	}
+	parts = append(parts, "id"+direction(sort[len(sort)-1].Desc))
+
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// keysetCondition строит условие "строка идет после курсора" для порядка sort:
+// (f1 > v1) OR (f1 = v1 AND f2 > v2) OR ... OR (f1 = v1 AND ... AND id > id0),
+// где ">" заменяется на "<" для полей с обратной сортировкой.
+// argIndex — номер первого параметра; возвращается условие и его аргументы.
+func keysetCondition(sort []model.SortField, cursor pageCursor, argIndex int) (string, []interface{}, error) {
+	if cursor.Sort != sortSpec(sort) || len(cursor.Values) != len(sort) {
+		return "", nil, ErrInvalidCursor
+	}
+
+	exprs := make([]string, 0, len(sort)+1)
+	descs := make([]bool, 0, len(sort)+1)
+	args := make([]interface{}, 0, len(sort)+1)
+
+	for i, f := range sort {
+		value, err := decodeSortValue(f.Field, cursor.Values[i])
+		if err != nil {
+			return "", nil, ErrInvalidCursor
+		}
+		exprs = append(exprs, sortExpression(f.Field))
+		descs = append(descs, f.Desc)
+		args = append(args, value)
+	}
+	exprs = append(exprs, "id")
+	descs = append(descs, sort[len(sort)-1].Desc)
+	args = append(args, cursor.ID)
+
+	var alternatives []string
+	for i := range exprs {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = $%d", exprs[j], argIndex+j))
+		}
+
+		op := ">"
+		if descs[i] {
+			op = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s $%d", exprs[i], op, argIndex+i))
+
+		if len(parts) == 1 {
+			alternatives = append(alternatives, parts[0])
+		} else {
+			alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+		}
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+}
+
+// newPageCursor строит курсор по последней строке страницы
+func newPageCursor(sort []model.SortField, last *model.Subscription) pageCursor {
+	cursor := pageCursor{Sort: sortSpec(sort), ID: last.ID}
+	for _, f := range sort {
+		data, _ := json.Marshal(sortValue(f.Field, last))
+		cursor.Values = append(cursor.Values, data)
+	}
+
+	return cursor
+}
+
+// sortValue возвращает значение поля сортировки подписки так, как его сравнивает SQL-выражение поля
+func sortValue(field string, sub *model.Subscription) interface{} {
+	switch field {
+	case "price":
+		return sub.Price
+	case "start_date":
+		return sub.StartDate
+	case "end_date":
+		if sub.EndDate == nil {
+			return openEndDate
+		}
+		return *sub.EndDate
+	case "service_name":
+		return sub.ServiceName
+	case "updated_at":
+		return sub.UpdatedAt
+	default:
+		return sub.CreatedAt
+	}
+}
+
+func decodeSortValue(field string, raw json.RawMessage) (interface{}, error) {
+	switch field {
+	case "price":
+		var v int
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case "service_name":
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	default:
+		var v time.Time
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+
+	return " ASC"
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// dbtx — общий интерфейс *sql.DB и *sql.Tx, через который репозиторий выполняет запросы
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// WithTx выполняет fn в транзакции: репозиторий, переданный в fn, работает внутри неё.
+// Если fn вернула ошибку, все её изменения откатываются. Вызов WithTx на репозитории,
+// уже работающем в транзакции, создает точку сохранения, и при ошибке откатывается
+// только вложенная часть.
+func (r *PostgresRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	return r.inTx(ctx, func(tx *PostgresRepository) error {
+		return fn(tx)
+	})
+}
+
+func (r *PostgresRepository) inTx(ctx context.Context, fn func(tx *PostgresRepository) error) error {
+	if r.tx != nil {
+		return r.inSavepoint(ctx, fn)
+	}
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&PostgresRepository{db: tx, conn: r.conn, tx: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// inSavepoint выполняет fn внутри точки сохранения текущей транзакции
+func (r *PostgresRepository) inSavepoint(ctx context.Context, fn func(tx *PostgresRepository) error) error {
+	name := fmt.Sprintf("sp_%d", r.depth+1)
+
+	if _, err := r.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(&PostgresRepository{db: r.tx, conn: r.conn, tx: r.tx, depth: r.depth + 1}); err != nil {
+		if _, rbErr := r.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	_, err := r.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *PostgresRepositoryTestSuite) TestWithTx_Commit() {
+	subID := uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM subscriptions WHERE id = \$1`).
+		WithArgs(subID, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.WithTx(s.ctx, func(repo Repository) error {
+		return repo.DeleteSubscription(s.ctx, subID, nil)
+	})
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestWithTx_RollbackOnError() {
+	fnErr := errors.New("operation failed")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectRollback()
+
+	err := s.repo.WithTx(s.ctx, func(repo Repository) error {
+		return fnErr
+	})
+
+	assert.ErrorIs(s.T(), err, fnErr)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestWithTx_NestedSavepoints() {
+	subID := uuid.New()
+	horizon := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fnErr := errors.New("operation failed")
+
+	s.mock.ExpectBegin()
+	// Неудачная вложенная часть откатывается до точки сохранения
+	s.mock.ExpectExec(`SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	// Вложенная транзакция RegenerateCharges на втором уровне тоже становится точкой сохранения
+	s.mock.ExpectExec(`SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`SAVEPOINT sp_2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`DELETE FROM charges WHERE subscription_id = \$1`).
+		WithArgs(subID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`INSERT INTO charges`).
+		WithArgs(horizon, subID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(`RELEASE SAVEPOINT sp_2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(`RELEASE SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := s.repo.WithTx(s.ctx, func(tx Repository) error {
+		nestedErr := tx.WithTx(s.ctx, func(repo Repository) error {
+			return fnErr
+		})
+		assert.ErrorIs(s.T(), nestedErr, fnErr)
+
+		return tx.WithTx(s.ctx, func(repo Repository) error {
+			return repo.RegenerateCharges(s.ctx, subID, horizon)
+		})
+	})
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
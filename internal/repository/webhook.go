@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+func (r *PostgresRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		webhook.ID, webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes), webhook.CreatedAt)
+
+	return translateError(err)
+}
+
+func (r *PostgresRepository) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhooks ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*model.Webhook
+	for rows.Next() {
+		var webhook model.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.EventTypes), &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *PostgresRepository) GetWebhook(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	var webhook model.Webhook
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhooks WHERE id = $1
+	`, id).Scan(&webhook.ID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.EventTypes), &webhook.CreatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &webhook, nil
+}
+
+// DeleteWebhook удаляет вебхук вместе с его доставками
+func (r *PostgresRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// EnqueueWebhookDeliveries ставит событие в очередь доставки на все вебхуки,
+// подписанные на его тип. В транзакции очередь пополняется вместе с самим изменением.
+func (r *PostgresRepository) EnqueueWebhookDeliveries(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte, now time.Time) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), id, $1, $2, $3, 'pending', 0, $4, $4
+		FROM webhooks WHERE $2 = ANY(event_types)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, eventID, eventType, payload, now)
+	return translateError(err)
+}
+
+// ClaimWebhookDeliveries берет в работу до limit доставок, срок попытки которых наступил к now.
+// Попытка взятой доставки переносится на leaseUntil: если экземпляр сервиса не запишет
+// её результат (например, остановится), доставку после этого срока возьмет любой экземпляр.
+// Доставки, которые уже берет другая транзакция, пропускаются.
+func (r *PostgresRepository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutgoingWebhook, error) {
+	query := `
+		UPDATE webhook_deliveries d SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns + `, w.url, w.secret
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []*model.OutgoingWebhook
+	for rows.Next() {
+		var outgoing model.OutgoingWebhook
+		var delivery model.WebhookDelivery
+		if err := rows.Scan(append(webhookDeliveryFields(&delivery), &outgoing.URL, &outgoing.Secret)...); err != nil {
+			return nil, err
+		}
+		outgoing.Delivery = &delivery
+		claimed = append(claimed, &outgoing)
+	}
+
+	return claimed, rows.Err()
+}
+
+// RecordWebhookAttempt записывает попытку доставки в журнал и переводит доставку в статус status.
+// nextAttemptAt — время следующей попытки (nil, если попыток больше не будет),
+// deliveredAt — время успешной доставки.
+func (r *PostgresRepository) RecordWebhookAttempt(ctx context.Context, attempt *model.WebhookAttempt, status string, nextAttemptAt, deliveredAt *time.Time) error {
+	query := `
+		WITH attempt AS (
+			INSERT INTO webhook_delivery_attempts (delivery_id, attempted_at, response_status, error, duration_ms)
+			VALUES ($1, $2, $3, $4, $5)
+		)
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, status = $6, next_attempt_at = $7, delivered_at = COALESCE($8, delivered_at)
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		attempt.DeliveryID, attempt.AttemptedAt, attempt.ResponseStatus, attempt.Error, attempt.Duration,
+		status, nextAttemptAt, deliveredAt)
+
+	return translateError(err)
+}
+
+// ListWebhookDeliveries возвращает до limit последних доставок вебхука, от новых к старым
+func (r *PostgresRepository) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d WHERE d.webhook_id = $1
+		ORDER BY d.created_at DESC, d.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		if err := rows.Scan(webhookDeliveryFields(&delivery)...); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// GetWebhookDelivery возвращает доставку вместе с журналом попыток
+func (r *PostgresRepository) GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries d WHERE d.id = $1`
+
+	var delivery model.WebhookDelivery
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(webhookDeliveryFields(&delivery)...); err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT delivery_id, attempted_at, response_status, error, duration_ms
+		FROM webhook_delivery_attempts WHERE delivery_id = $1
+		ORDER BY attempted_at, id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt model.WebhookAttempt
+		if err := rows.Scan(&attempt.DeliveryID, &attempt.AttemptedAt, &attempt.ResponseStatus, &attempt.Error, &attempt.Duration); err != nil {
+			return nil, err
+		}
+		delivery.AttemptLog = append(delivery.AttemptLog, &attempt)
+	}
+
+	return &delivery, rows.Err()
+}
+
+// RedeliverWebhookDelivery возвращает доставку в очередь с новым набором попыток, начиная с now
+func (r *PostgresRepository) RedeliverWebhookDelivery(ctx context.Context, id uuid.UUID, now time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = $2
+		WHERE id = $1
+	`, id, now)
+	if err != nil {
+		return translateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// webhookDeliveryColumns — столбцы доставки в порядке webhookDeliveryFields (таблица под псевдонимом d)
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.created_at, d.delivered_at`
+
+// Тело события сканируется как *[]byte: так драйвер копирует значение, а не отдает свой буфер
+func webhookDeliveryFields(d *model.WebhookDelivery) []any {
+	return []any{&d.ID, &d.WebhookID, &d.EventID, &d.EventType, (*[]byte)(&d.Payload), &d.Status, &d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt}
+}
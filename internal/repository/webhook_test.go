@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"github.com/ZnNr/subscription-service/internal/model"
+	"net/http"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var webhookDeliveryRowColumns = []string{"id", "webhook_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "created_at", "delivered_at"}
+
+func (s *PostgresRepositoryTestSuite) TestEnqueueWebhookDeliveries() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	eventID := uuid.New()
+	payload := []byte(`{"type":"subscription.created"}`)
+
+	s.mock.ExpectExec(`INSERT INTO webhook_deliveries .* SELECT .* FROM webhooks WHERE \$2 = ANY\(event_types\)`).
+		WithArgs(eventID, model.SubscriptionEventCreated, payload, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := s.repo.EnqueueWebhookDeliveries(s.ctx, eventID, model.SubscriptionEventCreated, payload, now)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestClaimWebhookDeliveries() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	leaseUntil := now.Add(time.Minute)
+	deliveryID, webhookID, eventID := uuid.New(), uuid.New(), uuid.New()
+
+	s.mock.ExpectQuery(`UPDATE webhook_deliveries d SET next_attempt_at = \$2 .* FOR UPDATE SKIP LOCKED .* RETURNING .*, w.url, w.secret`).
+		WithArgs(now, leaseUntil, 20).
+		WillReturnRows(sqlmock.NewRows(append(webhookDeliveryRowColumns, "url", "secret")).
+			AddRow(deliveryID, webhookID, eventID, model.SubscriptionEventDeleted, []byte(`{}`), model.WebhookDeliveryPending, 1, leaseUntil, now, nil,
+				"https://example.com/hooks", "0123456789abcdef"))
+
+	claimed, err := s.repo.ClaimWebhookDeliveries(s.ctx, now, leaseUntil, 20)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), claimed, 1)
+	assert.Equal(s.T(), "https://example.com/hooks", claimed[0].URL)
+	assert.Equal(s.T(), "0123456789abcdef", claimed[0].Secret)
+	assert.Equal(s.T(), deliveryID, claimed[0].Delivery.ID)
+	assert.Equal(s.T(), 1, claimed[0].Delivery.Attempts)
+	assert.Equal(s.T(), `{}`, string(claimed[0].Delivery.Payload))
+	assert.Nil(s.T(), claimed[0].Delivery.DeliveredAt)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestRecordWebhookAttempt() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	next := now.Add(30 * time.Second)
+	status := http.StatusBadGateway
+	message := "unexpected response status 502"
+	attempt := &model.WebhookAttempt{DeliveryID: uuid.New(), AttemptedAt: now, ResponseStatus: &status, Error: &message, Duration: 35}
+
+	s.mock.ExpectExec(`WITH attempt AS \( INSERT INTO webhook_delivery_attempts .* \) UPDATE webhook_deliveries SET attempts = attempts \+ 1`).
+		WithArgs(attempt.DeliveryID, now, &status, &message, 35, model.WebhookDeliveryPending, &next, (*time.Time)(nil)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.RecordWebhookAttempt(s.ctx, attempt, model.WebhookDeliveryPending, &next, nil)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestGetWebhookDelivery_WithAttempts() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	deliveryID := uuid.New()
+
+	s.mock.ExpectQuery(`SELECT .* FROM webhook_deliveries d WHERE d.id = \$1`).
+		WithArgs(deliveryID).
+		WillReturnRows(sqlmock.NewRows(webhookDeliveryRowColumns).
+			AddRow(deliveryID, uuid.New(), uuid.New(), model.SubscriptionEventCreated, []byte(`{}`), model.WebhookDeliverySucceeded, 2, nil, now, now.Add(time.Minute)))
+	s.mock.ExpectQuery(`SELECT delivery_id, attempted_at, response_status, error, duration_ms FROM webhook_delivery_attempts WHERE delivery_id = \$1`).
+		WithArgs(deliveryID).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "attempted_at", "response_status", "error", "duration_ms"}).
+			AddRow(deliveryID, now, nil, "connection refused", 3).
+			AddRow(deliveryID, now.Add(time.Minute), 200, nil, 42))
+
+	delivery, err := s.repo.GetWebhookDelivery(s.ctx, deliveryID)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.WebhookDeliverySucceeded, delivery.Status)
+	assert.Nil(s.T(), delivery.NextAttemptAt)
+	assert.Len(s.T(), delivery.AttemptLog, 2)
+	assert.Nil(s.T(), delivery.AttemptLog[0].ResponseStatus)
+	assert.Equal(s.T(), "connection refused", *delivery.AttemptLog[0].Error)
+	assert.Equal(s.T(), 200, *delivery.AttemptLog[1].ResponseStatus)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestRedeliverWebhookDelivery_NotFound() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	id := uuid.New()
+
+	s.mock.ExpectExec(`UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = \$2 WHERE id = \$1`).
+		WithArgs(id, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.RedeliverWebhookDelivery(s.ctx, id, now)
+
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *PostgresRepositoryTestSuite) TestCreateWebhook() {
+	webhook := &model.Webhook{
+		ID:         uuid.New(),
+		URL:        "https://example.com/hooks",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{model.SubscriptionEventCreated},
+		CreatedAt:  time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	s.mock.ExpectExec(`INSERT INTO webhooks \(id, url, secret, event_types, created_at\)`).
+		WithArgs(webhook.ID, webhook.URL, webhook.Secret, sqlmock.AnyArg(), webhook.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.CreateWebhook(s.ctx, webhook)
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
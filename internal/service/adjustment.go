@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// CreateAdjustment записывает возврат, кредит или разовое списание по существующей подписке
+func (s *SubscriptionService) CreateAdjustment(ctx context.Context, adj *model.Adjustment) (*model.Adjustment, error) {
+	if err := validateAdjustment(adj); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetSubscription(ctx, adj.SubscriptionID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.repo.CreateAdjustment(ctx, adj); err != nil {
+		return nil, err
+	}
+
+	return adj, nil
+}
+
+func (s *SubscriptionService) ListAdjustments(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Adjustment, error) {
+	return s.repo.ListAdjustments(ctx, subscriptionID)
+}
+
+func (s *SubscriptionService) DeleteAdjustment(ctx context.Context, id uuid.UUID) error {
+	err := s.repo.DeleteAdjustment(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAdjustmentNotFound
+	}
+
+	return err
+}
+
+func validateAdjustment(adj *model.Adjustment) error {
+	switch adj.Kind {
+	case model.AdjustmentRefund, model.AdjustmentCredit, model.AdjustmentCharge:
+	default:
+		return ErrInvalidAdjustmentKind
+	}
+
+	if adj.Amount <= 0 {
+		return ErrInvalidAdjustmentAmount
+	}
+
+	if adj.Date.IsZero() {
+		return ErrAdjustmentDateRequired
+	}
+
+	if adj.Reason == "" {
+		return ErrAdjustmentReasonRequired
+	}
+
+	return nil
+}
+
+var (
+	ErrInvalidAdjustmentKind    = NewServiceError(KindInvalid, "invalid_adjustment_kind", "adjustment kind must be one of refund, credit, charge")
+	ErrInvalidAdjustmentAmount  = NewServiceError(KindInvalid, "invalid_adjustment_amount", "adjustment amount must be greater than 0")
+	ErrAdjustmentDateRequired   = NewServiceError(KindInvalid, "adjustment_date_required", "adjustment date is required")
+	ErrAdjustmentReasonRequired = NewServiceError(KindInvalid, "adjustment_reason_required", "adjustment reason is required")
+	ErrAdjustmentNotFound       = NewServiceError(KindNotFound, "adjustment_not_found", "adjustment not found")
+)
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAdjustment(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	adj := &model.Adjustment{
+		ID:             uuid.New(),
+		SubscriptionID: uuid.New(),
+		Kind:           model.AdjustmentRefund,
+		Amount:         599,
+		Date:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Reason:         "service outage",
+	}
+
+	mockRepo.On("GetSubscription", ctx, adj.SubscriptionID).Return(&model.Subscription{ID: adj.SubscriptionID}, nil)
+	mockRepo.On("CreateAdjustment", ctx, adj).Return(nil)
+
+	result, err := service.CreateAdjustment(ctx, adj)
+
+	assert.NoError(t, err)
+	assert.Equal(t, -599, result.SignedAmount())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAdjustment_Validation(t *testing.T) {
+	service := NewSubscriptionService(nil)
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		adj      *model.Adjustment
+		expected error
+	}{
+		{"Unknown kind", &model.Adjustment{Kind: "bonus", Amount: 100, Date: date, Reason: "x"}, ErrInvalidAdjustmentKind},
+		{"Zero amount", &model.Adjustment{Kind: model.AdjustmentCredit, Amount: 0, Date: date, Reason: "x"}, ErrInvalidAdjustmentAmount},
+		{"Missing date", &model.Adjustment{Kind: model.AdjustmentCharge, Amount: 100, Reason: "x"}, ErrAdjustmentDateRequired},
+		{"Missing reason", &model.Adjustment{Kind: model.AdjustmentCharge, Amount: 100, Date: date}, ErrAdjustmentReasonRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateAdjustment(context.Background(), tt.adj)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestCreateAdjustment_SubscriptionNotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	adj := &model.Adjustment{
+		SubscriptionID: uuid.New(),
+		Kind:           model.AdjustmentCharge,
+		Amount:         100,
+		Date:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Reason:         "setup fee",
+	}
+
+	mockRepo.On("GetSubscription", ctx, adj.SubscriptionID).Return(nil, repository.ErrNotFound)
+
+	_, err := service.CreateAdjustment(ctx, adj)
+
+	assert.Equal(t, ErrNotFound, err)
+	mockRepo.AssertNotCalled(t, "CreateAdjustment", mock.Anything, mock.Anything)
+}
+
+func TestCalculateSummary_IncludesAdjustments(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("CalculateSummary", ctx, startDate, endDate, (*uuid.UUID)(nil), (*string)(nil)).
+		Return(&model.SummaryResponse{TotalAmount: 1000, Count: 2}, nil)
+	mockRepo.On("SumAdjustments", ctx, startDate, endDate, (*uuid.UUID)(nil), (*string)(nil)).Return(-300, nil)
+
+	result, err := service.CalculateSummary(ctx, startDate, endDate, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 700, result.TotalAmount)
+	assert.Equal(t, -300, result.AdjustmentsAmount)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+)
+
+// BatchError — операция пакета в режиме atomic, из-за которой транзакция была отменена
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %s", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Batch выполняет операции над подписками в одной транзакции.
+// В режиме atomic первая неудачная операция отменяет весь пакет, и возвращается *BatchError.
+// В режиме best_effort каждая операция выполняется в своей точке сохранения:
+// неудачные откатываются, остальные сохраняются.
+func (s *SubscriptionService) Batch(ctx context.Context, ops []model.BatchOperation, mode string, opts model.WriteOptions) (*model.BatchResponse, error) {
+	results := make([]*model.BatchItemResult, len(ops))
+	for i, op := range ops {
+		results[i] = &model.BatchItemResult{Index: i, Op: op.Op, ID: op.ID, Status: model.BatchStatusSkipped}
+	}
+
+	err := s.withTx(ctx, func(tx *SubscriptionService) error {
+		for i, op := range ops {
+			var opErr error
+			if mode == model.BatchModeBestEffort {
+				opErr = tx.withTx(ctx, func(sp *SubscriptionService) error {
+					return sp.applyBatchOperation(ctx, op, opts, results[i])
+				})
+			} else {
+				opErr = tx.applyBatchOperation(ctx, op, opts, results[i])
+			}
+
+			if opErr != nil {
+				described := Describe(opErr)
+				results[i].Status = model.BatchStatusFailed
+				results[i].Error = described.Message
+				results[i].Code = described.Code
+				results[i].Subscription = nil
+				results[i].Warnings = nil
+
+				if mode != model.BatchModeBestEffort {
+					return &BatchError{Index: i, Err: opErr}
+				}
+				continue
+			}
+
+			results[i].Status = model.BatchStatusOK
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		for _, r := range results {
+			if r.Status == model.BatchStatusOK {
+				r.Status = model.BatchStatusRolledBack
+			}
+		}
+		return &model.BatchResponse{Committed: false, Results: results}, err
+	}
+
+	return &model.BatchResponse{Committed: true, Results: results}, nil
+}
+
+func (s *SubscriptionService) applyBatchOperation(ctx context.Context, op model.BatchOperation, opts model.WriteOptions, result *model.BatchItemResult) error {
+	opts.IfMatch = op.Version
+
+	switch op.Op {
+	case model.BatchOpCreate:
+		resp, err := s.CreateSubscription(ctx, op.Subscription, opts)
+		if err != nil {
+			return err
+		}
+		result.ID = resp.ID
+		result.Subscription = resp.Subscription
+		result.Warnings = resp.Warnings
+
+	case model.BatchOpUpdate:
+		if err := s.UpdateSubscription(ctx, op.Subscription, opts); err != nil {
+			return err
+		}
+
+		sub, err := s.repo.GetSubscription(ctx, op.ID)
+		if err != nil {
+			return err
+		}
+		result.Subscription = sub
+
+	case model.BatchOpDelete:
+		return s.DeleteSubscription(ctx, op.ID, opts)
+
+	default:
+		return ErrInvalidBatchOperation
+	}
+
+	return nil
+}
+
+// withTx выполняет fn сервисом, репозиторий которого работает в транзакции
+// (или в точке сохранения, если s уже работает в транзакции).
+// События об изменениях публикуются только после фиксации самой внешней транзакции.
+func (s *SubscriptionService) withTx(ctx context.Context, fn func(tx *SubscriptionService) error) error {
+	var pending []model.SubscriptionEvent
+	err := s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		tx := *s
+		tx.repo = repo
+		tx.pending = &pending
+		return fn(&tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Внешней транзакции события передаются, самостоятельная — публикует их после фиксации
+	if s.pending != nil {
+		*s.pending = append(*s.pending, pending...)
+		return nil
+	}
+	for _, event := range pending {
+		s.events.Publish(event)
+	}
+
+	return nil
+}
+
+// atomically выполняет fn в транзакции, а если s уже работает в транзакции — в ней же,
+// без отдельной точки сохранения
+func (s *SubscriptionService) atomically(ctx context.Context, fn func(tx *SubscriptionService) error) error {
+	if s.pending != nil {
+		return fn(s)
+	}
+
+	return s.withTx(ctx, fn)
+}
+
+var ErrInvalidBatchOperation = NewServiceError(KindInvalid, "invalid_batch_operation", "operation must be one of create, update, delete")
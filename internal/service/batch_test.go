@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/events"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func batchTestSubscription() *model.Subscription {
+	return &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBatch_Atomic(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	created := batchTestSubscription()
+	deletedID := uuid.New()
+
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, created).Return(nil, nil)
+	mockRepo.On("ListBudgets", ctx, created.UserID).Return(nil, nil)
+	mockRepo.On("CreateSubscription", ctx, created).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, created.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("GetSubscription", ctx, created.ID).Return(created, nil)
+	mockRepo.On("GetSubscription", ctx, deletedID).Return(&model.Subscription{ID: deletedID}, nil)
+	mockRepo.On("DeleteSubscription", ctx, deletedID, (*int)(nil)).Return(nil)
+
+	resp, err := service.Batch(ctx, []model.BatchOperation{
+		{Op: model.BatchOpCreate, ID: created.ID, Subscription: created},
+		{Op: model.BatchOpDelete, ID: deletedID},
+	}, model.BatchModeAtomic, model.WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, model.BatchStatusOK, resp.Results[0].Status)
+	assert.Equal(t, created, resp.Results[0].Subscription)
+	assert.Equal(t, model.BatchStatusOK, resp.Results[1].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBatch_AtomicFailureRollsBack(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	deletedID, missingID, skippedID := uuid.New(), uuid.New(), uuid.New()
+
+	mockRepo.On("GetSubscription", ctx, deletedID).Return(&model.Subscription{ID: deletedID}, nil)
+	mockRepo.On("DeleteSubscription", ctx, deletedID, (*int)(nil)).Return(nil)
+	mockRepo.On("GetSubscription", ctx, missingID).Return(nil, repository.ErrNotFound)
+
+	resp, err := service.Batch(ctx, []model.BatchOperation{
+		{Op: model.BatchOpDelete, ID: deletedID},
+		{Op: model.BatchOpDelete, ID: missingID},
+		{Op: model.BatchOpDelete, ID: skippedID},
+	}, model.BatchModeAtomic, model.WriteOptions{})
+
+	var batchErr *BatchError
+	assert.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.False(t, resp.Committed)
+	assert.Equal(t, model.BatchStatusRolledBack, resp.Results[0].Status)
+	assert.Equal(t, model.BatchStatusFailed, resp.Results[1].Status)
+	assert.Equal(t, ErrNotFound.Error(), resp.Results[1].Error)
+	assert.Equal(t, model.BatchStatusSkipped, resp.Results[2].Status)
+	mockRepo.AssertNotCalled(t, "GetSubscription", ctx, skippedID)
+}
+
+func TestBatch_BestEffort(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	invalid := batchTestSubscription()
+	invalid.Price = 0
+	deletedID := uuid.New()
+
+	mockRepo.On("GetSubscription", ctx, deletedID).Return(&model.Subscription{ID: deletedID}, nil)
+	mockRepo.On("DeleteSubscription", ctx, deletedID, (*int)(nil)).Return(nil)
+
+	resp, err := service.Batch(ctx, []model.BatchOperation{
+		{Op: model.BatchOpCreate, ID: invalid.ID, Subscription: invalid},
+		{Op: model.BatchOpDelete, ID: deletedID},
+	}, model.BatchModeBestEffort, model.WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, model.BatchStatusFailed, resp.Results[0].Status)
+	assert.Equal(t, ErrInvalidPrice.Error(), resp.Results[0].Error)
+	assert.Equal(t, model.BatchStatusOK, resp.Results[1].Status)
+	mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBatch_PublishesEventsAfterCommit(t *testing.T) {
+	mockRepo := new(MockRepository)
+	broker := events.NewBroker(0)
+	service := NewSubscriptionService(mockRepo, WithEvents(broker))
+	ctx := context.Background()
+
+	created := batchTestSubscription()
+	deleted := batchTestSubscription()
+
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, created).Return(nil, nil)
+	mockRepo.On("ListBudgets", ctx, created.UserID).Return(nil, nil)
+	mockRepo.On("CreateSubscription", ctx, created).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, created.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("GetSubscription", ctx, created.ID).Return(created, nil)
+	mockRepo.On("GetSubscription", ctx, deleted.ID).Return(deleted, nil)
+	mockRepo.On("DeleteSubscription", ctx, deleted.ID, (*int)(nil)).Return(nil)
+
+	listener := broker.Subscribe(nil)
+
+	_, err := service.Batch(ctx, []model.BatchOperation{
+		{Op: model.BatchOpCreate, ID: created.ID, Subscription: created},
+		{Op: model.BatchOpDelete, ID: deleted.ID},
+	}, model.BatchModeAtomic, model.WriteOptions{})
+	assert.NoError(t, err)
+
+	require.Len(t, listener.Events, 2)
+	first, second := <-listener.Events, <-listener.Events
+	assert.Equal(t, model.SubscriptionEventCreated, first.Type)
+	assert.Equal(t, created, first.Subscription)
+	assert.Equal(t, model.SubscriptionEventDeleted, second.Type)
+	assert.Equal(t, deleted, second.Subscription)
+	assert.Equal(t, first.ID+1, second.ID)
+}
+
+func TestBatch_RollbackPublishesNothing(t *testing.T) {
+	mockRepo := new(MockRepository)
+	broker := events.NewBroker(0)
+	service := NewSubscriptionService(mockRepo, WithEvents(broker))
+	ctx := context.Background()
+
+	deletedID, missingID := uuid.New(), uuid.New()
+
+	mockRepo.On("GetSubscription", ctx, deletedID).Return(&model.Subscription{ID: deletedID}, nil)
+	mockRepo.On("DeleteSubscription", ctx, deletedID, (*int)(nil)).Return(nil)
+	mockRepo.On("GetSubscription", ctx, missingID).Return(nil, repository.ErrNotFound)
+
+	listener := broker.Subscribe(nil)
+
+	_, err := service.Batch(ctx, []model.BatchOperation{
+		{Op: model.BatchOpDelete, ID: deletedID},
+		{Op: model.BatchOpDelete, ID: missingID},
+	}, model.BatchModeAtomic, model.WriteOptions{})
+	assert.Error(t, err)
+
+	// Удаление первой подписки отменено вместе с транзакцией, подписчики о нем не узнают
+	assert.Empty(t, listener.Events)
+}
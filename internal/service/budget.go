@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// budgetHorizonMonths — сколько месяцев от начала новой подписки проверяется на превышение бюджета
+const budgetHorizonMonths = 12
+
+func (s *SubscriptionService) SetBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error) {
+	if budget.UserID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if budget.MonthlyLimit <= 0 {
+		return nil, ErrInvalidBudgetLimit
+	}
+
+	return s.repo.UpsertBudget(ctx, budget)
+}
+
+func (s *SubscriptionService) ListBudgets(ctx context.Context, userID uuid.UUID) ([]*model.Budget, error) {
+	return s.repo.ListBudgets(ctx, userID)
+}
+
+func (s *SubscriptionService) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	err := s.repo.DeleteBudget(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrBudgetNotFound
+	}
+
+	return err
+}
+
+// BudgetReport сравнивает расходы пользователя за каждый месяц периода с каждым из его бюджетов
+func (s *SubscriptionService) BudgetReport(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]*model.BudgetMonth, error) {
+	if startDate.After(endDate) {
+		return nil, ErrInvalidPeriod
+	}
+
+	budgets, err := s.repo.ListBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := []*model.BudgetMonth{}
+	for _, budget := range budgets {
+		costs, err := s.repo.MonthlyCosts(ctx, userID, budget.Category, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cost := range costs {
+			report = append(report, &model.BudgetMonth{
+				Month:        cost.Month,
+				BudgetID:     budget.ID,
+				Category:     budget.Category,
+				MonthlyLimit: budget.MonthlyLimit,
+				Actual:       cost.Amount,
+				OverBudget:   cost.Amount > budget.MonthlyLimit,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// checkBudgets возвращает предупреждения для бюджетов, которые будут превышены после добавления sub.
+// Проверяются месяцы от начала подписки до её окончания, но не дальше budgetHorizonMonths.
+func (s *SubscriptionService) checkBudgets(ctx context.Context, sub *model.Subscription) ([]string, error) {
+	budgets, err := s.repo.ListBudgets(ctx, sub.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	periodEnd := sub.StartDate.AddDate(0, budgetHorizonMonths-1, 0)
+	if sub.EndDate != nil && sub.EndDate.Before(periodEnd) {
+		periodEnd = *sub.EndDate
+	}
+
+	var warnings []string
+	for _, budget := range budgets {
+		if budget.Category != nil && (sub.Category == nil || *sub.Category != *budget.Category) {
+			continue
+		}
+
+		costs, err := s.repo.MonthlyCosts(ctx, sub.UserID, budget.Category, sub.StartDate, periodEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cost := range costs {
+			projected := cost.Amount + sub.Price
+			if projected > budget.MonthlyLimit {
+				warnings = append(warnings, fmt.Sprintf("%s: projected spend %d exceeds monthly budget %d in %s",
+					budgetName(budget), projected, budget.MonthlyLimit, cost.Month.Format(monthYearLayout)))
+				break
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+func budgetName(budget *model.Budget) string {
+	if budget.Category == nil {
+		return "total budget"
+	}
+
+	return fmt.Sprintf("budget for category %q", *budget.Category)
+}
+
+var (
+	ErrInvalidBudgetLimit = NewServiceError(KindInvalid, "invalid_budget_limit", "monthly limit must be greater than 0")
+	ErrBudgetNotFound     = NewServiceError(KindNotFound, "budget_not_found", "budget not found")
+)
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateSubscription_OverBudgetWarning(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	category := "streaming"
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	sub := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       600,
+		UserID:      uuid.New(),
+		StartDate:   startDate,
+		EndDate:     &endDate,
+		Category:    &category,
+	}
+
+	otherCategory := "music"
+	budgets := []*model.Budget{
+		{ID: uuid.New(), UserID: sub.UserID, MonthlyLimit: 5000},
+		{ID: uuid.New(), UserID: sub.UserID, Category: &category, MonthlyLimit: 1000},
+		{ID: uuid.New(), UserID: sub.UserID, Category: &otherCategory, MonthlyLimit: 100},
+	}
+
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, sub).Return(nil, nil)
+	mockRepo.On("ListBudgets", ctx, sub.UserID).Return(budgets, nil)
+	mockRepo.On("MonthlyCosts", ctx, sub.UserID, (*string)(nil), startDate, endDate).Return([]*model.MonthlyCost{
+		{Month: startDate, Amount: 1000},
+	}, nil)
+	mockRepo.On("MonthlyCosts", ctx, sub.UserID, &category, startDate, endDate).Return([]*model.MonthlyCost{
+		{Month: startDate, Amount: 200},
+		{Month: startDate.AddDate(0, 1, 0), Amount: 500},
+	}, nil)
+	mockRepo.On("CreateSubscription", ctx, sub).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, sub.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("GetSubscription", ctx, sub.ID).Return(sub, nil)
+
+	result, err := service.CreateSubscription(ctx, sub, model.WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], `"streaming"`)
+	assert.Contains(t, result.Warnings[0], "02-2025")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetReport(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	budget := &model.Budget{ID: uuid.New(), UserID: userID, MonthlyLimit: 1000}
+
+	mockRepo.On("ListBudgets", ctx, userID).Return([]*model.Budget{budget}, nil)
+	mockRepo.On("MonthlyCosts", ctx, userID, (*string)(nil), startDate, endDate).Return([]*model.MonthlyCost{
+		{Month: startDate, Amount: 900},
+		{Month: endDate, Amount: 1100},
+	}, nil)
+
+	report, err := service.BudgetReport(ctx, userID, startDate, endDate)
+
+	assert.NoError(t, err)
+	assert.Len(t, report, 2)
+	assert.False(t, report[0].OverBudget)
+	assert.True(t, report[1].OverBudget)
+	assert.Equal(t, 1100, report[1].Actual)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetReport_InvalidPeriod(t *testing.T) {
+	service := NewSubscriptionService(nil)
+
+	_, err := service.BudgetReport(context.Background(), uuid.New(),
+		time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, ErrInvalidPeriod, err)
+}
+
+func TestDeleteBudget_NotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	budgetID := uuid.New()
+	mockRepo.On("DeleteBudget", ctx, budgetID).Return(repository.ErrNotFound)
+
+	err := service.DeleteBudget(ctx, budgetID)
+
+	assert.Equal(t, ErrBudgetNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// ledgerHorizonMonths — на сколько месяцев вперед от текущего генерируются списания бессрочных подписок;
+	// тот же горизонт задан в миграции, заполняющей журнал для старых подписок
+	ledgerHorizonMonths = 12
+	// ledgerExtendInterval — как часто горизонт журнала продлевается вслед за текущим месяцем
+	ledgerExtendInterval = 24 * time.Hour
+)
+
+// ListCharges возвращает страницу журнала списаний. Размер страницы — filter.Limit
+// (по умолчанию DefaultPageSize, не больше MaxPageSize).
+func (s *SubscriptionService) ListCharges(ctx context.Context, filter model.ChargeFilter) (*model.ChargePage, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, ErrInvalidPeriod
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+
+	page, err := s.repo.ListCharges(ctx, filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, ErrInvalidCursor
+	}
+
+	return page, err
+}
+
+// ExportCharges передает в fn все строки журнала, подходящие под фильтр, читая их из базы потоком;
+// filter.Limit и filter.Cursor не учитываются
+func (s *SubscriptionService) ExportCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return ErrInvalidPeriod
+	}
+
+	return s.repo.StreamCharges(ctx, filter, fn)
+}
+
+// RebuildCharges пересоздает журнал списаний по всем подпискам.
+// Нужен, если журнал разошелся с подписками; первичное заполнение выполняет миграция.
+func (s *SubscriptionService) RebuildCharges(ctx context.Context) error {
+	return s.repo.RebuildCharges(ctx, ledgerHorizon())
+}
+
+// ExtendCharges дописывает списания бессрочных подписок до текущего горизонта журнала
+func (s *SubscriptionService) ExtendCharges(ctx context.Context) error {
+	return s.repo.ExtendCharges(ctx, ledgerHorizon())
+}
+
+// RunLedgerExtension продлевает горизонт журнала при запуске и затем раз в сутки до отмены ctx,
+// чтобы бессрочные подписки, которые давно не менялись, не выпадали из расчетов по журналу
+func (s *SubscriptionService) RunLedgerExtension(ctx context.Context) {
+	ticker := time.NewTicker(ledgerExtendInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ExtendCharges(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to extend charges ledger", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SubscriptionService) CalculateLedgerSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
+	if startDate.After(endDate) {
+		return nil, ErrInvalidPeriod
+	}
+
+	return s.repo.CalculateLedgerSummary(ctx, startDate, endDate, userID, serviceName)
+}
+
+// ledgerHorizon возвращает последний месяц, до которого генерируются списания бессрочных подписок
+func ledgerHorizon() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, ledgerHorizonMonths, 0)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListCharges_InvalidPeriod(t *testing.T) {
+	service := NewSubscriptionService(nil)
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.ListCharges(context.Background(), model.ChargeFilter{From: &from, To: &to})
+
+	assert.Equal(t, ErrInvalidPeriod, err)
+}
+
+func TestListCharges_PageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{"Default", 0, DefaultPageSize},
+		{"Custom", 10, 10},
+		{"Capped", MaxPageSize + 1, MaxPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+
+			mockRepo.On("ListCharges", mock.Anything, model.ChargeFilter{Limit: tt.expected}).
+				Return(&model.ChargePage{Items: []*model.Charge{}}, nil)
+
+			_, err := service.ListCharges(context.Background(), model.ChargeFilter{Limit: tt.limit})
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListCharges_InvalidCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	mockRepo.On("ListCharges", mock.Anything, mock.Anything).Return(nil, repository.ErrInvalidCursor)
+
+	_, err := service.ListCharges(context.Background(), model.ChargeFilter{Cursor: "broken"})
+
+	assert.Equal(t, ErrInvalidCursor, err)
+}
+
+func TestExportCharges_InvalidPeriod(t *testing.T) {
+	service := NewSubscriptionService(nil)
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := service.ExportCharges(context.Background(), model.ChargeFilter{From: &from, To: &to}, func(*model.Charge) error {
+		return nil
+	})
+
+	assert.Equal(t, ErrInvalidPeriod, err)
+}
+
+func TestRebuildCharges_UsesFutureHorizon(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("RebuildCharges", ctx, mock.MatchedBy(func(horizon time.Time) bool {
+		return horizon.Day() == 1 && horizon.After(time.Now().AddDate(0, ledgerHorizonMonths-1, 0))
+	})).Return(nil)
+
+	err := service.RebuildCharges(ctx)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunLedgerExtension_ExtendsOnStart(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockRepo.On("ExtendCharges", ctx, mock.MatchedBy(func(horizon time.Time) bool {
+		return horizon.Equal(ledgerHorizon())
+	})).Run(func(mock.Arguments) { cancel() }).Return(nil)
+
+	done := make(chan struct{})
+	go func() {
+		service.RunLedgerExtension(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunLedgerExtension did not stop after cancellation")
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCalculateLedgerSummary(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	expected := &model.SummaryResponse{TotalAmount: 7188, Count: 1}
+
+	mockRepo.On("CalculateLedgerSummary", ctx, startDate, endDate, &userID, (*string)(nil)).Return(expected, nil)
+
+	result, err := service.CalculateLedgerSummary(ctx, startDate, endDate, &userID, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+)
+
+// ErrorKind — категория ошибки, по которой обработчики выбирают HTTP-статус ответа
+type ErrorKind string
+
+const (
+	// KindInvalid — некорректные входные данные (400)
+	KindInvalid ErrorKind = "invalid"
+	// KindNotFound — запись не найдена (404)
+	KindNotFound ErrorKind = "not_found"
+	// KindConflict — запись конфликтует с существующими (409)
+	KindConflict ErrorKind = "conflict"
+	// KindPreconditionFailed — не выполнено условие запроса, например If-Match (412)
+	KindPreconditionFailed ErrorKind = "precondition_failed"
+	// KindUnprocessable — запрос корректен, но нарушает ограничения данных (422)
+	KindUnprocessable ErrorKind = "unprocessable"
+	// KindInternal — внутренняя ошибка (500)
+	KindInternal ErrorKind = "internal"
+)
+
+// ServiceError — ошибка сервиса с категорией и стабильным машиночитаемым кодом
+type ServiceError struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e ServiceError) Error() string {
+	return e.Message
+}
+
+func NewServiceError(kind ErrorKind, code, message string) ServiceError {
+	return ServiceError{Kind: kind, Code: code, Message: message}
+}
+
+// OverlapError содержит подписки, с которыми пересекается создаваемая или обновляемая подписка
+type OverlapError struct {
+	Overlaps []*model.Subscription
+}
+
+func (e *OverlapError) Error() string {
+	return ErrSubscriptionOverlap.Message
+}
+
+func (e *OverlapError) Is(target error) bool {
+	return target == ErrSubscriptionOverlap
+}
+
+// ErrInternal описывает ошибки, которые не относятся ни к одной известной категории.
+// Текст исходной ошибки клиенту не передается.
+var ErrInternal = NewServiceError(KindInternal, "internal_error", "internal server error")
+
+// repositoryErrors — категории, коды и тексты ошибок репозитория. Текст фиксированный:
+// ошибка репозитория может содержать имена ограничений и другие подробности схемы БД.
+var repositoryErrors = []struct {
+	err     error
+	kind    ErrorKind
+	code    string
+	message string
+}{
+	{repository.ErrNotFound, KindNotFound, "not_found", "record not found"},
+	{repository.ErrAlreadyExists, KindConflict, "already_exists", "record already exists"},
+	{repository.ErrVersionConflict, KindPreconditionFailed, "precondition_failed", "version does not match"},
+	{repository.ErrConstraintViolation, KindUnprocessable, "constraint_violation", "data violates a database constraint"},
+}
+
+// Describe возвращает ServiceError, описывающую err: саму ошибку сервиса, перевод ошибки
+// репозитория или ErrInternal для неизвестных ошибок. Текст ошибок репозитория и неизвестных
+// ошибок в описание не попадает.
+func Describe(err error) ServiceError {
+	var serviceErr ServiceError
+	var overlapErr *OverlapError
+
+	switch {
+	case errors.As(err, &overlapErr):
+		return ErrSubscriptionOverlap
+	case errors.As(err, &serviceErr):
+		return serviceErr
+	}
+
+	for _, e := range repositoryErrors {
+		if errors.Is(err, e.err) {
+			return NewServiceError(e.kind, e.code, e.message)
+		}
+	}
+
+	return ErrInternal
+}
+
+// Concealed сообщает, что описание err не содержит её исходного текста (например, имени
+// нарушенного ограничения), поэтому err нужно записать в лог
+func Concealed(err error) bool {
+	return Describe(err).Message != err.Error()
+}
+
+// KindOf возвращает категорию ошибки сервиса или репозитория; неизвестные ошибки — KindInternal
+func KindOf(err error) ErrorKind {
+	return Describe(err).Kind
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{name: "validation", err: ErrInvalidEndDate, want: KindInvalid},
+		{name: "not found", err: ErrNotFound, want: KindNotFound},
+		{name: "overlap", err: &OverlapError{}, want: KindConflict},
+		{name: "precondition", err: ErrPreconditionFailed, want: KindPreconditionFailed},
+		{name: "idempotency key reused", err: ErrIdempotencyKeyReused, want: KindUnprocessable},
+		{name: "wrapped service error", err: fmt.Errorf("operation 2: %w", ErrBudgetNotFound), want: KindNotFound},
+		{name: "repository not found", err: repository.ErrNotFound, want: KindNotFound},
+		{name: "repository unique violation", err: fmt.Errorf("%w: budgets_pkey", repository.ErrAlreadyExists), want: KindConflict},
+		{name: "repository constraint violation", err: fmt.Errorf("%w: adjustments_amount_check", repository.ErrConstraintViolation), want: KindUnprocessable},
+		{name: "unknown", err: errors.New("connection refused"), want: KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KindOf(tt.err))
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, ErrInvalidEndDate, Describe(fmt.Errorf("operation 1: %w", ErrInvalidEndDate)))
+	assert.Equal(t, ErrSubscriptionOverlap, Describe(&OverlapError{}))
+	assert.Equal(t, NewServiceError(KindConflict, "already_exists", "record already exists"), Describe(fmt.Errorf("%w: budgets_pkey", repository.ErrAlreadyExists)))
+
+	// Текст неизвестной ошибки не попадает в описание
+	assert.Equal(t, ErrInternal, Describe(errors.New("pq: connection refused")))
+}
+
+func TestConcealed(t *testing.T) {
+	assert.False(t, Concealed(ErrInvalidEndDate))
+	assert.True(t, Concealed(fmt.Errorf("%w: budgets_pkey", repository.ErrAlreadyExists)))
+	assert.True(t, Concealed(errors.New("pq: connection refused")))
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// DefaultIdempotencyTTL — срок хранения ответа по ключу идемпотентности по умолчанию
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyLockTimeout — сколько ключ остается занятым выполняющимся запросом. Если процесс
+// упал, не сохранив ответ, повтор запроса занимает ключ после этого срока, а не через TTL.
+// Срок с запасом превышает время выполнения любого запроса с ключом идемпотентности.
+const IdempotencyLockTimeout = time.Minute
+
+// maxIdempotencyKeyLength — максимальная длина ключа (размер колонки idempotency_keys.key)
+const maxIdempotencyKeyLength = 255
+
+// Option настраивает SubscriptionService
+type Option func(*SubscriptionService)
+
+// WithIdempotencyTTL задает срок хранения ответов по ключам идемпотентности
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(s *SubscriptionService) {
+		if ttl > 0 {
+			s.idempotencyTTL = ttl
+		}
+	}
+}
+
+// BeginIdempotentRequest занимает ключ идемпотентности для запроса с отпечатком fingerprint.
+// Если запрос нужно выполнить, возвращается токен аренды ключа: с ним запрос сохраняет ответ
+// в FinishIdempotentRequest. Если запрос с этим ключом уже выполнялся, возвращается сохраненный
+// ответ. Ключ, использованный с другим запросом, — ErrIdempotencyKeyReused; запрос с этим
+// ключом еще выполняется — ErrIdempotencyRequestInProgress.
+func (s *SubscriptionService) BeginIdempotentRequest(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, uuid.UUID, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, uuid.Nil, ErrInvalidIdempotencyKey
+	}
+
+	lease := uuid.New()
+	now := time.Now().UTC()
+	record, claimed, err := s.repo.ClaimIdempotencyKey(ctx, key, fingerprint, lease, now, now.Add(IdempotencyLockTimeout), now.Add(s.idempotencyTTL))
+	if errors.Is(err, repository.ErrNotFound) {
+		// Ключ освободили между попыткой занять его и чтением записи
+		return nil, uuid.Nil, ErrIdempotencyRequestInProgress
+	}
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	if claimed {
+		return nil, lease, nil
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, uuid.Nil, ErrIdempotencyKeyReused
+	}
+
+	if !record.Completed() {
+		return nil, uuid.Nil, ErrIdempotencyRequestInProgress
+	}
+
+	return record, uuid.Nil, nil
+}
+
+// FinishIdempotentRequest сохраняет ответ на запрос, занявший ключ с токеном lease. Ответы
+// с ошибкой сервера не сохраняются: ключ освобождается, и запрос можно повторить. Если срок
+// блокировки истек и ключ уже занял повтор запроса, ответ не сохраняется —
+// ErrIdempotencyRequestInProgress.
+func (s *SubscriptionService) FinishIdempotentRequest(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error {
+	if statusCode >= 500 {
+		return s.repo.ReleaseIdempotencyKey(ctx, key, lease)
+	}
+
+	err := s.repo.CompleteIdempotencyKey(ctx, key, lease, statusCode, headers, body)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrIdempotencyRequestInProgress
+	}
+
+	return err
+}
+
+var (
+	ErrInvalidIdempotencyKey        = NewServiceError(KindInvalid, "invalid_idempotency_key", "idempotency key must be 1 to 255 characters long")
+	ErrIdempotencyKeyReused         = NewServiceError(KindUnprocessable, "idempotency_key_reused", "idempotency key was already used with a different request")
+	ErrIdempotencyRequestInProgress = NewServiceError(KindConflict, "idempotency_request_in_progress", "a request with this idempotency key is still in progress")
+)
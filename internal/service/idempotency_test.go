@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginIdempotentRequest(t *testing.T) {
+	ctx := context.Background()
+	completed := &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fp", StatusCode: 201, Body: []byte(`{}`)}
+
+	tests := []struct {
+		name    string
+		record  *model.IdempotencyRecord
+		claimed bool
+		want    *model.IdempotencyRecord
+		wantErr error
+	}{
+		{name: "new key", claimed: true},
+		{name: "replay", record: completed, want: completed},
+		{name: "different request", record: &model.IdempotencyRecord{Key: "key-1", Fingerprint: "other", StatusCode: 201}, wantErr: ErrIdempotencyKeyReused},
+		{name: "in progress", record: &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fp"}, wantErr: ErrIdempotencyRequestInProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo, WithIdempotencyTTL(time.Hour))
+
+			var claimedLease uuid.UUID
+			mockRepo.On("ClaimIdempotencyKey", ctx, "key-1", "fp", mock.MatchedBy(func(lease uuid.UUID) bool {
+				claimedLease = lease
+				return lease != uuid.Nil
+			}), mock.AnythingOfType("time.Time"), mock.MatchedBy(func(lockedUntil time.Time) bool {
+				return time.Until(lockedUntil) > 59*time.Second && time.Until(lockedUntil) <= IdempotencyLockTimeout
+			}), mock.MatchedBy(func(expiresAt time.Time) bool {
+				return time.Until(expiresAt) > 59*time.Minute && time.Until(expiresAt) <= time.Hour
+			})).Return(tt.record, tt.claimed, nil)
+
+			record, lease, err := service.BeginIdempotentRequest(ctx, "key-1", "fp")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, record)
+			// Токен аренды возвращается, только если ключ занят этим запросом
+			if tt.claimed {
+				assert.Equal(t, claimedLease, lease)
+			} else {
+				assert.Equal(t, uuid.Nil, lease)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBeginIdempotentRequest_InvalidKey(t *testing.T) {
+	service := NewSubscriptionService(new(MockRepository))
+
+	_, _, err := service.BeginIdempotentRequest(context.Background(), "", "fp")
+
+	assert.ErrorIs(t, err, ErrInvalidIdempotencyKey)
+}
+
+func TestFinishIdempotentRequest(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	lease := uuid.New()
+	headers := map[string]string{"Content-Type": "application/json"}
+	mockRepo.On("CompleteIdempotencyKey", ctx, "key-1", lease, 201, headers, []byte(`{}`)).Return(nil)
+	mockRepo.On("ReleaseIdempotencyKey", ctx, "key-2", lease).Return(nil)
+
+	assert.NoError(t, service.FinishIdempotentRequest(ctx, "key-1", lease, 201, headers, []byte(`{}`)))
+	// Ответ с ошибкой сервера не сохраняется, ключ освобождается для повтора
+	assert.NoError(t, service.FinishIdempotentRequest(ctx, "key-2", lease, 500, headers, []byte(`{}`)))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFinishIdempotentRequest_LeaseLost(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	// Ключ уже занят повтором запроса с другим токеном: запись с этим токеном не найдена
+	lease := uuid.New()
+	mockRepo.On("CompleteIdempotencyKey", ctx, "key-1", lease, 201, map[string]string(nil), []byte(`{}`)).Return(repository.ErrNotFound)
+
+	err := service.FinishIdempotentRequest(ctx, "key-1", lease, 201, nil, []byte(`{}`))
+
+	assert.Equal(t, ErrIdempotencyRequestInProgress, err)
+}
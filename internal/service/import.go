@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+)
+
+// importChunkSize — число строк импорта, сохраняемых в одной транзакции
+const importChunkSize = 500
+
+// ImportError — строка импорта, из-за которой отменена транзакция её части импорта.
+// Части до нее уже сохранены; импорт можно продолжить с report.ResumeFrom.
+type ImportError struct {
+	Row int
+	Err error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// errDryRun отменяет транзакцию пробного импорта
+var errDryRun = errors.New("dry run")
+
+// ImportSubscriptions создает подписки из строк файла импорта.
+// Сначала все строки проверяются по тем же правилам, что и при создании подписки; если хотя бы одна
+// строка не прошла проверку, ничего не сохраняется и возвращается ErrImportInvalid.
+// Затем строки сохраняются частями по importChunkSize в отдельных транзакциях: ошибка строки
+// отменяет только её часть, а в отчете указывается ResumeFrom — строка, с которой повторить импорт.
+// В режиме DryRun строки сохраняются в транзакции, которая затем отменяется, поэтому отчет
+// учитывает и пересечения подписок, в том числе между строками самого файла.
+func (s *SubscriptionService) ImportSubscriptions(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions) (*model.ImportReport, error) {
+	report := &model.ImportReport{DryRun: opts.DryRun, Total: len(rows), Rows: make([]*model.ImportRowResult, len(rows))}
+
+	var pending []int
+	for i, row := range rows {
+		result := &model.ImportRowResult{Row: row.Row, Status: model.ImportStatusSkipped}
+		report.Rows[i] = result
+
+		if row.Row < opts.ResumeFrom {
+			continue
+		}
+
+		err := validateImportRow(row)
+		if err != nil {
+			described := Describe(err)
+			result.Status = model.ImportStatusInvalid
+			result.Code = described.Code
+			result.Error = described.Message
+			result.Errors = row.Errors
+			report.Invalid++
+			continue
+		}
+
+		report.Valid++
+		pending = append(pending, i)
+	}
+
+	writeOpts := model.WriteOptions{Force: opts.Force}
+
+	if opts.DryRun {
+		err := s.withTx(ctx, func(tx *SubscriptionService) error {
+			for n, i := range pending {
+				if n%importChunkSize == 0 {
+					chunkStarted(opts)
+				}
+
+				// Каждая строка — в своей точке сохранения, чтобы проверить все строки
+				err := tx.withTx(ctx, func(sp *SubscriptionService) error {
+					return sp.importRow(ctx, rows[i], writeOpts, report.Rows[i])
+				})
+				if err != nil {
+					// Сбой базы делает проверку остальных строк бессмысленной
+					if KindOf(err) == KindInternal {
+						return err
+					}
+					markImportRowFailed(report.Rows[i], err)
+					continue
+				}
+				report.Rows[i].Status = model.ImportStatusValid
+			}
+			return errDryRun
+		})
+		if !errors.Is(err, errDryRun) {
+			return nil, err
+		}
+
+		for _, result := range report.Rows {
+			result.ID = nil
+		}
+		return report, nil
+	}
+
+	if report.Invalid > 0 {
+		return report, ErrImportInvalid
+	}
+
+	for start := 0; start < len(pending); start += importChunkSize {
+		chunk := pending[start:min(start+importChunkSize, len(pending))]
+		chunkStarted(opts)
+
+		err := s.withTx(ctx, func(tx *SubscriptionService) error {
+			for _, i := range chunk {
+				if err := tx.importRow(ctx, rows[i], writeOpts, report.Rows[i]); err != nil {
+					markImportRowFailed(report.Rows[i], err)
+					return &ImportError{Row: rows[i].Row, Err: err}
+				}
+				report.Rows[i].Status = model.ImportStatusCreated
+			}
+			return nil
+		})
+
+		if err != nil {
+			for _, i := range chunk {
+				if report.Rows[i].Status == model.ImportStatusCreated {
+					report.Rows[i].Status = model.ImportStatusRolledBack
+					report.Rows[i].ID = nil
+				}
+			}
+			resumeFrom := rows[chunk[0]].Row
+			report.ResumeFrom = &resumeFrom
+			return report, err
+		}
+
+		report.Created += len(chunk)
+	}
+
+	return report, nil
+}
+
+// chunkStarted сообщает вызывающему о начале обработки очередной части строк
+func chunkStarted(opts model.ImportOptions) {
+	if opts.OnChunk != nil {
+		opts.OnChunk()
+	}
+}
+
+// validateImportRow проверяет строку импорта по правилам создания подписки
+func validateImportRow(row model.ImportRow) error {
+	if len(row.Errors) > 0 || row.Subscription == nil {
+		return ErrInvalidImportRow
+	}
+
+	return validateSubscription(row.Subscription)
+}
+
+// importRow создает подписку строки импорта и записывает в результат её ID и предупреждения
+func (s *SubscriptionService) importRow(ctx context.Context, row model.ImportRow, opts model.WriteOptions, result *model.ImportRowResult) error {
+	resp, err := s.CreateSubscription(ctx, row.Subscription, opts)
+	if err != nil {
+		return err
+	}
+
+	id := resp.ID
+	result.ID = &id
+	result.Warnings = resp.Warnings
+	return nil
+}
+
+// markImportRowFailed записывает в результат строки ошибку её сохранения
+func markImportRowFailed(result *model.ImportRowResult, err error) {
+	described := Describe(err)
+	result.Status = model.ImportStatusFailed
+	result.Code = described.Code
+	result.Error = described.Message
+	result.ID = nil
+	result.Warnings = nil
+}
+
+var (
+	ErrInvalidImportRow = NewServiceError(KindInvalid, "validation_failed", "row failed validation")
+	ErrImportInvalid    = NewServiceError(KindUnprocessable, "import_invalid", "some rows failed validation, nothing was imported")
+)
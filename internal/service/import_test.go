@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// expectImportCreate настраивает мок на успешное создание подписки sub
+func expectImportCreate(mockRepo *MockRepository, ctx context.Context, sub *model.Subscription) {
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, sub).Return(nil, nil)
+	mockRepo.On("ListBudgets", ctx, sub.UserID).Return(nil, nil)
+	mockRepo.On("CreateSubscription", ctx, sub).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, sub.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("GetSubscription", ctx, sub.ID).Return(sub, nil)
+}
+
+func TestImportSubscriptions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	first, second := batchTestSubscription(), batchTestSubscription()
+	expectImportCreate(mockRepo, ctx, first)
+	expectImportCreate(mockRepo, ctx, second)
+
+	report, err := service.ImportSubscriptions(ctx, []model.ImportRow{
+		{Row: 1, Subscription: first},
+		{Row: 2, Subscription: second},
+	}, model.ImportOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 2, report.Created)
+	assert.Nil(t, report.ResumeFrom)
+	assert.Equal(t, model.ImportStatusCreated, report.Rows[0].Status)
+	assert.Equal(t, &first.ID, report.Rows[0].ID)
+	assert.Equal(t, model.ImportStatusCreated, report.Rows[1].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportSubscriptions_ReportsChunks(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	rows := make([]model.ImportRow, importChunkSize+1)
+	for i := range rows {
+		sub := batchTestSubscription()
+		expectImportCreate(mockRepo, ctx, sub)
+		rows[i] = model.ImportRow{Row: i + 1, Subscription: sub}
+	}
+
+	chunks := 0
+	report, err := service.ImportSubscriptions(ctx, rows, model.ImportOptions{OnChunk: func() { chunks++ }})
+
+	assert.NoError(t, err)
+	assert.Equal(t, importChunkSize+1, report.Created)
+	assert.Equal(t, 2, chunks)
+}
+
+func TestImportSubscriptions_InvalidRowsWriteNothing(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	invalid := batchTestSubscription()
+	invalid.Price = 0
+
+	report, err := service.ImportSubscriptions(ctx, []model.ImportRow{
+		{Row: 1, Subscription: batchTestSubscription()},
+		{Row: 2, Subscription: invalid},
+		{Row: 3, Errors: []model.FieldViolation{{Field: "start_date", Message: "must be in MM-YYYY format"}}},
+	}, model.ImportOptions{})
+
+	assert.ErrorIs(t, err, ErrImportInvalid)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 2, report.Invalid)
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, model.ImportStatusSkipped, report.Rows[0].Status)
+	assert.Equal(t, model.ImportStatusInvalid, report.Rows[1].Status)
+	assert.Equal(t, ErrInvalidPrice.Code, report.Rows[1].Code)
+	assert.Equal(t, model.ImportStatusInvalid, report.Rows[2].Status)
+	assert.Equal(t, ErrInvalidImportRow.Code, report.Rows[2].Code)
+	assert.Len(t, report.Rows[2].Errors, 1)
+	mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func TestImportSubscriptions_DryRun(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	valid, overlapping := batchTestSubscription(), batchTestSubscription()
+	invalid := batchTestSubscription()
+	invalid.ServiceName = ""
+
+	expectImportCreate(mockRepo, ctx, valid)
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, overlapping).Return([]*model.Subscription{{ID: uuid.New()}}, nil)
+
+	report, err := service.ImportSubscriptions(ctx, []model.ImportRow{
+		{Row: 1, Subscription: valid},
+		{Row: 2, Subscription: overlapping},
+		{Row: 3, Subscription: invalid},
+	}, model.ImportOptions{DryRun: true})
+
+	// Пробный запуск проверяет все строки и не возвращает ошибку
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 1, report.Invalid)
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, model.ImportStatusValid, report.Rows[0].Status)
+	assert.Nil(t, report.Rows[0].ID)
+	assert.Equal(t, model.ImportStatusFailed, report.Rows[1].Status)
+	assert.Equal(t, ErrSubscriptionOverlap.Code, report.Rows[1].Code)
+	assert.Equal(t, model.ImportStatusInvalid, report.Rows[2].Status)
+	assert.Equal(t, ErrServiceNameRequired.Code, report.Rows[2].Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportSubscriptions_FailureReportsResumeRow(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	created, overlapping, skipped := batchTestSubscription(), batchTestSubscription(), batchTestSubscription()
+	expectImportCreate(mockRepo, ctx, created)
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, overlapping).Return([]*model.Subscription{{ID: uuid.New()}}, nil)
+
+	report, err := service.ImportSubscriptions(ctx, []model.ImportRow{
+		{Row: 1, Subscription: batchTestSubscription()},
+		{Row: 2, Subscription: created},
+		{Row: 3, Subscription: overlapping},
+		{Row: 4, Subscription: skipped},
+	}, model.ImportOptions{ResumeFrom: 2})
+
+	var importErr *ImportError
+	assert.ErrorAs(t, err, &importErr)
+	assert.Equal(t, 3, importErr.Row)
+	assert.ErrorIs(t, err, ErrSubscriptionOverlap)
+
+	// Строка 1 пропущена как уже импортированная, часть со строками 2–4 отменена
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 2, *report.ResumeFrom)
+	assert.Equal(t, model.ImportStatusSkipped, report.Rows[0].Status)
+	assert.Equal(t, model.ImportStatusRolledBack, report.Rows[1].Status)
+	assert.Nil(t, report.Rows[1].ID)
+	assert.Equal(t, model.ImportStatusFailed, report.Rows[2].Status)
+	assert.Equal(t, model.ImportStatusSkipped, report.Rows[3].Status)
+	mockRepo.AssertNotCalled(t, "FindOverlappingSubscriptions", ctx, skipped)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/google/uuid"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultSearchLimit — число результатов поиска и автодополнения по умолчанию
+	DefaultSearchLimit = 20
+	// MaxSearchLimit — наибольшее число результатов поиска и автодополнения
+	MaxSearchLimit = 100
+	// maxSearchQueryLength — наибольшая длина запроса в символах (как у названия сервиса)
+	maxSearchQueryLength = 255
+	// highlightSimilarity — наименьшее сходство слова названия со словом запроса, при котором
+	// слово подсвечивается; совпадает с pg_trgm.similarity_threshold по умолчанию
+	highlightSimilarity = 0.3
+)
+
+var (
+	ErrSearchQueryRequired = NewServiceError(KindInvalid, "search_query_required", "search query is required")
+	ErrSearchQueryTooLong  = NewServiceError(KindInvalid, "search_query_too_long", "search query must be at most 255 characters long")
+)
+
+// SearchSubscriptions ищет подписки по названию сервиса с учетом опечаток и регистра
+// и подсвечивает в названиях слова, похожие на слова запроса
+func (s *SubscriptionService) SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if err := validateSearchQuery(search.Query); err != nil {
+		return nil, err
+	}
+
+	limit, err := searchLimit(search.Limit)
+	if err != nil {
+		return nil, err
+	}
+	search.Limit = limit
+
+	matches, err := s.repo.SearchSubscriptions(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, match := range matches {
+		match.Highlights = highlight(match.Subscription.ServiceName, search.Query)
+	}
+
+	return matches, nil
+}
+
+// SuggestServiceNames возвращает названия сервисов, начинающиеся с prefix без учета регистра,
+// для автодополнения
+func (s *SubscriptionService) SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error) {
+	// Пробел в конце значим («YouTube » не то же, что «YouTubers»), в начале — нет
+	prefix = strings.TrimLeftFunc(prefix, unicode.IsSpace)
+	if err := validateSearchQuery(prefix); err != nil {
+		return nil, err
+	}
+
+	limit, err := searchLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.SuggestServiceNames(ctx, prefix, userID, limit)
+}
+
+func validateSearchQuery(query string) error {
+	if query == "" {
+		return ErrSearchQueryRequired
+	}
+
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return ErrSearchQueryTooLong
+	}
+
+	return nil
+}
+
+func searchLimit(limit int) (int, error) {
+	if limit < 0 {
+		return 0, ErrInvalidLimit
+	}
+
+	if limit == 0 {
+		return DefaultSearchLimit, nil
+	}
+
+	return min(limit, MaxSearchLimit), nil
+}
+
+// word — слово строки и его позиция в символах
+type word struct {
+	text   string
+	offset int
+	length int
+}
+
+// splitWords разбивает строку на слова из букв и цифр, как pg_trgm при построении триграмм
+func splitWords(s string) []word {
+	var words []word
+	var current []rune
+	start := 0
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, word{text: strings.ToLower(string(current)), offset: start, length: len(current)})
+			current = current[:0]
+		}
+	}
+
+	i := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if len(current) == 0 {
+				start = i
+			}
+			current = append(current, r)
+		} else {
+			flush()
+		}
+		i++
+	}
+	flush()
+
+	return words
+}
+
+// highlight возвращает фрагменты name, совпадающие со словами query: начало слова, совпадающее
+// со словом запроса, или слово целиком, если оно похоже на слово запроса по триграммам
+func highlight(name, query string) []model.Highlight {
+	queryWords := splitWords(query)
+	highlights := []model.Highlight{}
+
+	for _, w := range splitWords(name) {
+		best := 0
+		for _, q := range queryWords {
+			switch {
+			case strings.HasPrefix(w.text, q.text):
+				best = max(best, q.length)
+			case trigramSimilarity(w.text, q.text) >= highlightSimilarity:
+				best = w.length
+			}
+		}
+
+		if best > 0 {
+			highlights = append(highlights, model.Highlight{Offset: w.offset, Length: best})
+		}
+	}
+
+	return highlights
+}
+
+// trigramSimilarity — сходство двух слов как в pg_trgm: доля общих триграмм среди всех триграмм
+// слов, дополненных двумя пробелами в начале и одним в конце
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+
+	common := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			common++
+		}
+	}
+
+	total := len(ta) + len(tb) - common
+	if total == 0 {
+		return 0
+	}
+
+	return float64(common) / float64(total)
+}
+
+func trigrams(w string) map[string]struct{} {
+	if w == "" {
+		return nil
+	}
+
+	runes := []rune("  " + w + " ")
+	set := make(map[string]struct{}, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = struct{}{}
+	}
+
+	return set
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchSubscriptions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockRepo.On("SearchSubscriptions", ctx, model.SubscriptionSearch{Query: "Yotube premum", UserID: &userID, Limit: DefaultSearchLimit}).
+		Return([]*model.SubscriptionMatch{
+			{Subscription: &model.Subscription{ServiceName: "YouTube Premium"}, Score: 0.52},
+			{Subscription: &model.Subscription{ServiceName: "Netflix"}, Score: 0.31},
+		}, nil)
+
+	matches, err := service.SearchSubscriptions(ctx, model.SubscriptionSearch{Query: "  Yotube premum ", UserID: &userID})
+
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, []model.Highlight{{Offset: 0, Length: 7}, {Offset: 8, Length: 7}}, matches[0].Highlights)
+	assert.Equal(t, []model.Highlight{}, matches[1].Highlights)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchSubscriptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		search model.SubscriptionSearch
+		err    error
+	}{
+		{"empty query", model.SubscriptionSearch{Query: "   "}, ErrSearchQueryRequired},
+		{"long query", model.SubscriptionSearch{Query: strings.Repeat("я", 256)}, ErrSearchQueryTooLong},
+		{"negative limit", model.SubscriptionSearch{Query: "netflix", Limit: -1}, ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+
+			_, err := service.SearchSubscriptions(context.Background(), tt.search)
+
+			assert.ErrorIs(t, err, tt.err)
+			mockRepo.AssertNotCalled(t, "SearchSubscriptions")
+		})
+	}
+}
+
+func TestSuggestServiceNames(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	suggestions := []*model.ServiceNameSuggestion{{ServiceName: "YouTube Premium", Subscriptions: 12}}
+	mockRepo.On("SuggestServiceNames", ctx, "YouTube ", (*uuid.UUID)(nil), MaxSearchLimit).Return(suggestions, nil)
+
+	result, err := service.SuggestServiceNames(ctx, " YouTube ", nil, 1000)
+
+	require.NoError(t, err)
+	assert.Equal(t, suggestions, result)
+	mockRepo.AssertExpectations(t)
+
+	_, err = service.SuggestServiceNames(ctx, "", nil, 0)
+	assert.ErrorIs(t, err, ErrSearchQueryRequired)
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name, service, query string
+		want                 []model.Highlight
+	}{
+		{"other case", "YouTube Premium", "youtube premium", []model.Highlight{{Offset: 0, Length: 7}, {Offset: 8, Length: 7}}},
+		{"typo", "YouTube Premium", "yutube", []model.Highlight{{Offset: 0, Length: 7}}},
+		{"prefix", "YouTube Premium", "prem", []model.Highlight{{Offset: 8, Length: 4}}},
+		{"offsets in characters", "Кинопоиск HD", "кинапоиск", []model.Highlight{{Offset: 0, Length: 9}}},
+		{"punctuation", "Yandex.Plus", "plus", []model.Highlight{{Offset: 7, Length: 4}}},
+		{"unrelated", "Netflix", "spotify", []model.Highlight{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, highlight(tt.service, tt.query))
+		})
+	}
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, trigramSimilarity("netflix", "netflix"))
+	// Как similarity('premium', 'premum') в pg_trgm: 5 общих триграмм из 10
+	assert.Equal(t, 0.5, trigramSimilarity("premium", "premum"))
+	assert.Equal(t, 0.0, trigramSimilarity("", ""))
+}
@@ -2,75 +2,371 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/events"
 	"github.com/ZnNr/subscription-service/internal/model"
 	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/internal/webhook"
 	"github.com/google/uuid"
+	"slices"
 
 	"time"
 )
 
 type Service interface {
-	CreateSubscription(ctx context.Context, sub *model.Subscription) (*model.Subscription, error)
+	CreateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) (*model.CreateSubscriptionResponse, error)
 	GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
-	UpdateSubscription(ctx context.Context, id uuid.UUID, req *model.UpdateSubscriptionRequest) error
-	DeleteSubscription(ctx context.Context, id uuid.UUID) error
-	ListSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string) ([]*model.Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) error
+	PatchSubscription(ctx context.Context, id uuid.UUID, patch *model.SubscriptionPatch, opts model.WriteOptions) (*model.Subscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID, opts model.WriteOptions) error
+	Batch(ctx context.Context, ops []model.BatchOperation, mode string, opts model.WriteOptions) (*model.BatchResponse, error)
+	ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error)
+	ExportSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error
+	ImportSubscriptions(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions) (*model.ImportReport, error)
 	CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error)
+	ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error)
+	SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error)
+	SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error)
+	SetBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error)
+	ListBudgets(ctx context.Context, userID uuid.UUID) ([]*model.Budget, error)
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+	BudgetReport(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]*model.BudgetMonth, error)
+	ListCharges(ctx context.Context, filter model.ChargeFilter) (*model.ChargePage, error)
+	ExportCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error
+	RebuildCharges(ctx context.Context) error
+	CalculateLedgerSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error)
+	CreateAdjustment(ctx context.Context, adj *model.Adjustment) (*model.Adjustment, error)
+	ListAdjustments(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Adjustment, error)
+	DeleteAdjustment(ctx context.Context, id uuid.UUID) error
+	BeginIdempotentRequest(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, uuid.UUID, error)
+	FinishIdempotentRequest(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*model.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error)
 }
 
 type SubscriptionService struct {
-	repo repository.Repository
+	repo           repository.Repository
+	idempotencyTTL time.Duration
+	// events — лента изменений подписок; nil — события не публикуются
+	events *events.Broker
+	// webhooks — ставить в очередь доставку событий на зарегистрированные вебхуки
+	webhooks bool
+	// webhookTargets — допустимые адреса вебхуков
+	webhookTargets webhook.TargetPolicy
+	// pending — события текущей транзакции, которые публикуются после её фиксации
+	pending *[]model.SubscriptionEvent
 }
 
-func NewSubscriptionService(repo repository.Repository) *SubscriptionService {
-	return &SubscriptionService{repo: repo}
+func NewSubscriptionService(repo repository.Repository, opts ...Option) *SubscriptionService {
+	s := &SubscriptionService{repo: repo, idempotencyTTL: DefaultIdempotencyTTL}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-func (s *SubscriptionService) CreateSubscription(ctx context.Context, sub *model.Subscription) (*model.Subscription, error) {
-	if err := validateSubscription(sub); err != nil {
-		return nil, err
+// WithEvents публикует изменения подписок в ленту событий broker
+func WithEvents(broker *events.Broker) Option {
+	return func(s *SubscriptionService) {
+		s.events = broker
 	}
+}
 
-	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+func (s *SubscriptionService) CreateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) (*model.CreateSubscriptionResponse, error) {
+	if err := validateSubscription(sub); err != nil {
 		return nil, err
 	}
 
-	createdSub, err := s.repo.GetSubscription(ctx, sub.ID)
+	var (
+		createdSub *model.Subscription
+		warnings   []string
+	)
+	err := s.atomically(ctx, func(tx *SubscriptionService) error {
+		overlapWarnings, err := tx.lockAndCheckOverlaps(ctx, sub, opts)
+		if err != nil {
+			return err
+		}
+
+		budgetWarnings, err := tx.checkBudgets(ctx, sub)
+		if err != nil {
+			return err
+		}
+		warnings = append(overlapWarnings, budgetWarnings...)
+
+		if err := tx.repo.CreateSubscription(ctx, sub); err != nil {
+			return err
+		}
+
+		if err := tx.repo.RegenerateCharges(ctx, sub.ID, ledgerHorizon()); err != nil {
+			return err
+		}
+
+		created, err := tx.repo.GetSubscription(ctx, sub.ID)
+		if err != nil {
+			return err
+		}
+		createdSub = created
+
+		return tx.publish(ctx, model.SubscriptionEventCreated, createdSub)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return createdSub, nil
+	return &model.CreateSubscriptionResponse{Subscription: createdSub, Warnings: warnings}, nil
 }
 
 func (s *SubscriptionService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
-	return s.repo.GetSubscription(ctx, id)
+	return s.getExisting(ctx, id)
 }
 
-func (s *SubscriptionService) UpdateSubscription(ctx context.Context, id uuid.UUID, req *model.UpdateSubscriptionRequest) error {
-	existing, err := s.repo.GetSubscription(ctx, id)
+// UpdateSubscription полностью заменяет подписку sub.ID данными sub.
+// После успешной записи sub содержит сохраненную подписку с новой версией.
+func (s *SubscriptionService) UpdateSubscription(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) error {
+	existing, err := s.getExisting(ctx, sub.ID)
 	if err != nil {
 		return err
 	}
 
-	if err := validateUpdateRequest(req, existing); err != nil {
+	if err := checkVersion(existing, opts); err != nil {
 		return err
 	}
 
-	return s.repo.UpdateSubscription(ctx, id, req)
+	replaced := *sub
+	replaced.CreatedAt = existing.CreatedAt
+	replaced.UpdatedAt = time.Now().UTC()
+
+	if err := s.saveSubscription(ctx, existing, &replaced, opts); err != nil {
+		return err
+	}
+
+	// Возвращаем вызывающему сохраненное состояние, включая новую версию
+	*sub = replaced
+	return nil
 }
 
-func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	_, err := s.repo.GetSubscription(ctx, id)
+// PatchSubscription применяет к подписке документ JSON Merge Patch и возвращает результат
+func (s *SubscriptionService) PatchSubscription(ctx context.Context, id uuid.UUID, patch *model.SubscriptionPatch, opts model.WriteOptions) (*model.Subscription, error) {
+	existing, err := s.getExisting(ctx, id)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(existing, opts); err != nil {
+		return nil, err
+	}
+
+	merged := applySubscriptionPatch(existing, patch)
+	if err := s.saveSubscription(ctx, existing, merged, opts); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// getExisting загружает подписку для изменения, отсутствие подписки — ErrNotFound
+func (s *SubscriptionService) getExisting(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	existing, err := s.repo.GetSubscription(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+
+	return existing, err
+}
+
+// saveSubscription проверяет измененную подписку по тем же правилам, что и при создании,
+// сохраняет её, пересоздает её журнал списаний и сообщает об изменении
+func (s *SubscriptionService) saveSubscription(ctx context.Context, existing, sub *model.Subscription, opts model.WriteOptions) error {
+	if err := validateSubscription(sub); err != nil {
 		return err
 	}
 
-	return s.repo.DeleteSubscription(ctx, id)
+	return s.atomically(ctx, func(tx *SubscriptionService) error {
+		if _, err := tx.lockAndCheckOverlaps(ctx, sub, opts); err != nil {
+			return err
+		}
+
+		err := tx.repo.UpdateSubscription(ctx, sub, opts.IfMatch)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return ErrPreconditionFailed
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.repo.RegenerateCharges(ctx, sub.ID, ledgerHorizon()); err != nil {
+			return err
+		}
+
+		return tx.publish(ctx, updateEventType(existing, sub), sub)
+	})
+}
+
+// updateEventType — тип события об изменении: появление даты окончания означает отмену подписки
+func updateEventType(existing, updated *model.Subscription) string {
+	if existing.EndDate == nil && updated.EndDate != nil {
+		return model.SubscriptionEventCancelled
+	}
+
+	return model.SubscriptionEventUpdated
+}
+
+// DeleteSubscription удаляет подписку; если задан opts.IfMatch — только при совпадении версии
+func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID, opts model.WriteOptions) error {
+	existing, err := s.getExisting(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(existing, opts); err != nil {
+		return err
+	}
+
+	return s.atomically(ctx, func(tx *SubscriptionService) error {
+		err := tx.repo.DeleteSubscription(ctx, id, opts.IfMatch)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return ErrPreconditionFailed
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.publish(ctx, model.SubscriptionEventDeleted, existing)
+	})
 }
 
-func (s *SubscriptionService) ListSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string) ([]*model.Subscription, error) {
-	return s.repo.ListSubscriptions(ctx, userID, serviceName)
+// publish сообщает об изменении подписки: ставит в очередь вебхуки (в той же транзакции,
+// что и само изменение) и откладывает событие ленты до фиксации транзакции, чтобы
+// подписчики не узнали об изменении, которое будет отменено. Вызывается внутри atomically.
+func (s *SubscriptionService) publish(ctx context.Context, eventType string, sub *model.Subscription) error {
+	event := model.SubscriptionEvent{Type: eventType, Subscription: sub, OccurredAt: time.Now().UTC()}
+
+	if s.webhooks {
+		if err := s.enqueueWebhooks(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	if s.events != nil {
+		*s.pending = append(*s.pending, event)
+	}
+
+	return nil
+}
+
+// checkVersion сверяет версию подписки с If-Match до выполнения изменения.
+// Окончательная проверка выполняется атомарно при записи в репозитории.
+func checkVersion(existing *model.Subscription, opts model.WriteOptions) error {
+	if opts.IfMatch != nil && *opts.IfMatch != existing.Version {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// Размеры страницы списка подписок
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	if filter.Limit < 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	if err := validateSubscriptionFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+
+	page, err := s.repo.ListSubscriptions(ctx, filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, ErrInvalidCursor
+	}
+
+	if errors.Is(err, repository.ErrInvalidSort) {
+		return nil, ErrInvalidSort
+	}
+
+	return page, err
+}
+
+// ExportSubscriptions передает в fn все подписки, подходящие под фильтр, в порядке filter.Sort
+// (при filter.ByUpdate — по updated_at).
+// Подписки читаются из базы потоком; filter.Limit и filter.Cursor не учитываются.
+func (s *SubscriptionService) ExportSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error {
+	if err := validateSubscriptionFilter(filter); err != nil {
+		return err
+	}
+
+	filter.Limit = 0
+	filter.Cursor = ""
+
+	err := s.repo.StreamSubscriptions(ctx, filter, fn)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		return ErrInvalidSort
+	}
+
+	return err
+}
+
+// validateSubscriptionFilter проверяет согласованность параметров фильтра списка подписок
+func validateSubscriptionFilter(filter model.SubscriptionFilter) error {
+	if filter.PriceMin != nil && *filter.PriceMin < 0 || filter.PriceMax != nil && *filter.PriceMax < 0 {
+		return ErrInvalidPriceFilter
+	}
+
+	if filter.PriceMin != nil && filter.PriceMax != nil && *filter.PriceMin > *filter.PriceMax {
+		return ErrInvalidPriceRange
+	}
+
+	if filter.StartFrom != nil && filter.StartTo != nil && filter.StartFrom.After(*filter.StartTo) {
+		return ErrInvalidStartRange
+	}
+
+	if filter.EndFrom != nil && filter.EndTo != nil && filter.EndFrom.After(*filter.EndTo) {
+		return ErrInvalidEndRange
+	}
+
+	switch filter.Status {
+	case "", model.SubscriptionStatusActive, model.SubscriptionStatusEnded, model.SubscriptionStatusFuture:
+	default:
+		return ErrInvalidStatus
+	}
+
+	if filter.ByUpdate && len(filter.Sort) > 0 {
+		return ErrInvalidSort
+	}
+
+	seen := make(map[string]bool, len(filter.Sort))
+	for _, f := range filter.Sort {
+		if seen[f.Field] || !slices.Contains(model.SubscriptionSortFields, f.Field) {
+			return ErrInvalidSort
+		}
+		seen[f.Field] = true
+	}
+
+	return nil
 }
 
 func (s *SubscriptionService) CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
@@ -78,7 +374,59 @@ func (s *SubscriptionService) CalculateSummary(ctx context.Context, startDate, e
 		return nil, ErrInvalidPeriod
 	}
 
-	return s.repo.CalculateSummary(ctx, startDate, endDate, userID, serviceName)
+	summary, err := s.repo.CalculateSummary(ctx, startDate, endDate, userID, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.repo.SumAdjustments(ctx, startDate, endDate, userID, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.AdjustmentsAmount = adjustments
+	summary.TotalAmount += adjustments
+
+	return summary, nil
+}
+
+func (s *SubscriptionService) ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error) {
+	return s.repo.ListOverlaps(ctx)
+}
+
+// lockAndCheckOverlaps блокирует подписки пользователя на сервис до конца транзакции и проверяет
+// пересечения под блокировкой: параллельная запись той же пары ждет фиксации и видит сохраненную
+// подписку. Вызывается только в транзакции, в которой затем сохраняется sub.
+func (s *SubscriptionService) lockAndCheckOverlaps(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) ([]string, error) {
+	if err := s.repo.LockSubscriptionKey(ctx, sub.UserID, sub.ServiceName); err != nil {
+		return nil, err
+	}
+
+	return s.checkOverlaps(ctx, sub, opts)
+}
+
+// checkOverlaps ищет подписки того же пользователя на тот же сервис с пересекающимся периодом.
+// Без opts.Force пересечение считается ошибкой, с opts.Force — возвращается как предупреждение.
+func (s *SubscriptionService) checkOverlaps(ctx context.Context, sub *model.Subscription, opts model.WriteOptions) ([]string, error) {
+	overlaps, err := s.repo.FindOverlappingSubscriptions(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(overlaps) == 0 {
+		return nil, nil
+	}
+
+	if !opts.Force {
+		return nil, &OverlapError{Overlaps: overlaps}
+	}
+
+	warnings := make([]string, 0, len(overlaps))
+	for _, o := range overlaps {
+		warnings = append(warnings, fmt.Sprintf("overlaps with subscription %s", o.ID))
+	}
+
+	return warnings, nil
 }
 
 func validateSubscription(sub *model.Subscription) error {
@@ -106,57 +454,69 @@ func validateSubscription(sub *model.Subscription) error {
 	return nil
 }
 
-func validateUpdateRequest(req *model.UpdateSubscriptionRequest, existing *model.Subscription) error {
-	if req.Price != nil && *req.Price <= 0 {
-		return ErrInvalidPrice
+// applySubscriptionPatch возвращает копию existing с примененным patch.
+// null в обязательном поле обнуляет его, и такая подписка не пройдет validateSubscription.
+func applySubscriptionPatch(existing *model.Subscription, patch *model.SubscriptionPatch) *model.Subscription {
+	merged := *existing
+	merged.UpdatedAt = time.Now().UTC()
+
+	if patch.ServiceName.Set {
+		merged.ServiceName = patch.ServiceName.Value
 	}
 
-	if req.StartDate != nil {
-		parsedDate, err := time.Parse("01-YYYY", *req.StartDate)
-		if err != nil {
-			return ErrInvalidDateFormat
-		}
+	if patch.Price.Set {
+		merged.Price = patch.Price.Value
+	}
+
+	if patch.UserID.Set {
+		merged.UserID = patch.UserID.Value
+	}
 
-		if existing.EndDate != nil && parsedDate.After(*existing.EndDate) {
-			return ErrInvalidStartDate
+	if patch.StartDate.Set {
+		merged.StartDate = time.Time{}
+		if !patch.StartDate.Null {
+			merged.StartDate = patch.StartDate.Value
 		}
 	}
 
-	if req.EndDate != nil {
-		parsedDate, err := time.Parse("01-YYYY", *req.EndDate)
-		if err != nil {
-			return ErrInvalidDateFormat
+	if patch.EndDate.Set {
+		merged.EndDate = nil
+		if !patch.EndDate.Null {
+			endDate := patch.EndDate.Value
+			merged.EndDate = &endDate
 		}
+	}
 
-		if parsedDate.Before(existing.StartDate) {
-			return ErrInvalidEndDate
+	if patch.Category.Set {
+		merged.Category = nil
+		if !patch.Category.Null {
+			merged.Category = &patch.Category.Value
 		}
 	}
 
-	return nil
+	return &merged
 }
 
+// monthYearLayout — формат дат "MM-YYYY" для time.Parse
+const monthYearLayout = "01-2006"
+
 // Ошибки
 var (
-	ErrServiceNameRequired = NewServiceError("service name is required")
-	ErrInvalidPrice        = NewServiceError("price must be greater than 0")
-	ErrUserIDRequired      = NewServiceError("user ID is required")
-	ErrStartDateRequired   = NewServiceError("start date is required")
-	ErrInvalidEndDate      = NewServiceError("end date cannot be before start date")
-	ErrInvalidStartDate    = NewServiceError("start date cannot be after end date")
-	ErrInvalidPeriod       = NewServiceError("start date cannot be after end date")
-	ErrInvalidDateFormat   = NewServiceError("invalid date format, expected MM-YYYY")
-	ErrNotFound            = NewServiceError("subscription not found")
+	ErrServiceNameRequired = NewServiceError(KindInvalid, "service_name_required", "service name is required")
+	ErrInvalidPrice        = NewServiceError(KindInvalid, "invalid_price", "price must be greater than 0")
+	ErrUserIDRequired      = NewServiceError(KindInvalid, "user_id_required", "user ID is required")
+	ErrStartDateRequired   = NewServiceError(KindInvalid, "start_date_required", "start date is required")
+	ErrInvalidEndDate      = NewServiceError(KindInvalid, "invalid_end_date", "end date cannot be before start date")
+	ErrInvalidPeriod       = NewServiceError(KindInvalid, "invalid_period", "start date cannot be after end date")
+	ErrNotFound            = NewServiceError(KindNotFound, "subscription_not_found", "subscription not found")
+	ErrPreconditionFailed  = NewServiceError(KindPreconditionFailed, "precondition_failed", "subscription version does not match If-Match")
+	ErrSubscriptionOverlap = NewServiceError(KindConflict, "subscription_overlap", "subscription overlaps with an existing subscription to the same service")
+	ErrInvalidLimit        = NewServiceError(KindInvalid, "invalid_limit", "limit must be a positive number")
+	ErrInvalidCursor       = NewServiceError(KindInvalid, "invalid_cursor", "invalid cursor")
+	ErrInvalidPriceFilter  = NewServiceError(KindInvalid, "invalid_price_filter", "price_min and price_max cannot be negative")
+	ErrInvalidPriceRange   = NewServiceError(KindInvalid, "invalid_price_range", "price_min cannot be greater than price_max")
+	ErrInvalidStartRange   = NewServiceError(KindInvalid, "invalid_start_range", "start_from cannot be after start_to")
+	ErrInvalidEndRange     = NewServiceError(KindInvalid, "invalid_end_range", "end_from cannot be after end_to")
+	ErrInvalidStatus       = NewServiceError(KindInvalid, "invalid_status", "status must be one of active, ended, future")
+	ErrInvalidSort         = NewServiceError(KindInvalid, "invalid_sort", "sort fields must be unique and one of price, start_date, end_date, service_name, created_at")
 )
-
-type ServiceError struct {
-	Message string
-}
-
-func (e ServiceError) Error() string {
-	return e.Message
-}
-
-func NewServiceError(message string) ServiceError {
-	return ServiceError{Message: message}
-}
@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,6 +19,11 @@ type MockRepository struct {
 	mock.Mock
 }
 
+// WithTx выполняет fn на том же моке: транзакционность проверяется в тестах репозитория
+func (m *MockRepository) WithTx(ctx context.Context, fn func(repo repository.Repository) error) error {
+	return fn(m)
+}
+
 func (m *MockRepository) CreateSubscription(ctx context.Context, sub *model.Subscription) error {
 	args := m.Called(ctx, sub)
 	return args.Error(0)
@@ -29,22 +37,34 @@ func (m *MockRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*mo
 	return args.Get(0).(*model.Subscription), args.Error(1)
 }
 
-func (m *MockRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, req *model.UpdateSubscriptionRequest) error {
-	args := m.Called(ctx, id, req)
+func (m *MockRepository) UpdateSubscription(ctx context.Context, sub *model.Subscription, expectedVersion *int) error {
+	args := m.Called(ctx, sub, expectedVersion)
 	return args.Error(0)
 }
 
-func (m *MockRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockRepository) DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion *int) error {
+	args := m.Called(ctx, id, expectedVersion)
 	return args.Error(0)
 }
 
-func (m *MockRepository) ListSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string) ([]*model.Subscription, error) {
-	args := m.Called(ctx, userID, serviceName)
+func (m *MockRepository) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Subscription), args.Error(1)
+	return args.Get(0).(*model.SubscriptionPage), args.Error(1)
+}
+
+func (m *MockRepository) StreamSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(sub *model.Subscription) error) error {
+	args := m.Called(ctx, filter, fn)
+	if subs, ok := args.Get(0).([]*model.Subscription); ok {
+		for _, sub := range subs {
+			if err := fn(sub); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
 }
 
 func (m *MockRepository) CalculateSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
@@ -55,6 +75,221 @@ func (m *MockRepository) CalculateSummary(ctx context.Context, startDate, endDat
 	return args.Get(0).(*model.SummaryResponse), args.Error(1)
 }
 
+func (m *MockRepository) FindOverlappingSubscriptions(ctx context.Context, sub *model.Subscription) ([]*model.Subscription, error) {
+	args := m.Called(ctx, sub)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Subscription), args.Error(1)
+}
+
+func (m *MockRepository) LockSubscriptionKey(ctx context.Context, userID uuid.UUID, serviceName string) error {
+	args := m.Called(ctx, userID, serviceName)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListOverlaps(ctx context.Context) ([]*model.SubscriptionOverlap, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SubscriptionOverlap), args.Error(1)
+}
+
+func (m *MockRepository) SearchSubscriptions(ctx context.Context, search model.SubscriptionSearch) ([]*model.SubscriptionMatch, error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SubscriptionMatch), args.Error(1)
+}
+
+func (m *MockRepository) SuggestServiceNames(ctx context.Context, prefix string, userID *uuid.UUID, limit int) ([]*model.ServiceNameSuggestion, error) {
+	args := m.Called(ctx, prefix, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ServiceNameSuggestion), args.Error(1)
+}
+
+func (m *MockRepository) UpsertBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error) {
+	args := m.Called(ctx, budget)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Budget), args.Error(1)
+}
+
+func (m *MockRepository) ListBudgets(ctx context.Context, userID uuid.UUID) ([]*model.Budget, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Budget), args.Error(1)
+}
+
+func (m *MockRepository) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MonthlyCosts(ctx context.Context, userID uuid.UUID, category *string, startDate, endDate time.Time) ([]*model.MonthlyCost, error) {
+	args := m.Called(ctx, userID, category, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MonthlyCost), args.Error(1)
+}
+
+func (m *MockRepository) RegenerateCharges(ctx context.Context, subscriptionID uuid.UUID, horizon time.Time) error {
+	args := m.Called(ctx, subscriptionID, horizon)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RebuildCharges(ctx context.Context, horizon time.Time) error {
+	args := m.Called(ctx, horizon)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ExtendCharges(ctx context.Context, horizon time.Time) error {
+	args := m.Called(ctx, horizon)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListCharges(ctx context.Context, filter model.ChargeFilter) (*model.ChargePage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ChargePage), args.Error(1)
+}
+
+func (m *MockRepository) StreamCharges(ctx context.Context, filter model.ChargeFilter, fn func(charge *model.Charge) error) error {
+	args := m.Called(ctx, filter, fn)
+	if charges, ok := args.Get(0).([]*model.Charge); ok {
+		for _, charge := range charges {
+			if err := fn(charge); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockRepository) CalculateLedgerSummary(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (*model.SummaryResponse, error) {
+	args := m.Called(ctx, startDate, endDate, userID, serviceName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SummaryResponse), args.Error(1)
+}
+
+func (m *MockRepository) CreateAdjustment(ctx context.Context, adj *model.Adjustment) error {
+	args := m.Called(ctx, adj)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListAdjustments(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Adjustment, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Adjustment), args.Error(1)
+}
+
+func (m *MockRepository) DeleteAdjustment(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SumAdjustments(ctx context.Context, startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (int, error) {
+	args := m.Called(ctx, startDate, endDate, userID, serviceName)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, lease uuid.UUID, now, lockedUntil, expiresAt time.Time) (*model.IdempotencyRecord, bool, error) {
+	args := m.Called(ctx, key, fingerprint, lease, now, lockedUntil, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*model.IdempotencyRecord), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRepository) CompleteIdempotencyKey(ctx context.Context, key string, lease uuid.UUID, statusCode int, headers map[string]string, body []byte) error {
+	args := m.Called(ctx, key, lease, statusCode, headers, body)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReleaseIdempotencyKey(ctx context.Context, key string, lease uuid.UUID) error {
+	args := m.Called(ctx, key, lease)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) GetWebhook(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) EnqueueWebhookDeliveries(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte, now time.Time) error {
+	args := m.Called(ctx, eventID, eventType, payload, now)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutgoingWebhook, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.OutgoingWebhook), args.Error(1)
+}
+
+func (m *MockRepository) RecordWebhookAttempt(ctx context.Context, attempt *model.WebhookAttempt, status string, nextAttemptAt, deliveredAt *time.Time) error {
+	args := m.Called(ctx, attempt, status, nextAttemptAt, deliveredAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) RedeliverWebhookDelivery(ctx context.Context, id uuid.UUID, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
 func TestCreateSubscription(t *testing.T) {
 	mockRepo := new(MockRepository)
 	service := NewSubscriptionService(mockRepo)
@@ -74,19 +309,213 @@ func TestCreateSubscription(t *testing.T) {
 	}
 
 	// Настраиваем мок
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, sub).Return(nil, nil)
+	mockRepo.On("ListBudgets", ctx, userID).Return(nil, nil)
 	mockRepo.On("CreateSubscription", ctx, sub).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, sub.ID, mock.AnythingOfType("time.Time")).Return(nil)
 	mockRepo.On("GetSubscription", ctx, sub.ID).Return(sub, nil)
 
 	// Вызываем метод
-	result, err := service.CreateSubscription(ctx, sub)
+	result, err := service.CreateSubscription(ctx, sub, model.WriteOptions{})
 
 	// Проверяем
 	assert.NoError(t, err)
 	assert.Equal(t, sub.ServiceName, result.ServiceName)
 	assert.Equal(t, sub.Price, result.Price)
+	assert.Empty(t, result.Warnings)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateSubscription_Overlap(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	sub := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	existing := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      sub.UserID,
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, sub).Return([]*model.Subscription{existing}, nil)
+
+	_, err := service.CreateSubscription(ctx, sub, model.WriteOptions{})
+
+	assert.ErrorIs(t, err, ErrSubscriptionOverlap)
+	var overlapErr *OverlapError
+	assert.ErrorAs(t, err, &overlapErr)
+	assert.Equal(t, existing.ID, overlapErr.Overlaps[0].ID)
+	mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func TestCreateSubscription_OverlapForced(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	sub := &model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	existing := &model.Subscription{ID: uuid.New()}
+
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, sub).Return([]*model.Subscription{existing}, nil)
+	mockRepo.On("ListBudgets", ctx, sub.UserID).Return(nil, nil)
+	mockRepo.On("CreateSubscription", ctx, sub).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, sub.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("GetSubscription", ctx, sub.ID).Return(sub, nil)
+
+	result, err := service.CreateSubscription(ctx, sub, model.WriteOptions{Force: true})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], existing.ID.String())
 	mockRepo.AssertExpectations(t)
 }
 
+// lockingRepository хранит подписки в памяти и, как pg_advisory_xact_lock, держит блокировку
+// пары пользователь+сервис до конца транзакции. Остальные методы — из MockRepository.
+type lockingRepository struct {
+	*MockRepository
+	mu    sync.Mutex
+	subs  []*model.Subscription
+	locks map[string]*sync.Mutex
+}
+
+// lockingTx — репозиторий внутри транзакции lockingRepository: запоминает взятые блокировки
+type lockingTx struct {
+	*lockingRepository
+	held []*sync.Mutex
+}
+
+func (r *lockingRepository) WithTx(ctx context.Context, fn func(repo repository.Repository) error) error {
+	tx := &lockingTx{lockingRepository: r}
+	defer func() {
+		for _, l := range tx.held {
+			l.Unlock()
+		}
+	}()
+
+	return fn(tx)
+}
+
+func (tx *lockingTx) LockSubscriptionKey(_ context.Context, userID uuid.UUID, serviceName string) error {
+	key := userID.String() + ":" + strings.ToLower(serviceName)
+
+	tx.mu.Lock()
+	l, ok := tx.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		tx.locks[key] = l
+	}
+	tx.mu.Unlock()
+
+	l.Lock()
+	tx.held = append(tx.held, l)
+	return nil
+}
+
+func (r *lockingRepository) FindOverlappingSubscriptions(_ context.Context, sub *model.Subscription) ([]*model.Subscription, error) {
+	r.mu.Lock()
+	var overlaps []*model.Subscription
+	for _, other := range r.subs {
+		if other.UserID == sub.UserID && strings.EqualFold(other.ServiceName, sub.ServiceName) && other.ID != sub.ID &&
+			(sub.EndDate == nil || !other.StartDate.After(*sub.EndDate)) &&
+			(other.EndDate == nil || !other.EndDate.Before(sub.StartDate)) {
+			overlaps = append(overlaps, other)
+		}
+	}
+	r.mu.Unlock()
+
+	// Между проверкой и записью проходит время: без блокировки параллельная запись успела бы пройти проверку
+	time.Sleep(5 * time.Millisecond)
+	return overlaps, nil
+}
+
+func (r *lockingRepository) CreateSubscription(_ context.Context, sub *model.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subs = append(r.subs, sub)
+	return nil
+}
+
+func (r *lockingRepository) GetSubscription(_ context.Context, id uuid.UUID) (*model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, sub := range r.subs {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *lockingRepository) ListBudgets(context.Context, uuid.UUID) ([]*model.Budget, error) {
+	return nil, nil
+}
+
+func (r *lockingRepository) RegenerateCharges(context.Context, uuid.UUID, time.Time) error {
+	return nil
+}
+
+func TestCreateSubscription_ConcurrentOverlap(t *testing.T) {
+	repo := &lockingRepository{MockRepository: new(MockRepository), locks: map[string]*sync.Mutex{}}
+	service := NewSubscriptionService(repo)
+	userID := uuid.New()
+
+	// Параллельные создания пересекающихся подписок одного пользователя на один сервис
+	// (название в разном регистре): сохраниться должна только одна
+	const writers = 8
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serviceName := "Netflix"
+			if i%2 == 1 {
+				serviceName = "NETFLIX"
+			}
+			_, errs[i] = service.CreateSubscription(context.Background(), &model.Subscription{
+				ID:          uuid.New(),
+				ServiceName: serviceName,
+				Price:       599,
+				UserID:      userID,
+				StartDate:   time.Date(2025, time.Month(1+i), 1, 0, 0, 0, 0, time.UTC),
+			}, model.WriteOptions{})
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrSubscriptionOverlap)
+	}
+	assert.Equal(t, 1, created)
+	assert.Len(t, repo.subs, 1)
+}
+
 func TestCreateSubscription_InvalidPrice(t *testing.T) {
 	service := NewSubscriptionService(nil)
 	ctx := context.Background()
@@ -99,7 +528,7 @@ func TestCreateSubscription_InvalidPrice(t *testing.T) {
 		StartDate:   time.Now().UTC(),
 	}
 
-	_, err := service.CreateSubscription(ctx, sub)
+	_, err := service.CreateSubscription(ctx, sub, model.WriteOptions{})
 	assert.Error(t, err)
 	assert.Equal(t, ErrInvalidPrice, err)
 }
@@ -136,30 +565,270 @@ func TestUpdateSubscription(t *testing.T) {
 	ctx := context.Background()
 
 	subID := uuid.New()
+	userID := uuid.New()
+	createdAt := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
 	existingSub := &model.Subscription{
 		ID:          subID,
 		ServiceName: "Netflix",
 		Price:       599,
-		UserID:      uuid.New(),
+		UserID:      userID,
 		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     &endDate,
+		CreatedAt:   createdAt,
 	}
 
-	updateReq := &model.UpdateSubscriptionRequest{
-		Price: &[]int{699}[0],
+	// Полная замена: end_date не передан и очищается
+	replacement := &model.Subscription{
+		ID:          subID,
+		ServiceName: "Netflix",
+		Price:       699,
+		UserID:      userID,
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
 	// Настраиваем моки
 	mockRepo.On("GetSubscription", ctx, subID).Return(existingSub, nil)
-	mockRepo.On("UpdateSubscription", ctx, subID, updateReq).Return(nil)
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateSubscription", ctx, mock.MatchedBy(func(s *model.Subscription) bool {
+		return s.ID == subID && s.Price == 699 && s.EndDate == nil && s.CreatedAt.Equal(createdAt) && !s.UpdatedAt.IsZero()
+	}), (*int)(nil)).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, subID, mock.AnythingOfType("time.Time")).Return(nil)
 
 	// Вызываем метод
-	err := service.UpdateSubscription(ctx, subID, updateReq)
+	err := service.UpdateSubscription(ctx, replacement, model.WriteOptions{})
 
 	// Проверяем
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdateSubscription_NotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	subID := uuid.New()
+	mockRepo.On("GetSubscription", ctx, subID).Return(nil, repository.ErrNotFound)
+
+	err := service.UpdateSubscription(ctx, &model.Subscription{ID: subID}, model.WriteOptions{})
+
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestUpdateSubscription_OverlapAfterDateChange(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	subID := uuid.New()
+	existingSub := &model.Subscription{
+		ID:          subID,
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	patch := &model.SubscriptionPatch{
+		StartDate: model.Optional[time.Time]{Set: true, Value: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	mockRepo.On("GetSubscription", ctx, subID).Return(existingSub, nil)
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, mock.MatchedBy(func(s *model.Subscription) bool {
+		return s.StartDate.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	})).Return([]*model.Subscription{{ID: uuid.New()}}, nil)
+
+	_, err := service.PatchSubscription(ctx, subID, patch, model.WriteOptions{})
+
+	assert.ErrorIs(t, err, ErrSubscriptionOverlap)
+	mockRepo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchSubscription_ClearEndDate(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	subID := uuid.New()
+	category := "video"
+	endDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	existingSub := &model.Subscription{
+		ID:          subID,
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     &endDate,
+		Category:    &category,
+	}
+
+	// end_date: null очищает дату окончания, отсутствующие поля не меняются
+	patch := model.SubscriptionPatch{
+		EndDate: model.Optional[time.Time]{Set: true, Null: true},
+		Price:   model.Optional[int]{Set: true, Value: 699},
+	}
+
+	mockRepo.On("GetSubscription", ctx, subID).Return(existingSub, nil)
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateSubscription", ctx, mock.MatchedBy(func(s *model.Subscription) bool {
+		return s.EndDate == nil && s.Price == 699 && s.Category != nil && *s.Category == category
+	}), (*int)(nil)).Return(nil)
+	mockRepo.On("RegenerateCharges", ctx, subID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := service.PatchSubscription(ctx, subID, &patch, model.WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.EndDate)
+	assert.Equal(t, "Netflix", result.ServiceName)
+	assert.NotNil(t, existingSub.EndDate, "исходная подписка не должна меняться")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchSubscription_InvalidMergedResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    model.SubscriptionPatch
+		expected error
+	}{
+		{"Null required field", model.SubscriptionPatch{ServiceName: model.Optional[string]{Set: true, Null: true}}, ErrServiceNameRequired},
+		{"Null price", model.SubscriptionPatch{Price: model.Optional[int]{Set: true, Null: true}}, ErrInvalidPrice},
+		{"Null start date", model.SubscriptionPatch{StartDate: model.Optional[time.Time]{Set: true, Null: true}}, ErrStartDateRequired},
+		{"End before start", model.SubscriptionPatch{EndDate: model.Optional[time.Time]{Set: true, Value: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)}}, ErrInvalidEndDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+			ctx := context.Background()
+
+			subID := uuid.New()
+			mockRepo.On("GetSubscription", ctx, subID).Return(&model.Subscription{
+				ID:          subID,
+				ServiceName: "Netflix",
+				Price:       599,
+				UserID:      uuid.New(),
+				StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			}, nil)
+
+			_, err := service.PatchSubscription(ctx, subID, &tt.patch, model.WriteOptions{})
+
+			assert.Equal(t, tt.expected, err)
+			mockRepo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListSubscriptions_PageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{"Default page size", 0, DefaultPageSize},
+		{"Requested page size", 10, 10},
+		{"Clamped to max page size", MaxPageSize + 1, MaxPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+			ctx := context.Background()
+
+			mockRepo.On("ListSubscriptions", ctx, model.SubscriptionFilter{Limit: tt.expected}).
+				Return(&model.SubscriptionPage{Items: []*model.Subscription{}}, nil)
+
+			_, err := service.ListSubscriptions(ctx, model.SubscriptionFilter{Limit: tt.limit})
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListSubscriptions_InvalidFilter(t *testing.T) {
+	negative, low, high := -1, 100, 500
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   model.SubscriptionFilter
+		expected error
+	}{
+		{"Negative price", model.SubscriptionFilter{PriceMin: &negative}, ErrInvalidPriceFilter},
+		{"Inverted price range", model.SubscriptionFilter{PriceMin: &high, PriceMax: &low}, ErrInvalidPriceRange},
+		{"Inverted start range", model.SubscriptionFilter{StartFrom: &from, StartTo: &to}, ErrInvalidStartRange},
+		{"Inverted end range", model.SubscriptionFilter{EndFrom: &from, EndTo: &to}, ErrInvalidEndRange},
+		{"Unknown status", model.SubscriptionFilter{Status: "paused"}, ErrInvalidStatus},
+		{"Unknown sort field", model.SubscriptionFilter{Sort: []model.SortField{{Field: "user_id"}}}, ErrInvalidSort},
+		{"Duplicate sort field", model.SubscriptionFilter{Sort: []model.SortField{{Field: "price"}, {Field: "price", Desc: true}}}, ErrInvalidSort},
+		{"Internal sort field", model.SubscriptionFilter{Sort: []model.SortField{{Field: "updated_at"}}}, ErrInvalidSort},
+		{"Sort with update order", model.SubscriptionFilter{Sort: []model.SortField{{Field: "price"}}, ByUpdate: true}, ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+
+			_, err := service.ListSubscriptions(context.Background(), tt.filter)
+
+			assert.Equal(t, tt.expected, err)
+			mockRepo.AssertNotCalled(t, "ListSubscriptions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestExportSubscriptions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	sub := &model.Subscription{ID: uuid.New()}
+	// Размер страницы и курсор к выгрузке не относятся
+	mockRepo.On("StreamSubscriptions", ctx, model.SubscriptionFilter{Status: model.SubscriptionStatusActive}, mock.Anything).
+		Return([]*model.Subscription{sub}, nil)
+
+	var exported []*model.Subscription
+	err := service.ExportSubscriptions(ctx, model.SubscriptionFilter{Status: model.SubscriptionStatusActive, Limit: 10, Cursor: "abc"}, func(s *model.Subscription) error {
+		exported = append(exported, s)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Subscription{sub}, exported)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportSubscriptions_InvalidFilter(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	err := service.ExportSubscriptions(context.Background(), model.SubscriptionFilter{Status: "paused"}, func(*model.Subscription) error { return nil })
+
+	assert.Equal(t, ErrInvalidStatus, err)
+	mockRepo.AssertNotCalled(t, "StreamSubscriptions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListSubscriptions_InvalidCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	filter := model.SubscriptionFilter{Limit: 10, Cursor: "garbage"}
+	mockRepo.On("ListSubscriptions", ctx, filter).Return(nil, repository.ErrInvalidCursor)
+
+	_, err := service.ListSubscriptions(ctx, filter)
+
+	assert.Equal(t, ErrInvalidCursor, err)
+}
+
 func TestDeleteSubscription(t *testing.T) {
 	mockRepo := new(MockRepository)
 	service := NewSubscriptionService(mockRepo)
@@ -172,16 +841,66 @@ func TestDeleteSubscription(t *testing.T) {
 
 	// Настраиваем моки
 	mockRepo.On("GetSubscription", ctx, subID).Return(existingSub, nil)
-	mockRepo.On("DeleteSubscription", ctx, subID).Return(nil)
+	mockRepo.On("DeleteSubscription", ctx, subID, (*int)(nil)).Return(nil)
 
 	// Вызываем метод
-	err := service.DeleteSubscription(ctx, subID)
+	err := service.DeleteSubscription(ctx, subID, model.WriteOptions{})
 
 	// Проверяем
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestDeleteSubscription_IfMatch(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	subID := uuid.New()
+	version := 3
+	mockRepo.On("GetSubscription", ctx, subID).Return(&model.Subscription{ID: subID, Version: 3}, nil)
+	mockRepo.On("DeleteSubscription", ctx, subID, &version).Return(nil)
+
+	err := service.DeleteSubscription(ctx, subID, model.WriteOptions{IfMatch: &version})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateSubscription_StaleIfMatch(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	sub := batchTestSubscription()
+	stale := 1
+	mockRepo.On("GetSubscription", ctx, sub.ID).Return(&model.Subscription{ID: sub.ID, Version: 2}, nil)
+
+	err := service.UpdateSubscription(ctx, sub, model.WriteOptions{IfMatch: &stale})
+
+	assert.Equal(t, ErrPreconditionFailed, err)
+	mockRepo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscription_ConcurrentVersionChange(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	// Версия совпала при чтении, но подписку изменили до записи
+	sub := batchTestSubscription()
+	version := 2
+	mockRepo.On("GetSubscription", ctx, sub.ID).Return(&model.Subscription{ID: sub.ID, Version: 2}, nil)
+	mockRepo.On("LockSubscriptionKey", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindOverlappingSubscriptions", ctx, mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateSubscription", ctx, mock.Anything, &version).Return(repository.ErrVersionConflict)
+
+	err := service.UpdateSubscription(ctx, sub, model.WriteOptions{IfMatch: &version})
+
+	assert.Equal(t, ErrPreconditionFailed, err)
+	mockRepo.AssertNotCalled(t, "RegenerateCharges", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCalculateSummary(t *testing.T) {
 	mockRepo := new(MockRepository)
 	service := NewSubscriptionService(mockRepo)
@@ -198,6 +917,7 @@ func TestCalculateSummary(t *testing.T) {
 
 	// Настраиваем мок
 	mockRepo.On("CalculateSummary", ctx, startDate, endDate, &userID, mock.Anything).Return(expectedSummary, nil)
+	mockRepo.On("SumAdjustments", ctx, startDate, endDate, &userID, mock.Anything).Return(0, nil)
 
 	// Вызываем метод
 	result, err := service.CalculateSummary(ctx, startDate, endDate, &userID, nil)
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/internal/webhook"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// minWebhookSecretLength — минимальная длина ключа подписи вебхука
+const minWebhookSecretLength = 16
+
+// webhookDeliveriesLimit — сколько последних доставок вебхука возвращает ListWebhookDeliveries
+const webhookDeliveriesLimit = 100
+
+// WithWebhooks включает доставку событий на зарегистрированные вебхуки: каждое изменение
+// подписки ставит доставки в очередь в той же транзакции. Очередь разбирает webhook.Dispatcher.
+func WithWebhooks() Option {
+	return func(s *SubscriptionService) {
+		s.webhooks = true
+	}
+}
+
+// WithWebhookTargets задает, на какие адреса можно регистрировать вебхуки.
+// По умолчанию — только https-адреса в публичной сети.
+func WithWebhookTargets(policy webhook.TargetPolicy) Option {
+	return func(s *SubscriptionService) {
+		s.webhookTargets = policy
+	}
+}
+
+// CreateWebhook регистрирует вебхук. URL — абсолютный https в публичной сети (см. webhook.TargetPolicy);
+// события — из model.SubscriptionEventTypes
+func (s *SubscriptionService) CreateWebhook(ctx context.Context, target *model.Webhook) (*model.Webhook, error) {
+	if err := s.webhookTargets.CheckURL(ctx, target.URL); err != nil {
+		if errors.Is(err, webhook.ErrForbiddenAddress) {
+			return nil, ErrWebhookURLNotAllowed
+		}
+		return nil, ErrInvalidWebhookURL
+	}
+
+	if len(target.Secret) < minWebhookSecretLength {
+		return nil, ErrInvalidWebhookSecret
+	}
+
+	if len(target.EventTypes) == 0 {
+		return nil, ErrInvalidWebhookEventType
+	}
+	eventTypes := make([]string, 0, len(target.EventTypes))
+	for _, eventType := range target.EventTypes {
+		if !slices.Contains(model.SubscriptionEventTypes, eventType) {
+			return nil, ErrInvalidWebhookEventType
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	created := *target
+	created.ID = uuid.New()
+	created.EventTypes = eventTypes
+	created.CreatedAt = time.Now().UTC()
+
+	if err := s.repo.CreateWebhook(ctx, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *SubscriptionService) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	return s.repo.ListWebhooks(ctx)
+}
+
+// DeleteWebhook удаляет вебхук; недоставленные события на него больше не отправляются
+func (s *SubscriptionService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	err := s.repo.DeleteWebhook(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrWebhookNotFound
+	}
+
+	return err
+}
+
+// ListWebhookDeliveries возвращает последние доставки вебхука, от новых к старым
+func (s *SubscriptionService) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*model.WebhookDelivery, error) {
+	if _, err := s.repo.GetWebhook(ctx, webhookID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return s.repo.ListWebhookDeliveries(ctx, webhookID, webhookDeliveriesLimit)
+}
+
+// GetWebhookDelivery возвращает доставку вебхука с журналом попыток
+func (s *SubscriptionService) GetWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	delivery, err := s.repo.GetWebhookDelivery(ctx, deliveryID)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && delivery.WebhookID != webhookID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	return delivery, err
+}
+
+// RedeliverWebhookDelivery возвращает доставку в очередь: следующая попытка — сразу,
+// при неудаче повторы идут по тому же расписанию, что и для нового события
+func (s *SubscriptionService) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	if _, err := s.GetWebhookDelivery(ctx, webhookID, deliveryID); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.RedeliverWebhookDelivery(ctx, deliveryID, time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetWebhookDelivery(ctx, webhookID, deliveryID)
+}
+
+// enqueueWebhooks ставит событие в очередь доставки на вебхуки, подписанные на его тип
+func (s *SubscriptionService) enqueueWebhooks(ctx context.Context, event model.SubscriptionEvent) error {
+	webhookEvent := model.WebhookEvent{
+		ID:         uuid.New(),
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Subscription,
+	}
+
+	payload, err := json.Marshal(webhookEvent)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.EnqueueWebhookDeliveries(ctx, webhookEvent.ID, webhookEvent.Type, payload, event.OccurredAt)
+}
+
+var (
+	ErrWebhookNotFound         = NewServiceError(KindNotFound, "webhook_not_found", "webhook not found")
+	ErrWebhookDeliveryNotFound = NewServiceError(KindNotFound, "webhook_delivery_not_found", "webhook delivery not found")
+	ErrInvalidWebhookURL       = NewServiceError(KindInvalid, "invalid_webhook_url", "url must be an absolute https URL")
+	ErrWebhookURLNotAllowed    = NewServiceError(KindInvalid, "webhook_url_not_allowed", "url must resolve to a public address, not a loopback, private or link-local one")
+	ErrInvalidWebhookSecret    = NewServiceError(KindInvalid, "invalid_webhook_secret", "secret must be at least 16 characters long")
+	ErrInvalidWebhookEventType = NewServiceError(KindInvalid, "invalid_event_type", "event_types must list subscription.created, subscription.updated, subscription.cancelled or subscription.deleted")
+)
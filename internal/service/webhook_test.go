@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/repository"
+	"github.com/ZnNr/subscription-service/internal/webhook"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// publicResolver разрешает example.com в публичный адрес, а internal.example — в частный, без обращения к DNS
+type publicResolver struct{}
+
+func (publicResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	if host == "internal.example" {
+		return []net.IPAddr{{IP: net.ParseIP("10.0.0.5")}}, nil
+	}
+	return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+}
+
+var testWebhookTargets = WithWebhookTargets(webhook.TargetPolicy{Resolver: publicResolver{}})
+
+func TestCreateWebhook(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, testWebhookTargets)
+	ctx := context.Background()
+
+	mockRepo.On("CreateWebhook", ctx, mock.AnythingOfType("*model.Webhook")).Return(nil)
+
+	created, err := service.CreateWebhook(ctx, &model.Webhook{
+		URL:        "https://example.com/hooks",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{model.SubscriptionEventCreated, model.SubscriptionEventDeleted, model.SubscriptionEventCreated},
+	})
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+	assert.False(t, created.CreatedAt.IsZero())
+	// Повторяющиеся типы событий схлопываются
+	assert.Equal(t, []string{model.SubscriptionEventCreated, model.SubscriptionEventDeleted}, created.EventTypes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateWebhook_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook model.Webhook
+		err     error
+	}{
+		{"relative url", model.Webhook{URL: "/hooks", Secret: "0123456789abcdef", EventTypes: []string{model.SubscriptionEventCreated}}, ErrInvalidWebhookURL},
+		{"unsupported scheme", model.Webhook{URL: "ftp://example.com/hooks", Secret: "0123456789abcdef", EventTypes: []string{model.SubscriptionEventCreated}}, ErrInvalidWebhookURL},
+		{"plain http", model.Webhook{URL: "http://example.com/hooks", Secret: "0123456789abcdef", EventTypes: []string{model.SubscriptionEventCreated}}, ErrInvalidWebhookURL},
+		{"loopback", model.Webhook{URL: "https://127.0.0.1:8080/hooks", Secret: "0123456789abcdef", EventTypes: []string{model.SubscriptionEventCreated}}, ErrWebhookURLNotAllowed},
+		{"cloud metadata", model.Webhook{URL: "https://169.254.169.254/latest/meta-data", Secret: "0123456789abcdef", EventTypes: []string{model.SubscriptionEventCreated}}, ErrWebhookURLNotAllowed},
+		{"resolves to private", model.Webhook{URL: "https://internal.example/hooks", Secret: "0123456789abcdef", EventTypes: []string{model.SubscriptionEventCreated}}, ErrWebhookURLNotAllowed},
+		{"short secret", model.Webhook{URL: "https://example.com/hooks", Secret: "secret", EventTypes: []string{model.SubscriptionEventCreated}}, ErrInvalidWebhookSecret},
+		{"no events", model.Webhook{URL: "https://example.com/hooks", Secret: "0123456789abcdef"}, ErrInvalidWebhookEventType},
+		{"unknown event", model.Webhook{URL: "https://example.com/hooks", Secret: "0123456789abcdef", EventTypes: []string{"subscription.renewed"}}, ErrInvalidWebhookEventType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo, testWebhookTargets)
+
+			_, err := service.CreateWebhook(context.Background(), &tt.webhook)
+
+			assert.ErrorIs(t, err, tt.err)
+			mockRepo.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListWebhookDeliveries_WebhookNotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	id := uuid.New()
+	mockRepo.On("GetWebhook", ctx, id).Return(nil, repository.ErrNotFound)
+
+	_, err := service.ListWebhookDeliveries(ctx, id)
+
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+	ctx := context.Background()
+
+	webhookID := uuid.New()
+	delivery := &model.WebhookDelivery{ID: uuid.New(), WebhookID: webhookID, Status: model.WebhookDeliveryFailed}
+
+	mockRepo.On("GetWebhookDelivery", ctx, delivery.ID).Return(delivery, nil)
+	mockRepo.On("RedeliverWebhookDelivery", ctx, delivery.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	redelivered, err := service.RedeliverWebhookDelivery(ctx, webhookID, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, delivery, redelivered)
+
+	// Доставка другого вебхука по этому пути не находится
+	_, err = service.RedeliverWebhookDelivery(ctx, uuid.New(), delivery.ID)
+	assert.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+	mockRepo.AssertNumberOfCalls(t, "RedeliverWebhookDelivery", 1)
+}
+
+func TestDeleteSubscription_EnqueuesWebhooks(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithWebhooks())
+	ctx := context.Background()
+
+	existing := &model.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: 599, UserID: uuid.New()}
+
+	var payload []byte
+	mockRepo.On("GetSubscription", ctx, existing.ID).Return(existing, nil)
+	mockRepo.On("DeleteSubscription", ctx, existing.ID, (*int)(nil)).Return(nil)
+	mockRepo.On("EnqueueWebhookDeliveries", ctx, mock.AnythingOfType("uuid.UUID"), model.SubscriptionEventDeleted, mock.Anything, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { payload = args.Get(3).([]byte) }).
+		Return(nil)
+
+	require.NoError(t, service.DeleteSubscription(ctx, existing.ID, model.WriteOptions{}))
+
+	var event model.WebhookEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	assert.Equal(t, model.SubscriptionEventDeleted, event.Type)
+	assert.Equal(t, existing.ID, event.Data.ID)
+	assert.WithinDuration(t, time.Now(), event.OccurredAt, time.Minute)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateEventType(t *testing.T) {
+	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	active := &model.Subscription{}
+	cancelled := &model.Subscription{EndDate: &endDate}
+
+	assert.Equal(t, model.SubscriptionEventCancelled, updateEventType(active, cancelled))
+	assert.Equal(t, model.SubscriptionEventUpdated, updateEventType(active, active))
+	assert.Equal(t, model.SubscriptionEventUpdated, updateEventType(cancelled, cancelled))
+	assert.Equal(t, model.SubscriptionEventUpdated, updateEventType(cancelled, active))
+}
@@ -0,0 +1,254 @@
+// Package webhook — доставка событий об изменении подписок на зарегистрированные вебхуки.
+//
+// Доставки ставятся в очередь (таблица webhook_deliveries) в одной транзакции с самим
+// изменением, поэтому событие не теряется при остановке сервиса и не отправляется, если
+// изменение откатилось. Dispatcher разбирает очередь в фоне: отправляет подписанный
+// запрос, записывает попытку в журнал и при неудаче назначает повтор с экспоненциальной
+// задержкой. Очередь общая для всех экземпляров сервиса.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Заголовки запроса, который получает вебхук
+const (
+	HeaderWebhookID = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	// HeaderSignature — "t=<unix-время>,v1=<hex HMAC-SHA256 от "<t>.<тело>">"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// DefaultMaxAttempts — сколько попыток делается по умолчанию, прежде чем доставка считается неудачной
+const DefaultMaxAttempts = 10
+
+const (
+	// pollInterval — как часто проверяется очередь, если в ней нет доставок
+	pollInterval = time.Second
+	// batchSize — сколько доставок берется в работу за один проход
+	batchSize = 20
+	// lease — на сколько откладывается взятая доставка; должно с запасом перекрывать requestTimeout
+	lease = time.Minute
+	// requestTimeout — сколько ждать ответа получателя
+	requestTimeout = 10 * time.Second
+	// Задержка перед повтором: baseBackoff после первой неудачи, затем вдвое больше, не более maxBackoff
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// Repository — очередь доставок, с которой работает Dispatcher
+type Repository interface {
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutgoingWebhook, error)
+	RecordWebhookAttempt(ctx context.Context, attempt *model.WebhookAttempt, status string, nextAttemptAt, deliveredAt *time.Time) error
+}
+
+type Dispatcher struct {
+	repo        Repository
+	client      *http.Client
+	policy      TargetPolicy
+	maxAttempts int
+}
+
+// NewDispatcher создает обработчик очереди с maxAttempts попытками на доставку
+// (DefaultMaxAttempts, если maxAttempts <= 0). Соединения устанавливаются только
+// с адресами, которые допускает policy.
+func NewDispatcher(repo Repository, maxAttempts int, policy TargetPolicy) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   requestTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   policy.control,
+	}
+
+	return &Dispatcher{
+		repo:   repo,
+		policy: policy,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			// Прокси не используется: иначе проверялся бы адрес прокси, а не получателя
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: requestTimeout,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// Перенаправления не выполняются: ответ 3xx — неудачная попытка
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run разбирает очередь до отмены ctx. Доставки, прерванные остановкой, повторяются после
+// истечения их срока аренды — этим же или другим экземпляром сервиса.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		claimed, err := d.DeliverDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Failed to deliver webhooks", "error", err)
+		}
+
+		// Полная пачка — в очереди, скорее всего, есть еще: следующий проход сразу
+		if claimed == batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue выполняет один проход по очереди: отправляет доставки, срок которых наступил,
+// и возвращает, сколько их было взято в работу
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	claimed, err := d.repo.ClaimWebhookDeliveries(ctx, now, now.Add(lease), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Получатели отвечают независимо друг от друга: медленный не задерживает остальных
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, outgoing := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.deliver(ctx, outgoing); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return len(claimed), errors.Join(errs...)
+}
+
+// deliver делает одну попытку доставки и записывает ее результат
+func (d *Dispatcher) deliver(ctx context.Context, outgoing *model.OutgoingWebhook) error {
+	delivery := outgoing.Delivery
+	started := time.Now().UTC()
+
+	status, err := d.send(ctx, outgoing, started)
+	if ctx.Err() != nil {
+		// Остановка сервиса — не ошибка получателя: попытка не засчитывается
+		return ctx.Err()
+	}
+
+	attempt := &model.WebhookAttempt{
+		DeliveryID:  delivery.ID,
+		AttemptedAt: started,
+		Duration:    int(time.Since(started).Milliseconds()),
+	}
+	if status != 0 {
+		attempt.ResponseStatus = &status
+	}
+	if err == nil && (status < 200 || status > 299) {
+		err = fmt.Errorf("unexpected response status %d", status)
+	}
+
+	if err == nil {
+		deliveredAt := time.Now().UTC()
+		return d.repo.RecordWebhookAttempt(ctx, attempt, model.WebhookDeliverySucceeded, nil, &deliveredAt)
+	}
+
+	message := err.Error()
+	attempt.Error = &message
+
+	attempts := delivery.Attempts + 1
+	if attempts >= d.maxAttempts {
+		return d.repo.RecordWebhookAttempt(ctx, attempt, model.WebhookDeliveryFailed, nil, nil)
+	}
+
+	nextAttemptAt := time.Now().UTC().Add(Backoff(attempts))
+	return d.repo.RecordWebhookAttempt(ctx, attempt, model.WebhookDeliveryPending, &nextAttemptAt, nil)
+}
+
+// send отправляет событие получателю и возвращает статус ответа
+func (d *Dispatcher) send(ctx context.Context, outgoing *model.OutgoingWebhook, now time.Time) (int, error) {
+	delivery := outgoing.Delivery
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, outgoing.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	// Вебхук мог быть зарегистрирован с http до того, как это запретили
+	if !d.policy.AllowInsecure && req.URL.Scheme != "https" {
+		return 0, ErrInsecureURL
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "subscription-service-webhooks/1.0")
+	req.Header.Set(HeaderWebhookID, delivery.WebhookID.String())
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(outgoing.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Тело ответа не нужно, но дочитанное соединение возвращается в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает значение заголовка X-Webhook-Signature для тела payload, отправленного в момент t.
+// Получатель вычисляет HMAC-SHA256 от "<t>.<тело>" своим ключом и сравнивает с v1; по t он может
+// отклонить устаревший запрос, повторно отправленный третьей стороной.
+func Sign(secret string, t time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff возвращает задержку перед следующей попыткой после attempts неудачных
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
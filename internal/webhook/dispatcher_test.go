@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"context"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	logger.Init("error", "text")
+}
+
+// localTargets разрешает доставку на httptest-сервер по http на 127.0.0.1
+var localTargets = TargetPolicy{AllowInsecure: true}
+
+type recordedAttempt struct {
+	attempt       *model.WebhookAttempt
+	status        string
+	nextAttemptAt *time.Time
+	deliveredAt   *time.Time
+}
+
+// fakeRepository отдает заданные доставки один раз и запоминает записанные попытки
+type fakeRepository struct {
+	mu       sync.Mutex
+	due      []*model.OutgoingWebhook
+	limit    int
+	lease    time.Duration
+	attempts []recordedAttempt
+}
+
+func (r *fakeRepository) ClaimWebhookDeliveries(_ context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutgoingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limit = limit
+	r.lease = leaseUntil.Sub(now)
+	claimed := r.due
+	r.due = nil
+	return claimed, nil
+}
+
+func (r *fakeRepository) RecordWebhookAttempt(_ context.Context, attempt *model.WebhookAttempt, status string, nextAttemptAt, deliveredAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts = append(r.attempts, recordedAttempt{attempt, status, nextAttemptAt, deliveredAt})
+	return nil
+}
+
+func newOutgoing(url string, attempts int) *model.OutgoingWebhook {
+	return &model.OutgoingWebhook{
+		Delivery: &model.WebhookDelivery{
+			ID:        uuid.New(),
+			WebhookID: uuid.New(),
+			EventID:   uuid.New(),
+			EventType: model.SubscriptionEventCreated,
+			Payload:   []byte(`{"type":"subscription.created"}`),
+			Status:    model.WebhookDeliveryPending,
+			Attempts:  attempts,
+		},
+		URL:    url,
+		Secret: "0123456789abcdef",
+	}
+}
+
+func TestDeliverDue_SignedDelivery(t *testing.T) {
+	outgoing := newOutgoing("", 0)
+	delivery := outgoing.Delivery
+
+	var received *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	outgoing.URL = receiver.URL
+
+	repo := &fakeRepository{due: []*model.OutgoingWebhook{outgoing}}
+	claimed, err := NewDispatcher(repo, 0, localTargets).DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, batchSize, repo.limit)
+	assert.Equal(t, lease, repo.lease)
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, delivery.WebhookID.String(), received.Header.Get(HeaderWebhookID))
+	assert.Equal(t, model.SubscriptionEventCreated, received.Header.Get(HeaderEvent))
+	assert.Equal(t, delivery.EventID.String(), received.Header.Get(HeaderEventID))
+	assert.Equal(t, delivery.ID.String(), received.Header.Get(HeaderDelivery))
+	assert.Equal(t, string(delivery.Payload), string(body))
+
+	// Получатель проверяет подпись своим ключом по времени из заголовка
+	signature := received.Header.Get(HeaderSignature)
+	timestamp, _, ok := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	require.True(t, ok, signature)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign(outgoing.Secret, time.Unix(unix, 0), body), signature)
+	assert.NotEqual(t, Sign("another-secret-key", time.Unix(unix, 0), body), signature)
+
+	require.Len(t, repo.attempts, 1)
+	recorded := repo.attempts[0]
+	assert.Equal(t, model.WebhookDeliverySucceeded, recorded.status)
+	assert.Equal(t, delivery.ID, recorded.attempt.DeliveryID)
+	assert.Equal(t, http.StatusNoContent, *recorded.attempt.ResponseStatus)
+	assert.Nil(t, recorded.attempt.Error)
+	assert.Nil(t, recorded.nextAttemptAt)
+	assert.NotNil(t, recorded.deliveredAt)
+}
+
+func TestDeliverDue_RetriesWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer receiver.Close()
+
+	repo := &fakeRepository{due: []*model.OutgoingWebhook{newOutgoing(receiver.URL, 2)}}
+	_, err := NewDispatcher(repo, 5, localTargets).DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	// Перенаправление не выполняется и считается неудачей; это третья попытка
+	require.Len(t, repo.attempts, 1)
+	recorded := repo.attempts[0]
+	assert.Equal(t, model.WebhookDeliveryPending, recorded.status)
+	assert.Equal(t, http.StatusFound, *recorded.attempt.ResponseStatus)
+	assert.Equal(t, "unexpected response status 302", *recorded.attempt.Error)
+	require.NotNil(t, recorded.nextAttemptAt)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), *recorded.nextAttemptAt, 5*time.Second)
+	assert.Nil(t, recorded.deliveredAt)
+}
+
+func TestDeliverDue_FailsAfterLastAttempt(t *testing.T) {
+	// Получатель недоступен: соединение не устанавливается
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+
+	repo := &fakeRepository{due: []*model.OutgoingWebhook{newOutgoing(receiver.URL, 4)}}
+	_, err := NewDispatcher(repo, 5, localTargets).DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, repo.attempts, 1)
+	recorded := repo.attempts[0]
+	assert.Equal(t, model.WebhookDeliveryFailed, recorded.status)
+	assert.Nil(t, recorded.attempt.ResponseStatus)
+	require.NotNil(t, recorded.attempt.Error)
+	assert.Contains(t, *recorded.attempt.Error, "connection refused")
+	assert.Nil(t, recorded.nextAttemptAt)
+}
+
+func TestDeliverDue_RejectsForbiddenAddressOnDial(t *testing.T) {
+	// Имя хоста вебхука могло после регистрации начать разрешаться в закрытый адрес
+	var called bool
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	repo := &fakeRepository{due: []*model.OutgoingWebhook{newOutgoing(receiver.URL, 0)}}
+	_, err := NewDispatcher(repo, 0, TargetPolicy{}).DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, called)
+	require.Len(t, repo.attempts, 1)
+	require.NotNil(t, repo.attempts[0].attempt.Error)
+	assert.Contains(t, *repo.attempts[0].attempt.Error, ErrForbiddenAddress.Error())
+}
+
+func TestDeliverDue_StopDoesNotCountAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// Сервис останавливается, пока получатель обрабатывает запрос
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+	}))
+	defer receiver.Close()
+
+	repo := &fakeRepository{due: []*model.OutgoingWebhook{newOutgoing(receiver.URL, 0)}}
+	_, err := NewDispatcher(repo, 0, localTargets).DeliverDue(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, repo.attempts)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 4*time.Minute, Backoff(4))
+	assert.Equal(t, 256*time.Minute, Backoff(10))
+	assert.Equal(t, 6*time.Hour, Backoff(11))
+	assert.Equal(t, 6*time.Hour, Backoff(100))
+}
+
+func TestSign(t *testing.T) {
+	// Эталон: echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163",
+		Sign("secret", time.Unix(1700000000, 0), []byte("{}")))
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+var (
+	// ErrInsecureURL — URL не абсолютный или его схема не https (http разрешен только с AllowInsecure)
+	ErrInsecureURL = errors.New("webhook url must be an absolute https URL")
+	// ErrForbiddenAddress — адрес получателя в локальной, частной или служебной сети
+	ErrForbiddenAddress = errors.New("webhook address is not allowed")
+)
+
+// sharedAddressSpace — 100.64.0.0/10 (RFC 6598): адреса провайдерских NAT, снаружи не маршрутизируются
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Resolver разрешает имя хоста в адреса; реализуется *net.Resolver
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// TargetPolicy — какие адреса допустимы для вебхуков. Без AllowInsecure вебхук должен быть
+// https-адресом в публичной сети: иначе через вебхук можно обращаться к внутренним сервисам
+// и метаданным облака (169.254.169.254). Проверка выполняется при регистрации по адресам,
+// в которые разрешается имя хоста, и повторно при каждом соединении — имя могло измениться.
+type TargetPolicy struct {
+	// AllowInsecure разрешает http и любые адреса — для локальной разработки и тестов
+	AllowInsecure bool
+	// Resolver разрешает имена при регистрации; nil — net.DefaultResolver
+	Resolver Resolver
+}
+
+// CheckURL проверяет URL вебхука при регистрации
+func (p TargetPolicy) CheckURL(ctx context.Context, raw string) error {
+	target, err := url.Parse(raw)
+	if err != nil || target.Host == "" || target.Hostname() == "" {
+		return ErrInsecureURL
+	}
+
+	if p.AllowInsecure {
+		if target.Scheme != "http" && target.Scheme != "https" {
+			return ErrInsecureURL
+		}
+		return nil
+	}
+
+	if target.Scheme != "https" {
+		return ErrInsecureURL
+	}
+
+	host := target.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		return checkIP(ip)
+	}
+
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: %s does not resolve", ErrForbiddenAddress, host)
+	}
+
+	for _, addr := range addrs {
+		if err := checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// control проверяет адрес, с которым устанавливается соединение (net.Dialer.Control):
+// так запрос не уйдет во внутреннюю сеть, даже если имя хоста после регистрации стало
+// разрешаться в закрытый адрес
+func (p TargetPolicy) control(_, address string, _ syscall.RawConn) error {
+	if p.AllowInsecure {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+
+	return checkIP(ip)
+}
+
+func checkIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// staticResolver разрешает имена по таблице без обращения к DNS
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestTargetPolicy_CheckURL(t *testing.T) {
+	policy := TargetPolicy{Resolver: staticResolver{
+		"hooks.example.com": {"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"},
+		"internal.example":  {"93.184.216.34", "10.0.0.5"},
+	}}
+
+	tests := []struct {
+		name string
+		url  string
+		err  error
+	}{
+		{"public host", "https://hooks.example.com/subscriptions", nil},
+		{"public ip", "https://93.184.216.34:8443/hooks", nil},
+		{"http", "http://hooks.example.com/subscriptions", ErrInsecureURL},
+		{"relative", "/hooks", ErrInsecureURL},
+		{"no host", "https:///hooks", ErrInsecureURL},
+		{"loopback", "https://127.0.0.1/hooks", ErrForbiddenAddress},
+		{"ipv6 loopback", "https://[::1]/hooks", ErrForbiddenAddress},
+		{"ipv4-mapped loopback", "https://[::ffff:127.0.0.1]/hooks", ErrForbiddenAddress},
+		{"private", "https://192.168.1.10/hooks", ErrForbiddenAddress},
+		{"cloud metadata", "https://169.254.169.254/latest/meta-data", ErrForbiddenAddress},
+		{"shared address space", "https://100.64.0.1/hooks", ErrForbiddenAddress},
+		{"unspecified", "https://0.0.0.0/hooks", ErrForbiddenAddress},
+		{"resolves to private", "https://internal.example/hooks", ErrForbiddenAddress},
+		{"does not resolve", "https://unknown.example/hooks", ErrForbiddenAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.CheckURL(context.Background(), tt.url)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestTargetPolicy_AllowInsecure(t *testing.T) {
+	policy := TargetPolicy{AllowInsecure: true}
+
+	assert.NoError(t, policy.CheckURL(context.Background(), "http://localhost:8080/hooks"))
+	assert.ErrorIs(t, policy.CheckURL(context.Background(), "ftp://localhost/hooks"), ErrInsecureURL)
+	assert.NoError(t, policy.control("tcp", "127.0.0.1:8080", nil))
+}
+
+func TestTargetPolicy_Control(t *testing.T) {
+	var policy TargetPolicy
+
+	assert.NoError(t, policy.control("tcp", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, policy.control("tcp", "169.254.169.254:80", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, policy.control("tcp6", "[fd00::1]:443", nil), ErrForbiddenAddress)
+}
@@ -0,0 +1,3833 @@
+// gRPC API сервиса подписок. Операции повторяют REST API /api/v1; ошибки возвращаются
+// статусами gRPC с деталями google.rpc.ErrorInfo (reason — тот же код, что и поле code в
+// ответах application/problem+json) и google.rpc.BadRequest для нарушений по полям.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: subscription/v1/subscription.proto
+
+package subscriptionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BatchMode int32
+
+const (
+	// BATCH_MODE_UNSPECIFIED равнозначен BATCH_MODE_ATOMIC
+	BatchMode_BATCH_MODE_UNSPECIFIED BatchMode = 0
+	BatchMode_BATCH_MODE_ATOMIC      BatchMode = 1
+	BatchMode_BATCH_MODE_BEST_EFFORT BatchMode = 2
+)
+
+// Enum value maps for BatchMode.
+var (
+	BatchMode_name = map[int32]string{
+		0: "BATCH_MODE_UNSPECIFIED",
+		1: "BATCH_MODE_ATOMIC",
+		2: "BATCH_MODE_BEST_EFFORT",
+	}
+	BatchMode_value = map[string]int32{
+		"BATCH_MODE_UNSPECIFIED": 0,
+		"BATCH_MODE_ATOMIC":      1,
+		"BATCH_MODE_BEST_EFFORT": 2,
+	}
+)
+
+func (x BatchMode) Enum() *BatchMode {
+	p := new(BatchMode)
+	*p = x
+	return p
+}
+
+func (x BatchMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[0].Descriptor()
+}
+
+func (BatchMode) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[0]
+}
+
+func (x BatchMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchMode.Descriptor instead.
+func (BatchMode) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+type BatchOperationType int32
+
+const (
+	BatchOperationType_BATCH_OPERATION_TYPE_UNSPECIFIED BatchOperationType = 0
+	BatchOperationType_BATCH_OPERATION_TYPE_CREATE      BatchOperationType = 1
+	BatchOperationType_BATCH_OPERATION_TYPE_UPDATE      BatchOperationType = 2
+	BatchOperationType_BATCH_OPERATION_TYPE_DELETE      BatchOperationType = 3
+)
+
+// Enum value maps for BatchOperationType.
+var (
+	BatchOperationType_name = map[int32]string{
+		0: "BATCH_OPERATION_TYPE_UNSPECIFIED",
+		1: "BATCH_OPERATION_TYPE_CREATE",
+		2: "BATCH_OPERATION_TYPE_UPDATE",
+		3: "BATCH_OPERATION_TYPE_DELETE",
+	}
+	BatchOperationType_value = map[string]int32{
+		"BATCH_OPERATION_TYPE_UNSPECIFIED": 0,
+		"BATCH_OPERATION_TYPE_CREATE":      1,
+		"BATCH_OPERATION_TYPE_UPDATE":      2,
+		"BATCH_OPERATION_TYPE_DELETE":      3,
+	}
+)
+
+func (x BatchOperationType) Enum() *BatchOperationType {
+	p := new(BatchOperationType)
+	*p = x
+	return p
+}
+
+func (x BatchOperationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchOperationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[1].Descriptor()
+}
+
+func (BatchOperationType) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[1]
+}
+
+func (x BatchOperationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchOperationType.Descriptor instead.
+func (BatchOperationType) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+type BatchItemStatus int32
+
+const (
+	BatchItemStatus_BATCH_ITEM_STATUS_UNSPECIFIED BatchItemStatus = 0
+	BatchItemStatus_BATCH_ITEM_STATUS_OK          BatchItemStatus = 1
+	BatchItemStatus_BATCH_ITEM_STATUS_FAILED      BatchItemStatus = 2
+	BatchItemStatus_BATCH_ITEM_STATUS_ROLLED_BACK BatchItemStatus = 3
+	BatchItemStatus_BATCH_ITEM_STATUS_SKIPPED     BatchItemStatus = 4
+)
+
+// Enum value maps for BatchItemStatus.
+var (
+	BatchItemStatus_name = map[int32]string{
+		0: "BATCH_ITEM_STATUS_UNSPECIFIED",
+		1: "BATCH_ITEM_STATUS_OK",
+		2: "BATCH_ITEM_STATUS_FAILED",
+		3: "BATCH_ITEM_STATUS_ROLLED_BACK",
+		4: "BATCH_ITEM_STATUS_SKIPPED",
+	}
+	BatchItemStatus_value = map[string]int32{
+		"BATCH_ITEM_STATUS_UNSPECIFIED": 0,
+		"BATCH_ITEM_STATUS_OK":          1,
+		"BATCH_ITEM_STATUS_FAILED":      2,
+		"BATCH_ITEM_STATUS_ROLLED_BACK": 3,
+		"BATCH_ITEM_STATUS_SKIPPED":     4,
+	}
+)
+
+func (x BatchItemStatus) Enum() *BatchItemStatus {
+	p := new(BatchItemStatus)
+	*p = x
+	return p
+}
+
+func (x BatchItemStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchItemStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[2].Descriptor()
+}
+
+func (BatchItemStatus) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[2]
+}
+
+func (x BatchItemStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchItemStatus.Descriptor instead.
+func (BatchItemStatus) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{2}
+}
+
+type SubscriptionStatus int32
+
+const (
+	SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED SubscriptionStatus = 0
+	SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE      SubscriptionStatus = 1
+	SubscriptionStatus_SUBSCRIPTION_STATUS_ENDED       SubscriptionStatus = 2
+	SubscriptionStatus_SUBSCRIPTION_STATUS_FUTURE      SubscriptionStatus = 3
+)
+
+// Enum value maps for SubscriptionStatus.
+var (
+	SubscriptionStatus_name = map[int32]string{
+		0: "SUBSCRIPTION_STATUS_UNSPECIFIED",
+		1: "SUBSCRIPTION_STATUS_ACTIVE",
+		2: "SUBSCRIPTION_STATUS_ENDED",
+		3: "SUBSCRIPTION_STATUS_FUTURE",
+	}
+	SubscriptionStatus_value = map[string]int32{
+		"SUBSCRIPTION_STATUS_UNSPECIFIED": 0,
+		"SUBSCRIPTION_STATUS_ACTIVE":      1,
+		"SUBSCRIPTION_STATUS_ENDED":       2,
+		"SUBSCRIPTION_STATUS_FUTURE":      3,
+	}
+)
+
+func (x SubscriptionStatus) Enum() *SubscriptionStatus {
+	p := new(SubscriptionStatus)
+	*p = x
+	return p
+}
+
+func (x SubscriptionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscriptionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[3].Descriptor()
+}
+
+func (SubscriptionStatus) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[3]
+}
+
+func (x SubscriptionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscriptionStatus.Descriptor instead.
+func (SubscriptionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{3}
+}
+
+type ImportRowStatus int32
+
+const (
+	ImportRowStatus_IMPORT_ROW_STATUS_UNSPECIFIED ImportRowStatus = 0
+	ImportRowStatus_IMPORT_ROW_STATUS_VALID       ImportRowStatus = 1
+	ImportRowStatus_IMPORT_ROW_STATUS_INVALID     ImportRowStatus = 2
+	ImportRowStatus_IMPORT_ROW_STATUS_CREATED     ImportRowStatus = 3
+	ImportRowStatus_IMPORT_ROW_STATUS_FAILED      ImportRowStatus = 4
+	ImportRowStatus_IMPORT_ROW_STATUS_ROLLED_BACK ImportRowStatus = 5
+	ImportRowStatus_IMPORT_ROW_STATUS_SKIPPED     ImportRowStatus = 6
+)
+
+// Enum value maps for ImportRowStatus.
+var (
+	ImportRowStatus_name = map[int32]string{
+		0: "IMPORT_ROW_STATUS_UNSPECIFIED",
+		1: "IMPORT_ROW_STATUS_VALID",
+		2: "IMPORT_ROW_STATUS_INVALID",
+		3: "IMPORT_ROW_STATUS_CREATED",
+		4: "IMPORT_ROW_STATUS_FAILED",
+		5: "IMPORT_ROW_STATUS_ROLLED_BACK",
+		6: "IMPORT_ROW_STATUS_SKIPPED",
+	}
+	ImportRowStatus_value = map[string]int32{
+		"IMPORT_ROW_STATUS_UNSPECIFIED": 0,
+		"IMPORT_ROW_STATUS_VALID":       1,
+		"IMPORT_ROW_STATUS_INVALID":     2,
+		"IMPORT_ROW_STATUS_CREATED":     3,
+		"IMPORT_ROW_STATUS_FAILED":      4,
+		"IMPORT_ROW_STATUS_ROLLED_BACK": 5,
+		"IMPORT_ROW_STATUS_SKIPPED":     6,
+	}
+)
+
+func (x ImportRowStatus) Enum() *ImportRowStatus {
+	p := new(ImportRowStatus)
+	*p = x
+	return p
+}
+
+func (x ImportRowStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportRowStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[4].Descriptor()
+}
+
+func (ImportRowStatus) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[4]
+}
+
+func (x ImportRowStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportRowStatus.Descriptor instead.
+func (ImportRowStatus) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{4}
+}
+
+type SummarySource int32
+
+const (
+	// SUMMARY_SOURCE_UNSPECIFIED равнозначен SUMMARY_SOURCE_SUBSCRIPTIONS
+	SummarySource_SUMMARY_SOURCE_UNSPECIFIED   SummarySource = 0
+	SummarySource_SUMMARY_SOURCE_SUBSCRIPTIONS SummarySource = 1
+	// SUMMARY_SOURCE_LEDGER — сумма по журналу списаний
+	SummarySource_SUMMARY_SOURCE_LEDGER SummarySource = 2
+)
+
+// Enum value maps for SummarySource.
+var (
+	SummarySource_name = map[int32]string{
+		0: "SUMMARY_SOURCE_UNSPECIFIED",
+		1: "SUMMARY_SOURCE_SUBSCRIPTIONS",
+		2: "SUMMARY_SOURCE_LEDGER",
+	}
+	SummarySource_value = map[string]int32{
+		"SUMMARY_SOURCE_UNSPECIFIED":   0,
+		"SUMMARY_SOURCE_SUBSCRIPTIONS": 1,
+		"SUMMARY_SOURCE_LEDGER":        2,
+	}
+)
+
+func (x SummarySource) Enum() *SummarySource {
+	p := new(SummarySource)
+	*p = x
+	return p
+}
+
+func (x SummarySource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SummarySource) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[5].Descriptor()
+}
+
+func (SummarySource) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[5]
+}
+
+func (x SummarySource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SummarySource.Descriptor instead.
+func (SummarySource) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{5}
+}
+
+type ChargeKind int32
+
+const (
+	ChargeKind_CHARGE_KIND_UNSPECIFIED ChargeKind = 0
+	// CHARGE_KIND_SUBSCRIPTION — регулярное списание по подписке
+	ChargeKind_CHARGE_KIND_SUBSCRIPTION ChargeKind = 1
+	ChargeKind_CHARGE_KIND_REFUND       ChargeKind = 2
+	ChargeKind_CHARGE_KIND_CREDIT       ChargeKind = 3
+	ChargeKind_CHARGE_KIND_CHARGE       ChargeKind = 4
+)
+
+// Enum value maps for ChargeKind.
+var (
+	ChargeKind_name = map[int32]string{
+		0: "CHARGE_KIND_UNSPECIFIED",
+		1: "CHARGE_KIND_SUBSCRIPTION",
+		2: "CHARGE_KIND_REFUND",
+		3: "CHARGE_KIND_CREDIT",
+		4: "CHARGE_KIND_CHARGE",
+	}
+	ChargeKind_value = map[string]int32{
+		"CHARGE_KIND_UNSPECIFIED":  0,
+		"CHARGE_KIND_SUBSCRIPTION": 1,
+		"CHARGE_KIND_REFUND":       2,
+		"CHARGE_KIND_CREDIT":       3,
+		"CHARGE_KIND_CHARGE":       4,
+	}
+)
+
+func (x ChargeKind) Enum() *ChargeKind {
+	p := new(ChargeKind)
+	*p = x
+	return p
+}
+
+func (x ChargeKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChargeKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[6].Descriptor()
+}
+
+func (ChargeKind) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[6]
+}
+
+func (x ChargeKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChargeKind.Descriptor instead.
+func (ChargeKind) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{6}
+}
+
+type AdjustmentKind int32
+
+const (
+	AdjustmentKind_ADJUSTMENT_KIND_UNSPECIFIED AdjustmentKind = 0
+	AdjustmentKind_ADJUSTMENT_KIND_REFUND      AdjustmentKind = 1
+	AdjustmentKind_ADJUSTMENT_KIND_CREDIT      AdjustmentKind = 2
+	AdjustmentKind_ADJUSTMENT_KIND_CHARGE      AdjustmentKind = 3
+)
+
+// Enum value maps for AdjustmentKind.
+var (
+	AdjustmentKind_name = map[int32]string{
+		0: "ADJUSTMENT_KIND_UNSPECIFIED",
+		1: "ADJUSTMENT_KIND_REFUND",
+		2: "ADJUSTMENT_KIND_CREDIT",
+		3: "ADJUSTMENT_KIND_CHARGE",
+	}
+	AdjustmentKind_value = map[string]int32{
+		"ADJUSTMENT_KIND_UNSPECIFIED": 0,
+		"ADJUSTMENT_KIND_REFUND":      1,
+		"ADJUSTMENT_KIND_CREDIT":      2,
+		"ADJUSTMENT_KIND_CHARGE":      3,
+	}
+)
+
+func (x AdjustmentKind) Enum() *AdjustmentKind {
+	p := new(AdjustmentKind)
+	*p = x
+	return p
+}
+
+func (x AdjustmentKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AdjustmentKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[7].Descriptor()
+}
+
+func (AdjustmentKind) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[7]
+}
+
+func (x AdjustmentKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AdjustmentKind.Descriptor instead.
+func (AdjustmentKind) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{7}
+}
+
+// Month — календарный месяц; даты подписок и периоды отчетов задаются с точностью до месяца.
+type Month struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Year  int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	// month — от 1 до 12
+	Month         int32 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Month) Reset() {
+	*x = Month{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Month) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Month) ProtoMessage() {}
+
+func (x *Month) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Month.ProtoReflect.Descriptor instead.
+func (*Month) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Month) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Month) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+type Subscription struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceName string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price       int64                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	UserId      string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartDate   *Month                 `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date не задан у бессрочной подписки
+	EndDate   *Month                 `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Category  *string                `protobuf:"bytes,7,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// version увеличивается при каждом изменении; используется в if_match_version
+	Version       int32 `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Subscription) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// SubscriptionInput — данные подписки при создании и изменении
+type SubscriptionInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price         int64                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartDate     *Month                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *Month                 `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Category      *string                `protobuf:"bytes,6,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionInput) Reset() {
+	*x = SubscriptionInput{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionInput) ProtoMessage() {}
+
+func (x *SubscriptionInput) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionInput.ProtoReflect.Descriptor instead.
+func (*SubscriptionInput) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubscriptionInput) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SubscriptionInput) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *SubscriptionInput) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *SubscriptionInput) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+type CreateSubscriptionRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Subscription *SubscriptionInput     `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	// force сохраняет подписку, несмотря на пересечение с существующими
+	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	// idempotency_key делает повтор запроса безопасным (как заголовок Idempotency-Key)
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateSubscriptionRequest) GetSubscription() *SubscriptionInput {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *CreateSubscriptionRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type CreateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Warnings      []string               `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionResponse) Reset() {
+	*x = CreateSubscriptionResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateSubscriptionRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Subscription *SubscriptionInput     `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	// if_match_version — ожидаемая версия подписки (как заголовок If-Match)
+	IfMatchVersion *int32 `protobuf:"varint,3,opt,name=if_match_version,json=ifMatchVersion,proto3,oneof" json:"if_match_version,omitempty"`
+	Force          bool   `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetSubscription() *SubscriptionInput {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetIfMatchVersion() int32 {
+	if x != nil && x.IfMatchVersion != nil {
+		return *x.IfMatchVersion
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type PatchSubscriptionRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Subscription *SubscriptionInput     `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	// update_mask — изменяемые поля SubscriptionInput: service_name, price, user_id,
+	// start_date, end_date, category
+	UpdateMask     *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	IfMatchVersion *int32                 `protobuf:"varint,4,opt,name=if_match_version,json=ifMatchVersion,proto3,oneof" json:"if_match_version,omitempty"`
+	Force          bool                   `protobuf:"varint,5,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PatchSubscriptionRequest) Reset() {
+	*x = PatchSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchSubscriptionRequest) ProtoMessage() {}
+
+func (x *PatchSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*PatchSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PatchSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PatchSubscriptionRequest) GetSubscription() *SubscriptionInput {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *PatchSubscriptionRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *PatchSubscriptionRequest) GetIfMatchVersion() int32 {
+	if x != nil && x.IfMatchVersion != nil {
+		return *x.IfMatchVersion
+	}
+	return 0
+}
+
+func (x *PatchSubscriptionRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type DeleteSubscriptionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IfMatchVersion *int32                 `protobuf:"varint,2,opt,name=if_match_version,json=ifMatchVersion,proto3,oneof" json:"if_match_version,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteSubscriptionRequest) GetIfMatchVersion() int32 {
+	if x != nil && x.IfMatchVersion != nil {
+		return *x.IfMatchVersion
+	}
+	return 0
+}
+
+// BatchOperation — для create передается subscription, для update — id и subscription
+// (полная замена), для delete — id. if_match_version для update и delete работает как If-Match.
+type BatchOperation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Op             BatchOperationType     `protobuf:"varint,1,opt,name=op,proto3,enum=subscription.v1.BatchOperationType" json:"op,omitempty"`
+	Id             string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	IfMatchVersion *int32                 `protobuf:"varint,3,opt,name=if_match_version,json=ifMatchVersion,proto3,oneof" json:"if_match_version,omitempty"`
+	Subscription   *SubscriptionInput     `protobuf:"bytes,4,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchOperation) Reset() {
+	*x = BatchOperation{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOperation) ProtoMessage() {}
+
+func (x *BatchOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOperation.ProtoReflect.Descriptor instead.
+func (*BatchOperation) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchOperation) GetOp() BatchOperationType {
+	if x != nil {
+		return x.Op
+	}
+	return BatchOperationType_BATCH_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *BatchOperation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchOperation) GetIfMatchVersion() int32 {
+	if x != nil && x.IfMatchVersion != nil {
+		return *x.IfMatchVersion
+	}
+	return 0
+}
+
+func (x *BatchOperation) GetSubscription() *SubscriptionInput {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type BatchSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          BatchMode              `protobuf:"varint,1,opt,name=mode,proto3,enum=subscription.v1.BatchMode" json:"mode,omitempty"`
+	Operations    []*BatchOperation      `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchSubscriptionsRequest) Reset() {
+	*x = BatchSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSubscriptionsRequest) ProtoMessage() {}
+
+func (x *BatchSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*BatchSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchSubscriptionsRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_BATCH_MODE_UNSPECIFIED
+}
+
+func (x *BatchSubscriptionsRequest) GetOperations() []*BatchOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *BatchSubscriptionsRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type BatchItemResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Op            BatchOperationType     `protobuf:"varint,2,opt,name=op,proto3,enum=subscription.v1.BatchOperationType" json:"op,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Status        BatchItemStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=subscription.v1.BatchItemStatus" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Code          string                 `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
+	Subscription  *Subscription          `protobuf:"bytes,7,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Warnings      []string               `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemResult) ProtoMessage() {}
+
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchItemResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetOp() BatchOperationType {
+	if x != nil {
+		return x.Op
+	}
+	return BatchOperationType_BATCH_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *BatchItemResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetStatus() BatchItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BatchItemStatus_BATCH_ITEM_STATUS_UNSPECIFIED
+}
+
+func (x *BatchItemResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *BatchItemResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type BatchSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Committed     bool                   `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	Results       []*BatchItemResult     `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchSubscriptionsResponse) Reset() {
+	*x = BatchSubscriptionsResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSubscriptionsResponse) ProtoMessage() {}
+
+func (x *BatchSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*BatchSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchSubscriptionsResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *BatchSubscriptionsResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// SubscriptionFilter — фильтры списка подписок (как параметры GET /subscriptions)
+type SubscriptionFilter struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserId       *string                `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceNames []string               `protobuf:"bytes,2,rep,name=service_names,json=serviceNames,proto3" json:"service_names,omitempty"`
+	// query — подстрока названия сервиса или категории без учета регистра
+	Query     *string `protobuf:"bytes,3,opt,name=query,proto3,oneof" json:"query,omitempty"`
+	ActiveAt  *Month  `protobuf:"bytes,4,opt,name=active_at,json=activeAt,proto3" json:"active_at,omitempty"`
+	PriceMin  *int64  `protobuf:"varint,5,opt,name=price_min,json=priceMin,proto3,oneof" json:"price_min,omitempty"`
+	PriceMax  *int64  `protobuf:"varint,6,opt,name=price_max,json=priceMax,proto3,oneof" json:"price_max,omitempty"`
+	StartFrom *Month  `protobuf:"bytes,7,opt,name=start_from,json=startFrom,proto3" json:"start_from,omitempty"`
+	StartTo   *Month  `protobuf:"bytes,8,opt,name=start_to,json=startTo,proto3" json:"start_to,omitempty"`
+	EndFrom   *Month  `protobuf:"bytes,9,opt,name=end_from,json=endFrom,proto3" json:"end_from,omitempty"`
+	EndTo     *Month  `protobuf:"bytes,10,opt,name=end_to,json=endTo,proto3" json:"end_to,omitempty"`
+	// status — статус относительно текущего месяца
+	Status SubscriptionStatus `protobuf:"varint,11,opt,name=status,proto3,enum=subscription.v1.SubscriptionStatus" json:"status,omitempty"`
+	// updated_since — подписки, измененные в этот момент или позже
+	UpdatedSince  *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionFilter) Reset() {
+	*x = SubscriptionFilter{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionFilter) ProtoMessage() {}
+
+func (x *SubscriptionFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionFilter.ProtoReflect.Descriptor instead.
+func (*SubscriptionFilter) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscriptionFilter) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetServiceNames() []string {
+	if x != nil {
+		return x.ServiceNames
+	}
+	return nil
+}
+
+func (x *SubscriptionFilter) GetQuery() string {
+	if x != nil && x.Query != nil {
+		return *x.Query
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetActiveAt() *Month {
+	if x != nil {
+		return x.ActiveAt
+	}
+	return nil
+}
+
+func (x *SubscriptionFilter) GetPriceMin() int64 {
+	if x != nil && x.PriceMin != nil {
+		return *x.PriceMin
+	}
+	return 0
+}
+
+func (x *SubscriptionFilter) GetPriceMax() int64 {
+	if x != nil && x.PriceMax != nil {
+		return *x.PriceMax
+	}
+	return 0
+}
+
+func (x *SubscriptionFilter) GetStartFrom() *Month {
+	if x != nil {
+		return x.StartFrom
+	}
+	return nil
+}
+
+func (x *SubscriptionFilter) GetStartTo() *Month {
+	if x != nil {
+		return x.StartTo
+	}
+	return nil
+}
+
+func (x *SubscriptionFilter) GetEndFrom() *Month {
+	if x != nil {
+		return x.EndFrom
+	}
+	return nil
+}
+
+func (x *SubscriptionFilter) GetEndTo() *Month {
+	if x != nil {
+		return x.EndTo
+	}
+	return nil
+}
+
+func (x *SubscriptionFilter) GetStatus() SubscriptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED
+}
+
+func (x *SubscriptionFilter) GetUpdatedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return nil
+}
+
+type SortField struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// field — price, start_date, end_date, service_name или created_at
+	Field         string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Desc          bool   `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SortField) Reset() {
+	*x = SortField{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SortField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortField) ProtoMessage() {}
+
+func (x *SortField) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortField.ProtoReflect.Descriptor instead.
+func (*SortField) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SortField) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SortField) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+type ListSubscriptionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *SubscriptionFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// sort — порядок выдачи; по умолчанию от новых подписок к старым
+	Sort []*SortField `protobuf:"bytes,2,rep,name=sort,proto3" json:"sort,omitempty"`
+	// page_size — по умолчанию 50, максимум 500
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token — next_page_token предыдущей страницы (действителен только при том же sort)
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListSubscriptionsRequest) GetFilter() *SubscriptionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListSubscriptionsRequest) GetSort() []*SortField {
+	if x != nil {
+		return x.Sort
+	}
+	return nil
+}
+
+func (x *ListSubscriptionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *ListSubscriptionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ExportSubscriptionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *SubscriptionFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// sort — порядок выгрузки; по умолчанию по updated_at, затем по id
+	Sort          []*SortField `protobuf:"bytes,2,rep,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportSubscriptionsRequest) Reset() {
+	*x = ExportSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ExportSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ExportSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExportSubscriptionsRequest) GetFilter() *SubscriptionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ExportSubscriptionsRequest) GetSort() []*SortField {
+	if x != nil {
+		return x.Sort
+	}
+	return nil
+}
+
+type ImportSubscriptionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// rows — строки импорта; номер строки в отчете — позиция в списке, начиная с 1
+	Rows []*SubscriptionInput `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	// dry_run — только проверить строки, ничего не сохраняя
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// resume_from — номер строки, с которой продолжить импорт; предыдущие строки пропускаются
+	ResumeFrom    int32 `protobuf:"varint,3,opt,name=resume_from,json=resumeFrom,proto3" json:"resume_from,omitempty"`
+	Force         bool  `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSubscriptionsRequest) Reset() {
+	*x = ImportSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ImportSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ImportSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ImportSubscriptionsRequest) GetRows() []*SubscriptionInput {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *ImportSubscriptionsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportSubscriptionsRequest) GetResumeFrom() int32 {
+	if x != nil {
+		return x.ResumeFrom
+	}
+	return 0
+}
+
+func (x *ImportSubscriptionsRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ImportRowResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Status        ImportRowStatus        `protobuf:"varint,2,opt,name=status,proto3,enum=subscription.v1.ImportRowStatus" json:"status,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Errors        []*FieldViolation      `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`
+	Warnings      []string               `protobuf:"bytes,7,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowResult) Reset() {
+	*x = ImportRowResult{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowResult) ProtoMessage() {}
+
+func (x *ImportRowResult) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowResult.ProtoReflect.Descriptor instead.
+func (*ImportRowResult) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ImportRowResult) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowResult) GetStatus() ImportRowStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ImportRowStatus_IMPORT_ROW_STATUS_UNSPECIFIED
+}
+
+func (x *ImportRowResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetErrors() []*FieldViolation {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ImportRowResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type ImportReport struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	DryRun  bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Total   int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Valid   int32                  `protobuf:"varint,3,opt,name=valid,proto3" json:"valid,omitempty"`
+	Invalid int32                  `protobuf:"varint,4,opt,name=invalid,proto3" json:"invalid,omitempty"`
+	Created int32                  `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
+	// resume_from — строка, с которой повторить импорт после ошибки (0 — не требуется)
+	ResumeFrom    int32              `protobuf:"varint,6,opt,name=resume_from,json=resumeFrom,proto3" json:"resume_from,omitempty"`
+	Rows          []*ImportRowResult `protobuf:"bytes,7,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportReport) Reset() {
+	*x = ImportReport{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportReport) ProtoMessage() {}
+
+func (x *ImportReport) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportReport.ProtoReflect.Descriptor instead.
+func (*ImportReport) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImportReport) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportReport) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ImportReport) GetValid() int32 {
+	if x != nil {
+		return x.Valid
+	}
+	return 0
+}
+
+func (x *ImportReport) GetInvalid() int32 {
+	if x != nil {
+		return x.Invalid
+	}
+	return 0
+}
+
+func (x *ImportReport) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportReport) GetResumeFrom() int32 {
+	if x != nil {
+		return x.ResumeFrom
+	}
+	return 0
+}
+
+func (x *ImportReport) GetRows() []*ImportRowResult {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type CalculateSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     *Month                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *Month                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Source        SummarySource          `protobuf:"varint,5,opt,name=source,proto3,enum=subscription.v1.SummarySource" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateSummaryRequest) Reset() {
+	*x = CalculateSummaryRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateSummaryRequest) ProtoMessage() {}
+
+func (x *CalculateSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateSummaryRequest.ProtoReflect.Descriptor instead.
+func (*CalculateSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CalculateSummaryRequest) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CalculateSummaryRequest) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *CalculateSummaryRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *CalculateSummaryRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *CalculateSummaryRequest) GetSource() SummarySource {
+	if x != nil {
+		return x.Source
+	}
+	return SummarySource_SUMMARY_SOURCE_UNSPECIFIED
+}
+
+type Summary struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TotalAmount int64                  `protobuf:"varint,1,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	Count       int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// adjustments_amount — сумма корректировок за период (уже учтена в total_amount)
+	AdjustmentsAmount int64 `protobuf:"varint,3,opt,name=adjustments_amount,json=adjustmentsAmount,proto3" json:"adjustments_amount,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Summary) GetTotalAmount() int64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *Summary) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Summary) GetAdjustmentsAmount() int64 {
+	if x != nil {
+		return x.AdjustmentsAmount
+	}
+	return 0
+}
+
+type SubscriptionOverlap struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName    string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,3,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	OverlappingId  string                 `protobuf:"bytes,4,opt,name=overlapping_id,json=overlappingId,proto3" json:"overlapping_id,omitempty"`
+	OverlapStart   *Month                 `protobuf:"bytes,5,opt,name=overlap_start,json=overlapStart,proto3" json:"overlap_start,omitempty"`
+	// overlap_end не задан, если обе подписки бессрочные
+	OverlapEnd    *Month `protobuf:"bytes,6,opt,name=overlap_end,json=overlapEnd,proto3" json:"overlap_end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionOverlap) Reset() {
+	*x = SubscriptionOverlap{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionOverlap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionOverlap) ProtoMessage() {}
+
+func (x *SubscriptionOverlap) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionOverlap.ProtoReflect.Descriptor instead.
+func (*SubscriptionOverlap) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SubscriptionOverlap) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscriptionOverlap) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionOverlap) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SubscriptionOverlap) GetOverlappingId() string {
+	if x != nil {
+		return x.OverlappingId
+	}
+	return ""
+}
+
+func (x *SubscriptionOverlap) GetOverlapStart() *Month {
+	if x != nil {
+		return x.OverlapStart
+	}
+	return nil
+}
+
+func (x *SubscriptionOverlap) GetOverlapEnd() *Month {
+	if x != nil {
+		return x.OverlapEnd
+	}
+	return nil
+}
+
+type ListOverlapsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Overlaps      []*SubscriptionOverlap `protobuf:"bytes,1,rep,name=overlaps,proto3" json:"overlaps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOverlapsResponse) Reset() {
+	*x = ListOverlapsResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOverlapsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOverlapsResponse) ProtoMessage() {}
+
+func (x *ListOverlapsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOverlapsResponse.ProtoReflect.Descriptor instead.
+func (*ListOverlapsResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListOverlapsResponse) GetOverlaps() []*SubscriptionOverlap {
+	if x != nil {
+		return x.Overlaps
+	}
+	return nil
+}
+
+type Budget struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// category не задана у общего бюджета на все подписки пользователя
+	Category      *string                `protobuf:"bytes,3,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	MonthlyLimit  int64                  `protobuf:"varint,4,opt,name=monthly_limit,json=monthlyLimit,proto3" json:"monthly_limit,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Budget) Reset() {
+	*x = Budget{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Budget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Budget) ProtoMessage() {}
+
+func (x *Budget) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Budget.ProtoReflect.Descriptor instead.
+func (*Budget) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Budget) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Budget) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Budget) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *Budget) GetMonthlyLimit() int64 {
+	if x != nil {
+		return x.MonthlyLimit
+	}
+	return 0
+}
+
+func (x *Budget) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Budget) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SetBudgetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Category      *string                `protobuf:"bytes,2,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	MonthlyLimit  int64                  `protobuf:"varint,3,opt,name=monthly_limit,json=monthlyLimit,proto3" json:"monthly_limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBudgetRequest) Reset() {
+	*x = SetBudgetRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBudgetRequest) ProtoMessage() {}
+
+func (x *SetBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBudgetRequest.ProtoReflect.Descriptor instead.
+func (*SetBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SetBudgetRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetBudgetRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *SetBudgetRequest) GetMonthlyLimit() int64 {
+	if x != nil {
+		return x.MonthlyLimit
+	}
+	return 0
+}
+
+type ListBudgetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBudgetsRequest) Reset() {
+	*x = ListBudgetsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBudgetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBudgetsRequest) ProtoMessage() {}
+
+func (x *ListBudgetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBudgetsRequest.ProtoReflect.Descriptor instead.
+func (*ListBudgetsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListBudgetsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListBudgetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Budgets       []*Budget              `protobuf:"bytes,1,rep,name=budgets,proto3" json:"budgets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBudgetsResponse) Reset() {
+	*x = ListBudgetsResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBudgetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBudgetsResponse) ProtoMessage() {}
+
+func (x *ListBudgetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBudgetsResponse.ProtoReflect.Descriptor instead.
+func (*ListBudgetsResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListBudgetsResponse) GetBudgets() []*Budget {
+	if x != nil {
+		return x.Budgets
+	}
+	return nil
+}
+
+type DeleteBudgetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBudgetRequest) Reset() {
+	*x = DeleteBudgetRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBudgetRequest) ProtoMessage() {}
+
+func (x *DeleteBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBudgetRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteBudgetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetBudgetReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartDate     *Month                 `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *Month                 `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBudgetReportRequest) Reset() {
+	*x = GetBudgetReportRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBudgetReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBudgetReportRequest) ProtoMessage() {}
+
+func (x *GetBudgetReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBudgetReportRequest.ProtoReflect.Descriptor instead.
+func (*GetBudgetReportRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetBudgetReportRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetBudgetReportRequest) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetBudgetReportRequest) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+type BudgetMonth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         *Month                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`
+	BudgetId      string                 `protobuf:"bytes,2,opt,name=budget_id,json=budgetId,proto3" json:"budget_id,omitempty"`
+	Category      *string                `protobuf:"bytes,3,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	MonthlyLimit  int64                  `protobuf:"varint,4,opt,name=monthly_limit,json=monthlyLimit,proto3" json:"monthly_limit,omitempty"`
+	Actual        int64                  `protobuf:"varint,5,opt,name=actual,proto3" json:"actual,omitempty"`
+	OverBudget    bool                   `protobuf:"varint,6,opt,name=over_budget,json=overBudget,proto3" json:"over_budget,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BudgetMonth) Reset() {
+	*x = BudgetMonth{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BudgetMonth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BudgetMonth) ProtoMessage() {}
+
+func (x *BudgetMonth) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BudgetMonth.ProtoReflect.Descriptor instead.
+func (*BudgetMonth) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BudgetMonth) GetMonth() *Month {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *BudgetMonth) GetBudgetId() string {
+	if x != nil {
+		return x.BudgetId
+	}
+	return ""
+}
+
+func (x *BudgetMonth) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *BudgetMonth) GetMonthlyLimit() int64 {
+	if x != nil {
+		return x.MonthlyLimit
+	}
+	return 0
+}
+
+func (x *BudgetMonth) GetActual() int64 {
+	if x != nil {
+		return x.Actual
+	}
+	return 0
+}
+
+func (x *BudgetMonth) GetOverBudget() bool {
+	if x != nil {
+		return x.OverBudget
+	}
+	return false
+}
+
+type GetBudgetReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Months        []*BudgetMonth         `protobuf:"bytes,1,rep,name=months,proto3" json:"months,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBudgetReportResponse) Reset() {
+	*x = GetBudgetReportResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBudgetReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBudgetReportResponse) ProtoMessage() {}
+
+func (x *GetBudgetReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBudgetReportResponse.ProtoReflect.Descriptor instead.
+func (*GetBudgetReportResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetBudgetReportResponse) GetMonths() []*BudgetMonth {
+	if x != nil {
+		return x.Months
+	}
+	return nil
+}
+
+type Charge struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName    string                 `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Category       *string                `protobuf:"bytes,5,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	Month          *Month                 `protobuf:"bytes,6,opt,name=month,proto3" json:"month,omitempty"`
+	// amount — со знаком: возвраты и кредиты отрицательные
+	Amount        int64                  `protobuf:"varint,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Kind          ChargeKind             `protobuf:"varint,8,opt,name=kind,proto3,enum=subscription.v1.ChargeKind" json:"kind,omitempty"`
+	Reason        *string                `protobuf:"bytes,9,opt,name=reason,proto3,oneof" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Charge) Reset() {
+	*x = Charge{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Charge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Charge) ProtoMessage() {}
+
+func (x *Charge) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Charge.ProtoReflect.Descriptor instead.
+func (*Charge) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Charge) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Charge) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *Charge) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Charge) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Charge) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *Charge) GetMonth() *Month {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *Charge) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Charge) GetKind() ChargeKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ChargeKind_CHARGE_KIND_UNSPECIFIED
+}
+
+func (x *Charge) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *Charge) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListChargesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         *string                `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	SubscriptionId *string                `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3,oneof" json:"subscription_id,omitempty"`
+	ServiceName    *string                `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	From           *Month                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To             *Month                 `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListChargesRequest) Reset() {
+	*x = ListChargesRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChargesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChargesRequest) ProtoMessage() {}
+
+func (x *ListChargesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChargesRequest.ProtoReflect.Descriptor instead.
+func (*ListChargesRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListChargesRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *ListChargesRequest) GetSubscriptionId() string {
+	if x != nil && x.SubscriptionId != nil {
+		return *x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ListChargesRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *ListChargesRequest) GetFrom() *Month {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListChargesRequest) GetTo() *Month {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ListChargesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Charges       []*Charge              `protobuf:"bytes,1,rep,name=charges,proto3" json:"charges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChargesResponse) Reset() {
+	*x = ListChargesResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChargesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChargesResponse) ProtoMessage() {}
+
+func (x *ListChargesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChargesResponse.ProtoReflect.Descriptor instead.
+func (*ListChargesResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListChargesResponse) GetCharges() []*Charge {
+	if x != nil {
+		return x.Charges
+	}
+	return nil
+}
+
+type Adjustment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Kind           AdjustmentKind         `protobuf:"varint,3,opt,name=kind,proto3,enum=subscription.v1.AdjustmentKind" json:"kind,omitempty"`
+	Amount         int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Date           *Month                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	Reason         string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Adjustment) Reset() {
+	*x = Adjustment{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Adjustment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Adjustment) ProtoMessage() {}
+
+func (x *Adjustment) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Adjustment.ProtoReflect.Descriptor instead.
+func (*Adjustment) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Adjustment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Adjustment) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *Adjustment) GetKind() AdjustmentKind {
+	if x != nil {
+		return x.Kind
+	}
+	return AdjustmentKind_ADJUSTMENT_KIND_UNSPECIFIED
+}
+
+func (x *Adjustment) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Adjustment) GetDate() *Month {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Adjustment) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Adjustment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateAdjustmentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Kind           AdjustmentKind         `protobuf:"varint,2,opt,name=kind,proto3,enum=subscription.v1.AdjustmentKind" json:"kind,omitempty"`
+	Amount         int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Date           *Month                 `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	Reason         string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateAdjustmentRequest) Reset() {
+	*x = CreateAdjustmentRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAdjustmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAdjustmentRequest) ProtoMessage() {}
+
+func (x *CreateAdjustmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAdjustmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateAdjustmentRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CreateAdjustmentRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *CreateAdjustmentRequest) GetKind() AdjustmentKind {
+	if x != nil {
+		return x.Kind
+	}
+	return AdjustmentKind_ADJUSTMENT_KIND_UNSPECIFIED
+}
+
+func (x *CreateAdjustmentRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreateAdjustmentRequest) GetDate() *Month {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *CreateAdjustmentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ListAdjustmentsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListAdjustmentsRequest) Reset() {
+	*x = ListAdjustmentsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdjustmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdjustmentsRequest) ProtoMessage() {}
+
+func (x *ListAdjustmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdjustmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAdjustmentsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListAdjustmentsRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type ListAdjustmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Adjustments   []*Adjustment          `protobuf:"bytes,1,rep,name=adjustments,proto3" json:"adjustments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAdjustmentsResponse) Reset() {
+	*x = ListAdjustmentsResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdjustmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdjustmentsResponse) ProtoMessage() {}
+
+func (x *ListAdjustmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdjustmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAdjustmentsResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListAdjustmentsResponse) GetAdjustments() []*Adjustment {
+	if x != nil {
+		return x.Adjustments
+	}
+	return nil
+}
+
+type DeleteAdjustmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAdjustmentRequest) Reset() {
+	*x = DeleteAdjustmentRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAdjustmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAdjustmentRequest) ProtoMessage() {}
+
+func (x *DeleteAdjustmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAdjustmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAdjustmentRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DeleteAdjustmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_subscription_v1_subscription_proto protoreflect.FileDescriptor
+
+const file_subscription_v1_subscription_proto_rawDesc = "" +
+	"\n" +
+	"\"subscription/v1/subscription.proto\x12\x0fsubscription.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"1\n" +
+	"\x05Month\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\x05R\x05month\"\x98\x03\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x03R\x05price\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x125\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\v2\x16.subscription.v1.MonthR\tstartDate\x121\n" +
+	"\bend_date\x18\x06 \x01(\v2\x16.subscription.v1.MonthR\aendDate\x12\x1f\n" +
+	"\bcategory\x18\a \x01(\tH\x00R\bcategory\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\x05R\aversionB\v\n" +
+	"\t_category\"\xfd\x01\n" +
+	"\x11SubscriptionInput\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x125\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\tstartDate\x121\n" +
+	"\bend_date\x18\x05 \x01(\v2\x16.subscription.v1.MonthR\aendDate\x12\x1f\n" +
+	"\bcategory\x18\x06 \x01(\tH\x00R\bcategory\x88\x01\x01B\v\n" +
+	"\t_category\"\xa2\x01\n" +
+	"\x19CreateSubscriptionRequest\x12F\n" +
+	"\fsubscription\x18\x01 \x01(\v2\".subscription.v1.SubscriptionInputR\fsubscription\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"{\n" +
+	"\x1aCreateSubscriptionResponse\x12A\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1d.subscription.v1.SubscriptionR\fsubscription\x12\x1a\n" +
+	"\bwarnings\x18\x02 \x03(\tR\bwarnings\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xcd\x01\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12F\n" +
+	"\fsubscription\x18\x02 \x01(\v2\".subscription.v1.SubscriptionInputR\fsubscription\x12-\n" +
+	"\x10if_match_version\x18\x03 \x01(\x05H\x00R\x0eifMatchVersion\x88\x01\x01\x12\x14\n" +
+	"\x05force\x18\x04 \x01(\bR\x05forceB\x13\n" +
+	"\x11_if_match_version\"\x89\x02\n" +
+	"\x18PatchSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12F\n" +
+	"\fsubscription\x18\x02 \x01(\v2\".subscription.v1.SubscriptionInputR\fsubscription\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12-\n" +
+	"\x10if_match_version\x18\x04 \x01(\x05H\x00R\x0eifMatchVersion\x88\x01\x01\x12\x14\n" +
+	"\x05force\x18\x05 \x01(\bR\x05forceB\x13\n" +
+	"\x11_if_match_version\"o\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
+	"\x10if_match_version\x18\x02 \x01(\x05H\x00R\x0eifMatchVersion\x88\x01\x01B\x13\n" +
+	"\x11_if_match_version\"\xe1\x01\n" +
+	"\x0eBatchOperation\x123\n" +
+	"\x02op\x18\x01 \x01(\x0e2#.subscription.v1.BatchOperationTypeR\x02op\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12-\n" +
+	"\x10if_match_version\x18\x03 \x01(\x05H\x00R\x0eifMatchVersion\x88\x01\x01\x12F\n" +
+	"\fsubscription\x18\x04 \x01(\v2\".subscription.v1.SubscriptionInputR\fsubscriptionB\x13\n" +
+	"\x11_if_match_version\"\xa2\x01\n" +
+	"\x19BatchSubscriptionsRequest\x12.\n" +
+	"\x04mode\x18\x01 \x01(\x0e2\x1a.subscription.v1.BatchModeR\x04mode\x12?\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\v2\x1f.subscription.v1.BatchOperationR\n" +
+	"operations\x12\x14\n" +
+	"\x05force\x18\x03 \x01(\bR\x05force\"\xaf\x02\n" +
+	"\x0fBatchItemResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x123\n" +
+	"\x02op\x18\x02 \x01(\x0e2#.subscription.v1.BatchOperationTypeR\x02op\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x128\n" +
+	"\x06status\x18\x04 \x01(\x0e2 .subscription.v1.BatchItemStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x12\n" +
+	"\x04code\x18\x06 \x01(\tR\x04code\x12A\n" +
+	"\fsubscription\x18\a \x01(\v2\x1d.subscription.v1.SubscriptionR\fsubscription\x12\x1a\n" +
+	"\bwarnings\x18\b \x03(\tR\bwarnings\"v\n" +
+	"\x1aBatchSubscriptionsResponse\x12\x1c\n" +
+	"\tcommitted\x18\x01 \x01(\bR\tcommitted\x12:\n" +
+	"\aresults\x18\x02 \x03(\v2 .subscription.v1.BatchItemResultR\aresults\"\xe7\x04\n" +
+	"\x12SubscriptionFilter\x12\x1c\n" +
+	"\auser_id\x18\x01 \x01(\tH\x00R\x06userId\x88\x01\x01\x12#\n" +
+	"\rservice_names\x18\x02 \x03(\tR\fserviceNames\x12\x19\n" +
+	"\x05query\x18\x03 \x01(\tH\x01R\x05query\x88\x01\x01\x123\n" +
+	"\tactive_at\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\bactiveAt\x12 \n" +
+	"\tprice_min\x18\x05 \x01(\x03H\x02R\bpriceMin\x88\x01\x01\x12 \n" +
+	"\tprice_max\x18\x06 \x01(\x03H\x03R\bpriceMax\x88\x01\x01\x125\n" +
+	"\n" +
+	"start_from\x18\a \x01(\v2\x16.subscription.v1.MonthR\tstartFrom\x121\n" +
+	"\bstart_to\x18\b \x01(\v2\x16.subscription.v1.MonthR\astartTo\x121\n" +
+	"\bend_from\x18\t \x01(\v2\x16.subscription.v1.MonthR\aendFrom\x12-\n" +
+	"\x06end_to\x18\n" +
+	" \x01(\v2\x16.subscription.v1.MonthR\x05endTo\x12;\n" +
+	"\x06status\x18\v \x01(\x0e2#.subscription.v1.SubscriptionStatusR\x06status\x12?\n" +
+	"\rupdated_since\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedSinceB\n" +
+	"\n" +
+	"\b_user_idB\b\n" +
+	"\x06_queryB\f\n" +
+	"\n" +
+	"_price_minB\f\n" +
+	"\n" +
+	"_price_max\"5\n" +
+	"\tSortField\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x12\n" +
+	"\x04desc\x18\x02 \x01(\bR\x04desc\"\xc3\x01\n" +
+	"\x18ListSubscriptionsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.subscription.v1.SubscriptionFilterR\x06filter\x12.\n" +
+	"\x04sort\x18\x02 \x03(\v2\x1a.subscription.v1.SortFieldR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x88\x01\n" +
+	"\x19ListSubscriptionsResponse\x12C\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1d.subscription.v1.SubscriptionR\rsubscriptions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x89\x01\n" +
+	"\x1aExportSubscriptionsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.subscription.v1.SubscriptionFilterR\x06filter\x12.\n" +
+	"\x04sort\x18\x02 \x03(\v2\x1a.subscription.v1.SortFieldR\x04sort\"\xa4\x01\n" +
+	"\x1aImportSubscriptionsRequest\x126\n" +
+	"\x04rows\x18\x01 \x03(\v2\".subscription.v1.SubscriptionInputR\x04rows\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x1f\n" +
+	"\vresume_from\x18\x03 \x01(\x05R\n" +
+	"resumeFrom\x12\x14\n" +
+	"\x05force\x18\x04 \x01(\bR\x05force\"@\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xec\x01\n" +
+	"\x0fImportRowResult\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x128\n" +
+	"\x06status\x18\x02 \x01(\x0e2 .subscription.v1.ImportRowStatusR\x06status\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x127\n" +
+	"\x06errors\x18\x06 \x03(\v2\x1f.subscription.v1.FieldViolationR\x06errors\x12\x1a\n" +
+	"\bwarnings\x18\a \x03(\tR\bwarnings\"\xde\x01\n" +
+	"\fImportReport\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05valid\x18\x03 \x01(\x05R\x05valid\x12\x18\n" +
+	"\ainvalid\x18\x04 \x01(\x05R\ainvalid\x12\x18\n" +
+	"\acreated\x18\x05 \x01(\x05R\acreated\x12\x1f\n" +
+	"\vresume_from\x18\x06 \x01(\x05R\n" +
+	"resumeFrom\x124\n" +
+	"\x04rows\x18\a \x03(\v2 .subscription.v1.ImportRowResultR\x04rows\"\x9e\x02\n" +
+	"\x17CalculateSummaryRequest\x125\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x16.subscription.v1.MonthR\tstartDate\x121\n" +
+	"\bend_date\x18\x02 \x01(\v2\x16.subscription.v1.MonthR\aendDate\x12\x1c\n" +
+	"\auser_id\x18\x03 \x01(\tH\x00R\x06userId\x88\x01\x01\x12&\n" +
+	"\fservice_name\x18\x04 \x01(\tH\x01R\vserviceName\x88\x01\x01\x126\n" +
+	"\x06source\x18\x05 \x01(\x0e2\x1e.subscription.v1.SummarySourceR\x06sourceB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_name\"q\n" +
+	"\aSummary\x12!\n" +
+	"\ftotal_amount\x18\x01 \x01(\x03R\vtotalAmount\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12-\n" +
+	"\x12adjustments_amount\x18\x03 \x01(\x03R\x11adjustmentsAmount\"\x97\x02\n" +
+	"\x13SubscriptionOverlap\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12'\n" +
+	"\x0fsubscription_id\x18\x03 \x01(\tR\x0esubscriptionId\x12%\n" +
+	"\x0eoverlapping_id\x18\x04 \x01(\tR\roverlappingId\x12;\n" +
+	"\roverlap_start\x18\x05 \x01(\v2\x16.subscription.v1.MonthR\foverlapStart\x127\n" +
+	"\voverlap_end\x18\x06 \x01(\v2\x16.subscription.v1.MonthR\n" +
+	"overlapEnd\"X\n" +
+	"\x14ListOverlapsResponse\x12@\n" +
+	"\boverlaps\x18\x01 \x03(\v2$.subscription.v1.SubscriptionOverlapR\boverlaps\"\xfa\x01\n" +
+	"\x06Budget\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
+	"\bcategory\x18\x03 \x01(\tH\x00R\bcategory\x88\x01\x01\x12#\n" +
+	"\rmonthly_limit\x18\x04 \x01(\x03R\fmonthlyLimit\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\v\n" +
+	"\t_category\"~\n" +
+	"\x10SetBudgetRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\bcategory\x18\x02 \x01(\tH\x00R\bcategory\x88\x01\x01\x12#\n" +
+	"\rmonthly_limit\x18\x03 \x01(\x03R\fmonthlyLimitB\v\n" +
+	"\t_category\"-\n" +
+	"\x12ListBudgetsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"H\n" +
+	"\x13ListBudgetsResponse\x121\n" +
+	"\abudgets\x18\x01 \x03(\v2\x17.subscription.v1.BudgetR\abudgets\"%\n" +
+	"\x13DeleteBudgetRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x9b\x01\n" +
+	"\x16GetBudgetReportRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x125\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x16.subscription.v1.MonthR\tstartDate\x121\n" +
+	"\bend_date\x18\x03 \x01(\v2\x16.subscription.v1.MonthR\aendDate\"\xe4\x01\n" +
+	"\vBudgetMonth\x12,\n" +
+	"\x05month\x18\x01 \x01(\v2\x16.subscription.v1.MonthR\x05month\x12\x1b\n" +
+	"\tbudget_id\x18\x02 \x01(\tR\bbudgetId\x12\x1f\n" +
+	"\bcategory\x18\x03 \x01(\tH\x00R\bcategory\x88\x01\x01\x12#\n" +
+	"\rmonthly_limit\x18\x04 \x01(\x03R\fmonthlyLimit\x12\x16\n" +
+	"\x06actual\x18\x05 \x01(\x03R\x06actual\x12\x1f\n" +
+	"\vover_budget\x18\x06 \x01(\bR\n" +
+	"overBudgetB\v\n" +
+	"\t_category\"O\n" +
+	"\x17GetBudgetReportResponse\x124\n" +
+	"\x06months\x18\x01 \x03(\v2\x1c.subscription.v1.BudgetMonthR\x06months\"\x85\x03\n" +
+	"\x06Charge\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x04 \x01(\tR\vserviceName\x12\x1f\n" +
+	"\bcategory\x18\x05 \x01(\tH\x00R\bcategory\x88\x01\x01\x12,\n" +
+	"\x05month\x18\x06 \x01(\v2\x16.subscription.v1.MonthR\x05month\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x03R\x06amount\x12/\n" +
+	"\x04kind\x18\b \x01(\x0e2\x1b.subscription.v1.ChargeKindR\x04kind\x12\x1b\n" +
+	"\x06reason\x18\t \x01(\tH\x01R\x06reason\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\v\n" +
+	"\t_categoryB\t\n" +
+	"\a_reason\"\x8d\x02\n" +
+	"\x12ListChargesRequest\x12\x1c\n" +
+	"\auser_id\x18\x01 \x01(\tH\x00R\x06userId\x88\x01\x01\x12,\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tH\x01R\x0esubscriptionId\x88\x01\x01\x12&\n" +
+	"\fservice_name\x18\x03 \x01(\tH\x02R\vserviceName\x88\x01\x01\x12*\n" +
+	"\x04from\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\x04from\x12&\n" +
+	"\x02to\x18\x05 \x01(\v2\x16.subscription.v1.MonthR\x02toB\n" +
+	"\n" +
+	"\b_user_idB\x12\n" +
+	"\x10_subscription_idB\x0f\n" +
+	"\r_service_name\"H\n" +
+	"\x13ListChargesResponse\x121\n" +
+	"\acharges\x18\x01 \x03(\v2\x17.subscription.v1.ChargeR\acharges\"\x91\x02\n" +
+	"\n" +
+	"Adjustment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\x123\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x1f.subscription.v1.AdjustmentKindR\x04kind\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12*\n" +
+	"\x04date\x18\x05 \x01(\v2\x16.subscription.v1.MonthR\x04date\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd3\x01\n" +
+	"\x17CreateAdjustmentRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x123\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x1f.subscription.v1.AdjustmentKindR\x04kind\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12*\n" +
+	"\x04date\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\x04date\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"A\n" +
+	"\x16ListAdjustmentsRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\"X\n" +
+	"\x17ListAdjustmentsResponse\x12=\n" +
+	"\vadjustments\x18\x01 \x03(\v2\x1b.subscription.v1.AdjustmentR\vadjustments\")\n" +
+	"\x17DeleteAdjustmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id*Z\n" +
+	"\tBatchMode\x12\x1a\n" +
+	"\x16BATCH_MODE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11BATCH_MODE_ATOMIC\x10\x01\x12\x1a\n" +
+	"\x16BATCH_MODE_BEST_EFFORT\x10\x02*\x9d\x01\n" +
+	"\x12BatchOperationType\x12$\n" +
+	" BATCH_OPERATION_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bBATCH_OPERATION_TYPE_CREATE\x10\x01\x12\x1f\n" +
+	"\x1bBATCH_OPERATION_TYPE_UPDATE\x10\x02\x12\x1f\n" +
+	"\x1bBATCH_OPERATION_TYPE_DELETE\x10\x03*\xae\x01\n" +
+	"\x0fBatchItemStatus\x12!\n" +
+	"\x1dBATCH_ITEM_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14BATCH_ITEM_STATUS_OK\x10\x01\x12\x1c\n" +
+	"\x18BATCH_ITEM_STATUS_FAILED\x10\x02\x12!\n" +
+	"\x1dBATCH_ITEM_STATUS_ROLLED_BACK\x10\x03\x12\x1d\n" +
+	"\x19BATCH_ITEM_STATUS_SKIPPED\x10\x04*\x98\x01\n" +
+	"\x12SubscriptionStatus\x12#\n" +
+	"\x1fSUBSCRIPTION_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aSUBSCRIPTION_STATUS_ACTIVE\x10\x01\x12\x1d\n" +
+	"\x19SUBSCRIPTION_STATUS_ENDED\x10\x02\x12\x1e\n" +
+	"\x1aSUBSCRIPTION_STATUS_FUTURE\x10\x03*\xef\x01\n" +
+	"\x0fImportRowStatus\x12!\n" +
+	"\x1dIMPORT_ROW_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17IMPORT_ROW_STATUS_VALID\x10\x01\x12\x1d\n" +
+	"\x19IMPORT_ROW_STATUS_INVALID\x10\x02\x12\x1d\n" +
+	"\x19IMPORT_ROW_STATUS_CREATED\x10\x03\x12\x1c\n" +
+	"\x18IMPORT_ROW_STATUS_FAILED\x10\x04\x12!\n" +
+	"\x1dIMPORT_ROW_STATUS_ROLLED_BACK\x10\x05\x12\x1d\n" +
+	"\x19IMPORT_ROW_STATUS_SKIPPED\x10\x06*l\n" +
+	"\rSummarySource\x12\x1e\n" +
+	"\x1aSUMMARY_SOURCE_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cSUMMARY_SOURCE_SUBSCRIPTIONS\x10\x01\x12\x19\n" +
+	"\x15SUMMARY_SOURCE_LEDGER\x10\x02*\x8f\x01\n" +
+	"\n" +
+	"ChargeKind\x12\x1b\n" +
+	"\x17CHARGE_KIND_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18CHARGE_KIND_SUBSCRIPTION\x10\x01\x12\x16\n" +
+	"\x12CHARGE_KIND_REFUND\x10\x02\x12\x16\n" +
+	"\x12CHARGE_KIND_CREDIT\x10\x03\x12\x16\n" +
+	"\x12CHARGE_KIND_CHARGE\x10\x04*\x85\x01\n" +
+	"\x0eAdjustmentKind\x12\x1f\n" +
+	"\x1bADJUSTMENT_KIND_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16ADJUSTMENT_KIND_REFUND\x10\x01\x12\x1a\n" +
+	"\x16ADJUSTMENT_KIND_CREDIT\x10\x02\x12\x1a\n" +
+	"\x16ADJUSTMENT_KIND_CHARGE\x10\x032\xcd\x0e\n" +
+	"\x13SubscriptionService\x12m\n" +
+	"\x12CreateSubscription\x12*.subscription.v1.CreateSubscriptionRequest\x1a+.subscription.v1.CreateSubscriptionResponse\x12Y\n" +
+	"\x0fGetSubscription\x12'.subscription.v1.GetSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12_\n" +
+	"\x12UpdateSubscription\x12*.subscription.v1.UpdateSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12]\n" +
+	"\x11PatchSubscription\x12).subscription.v1.PatchSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12X\n" +
+	"\x12DeleteSubscription\x12*.subscription.v1.DeleteSubscriptionRequest\x1a\x16.google.protobuf.Empty\x12m\n" +
+	"\x12BatchSubscriptions\x12*.subscription.v1.BatchSubscriptionsRequest\x1a+.subscription.v1.BatchSubscriptionsResponse\x12j\n" +
+	"\x11ListSubscriptions\x12).subscription.v1.ListSubscriptionsRequest\x1a*.subscription.v1.ListSubscriptionsResponse\x12c\n" +
+	"\x13ExportSubscriptions\x12+.subscription.v1.ExportSubscriptionsRequest\x1a\x1d.subscription.v1.Subscription0\x01\x12a\n" +
+	"\x13ImportSubscriptions\x12+.subscription.v1.ImportSubscriptionsRequest\x1a\x1d.subscription.v1.ImportReport\x12V\n" +
+	"\x10CalculateSummary\x12(.subscription.v1.CalculateSummaryRequest\x1a\x18.subscription.v1.Summary\x12M\n" +
+	"\fListOverlaps\x12\x16.google.protobuf.Empty\x1a%.subscription.v1.ListOverlapsResponse\x12G\n" +
+	"\tSetBudget\x12!.subscription.v1.SetBudgetRequest\x1a\x17.subscription.v1.Budget\x12X\n" +
+	"\vListBudgets\x12#.subscription.v1.ListBudgetsRequest\x1a$.subscription.v1.ListBudgetsResponse\x12L\n" +
+	"\fDeleteBudget\x12$.subscription.v1.DeleteBudgetRequest\x1a\x16.google.protobuf.Empty\x12d\n" +
+	"\x0fGetBudgetReport\x12'.subscription.v1.GetBudgetReportRequest\x1a(.subscription.v1.GetBudgetReportResponse\x12X\n" +
+	"\vListCharges\x12#.subscription.v1.ListChargesRequest\x1a$.subscription.v1.ListChargesResponse\x12@\n" +
+	"\x0eRebuildCharges\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x12Y\n" +
+	"\x10CreateAdjustment\x12(.subscription.v1.CreateAdjustmentRequest\x1a\x1b.subscription.v1.Adjustment\x12d\n" +
+	"\x0fListAdjustments\x12'.subscription.v1.ListAdjustmentsRequest\x1a(.subscription.v1.ListAdjustmentsResponse\x12T\n" +
+	"\x10DeleteAdjustment\x12(.subscription.v1.DeleteAdjustmentRequest\x1a\x16.google.protobuf.EmptyBMZKgithub.com/ZnNr/subscription-service/pkg/api/subscription/v1;subscriptionv1b\x06proto3"
+
+var (
+	file_subscription_v1_subscription_proto_rawDescOnce sync.Once
+	file_subscription_v1_subscription_proto_rawDescData []byte
+)
+
+func file_subscription_v1_subscription_proto_rawDescGZIP() []byte {
+	file_subscription_v1_subscription_proto_rawDescOnce.Do(func() {
+		file_subscription_v1_subscription_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_subscription_v1_subscription_proto_rawDesc), len(file_subscription_v1_subscription_proto_rawDesc)))
+	})
+	return file_subscription_v1_subscription_proto_rawDescData
+}
+
+var file_subscription_v1_subscription_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_subscription_v1_subscription_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
+var file_subscription_v1_subscription_proto_goTypes = []any{
+	(BatchMode)(0),                     // 0: subscription.v1.BatchMode
+	(BatchOperationType)(0),            // 1: subscription.v1.BatchOperationType
+	(BatchItemStatus)(0),               // 2: subscription.v1.BatchItemStatus
+	(SubscriptionStatus)(0),            // 3: subscription.v1.SubscriptionStatus
+	(ImportRowStatus)(0),               // 4: subscription.v1.ImportRowStatus
+	(SummarySource)(0),                 // 5: subscription.v1.SummarySource
+	(ChargeKind)(0),                    // 6: subscription.v1.ChargeKind
+	(AdjustmentKind)(0),                // 7: subscription.v1.AdjustmentKind
+	(*Month)(nil),                      // 8: subscription.v1.Month
+	(*Subscription)(nil),               // 9: subscription.v1.Subscription
+	(*SubscriptionInput)(nil),          // 10: subscription.v1.SubscriptionInput
+	(*CreateSubscriptionRequest)(nil),  // 11: subscription.v1.CreateSubscriptionRequest
+	(*CreateSubscriptionResponse)(nil), // 12: subscription.v1.CreateSubscriptionResponse
+	(*GetSubscriptionRequest)(nil),     // 13: subscription.v1.GetSubscriptionRequest
+	(*UpdateSubscriptionRequest)(nil),  // 14: subscription.v1.UpdateSubscriptionRequest
+	(*PatchSubscriptionRequest)(nil),   // 15: subscription.v1.PatchSubscriptionRequest
+	(*DeleteSubscriptionRequest)(nil),  // 16: subscription.v1.DeleteSubscriptionRequest
+	(*BatchOperation)(nil),             // 17: subscription.v1.BatchOperation
+	(*BatchSubscriptionsRequest)(nil),  // 18: subscription.v1.BatchSubscriptionsRequest
+	(*BatchItemResult)(nil),            // 19: subscription.v1.BatchItemResult
+	(*BatchSubscriptionsResponse)(nil), // 20: subscription.v1.BatchSubscriptionsResponse
+	(*SubscriptionFilter)(nil),         // 21: subscription.v1.SubscriptionFilter
+	(*SortField)(nil),                  // 22: subscription.v1.SortField
+	(*ListSubscriptionsRequest)(nil),   // 23: subscription.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),  // 24: subscription.v1.ListSubscriptionsResponse
+	(*ExportSubscriptionsRequest)(nil), // 25: subscription.v1.ExportSubscriptionsRequest
+	(*ImportSubscriptionsRequest)(nil), // 26: subscription.v1.ImportSubscriptionsRequest
+	(*FieldViolation)(nil),             // 27: subscription.v1.FieldViolation
+	(*ImportRowResult)(nil),            // 28: subscription.v1.ImportRowResult
+	(*ImportReport)(nil),               // 29: subscription.v1.ImportReport
+	(*CalculateSummaryRequest)(nil),    // 30: subscription.v1.CalculateSummaryRequest
+	(*Summary)(nil),                    // 31: subscription.v1.Summary
+	(*SubscriptionOverlap)(nil),        // 32: subscription.v1.SubscriptionOverlap
+	(*ListOverlapsResponse)(nil),       // 33: subscription.v1.ListOverlapsResponse
+	(*Budget)(nil),                     // 34: subscription.v1.Budget
+	(*SetBudgetRequest)(nil),           // 35: subscription.v1.SetBudgetRequest
+	(*ListBudgetsRequest)(nil),         // 36: subscription.v1.ListBudgetsRequest
+	(*ListBudgetsResponse)(nil),        // 37: subscription.v1.ListBudgetsResponse
+	(*DeleteBudgetRequest)(nil),        // 38: subscription.v1.DeleteBudgetRequest
+	(*GetBudgetReportRequest)(nil),     // 39: subscription.v1.GetBudgetReportRequest
+	(*BudgetMonth)(nil),                // 40: subscription.v1.BudgetMonth
+	(*GetBudgetReportResponse)(nil),    // 41: subscription.v1.GetBudgetReportResponse
+	(*Charge)(nil),                     // 42: subscription.v1.Charge
+	(*ListChargesRequest)(nil),         // 43: subscription.v1.ListChargesRequest
+	(*ListChargesResponse)(nil),        // 44: subscription.v1.ListChargesResponse
+	(*Adjustment)(nil),                 // 45: subscription.v1.Adjustment
+	(*CreateAdjustmentRequest)(nil),    // 46: subscription.v1.CreateAdjustmentRequest
+	(*ListAdjustmentsRequest)(nil),     // 47: subscription.v1.ListAdjustmentsRequest
+	(*ListAdjustmentsResponse)(nil),    // 48: subscription.v1.ListAdjustmentsResponse
+	(*DeleteAdjustmentRequest)(nil),    // 49: subscription.v1.DeleteAdjustmentRequest
+	(*timestamppb.Timestamp)(nil),      // 50: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),      // 51: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),              // 52: google.protobuf.Empty
+}
+var file_subscription_v1_subscription_proto_depIdxs = []int32{
+	8,  // 0: subscription.v1.Subscription.start_date:type_name -> subscription.v1.Month
+	8,  // 1: subscription.v1.Subscription.end_date:type_name -> subscription.v1.Month
+	50, // 2: subscription.v1.Subscription.created_at:type_name -> google.protobuf.Timestamp
+	50, // 3: subscription.v1.Subscription.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 4: subscription.v1.SubscriptionInput.start_date:type_name -> subscription.v1.Month
+	8,  // 5: subscription.v1.SubscriptionInput.end_date:type_name -> subscription.v1.Month
+	10, // 6: subscription.v1.CreateSubscriptionRequest.subscription:type_name -> subscription.v1.SubscriptionInput
+	9,  // 7: subscription.v1.CreateSubscriptionResponse.subscription:type_name -> subscription.v1.Subscription
+	10, // 8: subscription.v1.UpdateSubscriptionRequest.subscription:type_name -> subscription.v1.SubscriptionInput
+	10, // 9: subscription.v1.PatchSubscriptionRequest.subscription:type_name -> subscription.v1.SubscriptionInput
+	51, // 10: subscription.v1.PatchSubscriptionRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 11: subscription.v1.BatchOperation.op:type_name -> subscription.v1.BatchOperationType
+	10, // 12: subscription.v1.BatchOperation.subscription:type_name -> subscription.v1.SubscriptionInput
+	0,  // 13: subscription.v1.BatchSubscriptionsRequest.mode:type_name -> subscription.v1.BatchMode
+	17, // 14: subscription.v1.BatchSubscriptionsRequest.operations:type_name -> subscription.v1.BatchOperation
+	1,  // 15: subscription.v1.BatchItemResult.op:type_name -> subscription.v1.BatchOperationType
+	2,  // 16: subscription.v1.BatchItemResult.status:type_name -> subscription.v1.BatchItemStatus
+	9,  // 17: subscription.v1.BatchItemResult.subscription:type_name -> subscription.v1.Subscription
+	19, // 18: subscription.v1.BatchSubscriptionsResponse.results:type_name -> subscription.v1.BatchItemResult
+	8,  // 19: subscription.v1.SubscriptionFilter.active_at:type_name -> subscription.v1.Month
+	8,  // 20: subscription.v1.SubscriptionFilter.start_from:type_name -> subscription.v1.Month
+	8,  // 21: subscription.v1.SubscriptionFilter.start_to:type_name -> subscription.v1.Month
+	8,  // 22: subscription.v1.SubscriptionFilter.end_from:type_name -> subscription.v1.Month
+	8,  // 23: subscription.v1.SubscriptionFilter.end_to:type_name -> subscription.v1.Month
+	3,  // 24: subscription.v1.SubscriptionFilter.status:type_name -> subscription.v1.SubscriptionStatus
+	50, // 25: subscription.v1.SubscriptionFilter.updated_since:type_name -> google.protobuf.Timestamp
+	21, // 26: subscription.v1.ListSubscriptionsRequest.filter:type_name -> subscription.v1.SubscriptionFilter
+	22, // 27: subscription.v1.ListSubscriptionsRequest.sort:type_name -> subscription.v1.SortField
+	9,  // 28: subscription.v1.ListSubscriptionsResponse.subscriptions:type_name -> subscription.v1.Subscription
+	21, // 29: subscription.v1.ExportSubscriptionsRequest.filter:type_name -> subscription.v1.SubscriptionFilter
+	22, // 30: subscription.v1.ExportSubscriptionsRequest.sort:type_name -> subscription.v1.SortField
+	10, // 31: subscription.v1.ImportSubscriptionsRequest.rows:type_name -> subscription.v1.SubscriptionInput
+	4,  // 32: subscription.v1.ImportRowResult.status:type_name -> subscription.v1.ImportRowStatus
+	27, // 33: subscription.v1.ImportRowResult.errors:type_name -> subscription.v1.FieldViolation
+	28, // 34: subscription.v1.ImportReport.rows:type_name -> subscription.v1.ImportRowResult
+	8,  // 35: subscription.v1.CalculateSummaryRequest.start_date:type_name -> subscription.v1.Month
+	8,  // 36: subscription.v1.CalculateSummaryRequest.end_date:type_name -> subscription.v1.Month
+	5,  // 37: subscription.v1.CalculateSummaryRequest.source:type_name -> subscription.v1.SummarySource
+	8,  // 38: subscription.v1.SubscriptionOverlap.overlap_start:type_name -> subscription.v1.Month
+	8,  // 39: subscription.v1.SubscriptionOverlap.overlap_end:type_name -> subscription.v1.Month
+	32, // 40: subscription.v1.ListOverlapsResponse.overlaps:type_name -> subscription.v1.SubscriptionOverlap
+	50, // 41: subscription.v1.Budget.created_at:type_name -> google.protobuf.Timestamp
+	50, // 42: subscription.v1.Budget.updated_at:type_name -> google.protobuf.Timestamp
+	34, // 43: subscription.v1.ListBudgetsResponse.budgets:type_name -> subscription.v1.Budget
+	8,  // 44: subscription.v1.GetBudgetReportRequest.start_date:type_name -> subscription.v1.Month
+	8,  // 45: subscription.v1.GetBudgetReportRequest.end_date:type_name -> subscription.v1.Month
+	8,  // 46: subscription.v1.BudgetMonth.month:type_name -> subscription.v1.Month
+	40, // 47: subscription.v1.GetBudgetReportResponse.months:type_name -> subscription.v1.BudgetMonth
+	8,  // 48: subscription.v1.Charge.month:type_name -> subscription.v1.Month
+	6,  // 49: subscription.v1.Charge.kind:type_name -> subscription.v1.ChargeKind
+	50, // 50: subscription.v1.Charge.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 51: subscription.v1.ListChargesRequest.from:type_name -> subscription.v1.Month
+	8,  // 52: subscription.v1.ListChargesRequest.to:type_name -> subscription.v1.Month
+	42, // 53: subscription.v1.ListChargesResponse.charges:type_name -> subscription.v1.Charge
+	7,  // 54: subscription.v1.Adjustment.kind:type_name -> subscription.v1.AdjustmentKind
+	8,  // 55: subscription.v1.Adjustment.date:type_name -> subscription.v1.Month
+	50, // 56: subscription.v1.Adjustment.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 57: subscription.v1.CreateAdjustmentRequest.kind:type_name -> subscription.v1.AdjustmentKind
+	8,  // 58: subscription.v1.CreateAdjustmentRequest.date:type_name -> subscription.v1.Month
+	45, // 59: subscription.v1.ListAdjustmentsResponse.adjustments:type_name -> subscription.v1.Adjustment
+	11, // 60: subscription.v1.SubscriptionService.CreateSubscription:input_type -> subscription.v1.CreateSubscriptionRequest
+	13, // 61: subscription.v1.SubscriptionService.GetSubscription:input_type -> subscription.v1.GetSubscriptionRequest
+	14, // 62: subscription.v1.SubscriptionService.UpdateSubscription:input_type -> subscription.v1.UpdateSubscriptionRequest
+	15, // 63: subscription.v1.SubscriptionService.PatchSubscription:input_type -> subscription.v1.PatchSubscriptionRequest
+	16, // 64: subscription.v1.SubscriptionService.DeleteSubscription:input_type -> subscription.v1.DeleteSubscriptionRequest
+	18, // 65: subscription.v1.SubscriptionService.BatchSubscriptions:input_type -> subscription.v1.BatchSubscriptionsRequest
+	23, // 66: subscription.v1.SubscriptionService.ListSubscriptions:input_type -> subscription.v1.ListSubscriptionsRequest
+	25, // 67: subscription.v1.SubscriptionService.ExportSubscriptions:input_type -> subscription.v1.ExportSubscriptionsRequest
+	26, // 68: subscription.v1.SubscriptionService.ImportSubscriptions:input_type -> subscription.v1.ImportSubscriptionsRequest
+	30, // 69: subscription.v1.SubscriptionService.CalculateSummary:input_type -> subscription.v1.CalculateSummaryRequest
+	52, // 70: subscription.v1.SubscriptionService.ListOverlaps:input_type -> google.protobuf.Empty
+	35, // 71: subscription.v1.SubscriptionService.SetBudget:input_type -> subscription.v1.SetBudgetRequest
+	36, // 72: subscription.v1.SubscriptionService.ListBudgets:input_type -> subscription.v1.ListBudgetsRequest
+	38, // 73: subscription.v1.SubscriptionService.DeleteBudget:input_type -> subscription.v1.DeleteBudgetRequest
+	39, // 74: subscription.v1.SubscriptionService.GetBudgetReport:input_type -> subscription.v1.GetBudgetReportRequest
+	43, // 75: subscription.v1.SubscriptionService.ListCharges:input_type -> subscription.v1.ListChargesRequest
+	52, // 76: subscription.v1.SubscriptionService.RebuildCharges:input_type -> google.protobuf.Empty
+	46, // 77: subscription.v1.SubscriptionService.CreateAdjustment:input_type -> subscription.v1.CreateAdjustmentRequest
+	47, // 78: subscription.v1.SubscriptionService.ListAdjustments:input_type -> subscription.v1.ListAdjustmentsRequest
+	49, // 79: subscription.v1.SubscriptionService.DeleteAdjustment:input_type -> subscription.v1.DeleteAdjustmentRequest
+	12, // 80: subscription.v1.SubscriptionService.CreateSubscription:output_type -> subscription.v1.CreateSubscriptionResponse
+	9,  // 81: subscription.v1.SubscriptionService.GetSubscription:output_type -> subscription.v1.Subscription
+	9,  // 82: subscription.v1.SubscriptionService.UpdateSubscription:output_type -> subscription.v1.Subscription
+	9,  // 83: subscription.v1.SubscriptionService.PatchSubscription:output_type -> subscription.v1.Subscription
+	52, // 84: subscription.v1.SubscriptionService.DeleteSubscription:output_type -> google.protobuf.Empty
+	20, // 85: subscription.v1.SubscriptionService.BatchSubscriptions:output_type -> subscription.v1.BatchSubscriptionsResponse
+	24, // 86: subscription.v1.SubscriptionService.ListSubscriptions:output_type -> subscription.v1.ListSubscriptionsResponse
+	9,  // 87: subscription.v1.SubscriptionService.ExportSubscriptions:output_type -> subscription.v1.Subscription
+	29, // 88: subscription.v1.SubscriptionService.ImportSubscriptions:output_type -> subscription.v1.ImportReport
+	31, // 89: subscription.v1.SubscriptionService.CalculateSummary:output_type -> subscription.v1.Summary
+	33, // 90: subscription.v1.SubscriptionService.ListOverlaps:output_type -> subscription.v1.ListOverlapsResponse
+	34, // 91: subscription.v1.SubscriptionService.SetBudget:output_type -> subscription.v1.Budget
+	37, // 92: subscription.v1.SubscriptionService.ListBudgets:output_type -> subscription.v1.ListBudgetsResponse
+	52, // 93: subscription.v1.SubscriptionService.DeleteBudget:output_type -> google.protobuf.Empty
+	41, // 94: subscription.v1.SubscriptionService.GetBudgetReport:output_type -> subscription.v1.GetBudgetReportResponse
+	44, // 95: subscription.v1.SubscriptionService.ListCharges:output_type -> subscription.v1.ListChargesResponse
+	52, // 96: subscription.v1.SubscriptionService.RebuildCharges:output_type -> google.protobuf.Empty
+	45, // 97: subscription.v1.SubscriptionService.CreateAdjustment:output_type -> subscription.v1.Adjustment
+	48, // 98: subscription.v1.SubscriptionService.ListAdjustments:output_type -> subscription.v1.ListAdjustmentsResponse
+	52, // 99: subscription.v1.SubscriptionService.DeleteAdjustment:output_type -> google.protobuf.Empty
+	80, // [80:100] is the sub-list for method output_type
+	60, // [60:80] is the sub-list for method input_type
+	60, // [60:60] is the sub-list for extension type_name
+	60, // [60:60] is the sub-list for extension extendee
+	0,  // [0:60] is the sub-list for field type_name
+}
+
+func init() { file_subscription_v1_subscription_proto_init() }
+func file_subscription_v1_subscription_proto_init() {
+	if File_subscription_v1_subscription_proto != nil {
+		return
+	}
+	file_subscription_v1_subscription_proto_msgTypes[1].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[2].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[6].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[7].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[8].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[9].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[13].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[22].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[26].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[27].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[32].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[34].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[35].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subscription_v1_subscription_proto_rawDesc), len(file_subscription_v1_subscription_proto_rawDesc)),
+			NumEnums:      8,
+			NumMessages:   42,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_subscription_v1_subscription_proto_goTypes,
+		DependencyIndexes: file_subscription_v1_subscription_proto_depIdxs,
+		EnumInfos:         file_subscription_v1_subscription_proto_enumTypes,
+		MessageInfos:      file_subscription_v1_subscription_proto_msgTypes,
+	}.Build()
+	File_subscription_v1_subscription_proto = out.File
+	file_subscription_v1_subscription_proto_goTypes = nil
+	file_subscription_v1_subscription_proto_depIdxs = nil
+}
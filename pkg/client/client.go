@@ -0,0 +1,320 @@
+// Package client — Go-клиент REST API сервиса подписок (по умолчанию /api/v2, см. WithAPIVersion).
+//
+//	c, err := client.New("http://localhost:8080")
+//	if err != nil {
+//		return err
+//	}
+//	sub, err := c.GetSubscription(ctx, id)
+//	if errors.Is(err, client.ErrNotFound) {
+//		// подписки нет
+//	}
+//
+// Методы повторяют операции сервиса: создание, получение, замена, удаление, список, сумма
+// и импорт подписок. Ответ с ошибкой возвращается как *Error с кодом и деталями из тела ответа.
+// Запросы, которые безопасно выполнить повторно, повторяются при сетевых ошибках и ответах
+// 429, 502, 503 и 504 с экспоненциальной задержкой (см. RetryPolicy). Создание подписки
+// всегда отправляется с заголовком Idempotency-Key, поэтому его повтор не создает дубликат.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIVersion — версия REST API, к которой обращается клиент
+type APIVersion string
+
+const (
+	// APIv2 — текущая версия API; используется по умолчанию
+	APIv2 APIVersion = "v2"
+	// APIv1 — устаревшая версия API для серверов без /api/v2. По умолчанию сервис считает /api/v1
+	// устаревшим с 2026-11-01 и может отключить его после 2027-05-01 (заголовки Deprecation и Sunset);
+	// поддержка APIv1 будет удалена из клиента в версии 2.0, выпущенной после этой даты.
+	APIv1 APIVersion = "v1"
+)
+
+// DefaultTimeout — ограничение времени одной попытки запроса, если не задан свой http.Client
+const DefaultTimeout = 30 * time.Second
+
+// DefaultRetryPolicy — повторы по умолчанию: до трех повторов с задержкой от 100 мс до 5 с
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 5 * time.Second,
+}
+
+// RetryPolicy задает повтор запросов при временных ошибках
+type RetryPolicy struct {
+	// MaxRetries — сколько раз запрос повторяется после первой попытки; 0 — без повторов
+	MaxRetries int
+	// MinBackoff — задержка перед первым повтором; перед каждым следующим она удваивается
+	MinBackoff time.Duration
+	// MaxBackoff — наибольшая задержка, в том числе запрошенная сервером в Retry-After
+	MaxBackoff time.Duration
+}
+
+// backoff возвращает задержку перед повтором номер retry (с нуля): половина — фиксированная,
+// половина — случайная, чтобы клиенты, получившие ошибку одновременно, не повторяли запрос разом
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.MinBackoff
+	for i := 0; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.MaxBackoff)
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Client — клиент API сервиса подписок; безопасен для одновременного использования
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+	userAgent  string
+	version    APIVersion
+}
+
+// Option настраивает Client
+type Option func(*Client)
+
+// WithHTTPClient задает http.Client для запросов (например, с другим таймаутом или транспортом)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy задает повтор запросов; RetryPolicy{} отключает повторы
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithUserAgent задает заголовок User-Agent запросов
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithAPIVersion задает версию API (по умолчанию APIv2). APIv1 нужен только для серверов,
+// где еще нет /api/v2: его поддержка будет удалена в версии клиента 2.0 после отключения
+// /api/v1 на сервисе (Sunset — 2027-05-01).
+func WithAPIVersion(version APIVersion) Option {
+	return func(c *Client) {
+		c.version = version
+	}
+}
+
+// New создает клиент сервиса по адресу baseURL, например "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("client: invalid base URL %q, expected http(s)://host[:port]", baseURL)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		retry:      DefaultRetryPolicy,
+		userAgent:  "subscription-service-go-client/1.0",
+		version:    APIv2,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.version != APIv1 && c.version != APIv2 {
+		return nil, fmt.Errorf("client: unsupported API version %q", c.version)
+	}
+
+	return c, nil
+}
+
+// request описывает запрос к API
+type request struct {
+	method string
+	// path — путь относительно /api/<версия>
+	path string
+	// version — версия API запроса; пустая — версия клиента
+	version APIVersion
+	query   url.Values
+	header  http.Header
+	body    any
+	// raw и contentType — тело запроса не в JSON (например, файл импорта); body при этом не задается
+	raw         []byte
+	contentType string
+	// retryable — запрос можно выполнить повторно без побочных эффектов
+	retryable bool
+}
+
+// response — прочитанный ответ сервера
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// retryableStatuses — ответы, после которых запрос стоит повторить
+var retryableStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// do выполняет запрос с повторами и декодирует успешный ответ в out (если out не nil).
+// Если сервис ответил ошибкой, вместе с *Error возвращается и сам ответ.
+func (c *Client) do(ctx context.Context, r request, out any) (*response, error) {
+	payload, contentType := r.raw, r.contentType
+	if r.body != nil {
+		var err error
+		if payload, err = json.Marshal(r.body); err != nil {
+			return nil, fmt.Errorf("client: encode request: %w", err)
+		}
+		contentType = "application/json"
+	}
+
+	for retry := 0; ; retry++ {
+		resp, err := c.send(ctx, r, payload, contentType)
+		if err == nil && resp.status < 300 {
+			if out != nil && len(resp.body) > 0 {
+				if err := json.Unmarshal(resp.body, out); err != nil {
+					return nil, fmt.Errorf("client: decode response: %w", err)
+				}
+			}
+			return resp, nil
+		}
+
+		// Отмену вызывающим не повторяем
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		temporary := err != nil || retryableStatuses[resp.status]
+		if !r.retryable || !temporary || retry >= c.retry.MaxRetries {
+			if err != nil {
+				return nil, err
+			}
+			return resp, decodeError(resp)
+		}
+
+		delay := c.retry.backoff(retry)
+		if err == nil {
+			delay = retryAfter(resp.header, delay, c.retry.MaxBackoff)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send выполняет одну попытку запроса и читает ответ целиком
+func (c *Client) send(ctx context.Context, r request, payload []byte, contentType string) (*response, error) {
+	version := r.version
+	if version == "" {
+		version = c.version
+	}
+
+	target := *c.baseURL
+	target.Path += "/api/" + string(version) + r.path
+	target.RawQuery = r.query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{status: resp.StatusCode, header: resp.Header, body: data}, nil
+}
+
+// retryAfter возвращает задержку из заголовка Retry-After (в секундах или датой), но не больше limit
+func retryAfter(header http.Header, fallback, limit time.Duration) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return fallback
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = time.Until(at)
+	} else {
+		return fallback
+	}
+
+	return max(0, min(delay, limit))
+}
+
+// decodeError превращает ответ с ошибкой в *Error
+func decodeError(resp *response) error {
+	apiErr := &Error{StatusCode: resp.status}
+
+	if err := json.Unmarshal(resp.body, apiErr); err != nil || apiErr.Code == "" {
+		// Ответ не от сервиса (например, от прокси): сохраняем начало тела как описание
+		apiErr = &Error{StatusCode: resp.status, Title: http.StatusText(resp.status)}
+		apiErr.Detail = strings.TrimSpace(string(resp.body[:min(len(resp.body), 512)]))
+	}
+	apiErr.StatusCode = resp.status
+
+	return apiErr
+}
+
+// Форматы месяцев в запросах и ответах версий API
+const (
+	monthLayoutV1 = "01-2006"
+	monthLayoutV2 = "2006-01"
+)
+
+// monthLayout возвращает формат месяцев версии API клиента
+func (c *Client) monthLayout() string {
+	if c.version == APIv1 {
+		return monthLayoutV1
+	}
+
+	return monthLayoutV2
+}
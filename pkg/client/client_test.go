@@ -0,0 +1,434 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/ZnNr/subscription-service/internal/handler"
+	handlerv2 "github.com/ZnNr/subscription-service/internal/handler/v2"
+	"github.com/ZnNr/subscription-service/internal/model"
+	"github.com/ZnNr/subscription-service/internal/service"
+	"github.com/ZnNr/subscription-service/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	logger.Init("error", "text")
+}
+
+// memoryService — сервис подписок в памяти: клиент проверяется через настоящие обработчики v1 и v2
+type memoryService struct {
+	service.Service
+
+	mu            sync.Mutex
+	subscriptions map[uuid.UUID]*model.Subscription
+	idempotency   map[string]*model.IdempotencyRecord
+}
+
+func newMemoryService() *memoryService {
+	return &memoryService{
+		subscriptions: make(map[uuid.UUID]*model.Subscription),
+		idempotency:   make(map[string]*model.IdempotencyRecord),
+	}
+}
+
+func (s *memoryService) CreateSubscription(_ context.Context, sub *model.Subscription, _ model.WriteOptions) (*model.CreateSubscriptionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created := *sub
+	created.Version = 1
+	s.subscriptions[created.ID] = &created
+	return &model.CreateSubscriptionResponse{Subscription: &created}, nil
+}
+
+func (s *memoryService) GetSubscription(_ context.Context, id uuid.UUID) (*model.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, service.ErrNotFound
+	}
+	found := *sub
+	return &found, nil
+}
+
+func (s *memoryService) UpdateSubscription(_ context.Context, sub *model.Subscription, opts model.WriteOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.subscriptions[sub.ID]
+	if !ok {
+		return service.ErrNotFound
+	}
+	if opts.IfMatch != nil && *opts.IfMatch != existing.Version {
+		return service.ErrPreconditionFailed
+	}
+
+	sub.CreatedAt = existing.CreatedAt
+	sub.Version = existing.Version + 1
+	updated := *sub
+	s.subscriptions[sub.ID] = &updated
+	return nil
+}
+
+func (s *memoryService) DeleteSubscription(_ context.Context, id uuid.UUID, opts model.WriteOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.subscriptions[id]
+	if !ok {
+		return service.ErrNotFound
+	}
+	if opts.IfMatch != nil && *opts.IfMatch != existing.Version {
+		return service.ErrPreconditionFailed
+	}
+	delete(s.subscriptions, id)
+	return nil
+}
+
+func (s *memoryService) ListSubscriptions(_ context.Context, filter model.SubscriptionFilter) (*model.SubscriptionPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page := &model.SubscriptionPage{Items: []*model.Subscription{}}
+	for _, sub := range s.subscriptions {
+		if filter.UserID != nil && sub.UserID != *filter.UserID {
+			continue
+		}
+		if len(filter.ServiceNames) > 0 && !slices.Contains(filter.ServiceNames, sub.ServiceName) {
+			continue
+		}
+		page.Items = append(page.Items, sub)
+	}
+	return page, nil
+}
+
+func (s *memoryService) CalculateSummary(_ context.Context, startDate, endDate time.Time, userID *uuid.UUID, _ *string) (*model.SummaryResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summary model.SummaryResponse
+	for _, sub := range s.subscriptions {
+		if userID != nil && sub.UserID != *userID {
+			continue
+		}
+		months := (endDate.Year()-startDate.Year())*12 + int(endDate.Month()-startDate.Month()) + 1
+		summary.TotalAmount += sub.Price * months
+		summary.Count++
+	}
+	return &summary, nil
+}
+
+func (s *memoryService) BeginIdempotentRequest(_ context.Context, key, fingerprint string) (*model.IdempotencyRecord, uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.idempotency[key]; ok {
+		return record, uuid.Nil, nil
+	}
+	return nil, uuid.New(), nil
+}
+
+func (s *memoryService) FinishIdempotentRequest(_ context.Context, key string, _ uuid.UUID, statusCode int, headers map[string]string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idempotency[key] = &model.IdempotencyRecord{Key: key, StatusCode: statusCode, Headers: headers, Body: body}
+	return nil
+}
+
+func (s *memoryService) ImportSubscriptions(_ context.Context, rows []model.ImportRow, opts model.ImportOptions) (*model.ImportReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &model.ImportReport{DryRun: opts.DryRun, Total: len(rows)}
+	for _, row := range rows {
+		result := &model.ImportRowResult{Row: row.Row, Status: model.ImportStatusValid}
+		if len(row.Errors) > 0 {
+			result.Status, result.Code, result.Errors = model.ImportStatusInvalid, "validation_failed", row.Errors
+			report.Invalid++
+		} else {
+			report.Valid++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+	if report.Invalid > 0 {
+		return report, service.ErrImportInvalid
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for i, row := range rows {
+		s.subscriptions[row.Subscription.ID] = row.Subscription
+		report.Rows[i].Status, report.Rows[i].ID = model.ImportStatusCreated, &row.Subscription.ID
+		report.Created++
+	}
+	return report, nil
+}
+
+func newRouter(svc service.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler.RequestID())
+	handler.NewHandler(svc).SetupRoutes(router)
+	handlerv2.NewHandler(svc, nil).SetupRoutes(router)
+	return router
+}
+
+// fastRetries — повторы без заметных задержек
+var fastRetries = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func newTestClient(t *testing.T, h http.Handler, opts ...Option) *Client {
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, append([]Option{WithRetryPolicy(fastRetries)}, opts...)...)
+	require.NoError(t, err)
+	return c
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestClient_SubscriptionLifecycle(t *testing.T) {
+	for _, apiVersion := range []APIVersion{APIv2, APIv1} {
+		t.Run(string(apiVersion), func(t *testing.T) {
+			testSubscriptionLifecycle(t, apiVersion)
+		})
+	}
+}
+
+func testSubscriptionLifecycle(t *testing.T, apiVersion APIVersion) {
+	svc := newMemoryService()
+	router := newRouter(svc)
+	var paths []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		router.ServeHTTP(w, r)
+	}), WithAPIVersion(apiVersion))
+	ctx := context.Background()
+
+	userID := uuid.New()
+	category := "video"
+	endDate := month(2025, time.December)
+
+	created, err := c.CreateSubscription(ctx, SubscriptionInput{
+		ServiceName: "Yandex Plus",
+		Price:       400,
+		UserID:      userID,
+		StartDate:   month(2025, time.July),
+		EndDate:     &endDate,
+		Category:    &category,
+	}, WriteOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.Version)
+	assert.Equal(t, month(2025, time.July), created.StartDate)
+	require.NotNil(t, created.EndDate)
+	assert.Equal(t, endDate, *created.EndDate)
+
+	sub, err := c.GetSubscription(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Yandex Plus", sub.ServiceName)
+	assert.Equal(t, &category, sub.Category)
+
+	version, err := c.UpdateSubscription(ctx, sub.ID, SubscriptionInput{
+		ServiceName: "Yandex Plus",
+		Price:       450,
+		UserID:      userID,
+		StartDate:   month(2025, time.July),
+	}, WriteOptions{IfMatch: &sub.Version})
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	page, err := c.ListSubscriptions(ctx, ListOptions{UserID: &userID, ServiceNames: []string{"Yandex Plus"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, 450, page.Items[0].Price)
+	assert.Nil(t, page.Items[0].EndDate)
+	assert.Nil(t, page.NextCursor)
+
+	summary, err := c.CalculateSummary(ctx, SummaryRequest{StartDate: month(2025, time.January), EndDate: month(2025, time.December), UserID: &userID})
+	require.NoError(t, err)
+	assert.Equal(t, 450*12, summary.TotalAmount)
+	assert.Equal(t, 1, summary.Count)
+
+	require.NoError(t, c.DeleteSubscription(ctx, sub.ID, WriteOptions{}))
+
+	_, err = c.GetSubscription(ctx, sub.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "subscription_not_found", apiErr.Code)
+	assert.NotEmpty(t, apiErr.RequestID)
+
+	for _, path := range paths {
+		assert.True(t, strings.HasPrefix(path, "/api/"+string(apiVersion)+"/"), path)
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	svc := newMemoryService()
+	c := newTestClient(t, newRouter(svc))
+	ctx := context.Background()
+
+	_, err := c.CreateSubscription(ctx, SubscriptionInput{ServiceName: "Netflix", UserID: uuid.New(), StartDate: month(2025, time.July)}, WriteOptions{})
+	assert.ErrorIs(t, err, ErrInvalid)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "validation_failed", apiErr.Code)
+	assert.Equal(t, []FieldViolation{{Field: "price", Message: apiErr.Errors[0].Message}}, apiErr.Errors)
+
+	created, err := c.CreateSubscription(ctx, SubscriptionInput{ServiceName: "Netflix", Price: 599, UserID: uuid.New(), StartDate: month(2025, time.July)}, WriteOptions{})
+	require.NoError(t, err)
+
+	stale := created.Version + 1
+	err = c.DeleteSubscription(ctx, created.ID, WriteOptions{IfMatch: &stale})
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	assert.Contains(t, err.Error(), "412 precondition_failed")
+}
+
+func TestClient_ImportSubscriptions(t *testing.T) {
+	svc := newMemoryService()
+	router := newRouter(svc)
+	// Клиент по умолчанию работает с v2, но импорт есть только в v1
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/subscriptions/import", r.URL.Path)
+		router.ServeHTTP(w, r)
+	}))
+	ctx := context.Background()
+
+	file := "service_name;price;user_id;start_date\nNetflix;599;60601fee-2bf1-4721-ae6f-7636e79a0cba;01-2025\n"
+	report, err := c.ImportSubscriptions(ctx, strings.NewReader(file), ImportOptions{Format: ImportFormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	require.Len(t, report.Rows, 1)
+	assert.Equal(t, "created", report.Rows[0].Status)
+	assert.Len(t, svc.subscriptions, 1)
+
+	file = `[{"service_name":"Netflix","price":599,"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","start_date":"2025-01"}]`
+	report, err = c.ImportSubscriptions(ctx, strings.NewReader(file), ImportOptions{Format: ImportFormatJSON, DryRun: true})
+	assert.ErrorIs(t, err, ErrUnprocessable)
+	assert.EqualError(t, err, "subscription service: 422 validation_failed: 1 of 1 rows failed validation, nothing was saved")
+	require.NotNil(t, report)
+	require.Len(t, report.Rows, 1)
+	assert.Equal(t, "start_date", report.Rows[0].Errors[0].Field)
+
+	_, err = c.ImportSubscriptions(ctx, strings.NewReader(file), ImportOptions{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestClient_RetriesCreateWithSameIdempotencyKey(t *testing.T) {
+	svc := newMemoryService()
+	router := newRouter(svc)
+
+	// Первый ответ теряется по дороге: подписка создана, но клиент получает 502
+	var keys []string
+	lost := false
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if !lost {
+			lost = true
+			router.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+
+	created, err := c.CreateSubscription(context.Background(), SubscriptionInput{
+		ServiceName: "Netflix", Price: 599, UserID: uuid.New(), StartDate: month(2025, time.July),
+	}, WriteOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+	assert.Len(t, svc.subscriptions, 1)
+	assert.Contains(t, svc.subscriptions, created.ID)
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "0")
+		http.Error(w, "<html>upstream unavailable</html>", http.StatusServiceUnavailable)
+	}))
+
+	_, err := c.GetSubscription(context.Background(), uuid.New())
+
+	assert.Equal(t, int32(fastRetries.MaxRetries+1), attempts.Load())
+	assert.ErrorIs(t, err, ErrServer)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Empty(t, apiErr.Code)
+	assert.Equal(t, "<html>upstream unavailable</html>", apiErr.Detail)
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	router := newRouter(newMemoryService())
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		router.ServeHTTP(w, r)
+	}))
+
+	_, err := c.GetSubscription(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClient_ContextCancelledDuringBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRetryPolicy(RetryPolicy{MaxRetries: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.GetSubscription(ctx, uuid.New())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://example.com", "http://"} {
+		_, err := New(baseURL)
+		assert.Error(t, err, baseURL)
+	}
+}
+
+func TestNew_UnsupportedAPIVersion(t *testing.T) {
+	_, err := New("http://localhost:8080", WithAPIVersion("v3"))
+	assert.EqualError(t, err, `client: unsupported API version "v3"`)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := policy.backoff(retry)
+		assert.GreaterOrEqual(t, delay, want/2, retry)
+		assert.LessOrEqual(t, delay, want, retry)
+	}
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Категории ошибок по статусу ответа; проверяются через errors.Is:
+//
+//	if errors.Is(err, client.ErrPreconditionFailed) { ... }
+var (
+	// ErrInvalid — некорректный запрос (400)
+	ErrInvalid = errors.New("invalid request")
+	// ErrNotFound — запись не найдена (404)
+	ErrNotFound = errors.New("not found")
+	// ErrConflict — запись конфликтует с существующими, например пересекается по периоду (409)
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed — версия подписки не совпала с If-Match (412)
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnprocessable — запрос нарушает ограничения данных или ключ идемпотентности уже использован (422)
+	ErrUnprocessable = errors.New("unprocessable entity")
+	// ErrServer — ошибка на стороне сервиса (5xx)
+	ErrServer = errors.New("server error")
+)
+
+// statusErrors — категория ошибки для статуса ответа
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrInvalid,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusPreconditionFailed:  ErrPreconditionFailed,
+	http.StatusUnprocessableEntity: ErrUnprocessable,
+}
+
+// Error — ответ сервиса с ошибкой (application/problem+json, RFC 9457)
+type Error struct {
+	// StatusCode — HTTP-статус ответа
+	StatusCode int    `json:"status"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+	Instance   string `json:"instance"`
+	// Code — стабильный машиночитаемый код ошибки, например "subscription_not_found";
+	// пуст, если ответ пришел не от сервиса (например, от прокси)
+	Code string `json:"code"`
+	// RequestID — идентификатор запроса для поиска в логах сервиса
+	RequestID string `json:"request_id"`
+	// Errors — нарушения правил проверки по полям (для Code "validation_failed")
+	Errors []FieldViolation `json:"errors"`
+	// Conflicts — подписки, с которыми пересекается сохраняемая (для Code "subscription_overlap")
+	Conflicts []uuid.UUID `json:"conflicts"`
+}
+
+// FieldViolation — нарушение правила проверки одним полем тела запроса
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+
+	if e.Code == "" {
+		return fmt.Sprintf("subscription service: %d: %s", e.StatusCode, message)
+	}
+
+	return fmt.Sprintf("subscription service: %d %s: %s", e.StatusCode, e.Code, message)
+}
+
+// Unwrap возвращает категорию ошибки (ErrNotFound и т.д.) по статусу ответа
+func (e *Error) Unwrap() error {
+	if err, ok := statusErrors[e.StatusCode]; ok {
+		return err
+	}
+
+	if e.StatusCode >= http.StatusInternalServerError {
+		return ErrServer
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Форматы файла импорта
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// ImportOptions задает формат файла и режим импорта
+type ImportOptions struct {
+	// Format — ImportFormatCSV или ImportFormatJSON
+	Format string
+	// DryRun — только проверить строки, ничего не сохраняя
+	DryRun bool
+	// ResumeFrom — номер строки данных (с 1), с которой продолжить импорт; 0 — с начала
+	ResumeFrom int
+	// Force разрешает сохранять подписки, пересекающиеся с существующими
+	Force bool
+}
+
+// ImportReport — отчет об импорте подписок
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+	// Total — число строк данных в файле
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	Created int `json:"created"`
+	// ResumeFrom — номер строки, с которой нужно повторить импорт после ошибки (ImportOptions.ResumeFrom)
+	ResumeFrom *int               `json:"resume_from,omitempty"`
+	Rows       []*ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult — результат обработки одной строки импорта
+type ImportRowResult struct {
+	Row int `json:"row"`
+	// Status — "valid", "invalid", "created", "failed", "rolled_back" или "skipped"
+	Status string     `json:"status"`
+	ID     *uuid.UUID `json:"id,omitempty"`
+	// Code и Error — код и описание ошибки строки
+	Code     string           `json:"code,omitempty"`
+	Error    string           `json:"error,omitempty"`
+	Errors   []FieldViolation `json:"errors,omitempty"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// importContentTypes — Content-Type тела запроса для формата файла
+var importContentTypes = map[string]string{
+	ImportFormatCSV:  "text/csv",
+	ImportFormatJSON: "application/json",
+}
+
+// ImportSubscriptions создает подписки из файла CSV или JSON. Если строки не прошли проверку
+// (ErrUnprocessable) или импорт остановлен на строке, которую не удалось сохранить (ErrConflict),
+// вместе с ошибкой возвращается отчет по строкам. Повторяется только пробный запуск.
+// В /api/v2 импорта нет, поэтому запрос всегда отправляется в /api/v1, какая бы версия
+// ни была задана в WithAPIVersion.
+func (c *Client) ImportSubscriptions(ctx context.Context, file io.Reader, opts ImportOptions) (*ImportReport, error) {
+	contentType, ok := importContentTypes[opts.Format]
+	if !ok {
+		return nil, fmt.Errorf("client: unsupported import format %q, expected csv or json", opts.Format)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("client: read import file: %w", err)
+	}
+
+	query := url.Values{}
+	if opts.DryRun {
+		query.Set("dry_run", "true")
+	}
+	if opts.ResumeFrom > 0 {
+		query.Set("resume_from", strconv.Itoa(opts.ResumeFrom))
+	}
+	if opts.Force {
+		query.Set("force", "true")
+	}
+
+	var report ImportReport
+	resp, err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        "/subscriptions/import",
+		version:     APIv1,
+		query:       query,
+		raw:         data,
+		contentType: contentType,
+		retryable:   opts.DryRun,
+	}, &report)
+	if err != nil {
+		if resp == nil || (resp.status != http.StatusConflict && resp.status != http.StatusUnprocessableEntity) {
+			return nil, err
+		}
+		// Отказ с отчетом вместо application/problem+json
+		if json.Unmarshal(resp.body, &report) != nil {
+			return nil, err
+		}
+		return &report, importError(resp.status, &report)
+	}
+
+	return &report, nil
+}
+
+// importError описывает отказ импорта по отчету; код ошибки берется из первой строки с ошибкой
+func importError(status int, report *ImportReport) *Error {
+	apiErr := &Error{StatusCode: status, Title: http.StatusText(status)}
+
+	for _, row := range report.Rows {
+		if row.Code != "" {
+			apiErr.Code = row.Code
+			break
+		}
+	}
+
+	if status == http.StatusUnprocessableEntity {
+		apiErr.Detail = fmt.Sprintf("%d of %d rows failed validation, nothing was saved", report.Invalid, report.Total)
+	} else if report.ResumeFrom != nil {
+		apiErr.Detail = fmt.Sprintf("import stopped, %d rows created, resume from row %d", report.Created, *report.ResumeFrom)
+	} else {
+		apiErr.Detail = fmt.Sprintf("import stopped, %d rows created", report.Created)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,424 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription — подписка пользователя на сервис
+type Subscription struct {
+	ID          uuid.UUID `json:"id"`
+	ServiceName string    `json:"service_name"`
+	Price       int       `json:"price"`
+	UserID      uuid.UUID `json:"user_id"`
+	// StartDate и EndDate — первое число месяца начала и окончания (UTC)
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+	Category  *string    `json:"category,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Version — версия подписки для WriteOptions.IfMatch
+	Version int `json:"version"`
+}
+
+// SubscriptionInput — данные подписки для создания и полной замены. Из дат учитываются
+// только год и месяц; не заданные EndDate и Category при замене очищаются.
+type SubscriptionInput struct {
+	ServiceName string
+	Price       int
+	UserID      uuid.UUID
+	StartDate   time.Time
+	EndDate     *time.Time
+	Category    *string
+}
+
+// CreatedSubscription — созданная подписка
+type CreatedSubscription struct {
+	Subscription
+	// Warnings — предупреждения о сохранении (например, о пересечении при Force)
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// WriteOptions — условия создания, замены и удаления подписки
+type WriteOptions struct {
+	// Force сохраняет подписку, даже если она пересекается с существующими
+	Force bool
+	// IfMatch — ожидаемая версия подписки; при несовпадении возвращается ErrPreconditionFailed
+	IfMatch *int
+	// IdempotencyKey — ключ идемпотентности создания; если не задан, клиент создает свой
+	// на каждый вызов CreateSubscription
+	IdempotencyKey string
+}
+
+// ListOptions — фильтры и страница списка подписок; нулевые значения не ограничивают выборку
+type ListOptions struct {
+	UserID *uuid.UUID
+	// ServiceNames — точные названия сервисов, подходит любое из них
+	ServiceNames []string
+	// Search — подстрока названия сервиса или категории без учета регистра
+	Search string
+	// ActiveAt — месяц, в котором подписка действует
+	ActiveAt  *time.Time
+	PriceMin  *int
+	PriceMax  *int
+	StartFrom *time.Time
+	StartTo   *time.Time
+	EndFrom   *time.Time
+	EndTo     *time.Time
+	// Status — "active", "ended" или "future" относительно текущего месяца
+	Status string
+	// Sort — поля сортировки через запятую, "-" — по убыванию, например "-price,service_name"
+	Sort string
+	// Limit — размер страницы (по умолчанию 50, максимум 500)
+	Limit int
+	// Cursor — NextCursor предыдущей страницы
+	Cursor string
+}
+
+// SubscriptionPage — страница списка подписок; NextCursor равен nil на последней странице
+type SubscriptionPage struct {
+	Items      []*Subscription `json:"items"`
+	NextCursor *string         `json:"next_cursor,omitempty"`
+}
+
+// SummaryRequest — параметры расчета суммы подписок за период (месяцы включительно)
+type SummaryRequest struct {
+	StartDate   time.Time
+	EndDate     time.Time
+	UserID      *uuid.UUID
+	ServiceName *string
+	// Source — "subscriptions" (по умолчанию) или "ledger" — расчет по журналу списаний
+	Source string
+}
+
+// Summary — сумма подписок за период
+type Summary struct {
+	TotalAmount int `json:"total_amount"`
+	Count       int `json:"count"`
+	// AdjustmentsAmount — сумма корректировок за период (уже учтена в TotalAmount)
+	AdjustmentsAmount int `json:"adjustments_amount"`
+}
+
+// subscriptionBody — тело запроса создания и замены подписки; месяцы — в формате версии API
+type subscriptionBody struct {
+	ServiceName string    `json:"service_name"`
+	Price       int       `json:"price"`
+	UserID      uuid.UUID `json:"user_id"`
+	StartDate   string    `json:"start_date"`
+	EndDate     *string   `json:"end_date,omitempty"`
+	Category    *string   `json:"category,omitempty"`
+}
+
+// summaryBody — тело запроса суммы подписок; месяцы — в формате версии API
+type summaryBody struct {
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	ServiceName *string    `json:"service_name,omitempty"`
+	Source      string     `json:"source,omitempty"`
+}
+
+// CreateSubscription создает подписку. Повтор после сетевой ошибки выполняется с тем же
+// ключом идемпотентности: сервис вернет сохраненный ответ, а не создаст вторую подписку.
+func (c *Client) CreateSubscription(ctx context.Context, input SubscriptionInput, opts WriteOptions) (*CreatedSubscription, error) {
+	key := opts.IdempotencyKey
+	if key == "" {
+		key = uuid.NewString()
+	}
+
+	r := request{
+		method:    http.MethodPost,
+		path:      "/subscriptions",
+		query:     writeQuery(opts),
+		header:    http.Header{"Idempotency-Key": {key}},
+		body:      newSubscriptionBody(input, c.monthLayout()),
+		retryable: true,
+	}
+
+	if c.version == APIv1 {
+		var created CreatedSubscription
+		if _, err := c.do(ctx, r, &created); err != nil {
+			return nil, err
+		}
+		return &created, nil
+	}
+
+	var resp struct {
+		envelope[subscriptionV2]
+		Warnings []string `json:"warnings"`
+	}
+	if _, err := c.do(ctx, r, &resp); err != nil {
+		return nil, err
+	}
+
+	sub, err := resp.Data.subscription()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedSubscription{Subscription: *sub, Warnings: resp.Warnings}, nil
+}
+
+// GetSubscription возвращает подписку по идентификатору
+func (c *Client) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	r := request{
+		method:    http.MethodGet,
+		path:      "/subscriptions/" + id.String(),
+		retryable: true,
+	}
+
+	if c.version == APIv1 {
+		var sub Subscription
+		if _, err := c.do(ctx, r, &sub); err != nil {
+			return nil, err
+		}
+		return &sub, nil
+	}
+
+	var resp envelope[subscriptionV2]
+	if _, err := c.do(ctx, r, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data.subscription()
+}
+
+// UpdateSubscription полностью заменяет подписку и возвращает её новую версию. С IfMatch
+// повтор после потерянного ответа может вернуть ErrPreconditionFailed: замена уже выполнена.
+func (c *Client) UpdateSubscription(ctx context.Context, id uuid.UUID, input SubscriptionInput, opts WriteOptions) (int, error) {
+	resp, err := c.do(ctx, request{
+		method:    http.MethodPut,
+		path:      "/subscriptions/" + id.String(),
+		query:     writeQuery(opts),
+		header:    ifMatchHeader(opts),
+		body:      newSubscriptionBody(input, c.monthLayout()),
+		retryable: true,
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	version, _ := strconv.Atoi(strings.Trim(resp.header.Get("ETag"), `"`))
+	return version, nil
+}
+
+// DeleteSubscription удаляет подписку. Повтор удаления, которое уже выполнилось,
+// возвращает ErrNotFound.
+func (c *Client) DeleteSubscription(ctx context.Context, id uuid.UUID, opts WriteOptions) error {
+	_, err := c.do(ctx, request{
+		method:    http.MethodDelete,
+		path:      "/subscriptions/" + id.String(),
+		header:    ifMatchHeader(opts),
+		retryable: true,
+	}, nil)
+
+	return err
+}
+
+// ListSubscriptions возвращает страницу списка подписок. Следующая страница запрашивается
+// с Cursor, равным NextCursor, и теми же фильтрами и Sort.
+func (c *Client) ListSubscriptions(ctx context.Context, opts ListOptions) (*SubscriptionPage, error) {
+	query := url.Values{}
+	if opts.UserID != nil {
+		query.Set("user_id", opts.UserID.String())
+	}
+	for _, name := range opts.ServiceNames {
+		query.Add("service_name", name)
+	}
+	layout := c.monthLayout()
+	setString(query, "q", opts.Search)
+	setMonth(query, "active_at", opts.ActiveAt, layout)
+	setInt(query, "price_min", opts.PriceMin)
+	setInt(query, "price_max", opts.PriceMax)
+	setMonth(query, "start_from", opts.StartFrom, layout)
+	setMonth(query, "start_to", opts.StartTo, layout)
+	setMonth(query, "end_from", opts.EndFrom, layout)
+	setMonth(query, "end_to", opts.EndTo, layout)
+	setString(query, "status", opts.Status)
+	setString(query, "sort", opts.Sort)
+	setString(query, "cursor", opts.Cursor)
+
+	// С limit v1 отвечает страницей с курсором, а не массивом
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query.Set("limit", strconv.Itoa(limit))
+
+	r := request{
+		method:    http.MethodGet,
+		path:      "/subscriptions",
+		query:     query,
+		retryable: true,
+	}
+
+	if c.version == APIv1 {
+		var page SubscriptionPage
+		if _, err := c.do(ctx, r, &page); err != nil {
+			return nil, err
+		}
+		return &page, nil
+	}
+
+	var resp subscriptionListV2
+	if _, err := c.do(ctx, r, &resp); err != nil {
+		return nil, err
+	}
+
+	page := &SubscriptionPage{Items: make([]*Subscription, 0, len(resp.Data)), NextCursor: resp.Pagination.NextCursor}
+	for _, item := range resp.Data {
+		sub, err := item.subscription()
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, sub)
+	}
+
+	return page, nil
+}
+
+// CalculateSummary считает суммарную стоимость подписок за период
+func (c *Client) CalculateSummary(ctx context.Context, req SummaryRequest) (*Summary, error) {
+	r := request{
+		method: http.MethodPost,
+		path:   "/subscriptions/summary",
+		body: summaryBody{
+			StartDate:   req.StartDate.Format(c.monthLayout()),
+			EndDate:     req.EndDate.Format(c.monthLayout()),
+			UserID:      req.UserID,
+			ServiceName: req.ServiceName,
+			Source:      req.Source,
+		},
+		// Расчет ничего не меняет, повтор безопасен
+		retryable: true,
+	}
+
+	if c.version == APIv1 {
+		var summary Summary
+		if _, err := c.do(ctx, r, &summary); err != nil {
+			return nil, err
+		}
+		return &summary, nil
+	}
+
+	var resp envelope[Summary]
+	if _, err := c.do(ctx, r, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+func newSubscriptionBody(input SubscriptionInput, layout string) subscriptionBody {
+	body := subscriptionBody{
+		ServiceName: input.ServiceName,
+		Price:       input.Price,
+		UserID:      input.UserID,
+		StartDate:   input.StartDate.Format(layout),
+		Category:    input.Category,
+	}
+	if input.EndDate != nil {
+		endDate := input.EndDate.Format(layout)
+		body.EndDate = &endDate
+	}
+
+	return body
+}
+
+// envelope — ответ /api/v2: данные передаются в поле data
+type envelope[T any] struct {
+	Data T `json:"data"`
+}
+
+// subscriptionV2 — подписка в ответах /api/v2: месяцы в формате YYYY-MM
+type subscriptionV2 struct {
+	ID          uuid.UUID `json:"id"`
+	ServiceName string    `json:"service_name"`
+	Price       int       `json:"price"`
+	UserID      uuid.UUID `json:"user_id"`
+	StartDate   string    `json:"start_date"`
+	EndDate     *string   `json:"end_date"`
+	Category    *string   `json:"category"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int       `json:"version"`
+}
+
+// subscriptionListV2 — страница списка подписок /api/v2
+type subscriptionListV2 struct {
+	Data       []subscriptionV2 `json:"data"`
+	Pagination struct {
+		NextCursor *string `json:"next_cursor"`
+	} `json:"pagination"`
+}
+
+// subscription переводит подписку /api/v2 в Subscription
+func (s subscriptionV2) subscription() (*Subscription, error) {
+	startDate, err := time.Parse(monthLayoutV2, s.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("client: decode response: start_date: %w", err)
+	}
+
+	sub := &Subscription{
+		ID:          s.ID,
+		ServiceName: s.ServiceName,
+		Price:       s.Price,
+		UserID:      s.UserID,
+		StartDate:   startDate,
+		Category:    s.Category,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+		Version:     s.Version,
+	}
+	if s.EndDate != nil {
+		endDate, err := time.Parse(monthLayoutV2, *s.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("client: decode response: end_date: %w", err)
+		}
+		sub.EndDate = &endDate
+	}
+
+	return sub, nil
+}
+
+func writeQuery(opts WriteOptions) url.Values {
+	query := url.Values{}
+	if opts.Force {
+		query.Set("force", "true")
+	}
+
+	return query
+}
+
+func ifMatchHeader(opts WriteOptions) http.Header {
+	if opts.IfMatch == nil {
+		return nil
+	}
+
+	return http.Header{"If-Match": {strconv.Quote(strconv.Itoa(*opts.IfMatch))}}
+}
+
+func setString(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+func setInt(query url.Values, name string, value *int) {
+	if value != nil {
+		query.Set(name, strconv.Itoa(*value))
+	}
+}
+
+func setMonth(query url.Values, name string, value *time.Time, layout string) {
+	if value != nil {
+		query.Set(name, value.Format(layout))
+	}
+}
@@ -61,6 +61,116 @@ func RunMigrations(db *sql.DB) error {
 			version INTEGER PRIMARY KEY,
 			applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 		)`,
+
+		// Миграция 4: Категории подписок и бюджеты пользователей
+		`ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS category VARCHAR(100)`,
+		`CREATE TABLE IF NOT EXISTS budgets (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			category VARCHAR(100),
+			monthly_limit INTEGER NOT NULL CHECK (monthly_limit > 0),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_budgets_user_category ON budgets(user_id, (COALESCE(category, '')))`,
+
+		// Миграция 5: Журнал ожидаемых списаний по подпискам
+		`CREATE TABLE IF NOT EXISTS charges (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL,
+			service_name VARCHAR(255) NOT NULL,
+			category VARCHAR(100),
+			month DATE NOT NULL,
+			amount INTEGER NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (subscription_id, month)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_charges_user_month ON charges(user_id, month)`,
+		`CREATE INDEX IF NOT EXISTS idx_charges_month ON charges(month)`,
+
+		// Миграция 6: Возвраты, кредиты и разовые списания по подпискам
+		`CREATE TABLE IF NOT EXISTS adjustments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+			kind VARCHAR(20) NOT NULL CHECK (kind IN ('refund', 'credit', 'charge')),
+			amount INTEGER NOT NULL CHECK (amount > 0),
+			date DATE NOT NULL,
+			reason TEXT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_adjustments_subscription_id ON adjustments(subscription_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_adjustments_date ON adjustments(date)`,
+
+		// Миграция 7: Версия подписки для оптимистичной блокировки (ETag / If-Match)
+		`ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1`,
+
+		// Миграция 8: Ключи идемпотентности и сохраненные ответы
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			key VARCHAR(255) PRIMARY KEY,
+			fingerprint VARCHAR(64) NOT NULL,
+			status_code INTEGER,
+			response_headers JSONB,
+			response_body BYTEA,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at)`,
+
+		// Миграция 9: Индекс для инкрементальной выгрузки по updated_at
+		`CREATE INDEX IF NOT EXISTS idx_subscriptions_updated_at ON subscriptions(updated_at, id)`,
+
+		// Миграция 10: Вебхуки и очередь их доставки
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			event_types TEXT[] NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+			event_id UUID NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			payload JSONB NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			delivered_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending'`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+			id BIGSERIAL PRIMARY KEY,
+			delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+			attempted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			response_status INTEGER,
+			error TEXT,
+			duration_ms INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id, attempted_at)`,
+
+		// Миграция 11: Нечеткий поиск и автодополнение названий сервисов
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE INDEX IF NOT EXISTS idx_subscriptions_service_name_trgm ON subscriptions USING GIN (LOWER(service_name) gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_subscriptions_service_name_prefix ON subscriptions(LOWER(service_name) text_pattern_ops)`,
+
+		// Миграция 12: Срок, до которого ключ идемпотентности занят выполняющимся запросом
+		`ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+
+		// Миграция 13: Журнал списаний для подписок, созданных до его появления. Заполняются только
+		// подписки без строк в журнале; горизонт бессрочных — 12 месяцев, как у сервиса
+		`INSERT INTO charges (subscription_id, user_id, service_name, category, month, amount)
+		SELECT s.id, s.user_id, s.service_name, s.category, m::date, s.price
+		FROM subscriptions s,
+			generate_series(s.start_date, COALESCE(s.end_date, (date_trunc('month', CURRENT_DATE) + interval '12 months')::date), interval '1 month') AS m
+		WHERE NOT EXISTS (SELECT 1 FROM charges c WHERE c.subscription_id = s.id)
+		ON CONFLICT (subscription_id, month) DO NOTHING`,
+
+		// Миграция 14: Токен аренды ключа идемпотентности: ответ сохраняет только запрос, занявший ключ
+		`ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS lease UUID`,
 	}
 
 	// Начинаем транзакцию
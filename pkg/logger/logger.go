@@ -62,14 +62,29 @@ func GinLogger() gin.HandlerFunc {
 
 		duration := time.Since(start)
 
-		log.WithFields(logrus.Fields{
+		entry := log.WithFields(logrus.Fields{
 			"method":     c.Request.Method,
 			"path":       c.Request.URL.Path,
 			"status":     c.Writer.Status(),
 			"duration":   duration,
 			"client_ip":  c.ClientIP(),
 			"user_agent": c.Request.UserAgent(),
-		}).Info("HTTP request")
+			"request_id": c.GetString("request_id"),
+		})
+
+		// Внутренние ошибки и подробности ошибок репозитория не отдаются клиенту, обработчики
+		// передают их сюда через c.Error; при ответе 4xx это предупреждение, а не ошибка сервера
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("error", c.Errors.String())
+			if status := c.Writer.Status(); status >= 400 && status < 500 {
+				entry.Warn("HTTP request")
+				return
+			}
+			entry.Error("HTTP request")
+			return
+		}
+
+		entry.Info("HTTP request")
 	}
 }
 